@@ -0,0 +1,202 @@
+package gsrf
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Field paths accepted by Get and Set. List values (type arguments, via) are
+// rendered and accepted as comma-separated strings, booleans and integers in
+// their strconv form. Custom metadata is addressed as "metadata.custom.<key>".
+const (
+	FieldPackagePath       = "packagePath"
+	FieldName              = "name"
+	FieldIsInit            = "isInit"
+	FieldIsAnonymous       = "isAnonymous"
+	FieldAnonParent        = "anonParent"
+	FieldAnonIndex         = "anonIndex"
+	FieldTypeArgs          = "typeArgs"
+	FieldContext           = "context"
+	FieldReceiverTypeName  = "receiver.typeName"
+	FieldReceiverIsPointer = "receiver.isPointer"
+	FieldReceiverTypeArgs  = "receiver.typeArgs"
+	FieldMetadataVia       = "metadata.via"
+	FieldMetadataAlias     = "metadata.alias"
+	FieldMetadataPosition  = "metadata.position"
+
+	customFieldPrefix = "metadata.custom."
+)
+
+// Get returns the value of the field addressed by path as a string.
+// Receiver fields of a function symbol and unset custom metadata keys
+// read as the empty string.
+func (s *Symbol) Get(path string) (string, error) {
+	switch path {
+	case FieldPackagePath:
+		return s.PackagePath, nil
+	case FieldName:
+		return s.Name, nil
+	case FieldIsInit:
+		return strconv.FormatBool(s.IsInit), nil
+	case FieldIsAnonymous:
+		return strconv.FormatBool(s.IsAnonymous), nil
+	case FieldAnonParent:
+		return s.AnonParent, nil
+	case FieldAnonIndex:
+		return strconv.Itoa(s.AnonIndex), nil
+	case FieldTypeArgs:
+		return strings.Join(s.TypeArgs, ", "), nil
+	case FieldContext:
+		return s.Context, nil
+	case FieldReceiverTypeName:
+		if s.Receiver == nil {
+			return "", nil
+		}
+		return s.Receiver.TypeName, nil
+	case FieldReceiverIsPointer:
+		if s.Receiver == nil {
+			return strconv.FormatBool(false), nil
+		}
+		return strconv.FormatBool(s.Receiver.IsPointer), nil
+	case FieldReceiverTypeArgs:
+		if s.Receiver == nil {
+			return "", nil
+		}
+		return strings.Join(s.Receiver.TypeArgs, ", "), nil
+	case FieldMetadataVia:
+		return strings.Join(s.Metadata.Via, ", "), nil
+	case FieldMetadataAlias:
+		return s.Metadata.Alias, nil
+	case FieldMetadataPosition:
+		return s.Metadata.Position, nil
+	}
+
+	if key, ok := customKey(path); ok {
+		return s.Metadata.Custom[key], nil
+	}
+	return "", fmt.Errorf("unknown field path: %q", path)
+}
+
+// Set assigns value to the field addressed by path. Setting the receiver type
+// name on a function symbol turns it into a method; the other receiver fields
+// can only be set on a method. Metadata values may not contain ',', '{' or
+// '}', and setting a custom metadata key to the empty string removes it.
+func (s *Symbol) Set(path, value string) error {
+	switch path {
+	case FieldPackagePath:
+		if value == "" {
+			return fmt.Errorf("invalid value for %s: empty string", path)
+		}
+		s.PackagePath = value
+	case FieldName:
+		if value == "" {
+			return fmt.Errorf("invalid value for %s: empty string", path)
+		}
+		s.Name = value
+	case FieldIsInit:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("invalid value for %s: %w", path, err)
+		}
+		s.IsInit = b
+	case FieldIsAnonymous:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("invalid value for %s: %w", path, err)
+		}
+		s.IsAnonymous = b
+	case FieldAnonParent:
+		s.AnonParent = value
+	case FieldAnonIndex:
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("invalid value for %s: %w", path, err)
+		}
+		if n < 0 {
+			return fmt.Errorf("invalid value for %s: negative index %d", path, n)
+		}
+		s.AnonIndex = n
+	case FieldTypeArgs:
+		s.TypeArgs = splitFieldList(value)
+	case FieldContext:
+		if strings.ContainsAny(value, "@{}") {
+			return fmt.Errorf("invalid value for %s: %q contains reserved characters", path, value)
+		}
+		s.Context = value
+	case FieldReceiverTypeName:
+		if value == "" {
+			return fmt.Errorf("invalid value for %s: empty string", path)
+		}
+		if s.Receiver == nil {
+			s.Receiver = &Receiver{}
+		}
+		s.Receiver.TypeName = value
+	case FieldReceiverIsPointer:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("invalid value for %s: %w", path, err)
+		}
+		if s.Receiver == nil {
+			return fmt.Errorf("invalid value for %s: symbol has no receiver", path)
+		}
+		s.Receiver.IsPointer = b
+	case FieldReceiverTypeArgs:
+		if s.Receiver == nil {
+			return fmt.Errorf("invalid value for %s: symbol has no receiver", path)
+		}
+		s.Receiver.TypeArgs = splitFieldList(value)
+	case FieldMetadataVia:
+		via := splitFieldList(value)
+		for _, v := range via {
+			if strings.ContainsAny(v, ",{}") {
+				return fmt.Errorf("invalid value for %s: reserved characters in %q", path, v)
+			}
+		}
+		s.Metadata.Via = via
+	case FieldMetadataAlias:
+		if strings.ContainsAny(value, ",{}") {
+			return fmt.Errorf("invalid value for %s: reserved characters in %q", path, value)
+		}
+		s.Metadata.Alias = value
+	case FieldMetadataPosition:
+		if strings.ContainsAny(value, ",{}") {
+			return fmt.Errorf("invalid value for %s: reserved characters in %q", path, value)
+		}
+		s.Metadata.Position = value
+	default:
+		key, ok := customKey(path)
+		if !ok {
+			return fmt.Errorf("unknown field path: %q", path)
+		}
+		if strings.ContainsAny(key, ":,{}") || strings.ContainsAny(value, ",{}") {
+			return fmt.Errorf("invalid value for %s: reserved characters in key or value", path)
+		}
+		if value == "" {
+			delete(s.Metadata.Custom, key)
+			return nil
+		}
+		if s.Metadata.Custom == nil {
+			s.Metadata.Custom = make(map[string]string)
+		}
+		s.Metadata.Custom[key] = value
+	}
+	return nil
+}
+
+// customKey extracts the key from a "metadata.custom.<key>" path.
+func customKey(path string) (string, bool) {
+	if !strings.HasPrefix(path, customFieldPrefix) {
+		return "", false
+	}
+	key := path[len(customFieldPrefix):]
+	return key, key != ""
+}
+
+// splitFieldList parses a comma-separated list value, nil for empty input.
+func splitFieldList(value string) []string {
+	if strings.TrimSpace(value) == "" {
+		return nil
+	}
+	return parseTypeArgs(value)
+}
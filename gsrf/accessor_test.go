@@ -0,0 +1,112 @@
+package gsrf
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSymbol_Get(t *testing.T) {
+	sym := MustParse("pkg.(*Cache[K, V]).Get[string]@linux{via:Base,pos:cache.go:1:1,owner:core}")
+
+	tests := []struct {
+		path    string
+		want    string
+		wantErr bool
+	}{
+		{path: "packagePath", want: "pkg"},
+		{path: "name", want: "Get"},
+		{path: "receiver.typeName", want: "Cache"},
+		{path: "receiver.isPointer", want: "true"},
+		{path: "receiver.typeArgs", want: "K, V"},
+		{path: "typeArgs", want: "string"},
+		{path: "context", want: "linux"},
+		{path: "metadata.via", want: "Base"},
+		{path: "metadata.position", want: "cache.go:1:1"},
+		{path: "metadata.custom.owner", want: "core"},
+		{path: "metadata.custom.missing", want: ""},
+		{path: "anonIndex", want: "0"},
+		{path: "receiver.name", wantErr: true},
+		{path: "metadata.custom.", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			got, err := sym.Get(tt.path)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Get() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Get() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("function receiver", func(t *testing.T) {
+		got, err := MustParse("fmt.Println").Get("receiver.typeName")
+		if err != nil || got != "" {
+			t.Errorf("Get() = %q, %v; want empty", got, err)
+		}
+	})
+}
+
+func TestSymbol_Set(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		path    string
+		value   string
+		want    string
+		wantErr bool
+	}{
+		{name: "rename", input: "fmt.Println", path: "name", value: "Printf", want: "fmt.Printf"},
+		{name: "add receiver", input: "pkg.Run", path: "receiver.typeName", value: "Server", want: "pkg.(Server).Run"},
+		{name: "pointer receiver", input: "pkg.(Server).Run", path: "receiver.isPointer", value: "true", want: "pkg.(*Server).Run"},
+		{name: "type args", input: "pkg.Map", path: "typeArgs", value: "string, []int", want: "pkg.Map[string, []int]"},
+		{name: "clear type args", input: "pkg.Map[int]", path: "typeArgs", value: "", want: "pkg.Map"},
+		{name: "context", input: "pkg.Open", path: "context", value: "windows", want: "pkg.Open@windows"},
+		{name: "custom metadata", input: "pkg.F", path: "metadata.custom.team", value: "infra", want: "pkg.F{team:infra}"},
+		{name: "remove custom metadata", input: "pkg.F{team:infra}", path: "metadata.custom.team", value: "", want: "pkg.F"},
+		{name: "anon index", input: "main.main·lit", path: "anonIndex", value: "2", want: "main.main·lit2"},
+		{name: "empty name", input: "pkg.F", path: "name", value: "", wantErr: true},
+		{name: "bad bool", input: "pkg.F", path: "isInit", value: "yes", wantErr: true},
+		{name: "negative index", input: "pkg.F", path: "anonIndex", value: "-1", wantErr: true},
+		{name: "reserved context", input: "pkg.F", path: "context", value: "linux{x}", wantErr: true},
+		{name: "pointer without receiver", input: "pkg.F", path: "receiver.isPointer", value: "true", wantErr: true},
+		{name: "type args without receiver", input: "pkg.F", path: "receiver.typeArgs", value: "T", wantErr: true},
+		{name: "reserved via", input: "pkg.(*T).M", path: "metadata.via", value: "Embedded{x}", wantErr: true},
+		{name: "reserved alias", input: "pkg.F", path: "metadata.alias", value: "a,b", wantErr: true},
+		{name: "reserved position", input: "pkg.F", path: "metadata.position", value: "f.go:1}", wantErr: true},
+		{name: "unknown path", input: "pkg.F", path: "nope", value: "x", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sym := MustParse(tt.input)
+			err := sym.Set(tt.path, tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Set() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if got := sym.Format(); got != tt.want {
+				t.Errorf("Format() after Set() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSymbol_SetGetRoundTrip(t *testing.T) {
+	sym := &Symbol{PackagePath: "pkg", Name: "F", Receiver: &Receiver{TypeName: "Cache"}}
+	if err := sym.Set("receiver.typeArgs", "Map[K, V], T"); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"Map[K, V]", "T"}
+	if !reflect.DeepEqual(sym.Receiver.TypeArgs, want) {
+		t.Errorf("Receiver.TypeArgs = %v, want %v", sym.Receiver.TypeArgs, want)
+	}
+	got, _ := sym.Get("receiver.typeArgs")
+	if got != "Map[K, V], T" {
+		t.Errorf("Get() = %q", got)
+	}
+}
@@ -0,0 +1,191 @@
+package adapters
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/kis9a/gsrf"
+)
+
+// Feature is a part of a symbol that a format may be unable to carry.
+type Feature string
+
+const (
+	FeatureReceiverPointer  Feature = "receiver-pointer"   // (T) vs (*T)
+	FeatureReceiverTypeArgs Feature = "receiver-type-args" // (*List[T])
+	FeatureTypeArgs         Feature = "type-args"          // F[int]
+	FeatureTypeParams       Feature = "type-params"        // F[T any]
+	FeatureContext          Feature = "context"            // @linux
+	FeatureMetadata         Feature = "metadata"           // {via:...,alias:...}
+	FeaturePosition         Feature = "position"           // {pos:file:line:col}
+	FeatureAnonIndex        Feature = "anon-index"         // ·lit2
+)
+
+// Converter describes a registered symbol format. Either conversion
+// function may be nil when the format can only be read or only be written.
+type Converter struct {
+	Name    string
+	Aliases []string
+	From    func(string) (*gsrf.Symbol, error)
+	To      func(*gsrf.Symbol) string
+	Loses   []Feature // Features the format cannot represent
+}
+
+var (
+	registryMu sync.RWMutex
+	registry   = map[string]*Converter{}
+)
+
+func init() {
+	Register(Converter{
+		Name: "gsrf",
+		From: gsrf.Parse,
+		To:   func(s *gsrf.Symbol) string { return s.Format() },
+	})
+	Register(Converter{
+		Name:  "ssa",
+		From:  FromSSA,
+		To:    ToSSA,
+		Loses: []Feature{FeatureReceiverTypeArgs, FeatureTypeArgs, FeatureTypeParams, FeatureContext, FeatureMetadata},
+	})
+	Register(Converter{
+		Name:    "stacktrace",
+		Aliases: []string{"stack"},
+		From:    FromStackTrace,
+		To:      ToStackTrace,
+		Loses:   []Feature{FeatureReceiverPointer, FeatureTypeParams, FeatureContext, FeatureMetadata, FeaturePosition},
+	})
+	Register(Converter{
+		Name:    "linker",
+		Aliases: []string{"symtab"},
+		From:    gsrf.FromLinkerName,
+		To:      ToLinkerName,
+		Loses:   []Feature{FeatureTypeParams, FeatureContext, FeatureMetadata, FeaturePosition},
+	})
+	Register(Converter{
+		Name:  "pprof",
+		From:  gsrf.FromRuntimeName,
+		To:    ToPprof,
+		Loses: []Feature{FeatureReceiverTypeArgs, FeatureTypeArgs, FeatureTypeParams, FeatureContext, FeatureMetadata, FeaturePosition},
+	})
+	Register(Converter{
+		Name:  "doc",
+		To:    ToDocURL,
+		Loses: []Feature{FeatureReceiverPointer, FeatureReceiverTypeArgs, FeatureTypeArgs, FeatureTypeParams, FeatureContext, FeatureMetadata, FeaturePosition, FeatureAnonIndex},
+	})
+	Register(Converter{
+		Name:    "prom",
+		Aliases: []string{"prometheus"},
+		To:      ToPromLabel,
+		Loses:   []Feature{FeatureMetadata, FeaturePosition},
+	})
+	Register(Converter{
+		Name:  "perf",
+		From:  FromPerfFrame,
+		Loses: []Feature{FeatureReceiverPointer, FeatureTypeParams, FeatureContext, FeatureMetadata, FeaturePosition},
+	})
+}
+
+// Register adds a converter, replacing any converter with the same name or alias.
+func Register(c Converter) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	conv := c
+	registry[strings.ToLower(c.Name)] = &conv
+	for _, alias := range c.Aliases {
+		registry[strings.ToLower(alias)] = &conv
+	}
+}
+
+// Lookup returns the converter registered under a name or alias.
+func Lookup(name string) (*Converter, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	c, ok := registry[strings.ToLower(name)]
+	return c, ok
+}
+
+// Formats returns the names of all registered converters, sorted.
+func Formats() []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	seen := map[string]bool{}
+	var names []string
+	for _, c := range registry {
+		if !seen[c.Name] {
+			seen[c.Name] = true
+			names = append(names, c.Name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Plan describes how a conversion is routed and what it cannot preserve.
+type Plan struct {
+	Steps []string  // Formats visited in order, e.g. ssa -> gsrf -> stacktrace
+	Lost  []Feature // Features lost by any step, sorted
+}
+
+// String renders the plan's route, e.g. "ssa -> gsrf -> stacktrace".
+func (p *Plan) String() string {
+	return strings.Join(p.Steps, " -> ")
+}
+
+// PlanConversion routes a conversion between two registered formats through
+// the GSRF model and accumulates the fidelity loss of every step.
+func PlanConversion(from, to string) (*Plan, error) {
+	src, ok := Lookup(from)
+	if !ok {
+		return nil, fmt.Errorf("unknown input format: %s", from)
+	}
+	if src.From == nil {
+		return nil, fmt.Errorf("format %s cannot be read", src.Name)
+	}
+	dst, ok := Lookup(to)
+	if !ok {
+		return nil, fmt.Errorf("unknown output format: %s", to)
+	}
+	if dst.To == nil {
+		return nil, fmt.Errorf("format %s cannot be written", dst.Name)
+	}
+
+	steps := []string{src.Name}
+	if src.Name != "gsrf" {
+		steps = append(steps, "gsrf")
+	}
+	if dst.Name != "gsrf" {
+		steps = append(steps, dst.Name)
+	}
+
+	lost := map[Feature]bool{}
+	for _, f := range src.Loses {
+		lost[f] = true
+	}
+	for _, f := range dst.Loses {
+		lost[f] = true
+	}
+	plan := &Plan{Steps: steps}
+	for f := range lost {
+		plan.Lost = append(plan.Lost, f)
+	}
+	sort.Slice(plan.Lost, func(i, j int) bool { return plan.Lost[i] < plan.Lost[j] })
+	return plan, nil
+}
+
+// Convert converts input between any two registered formats via GSRF.
+func Convert(from, to, input string) (string, error) {
+	if _, err := PlanConversion(from, to); err != nil {
+		return "", err
+	}
+	src, _ := Lookup(from)
+	dst, _ := Lookup(to)
+
+	sym, err := src.From(input)
+	if err != nil {
+		return "", err
+	}
+	return dst.To(sym), nil
+}
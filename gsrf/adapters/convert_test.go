@@ -0,0 +1,69 @@
+package adapters
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/kis9a/gsrf"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPlanConversion(t *testing.T) {
+	tests := []struct {
+		name      string
+		from, to  string
+		wantSteps []string
+		wantLost  []Feature
+		wantErr   bool
+	}{
+		{name: "identity", from: "gsrf", to: "gsrf", wantSteps: []string{"gsrf"}},
+		{name: "from gsrf", from: "gsrf", to: "ssa", wantSteps: []string{"gsrf", "ssa"},
+			wantLost: []Feature{FeatureContext, FeatureMetadata, FeatureReceiverTypeArgs, FeatureTypeArgs, FeatureTypeParams}},
+		{name: "to gsrf", from: "stack", to: "gsrf", wantSteps: []string{"stacktrace", "gsrf"},
+			wantLost: []Feature{FeatureContext, FeatureMetadata, FeaturePosition, FeatureReceiverPointer, FeatureTypeParams}},
+		{name: "any to any", from: "ssa", to: "stacktrace", wantSteps: []string{"ssa", "gsrf", "stacktrace"},
+			wantLost: []Feature{FeatureContext, FeatureMetadata, FeaturePosition, FeatureReceiverPointer, FeatureReceiverTypeArgs, FeatureTypeArgs, FeatureTypeParams}},
+		{name: "unknown source", from: "dtrace", to: "gsrf", wantErr: true},
+		{name: "unknown target", from: "gsrf", to: "sentry", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plan, err := PlanConversion(tt.from, tt.to)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantSteps, plan.Steps)
+			assert.Equal(t, tt.wantLost, plan.Lost)
+		})
+	}
+}
+
+func TestConvert(t *testing.T) {
+	got, err := Convert("ssa", "stacktrace", "github.com/user/repo.(Server).Start")
+	require.NoError(t, err)
+	assert.Equal(t, "github.com/user/repo.(*Server).Start", got)
+
+	_, err = Convert("ssa", "gsrf", "")
+	assert.Error(t, err)
+}
+
+func TestRegister(t *testing.T) {
+	Register(Converter{
+		Name:    "upper",
+		Aliases: []string{"UP"},
+		To:      func(s *gsrf.Symbol) string { return strings.ToUpper(s.Format()) },
+		Loses:   []Feature{FeatureMetadata},
+	})
+
+	got, err := Convert("gsrf", "up", "fmt.Println{pos:a.go:1:1}")
+	require.NoError(t, err)
+	assert.Equal(t, "FMT.PRINTLN{POS:A.GO:1:1}", got)
+	assert.Contains(t, Formats(), "upper")
+
+	_, err = PlanConversion("upper", "gsrf")
+	assert.Error(t, err, "write-only format should not be readable")
+}
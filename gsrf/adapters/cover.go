@@ -0,0 +1,212 @@
+package adapters
+
+import (
+	"bufio"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/kis9a/gsrf"
+)
+
+// "github.com/org/app/server.go:12.34,15.2 3 0"
+var coverBlockPattern = regexp.MustCompile(`^(.+):(\d+)\.(\d+),(\d+)\.(\d+) (\d+) (\d+)$`)
+
+// CoverBlock is one basic block of a Go coverage profile.
+type CoverBlock struct {
+	File      string // Import path of the package, a slash, and the file name
+	StartLine int
+	StartCol  int
+	EndLine   int
+	EndCol    int
+	NumStmt   int
+	Count     int
+}
+
+// Position returns the block's start as "file.go:line:col", the form of
+// GSRF pos metadata.
+func (b CoverBlock) Position() string {
+	return fmt.Sprintf("%s:%d:%d", path.Base(b.File), b.StartLine, b.StartCol)
+}
+
+// ReadCoverProfile reads a profile written by "go test -coverprofile". Blocks
+// reported more than once, as happens when several test binaries cover the
+// same package, are merged by adding their counts.
+func ReadCoverProfile(r io.Reader) ([]CoverBlock, error) {
+	var blocks []CoverBlock
+	index := make(map[string]int)
+	scanner := bufio.NewScanner(r)
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "mode:") {
+			continue
+		}
+		m := coverBlockPattern.FindStringSubmatch(line)
+		if m == nil {
+			return nil, fmt.Errorf("cover profile: line %d: unrecognized block %q", lineNo, line)
+		}
+		var n [6]int
+		for i := range n {
+			n[i], _ = strconv.Atoi(m[i+2])
+		}
+		b := CoverBlock{File: m[1], StartLine: n[0], StartCol: n[1], EndLine: n[2], EndCol: n[3], NumStmt: n[4], Count: n[5]}
+		key := line[:strings.LastIndexByte(line, ' ')]
+		if i, ok := index[key]; ok {
+			blocks[i].Count += b.Count
+			continue
+		}
+		index[key] = len(blocks)
+		blocks = append(blocks, b)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return blocks, nil
+}
+
+// SymbolCoverage is the coverage of one function or method.
+type SymbolCoverage struct {
+	Symbol     *gsrf.Symbol // With pos metadata for the declaration
+	Statements int
+	Covered    int
+	Uncovered  []CoverBlock // Blocks never executed, in source order
+}
+
+// Percent returns the share of covered statements, or 100 for a function
+// without statements.
+func (c *SymbolCoverage) Percent() float64 {
+	if c.Statements == 0 {
+		return 100
+	}
+	return 100 * float64(c.Covered) / float64(c.Statements)
+}
+
+// UncoveredStatements returns the number of statements never executed.
+func (c *SymbolCoverage) UncoveredStatements() int {
+	return c.Statements - c.Covered
+}
+
+// CoverageBySymbol attributes the blocks of a coverage profile to the
+// functions and methods declaring them. readFile returns the source of a
+// profile file name, such as "github.com/org/app/server.go". Blocks inside
+// function literals count toward the enclosing declaration. Results are in
+// file, then source order.
+func CoverageBySymbol(blocks []CoverBlock, readFile func(name string) ([]byte, error)) ([]*SymbolCoverage, error) {
+	byFile := make(map[string][]CoverBlock)
+	var files []string
+	for _, b := range blocks {
+		if _, ok := byFile[b.File]; !ok {
+			files = append(files, b.File)
+		}
+		byFile[b.File] = append(byFile[b.File], b)
+	}
+	sort.Strings(files)
+
+	var result []*SymbolCoverage
+	for _, name := range files {
+		src, err := readFile(name)
+		if err != nil {
+			return nil, err
+		}
+		funcs, err := fileFuncs(name, src)
+		if err != nil {
+			return nil, err
+		}
+		for _, b := range byFile[name] {
+			for _, f := range funcs {
+				if b.StartLine < f.start || b.StartLine > f.end {
+					continue
+				}
+				f.cov.Statements += b.NumStmt
+				if b.Count > 0 {
+					f.cov.Covered += b.NumStmt
+				} else {
+					f.cov.Uncovered = append(f.cov.Uncovered, b)
+				}
+				break
+			}
+		}
+		for _, f := range funcs {
+			sort.Slice(f.cov.Uncovered, func(i, j int) bool {
+				a, b := f.cov.Uncovered[i], f.cov.Uncovered[j]
+				return a.StartLine < b.StartLine || (a.StartLine == b.StartLine && a.StartCol < b.StartCol)
+			})
+			result = append(result, f.cov)
+		}
+	}
+	return result, nil
+}
+
+type funcExtent struct {
+	start, end int // Lines
+	cov        *SymbolCoverage
+}
+
+// fileFuncs parses a Go file and returns its function declarations.
+func fileFuncs(name string, src []byte) ([]*funcExtent, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, name, src, parser.SkipObjectResolution)
+	if err != nil {
+		return nil, err
+	}
+	pkg := path.Dir(name)
+
+	var funcs []*funcExtent
+	for _, decl := range file.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok || fn.Body == nil {
+			continue
+		}
+		sym := &gsrf.Symbol{PackagePath: pkg, Name: fn.Name.Name}
+		if fn.Recv != nil && len(fn.Recv.List) > 0 {
+			sym.Receiver = receiverOf(fn.Recv.List[0].Type)
+		} else if fn.Name.Name == "init" {
+			sym.IsInit = true
+		}
+		pos := fset.Position(fn.Pos())
+		sym.Metadata.Position = fmt.Sprintf("%s:%d:%d", path.Base(name), pos.Line, pos.Column)
+		funcs = append(funcs, &funcExtent{
+			start: pos.Line,
+			end:   fset.Position(fn.End()).Line,
+			cov:   &SymbolCoverage{Symbol: sym},
+		})
+	}
+	return funcs, nil
+}
+
+// receiverOf converts a receiver type expression such as *List[T].
+func receiverOf(expr ast.Expr) *gsrf.Receiver {
+	r := &gsrf.Receiver{}
+	if star, ok := expr.(*ast.StarExpr); ok {
+		r.IsPointer = true
+		expr = star.X
+	}
+	switch t := expr.(type) {
+	case *ast.IndexExpr:
+		expr = t.X
+		r.TypeArgs = []string{exprName(t.Index)}
+	case *ast.IndexListExpr:
+		expr = t.X
+		for _, idx := range t.Indices {
+			r.TypeArgs = append(r.TypeArgs, exprName(idx))
+		}
+	}
+	r.TypeName = exprName(expr)
+	return r
+}
+
+func exprName(expr ast.Expr) string {
+	if id, ok := expr.(*ast.Ident); ok {
+		return id.Name
+	}
+	return fmt.Sprintf("%v", expr)
+}
@@ -0,0 +1,94 @@
+package adapters
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const coverSource = `package app
+
+type List[T any] struct{ items []T }
+
+func (l *List[T]) Push(v T) {
+	l.items = append(l.items, v)
+}
+
+func Run(n int) int {
+	if n > 0 {
+		n++
+		n++
+	}
+	f := func() int {
+		return n
+	}
+	return f()
+}
+
+func init() {}
+`
+
+const coverProfile = `mode: count
+example.com/app/app.go:5.29,7.2 1 3
+example.com/app/app.go:9.22,10.11 1 2
+example.com/app/app.go:10.11,13.3 2 0
+example.com/app/app.go:14.2,14.17 1 2
+example.com/app/app.go:14.17,16.3 1 0
+example.com/app/app.go:17.2,17.12 1 2
+example.com/app/app.go:9.22,10.11 1 1
+`
+
+func TestReadCoverProfile(t *testing.T) {
+	blocks, err := ReadCoverProfile(strings.NewReader(coverProfile))
+	require.NoError(t, err)
+	require.Len(t, blocks, 6)
+
+	assert.Equal(t, CoverBlock{File: "example.com/app/app.go", StartLine: 9, StartCol: 22, EndLine: 10, EndCol: 11, NumStmt: 1, Count: 3}, blocks[1])
+	assert.Equal(t, "app.go:10:11", blocks[2].Position())
+
+	_, err = ReadCoverProfile(strings.NewReader("mode: set\nnot a block\n"))
+	assert.ErrorContains(t, err, "line 2")
+}
+
+func TestCoverageBySymbol(t *testing.T) {
+	blocks, err := ReadCoverProfile(strings.NewReader(coverProfile))
+	require.NoError(t, err)
+
+	covs, err := CoverageBySymbol(blocks, func(name string) ([]byte, error) {
+		if name != "example.com/app/app.go" {
+			return nil, fmt.Errorf("unexpected file %s", name)
+		}
+		return []byte(coverSource), nil
+	})
+	require.NoError(t, err)
+	require.Len(t, covs, 3)
+
+	push := covs[0]
+	assert.Equal(t, "example.com/app.(*List[T]).Push{pos:app.go:5:1}", push.Symbol.String())
+	assert.Equal(t, 100.0, push.Percent())
+
+	run := covs[1]
+	assert.Equal(t, "example.com/app.Run{pos:app.go:9:1}", run.Symbol.String())
+	assert.Equal(t, 6, run.Statements)
+	assert.Equal(t, 3, run.UncoveredStatements())
+	assert.Equal(t, 50.0, run.Percent())
+	require.Len(t, run.Uncovered, 2)
+	assert.Equal(t, "app.go:10:11", run.Uncovered[0].Position())
+	assert.Equal(t, "app.go:14:17", run.Uncovered[1].Position())
+
+	init := covs[2]
+	assert.True(t, init.Symbol.IsInit)
+	assert.Zero(t, init.Statements)
+	assert.Equal(t, 100.0, init.Percent())
+}
+
+func TestCoverageBySymbolReadError(t *testing.T) {
+	blocks := []CoverBlock{{File: "example.com/app/gone.go", StartLine: 1, EndLine: 1, NumStmt: 1}}
+	_, err := CoverageBySymbol(blocks, func(string) ([]byte, error) {
+		return nil, fmt.Errorf("missing")
+	})
+	assert.Error(t, err)
+}
@@ -0,0 +1,77 @@
+package adapters
+
+import (
+	"fmt"
+	"runtime"
+	"strconv"
+
+	"github.com/kis9a/gsrf"
+)
+
+// FrameOptions controls FromFrameWith, FromCallersWith and FromPCWith.
+type FrameOptions struct {
+	PathStyle gsrf.PathStyle // Separators of the position metadata
+}
+
+// FromFrame converts a frame of runtime.CallersFrames to a symbol, with the
+// frame's file and line as position metadata ("/src/app/server.go:42").
+// Functions are named as by gsrf.FromLinkerName, so closures, method value
+// wrappers and C functions convert too. It returns nil for a frame without a
+// function name.
+func FromFrame(frame runtime.Frame) *gsrf.Symbol {
+	return FromFrameWith(frame, FrameOptions{})
+}
+
+// FromFrameWith is like FromFrame, with the position written per opts.
+func FromFrameWith(frame runtime.Frame, opts FrameOptions) *gsrf.Symbol {
+	if frame.Function == "" {
+		return nil
+	}
+	sym, err := gsrf.FromLinkerName(frame.Function)
+	if err != nil {
+		return nil
+	}
+	if frame.File != "" {
+		sym.Metadata.Position = opts.PathStyle.Apply(frame.File) + ":" + strconv.Itoa(frame.Line)
+	}
+	return sym
+}
+
+// FromCallers converts the program counters of runtime.Callers to symbols,
+// innermost first, expanding inlined calls as runtime.CallersFrames does.
+// Frames without a function name are skipped.
+func FromCallers(pcs []uintptr) []*gsrf.Symbol {
+	return FromCallersWith(pcs, FrameOptions{})
+}
+
+// FromCallersWith is like FromCallers, with positions written per opts.
+func FromCallersWith(pcs []uintptr, opts FrameOptions) []*gsrf.Symbol {
+	var syms []*gsrf.Symbol
+	frames := runtime.CallersFrames(pcs)
+	for {
+		frame, more := frames.Next()
+		if sym := FromFrameWith(frame, opts); sym != nil {
+			syms = append(syms, sym)
+		}
+		if !more {
+			return syms
+		}
+	}
+}
+
+// FromPC resolves a program counter to a symbol with its file:line
+// position, for in-process symbolication such as custom profilers. Like
+// the entries of runtime.Callers, pc is taken to be a return address; if
+// the call was inlined, the innermost inlined function is returned.
+func FromPC(pc uintptr) (*gsrf.Symbol, error) {
+	return FromPCWith(pc, FrameOptions{})
+}
+
+// FromPCWith is like FromPC, with the position written per opts.
+func FromPCWith(pc uintptr, opts FrameOptions) (*gsrf.Symbol, error) {
+	frame, _ := runtime.CallersFrames([]uintptr{pc}).Next()
+	if sym := FromFrameWith(frame, opts); sym != nil {
+		return sym, nil
+	}
+	return nil, fmt.Errorf("no function at pc %#x", pc)
+}
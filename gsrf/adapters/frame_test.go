@@ -0,0 +1,69 @@
+package adapters
+
+import (
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/kis9a/gsrf"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type frameRecorder struct{}
+
+//go:noinline
+func (frameRecorder) record() []*gsrf.Symbol {
+	pcs := make([]uintptr, 8)
+	return FromCallers(pcs[:runtime.Callers(1, pcs)])
+}
+
+func TestFromCallers(t *testing.T) {
+	var syms []*gsrf.Symbol
+	func() {
+		syms = frameRecorder{}.record()
+	}()
+	require.GreaterOrEqual(t, len(syms), 3)
+
+	assert.Equal(t, "github.com/kis9a/gsrf/adapters.(frameRecorder).record", syms[0].WithoutMetadata().String())
+	assert.True(t, syms[1].IsAnonymous)
+	assert.Equal(t, "TestFromCallers", syms[1].Name)
+	assert.Equal(t, "github.com/kis9a/gsrf/adapters.TestFromCallers", syms[2].WithoutMetadata().String())
+	for _, sym := range syms[:3] {
+		file, line, ok := strings.Cut(sym.Metadata.Position, "frame_test.go:")
+		assert.True(t, ok && strings.HasSuffix(file, "/") && line != "0", "position %q", sym.Metadata.Position)
+	}
+}
+
+func TestFromFrame(t *testing.T) {
+	sym := FromFrame(runtime.Frame{Function: "net/http.(*Server).Serve", File: "/go/src/net/http/server.go", Line: 3285})
+	require.NotNil(t, sym)
+	assert.Equal(t, "net/http.(*Server).Serve{pos:/go/src/net/http/server.go:3285}", sym.String())
+
+	sym = FromFrame(runtime.Frame{Function: "main.main.func1"})
+	require.NotNil(t, sym)
+	assert.Equal(t, "main.main·lit1", sym.String())
+
+	assert.Nil(t, FromFrame(runtime.Frame{}))
+
+	sym = FromFrameWith(runtime.Frame{Function: "main.main", File: `C:\src\app\main.go`, Line: 7}, FrameOptions{PathStyle: gsrf.PathSlash})
+	require.NotNil(t, sym)
+	assert.Equal(t, "C:/src/app/main.go:7", sym.Metadata.Position)
+}
+
+func TestFromPC(t *testing.T) {
+	pcs := make([]uintptr, 1)
+	require.Equal(t, 1, runtime.Callers(1, pcs))
+	sym, err := FromPC(pcs[0])
+	require.NoError(t, err)
+	assert.Equal(t, "github.com/kis9a/gsrf/adapters.TestFromPC", sym.WithoutMetadata().String())
+	assert.Contains(t, sym.Metadata.Position, "frame_test.go:")
+
+	_, err = FromPC(0)
+	assert.Error(t, err)
+
+	sym, err = FromPCWith(pcs[0], FrameOptions{PathStyle: gsrf.PathSlash})
+	require.NoError(t, err)
+	assert.NotContains(t, sym.Metadata.Position, `\`)
+	assert.NotEmpty(t, FromCallersWith(pcs, FrameOptions{PathStyle: gsrf.PathSlash}))
+}
@@ -0,0 +1,170 @@
+package adapters
+
+import (
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/kis9a/gsrf"
+)
+
+var (
+	goroutineHeaderPattern = regexp.MustCompile(`^goroutine (\d+)(?: [^\[]*)?\[([^\]]*)\]:?$`)
+	ancestorHeaderPattern  = regexp.MustCompile(`^\[originating from goroutine (\d+)\]:?$`)
+	createdByPattern       = regexp.MustCompile(`^created by (\S+)(?: in goroutine (\d+))?$`)
+)
+
+// Goroutine is one goroutine of a runtime stack dump.
+type Goroutine struct {
+	ID        int
+	State     string       // e.g. "running" or "chan receive, 2 minutes"; empty for ancestors
+	Trace     *gsrf.Trace  // Stack, innermost first
+	CreatedBy *gsrf.Symbol // Function containing the go statement; nil if not reported
+	CreatorID int          // Goroutine that ran the go statement (Go 1.21+); 0 if not reported
+	Ancestors []*Goroutine // Creator stacks recorded with GODEBUG=tracebackancestors=N, nearest first
+}
+
+// GoroutinesFromStackTrace splits a runtime stack dump, such as the output
+// of a panic with GOTRACEBACK=all or of debug.Stack(true), into goroutines.
+// "created by" lines and "[originating from goroutine N]" ancestor sections
+// are attached to the goroutine they follow.
+func GoroutinesFromStackTrace(lines []string) []*Goroutine {
+	var (
+		goroutines []*Goroutine
+		current    *Goroutine // Goroutine or ancestor receiving frames
+		frames     []string
+	)
+	flush := func() {
+		if current != nil {
+			current.Trace = gsrf.ParseTrace(frames, FromStackTrace)
+		}
+		frames = nil
+	}
+
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+		if line == "" || isLocationLine(line) {
+			continue
+		}
+		if m := goroutineHeaderPattern.FindStringSubmatch(line); m != nil {
+			flush()
+			id, _ := strconv.Atoi(m[1])
+			current = &Goroutine{ID: id, State: m[2]}
+			goroutines = append(goroutines, current)
+			continue
+		}
+		if current == nil {
+			continue
+		}
+		if m := ancestorHeaderPattern.FindStringSubmatch(line); m != nil {
+			flush()
+			id, _ := strconv.Atoi(m[1])
+			g := goroutines[len(goroutines)-1]
+			current = &Goroutine{ID: id}
+			g.Ancestors = append(g.Ancestors, current)
+			continue
+		}
+		if m := createdByPattern.FindStringSubmatch(line); m != nil {
+			if sym, err := FromStackTrace(m[1]); err == nil {
+				current.CreatedBy = sym
+			}
+			current.CreatorID, _ = strconv.Atoi(m[2])
+			continue
+		}
+		frames = append(frames, stripCallArgs(line))
+	}
+	flush()
+	return goroutines
+}
+
+// GoroutineTree groups goroutines by the chain of functions that created
+// them. The root has no Creator; each child is one creator function below
+// its parent's, so the path from the root to a node is a creation chain,
+// outermost first.
+type GoroutineTree struct {
+	Creator    *gsrf.Symbol
+	Goroutines []*Goroutine // Goroutines whose creation chain ends at this node
+	Children   []*GoroutineTree
+}
+
+// BuildGoroutineTree arranges goroutines by creation chain. A goroutine's
+// chain follows its recorded ancestors, then creator goroutines found in
+// gs by CreatorID. Children are ordered by Count, largest first, so the
+// creator that spawned the most goroutines comes first.
+func BuildGoroutineTree(gs []*Goroutine) *GoroutineTree {
+	byID := make(map[int]*Goroutine, len(gs))
+	for _, g := range gs {
+		byID[g.ID] = g
+	}
+
+	root := &GoroutineTree{}
+	index := map[*GoroutineTree]map[gsrf.SymbolKey]*GoroutineTree{}
+	for _, g := range gs {
+		chain := creationChain(g, byID)
+		node := root
+		for i := len(chain) - 1; i >= 0; i-- {
+			children := index[node]
+			if children == nil {
+				children = make(map[gsrf.SymbolKey]*GoroutineTree)
+				index[node] = children
+			}
+			key := chain[i].Key()
+			child, ok := children[key]
+			if !ok {
+				child = &GoroutineTree{Creator: chain[i]}
+				children[key] = child
+				node.Children = append(node.Children, child)
+			}
+			node = child
+		}
+		node.Goroutines = append(node.Goroutines, g)
+	}
+	root.sort()
+	return root
+}
+
+// creationChain returns the creators of g, innermost first.
+func creationChain(g *Goroutine, byID map[int]*Goroutine) []*gsrf.Symbol {
+	var chain []*gsrf.Symbol
+	seen := map[int]bool{g.ID: true}
+	cur := g
+	for next := 0; cur.CreatedBy != nil; {
+		chain = append(chain, cur.CreatedBy)
+		var parent *Goroutine
+		if next < len(g.Ancestors) {
+			parent = g.Ancestors[next]
+			next++
+		} else if p, ok := byID[cur.CreatorID]; ok && cur.CreatorID != 0 && !seen[p.ID] {
+			parent = p
+		}
+		if parent == nil {
+			break
+		}
+		seen[parent.ID] = true
+		cur = parent
+	}
+	return chain
+}
+
+// Count returns the number of goroutines in the subtree.
+func (t *GoroutineTree) Count() int {
+	n := len(t.Goroutines)
+	for _, c := range t.Children {
+		n += c.Count()
+	}
+	return n
+}
+
+func (t *GoroutineTree) sort() {
+	for _, c := range t.Children {
+		c.sort()
+	}
+	sort.SliceStable(t.Children, func(i, j int) bool {
+		ci, cj := t.Children[i].Count(), t.Children[j].Count()
+		if ci != cj {
+			return ci > cj
+		}
+		return t.Children[i].Creator.String() < t.Children[j].Creator.String()
+	})
+}
@@ -0,0 +1,88 @@
+package adapters
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const goroutineDump = `goroutine 1 [running]:
+main.main()
+	/app/main.go:12 +0x3a
+
+goroutine 7 [select]:
+main.(*Pool).dispatch(0xc000010000)
+	/app/pool.go:30 +0x1d
+created by main.NewPool in goroutine 1
+	/app/pool.go:12 +0x25
+
+goroutine 21 [chan receive, 2 minutes]:
+main.(*Pool).work(0xc000010000)
+	/app/pool.go:40 +0x1d
+created by main.(*Pool).dispatch in goroutine 7
+	/app/pool.go:33 +0x25
+
+goroutine 22 [chan receive, 2 minutes]:
+main.(*Pool).work(0xc000010000)
+	/app/pool.go:40 +0x1d
+created by main.(*Pool).dispatch in goroutine 7
+	/app/pool.go:33 +0x25
+
+goroutine 30 [IO wait]:
+net/http.(*conn).serve(0xc000020000, {0x1, 0x2})
+	/usr/local/go/src/net/http/server.go:2000 +0x1d
+created by net/http.(*Server).Serve in goroutine 5
+	/usr/local/go/src/net/http/server.go:3000 +0x25
+[originating from goroutine 5]:
+net/http.(*Server).Serve(0xc000030000)
+	/usr/local/go/src/net/http/server.go:3000 +0x25
+created by main.startHTTP in goroutine 1
+	/app/http.go:9 +0x25
+`
+
+func TestGoroutinesFromStackTrace(t *testing.T) {
+	gs := GoroutinesFromStackTrace(strings.Split(goroutineDump, "\n"))
+	require.Len(t, gs, 5)
+
+	assert.Equal(t, 1, gs[0].ID)
+	assert.Nil(t, gs[0].CreatedBy)
+	assert.Equal(t, []string{"main.main"}, gs[0].Trace.Lines())
+
+	assert.Equal(t, 21, gs[2].ID)
+	assert.Equal(t, "chan receive, 2 minutes", gs[2].State)
+	assert.Equal(t, "main.(*Pool).dispatch", gs[2].CreatedBy.String())
+	assert.Equal(t, 7, gs[2].CreatorID)
+	assert.Equal(t, []string{"main.(*Pool).work"}, gs[2].Trace.Lines())
+
+	require.Len(t, gs[4].Ancestors, 1)
+	anc := gs[4].Ancestors[0]
+	assert.Equal(t, 5, anc.ID)
+	assert.Equal(t, "main.startHTTP", anc.CreatedBy.String())
+	assert.Equal(t, []string{"net/http.(*Server).Serve"}, anc.Trace.Lines())
+	assert.Equal(t, []string{"net/http.(*conn).serve"}, gs[4].Trace.Lines())
+}
+
+func TestBuildGoroutineTree(t *testing.T) {
+	tree := BuildGoroutineTree(GoroutinesFromStackTrace(strings.Split(goroutineDump, "\n")))
+	assert.Equal(t, 5, tree.Count())
+	require.Len(t, tree.Goroutines, 1) // goroutine 1 has no creator
+
+	// main.NewPool -> main.(*Pool).dispatch holds the two workers.
+	require.Len(t, tree.Children, 2)
+	pool := tree.Children[0]
+	assert.Equal(t, "main.NewPool", pool.Creator.String())
+	assert.Equal(t, 3, pool.Count())
+	require.Len(t, pool.Children, 1)
+	assert.Equal(t, "main.(*Pool).dispatch", pool.Children[0].Creator.String())
+	assert.Len(t, pool.Children[0].Goroutines, 2)
+
+	// The ancestor section links the connection to main.startHTTP even
+	// though goroutine 5 is not in the dump.
+	http := tree.Children[1]
+	assert.Equal(t, "main.startHTTP", http.Creator.String())
+	require.Len(t, http.Children, 1)
+	assert.Equal(t, "net/http.(*Server).Serve", http.Children[0].Creator.String())
+	assert.Equal(t, 30, http.Children[0].Goroutines[0].ID)
+}
@@ -0,0 +1,110 @@
+package adapters
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/kis9a/gsrf"
+)
+
+// ToLinkerName converts GSRF to the name of the function in a Go binary's
+// symbol table, such as "main.(*Server).handle.func1" or
+// "runtime.memmove.abi0" for an assembly function. Symbols that record their
+// original name in "raw" metadata, like C functions, return it unchanged.
+func ToLinkerName(sym *gsrf.Symbol) string {
+	name := runtimeName(sym, sym.TypeArgs, receiverTypeArgs(sym))
+	if sym.Context == gsrf.ContextAsm && sym.RawName() == "" {
+		name += ".abi0"
+	}
+	return name
+}
+
+// ToPprof converts GSRF to a function name as it appears in pprof profiles
+// and runtime.Frame.Function: like the linker name, but with generic
+// instantiations shown as "[...]" and no ABI suffix.
+func ToPprof(sym *gsrf.Symbol) string {
+	return runtimeName(sym, collapseTypeArgs(sym.TypeArgs), collapseTypeArgs(receiverTypeArgs(sym)))
+}
+
+// runtimeName writes sym the way the runtime names functions, with the given
+// type arguments for the function and its receiver.
+func runtimeName(sym *gsrf.Symbol, typeArgs, recvTypeArgs []string) string {
+	if raw := sym.RawName(); raw != "" {
+		return raw
+	}
+	if sym.IsForeign() {
+		return sym.Name
+	}
+
+	var b strings.Builder
+	b.WriteString(escapeLinkerPath(sym.PackagePath))
+	b.WriteByte('.')
+	switch {
+	case sym.IsAnonymous:
+		// The name of an anonymous function in a method includes its receiver.
+		b.WriteString(sym.Name)
+		b.WriteString(".func")
+		b.WriteString(strconv.Itoa(max(sym.AnonIndex, 1)))
+	case sym.Receiver != nil:
+		if sym.Receiver.IsPointer {
+			b.WriteString("(*")
+		}
+		b.WriteString(sym.Receiver.TypeName)
+		writeLinkerTypeArgs(&b, recvTypeArgs)
+		if sym.Receiver.IsPointer {
+			b.WriteByte(')')
+		}
+		b.WriteByte('.')
+		b.WriteString(sym.Name)
+	default:
+		b.WriteString(sym.Name)
+		writeLinkerTypeArgs(&b, typeArgs)
+	}
+	return b.String()
+}
+
+func receiverTypeArgs(sym *gsrf.Symbol) []string {
+	if sym.Receiver == nil {
+		return nil
+	}
+	return sym.Receiver.TypeArgs
+}
+
+// collapseTypeArgs replaces type arguments by the runtime's "...".
+func collapseTypeArgs(args []string) []string {
+	if len(args) == 0 {
+		return nil
+	}
+	return []string{"..."}
+}
+
+// escapeLinkerPath escapes dots in the last element of a package path, as
+// the linker does: "gopkg.in/yaml.v3" becomes "gopkg.in/yaml%2ev3".
+func escapeLinkerPath(path string) string {
+	slash := strings.LastIndex(path, "/")
+	return path[:slash+1] + strings.ReplaceAll(path[slash+1:], ".", "%2e")
+}
+
+// writeLinkerTypeArgs writes type arguments the way the linker does, with
+// no space after commas.
+func writeLinkerTypeArgs(b *strings.Builder, args []string) {
+	if len(args) == 0 {
+		return
+	}
+	b.WriteByte('[')
+	b.WriteString(strings.Join(args, ","))
+	b.WriteByte(']')
+}
+
+// Symbols converts every function in the table to a symbol, in address
+// order. Entries that are not Go functions, such as C functions or
+// assembly stubs, follow the conventions of gsrf.FromLinkerName.
+func (t *SymbolTable) Symbols() []*gsrf.Symbol {
+	syms := make([]*gsrf.Symbol, 0, len(t.funcs))
+	for _, f := range t.funcs {
+		if sym, err := gsrf.FromLinkerName(f.name); err == nil {
+			syms = append(syms, sym)
+		}
+	}
+	return syms
+}
@@ -0,0 +1,50 @@
+package adapters
+
+import (
+	"testing"
+
+	"github.com/kis9a/gsrf"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestToLinkerName(t *testing.T) {
+	names := []string{
+		"main.(*Server).handle",
+		"main.Server.String",
+		"main.(*Server).handle.func2",
+		"main.run.func1",
+		"gopkg.in/yaml%2ev3.Marshal",
+		"slices.Sort[go.shape.int]",
+		"main.(*List[go.shape.string]).Push",
+		"runtime.memmove.abi0",
+		"_cgoexp_1a2b3c_Hello",
+		"crosscall2",
+		"__libc_start_main@GLIBC_2.34",
+	}
+	for _, name := range names {
+		t.Run(name, func(t *testing.T) {
+			sym, err := gsrf.FromLinkerName(name)
+			require.NoError(t, err)
+			assert.Equal(t, name, ToLinkerName(sym))
+		})
+	}
+}
+
+func TestSymbolTable_Symbols(t *testing.T) {
+	table := NewSymbolTable(
+		map[uint64]string{0x1000: "main.main", 0x2000: "runtime.memmove.abi0", 0x3000: "__libc_write"},
+		nil,
+	)
+	var got []string
+	for _, sym := range table.Symbols() {
+		got = append(got, sym.String())
+	}
+	assert.Equal(t, []string{"main.main", "runtime.memmove@asm", "C.__libc_write"}, got)
+}
+
+func TestConvert_Linker(t *testing.T) {
+	out, err := Convert("symtab", "stacktrace", "main.(*Server).handle.func1")
+	require.NoError(t, err)
+	assert.Equal(t, "main.(*Server).handle.func1", out)
+}
@@ -0,0 +1,198 @@
+package adapters
+
+import (
+	"bufio"
+	"debug/elf"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/kis9a/gsrf"
+)
+
+var (
+	// "prog 1234/1235 12345.678901:     250000 cpu-clock:uhH:"
+	perfHeaderPattern = regexp.MustCompile(`^(\S.*?)\s+(\d+)(?:/(\d+))?\s+(?:\[\d+\]\s+)?[\d.]+:\s+(?:\d+\s+)?([^\s:]+(?::[^\s:]*)?):`)
+	// "4a5b3c main.(*Server).handle+0x1c (/srv/prog)"
+	perfFramePattern = regexp.MustCompile(`^([0-9a-fA-F]+)\s+(.*?)(?:\+0x[0-9a-fA-F]+)?(?:\s+\(([^()]*)\))?$`)
+)
+
+// PerfSample is one sample of "perf script" output.
+type PerfSample struct {
+	Command string
+	PID     int
+	TID     int
+	Event   string      // e.g. "cpu-clock:uhH"
+	Trace   *gsrf.Trace // Innermost first; kernel and C frames are unknown frames
+}
+
+// FromPerfFrame converts one "perf script" stack line, such as
+// "4a5b3c main.(*Server).handle+0x1c (/srv/prog)", to GSRF. The address,
+// offset, DSO, and any ABI suffix (".abi0") are dropped. Frames that are not
+// Go functions, like kernel or libc symbols, return an error.
+func FromPerfFrame(line string) (*gsrf.Symbol, error) {
+	_, name, _, ok := splitPerfFrame(line)
+	if !ok {
+		name = strings.TrimSpace(line)
+	}
+	return fromPerfName(name)
+}
+
+func fromPerfName(name string) (*gsrf.Symbol, error) {
+	name = strings.TrimSuffix(strings.TrimSuffix(name, ".abi0"), ".abiinternal")
+	if name == "" || name == "[unknown]" || !strings.Contains(name, ".") {
+		return nil, fmt.Errorf("not a Go function: %q", name)
+	}
+	return FromStackTrace(name)
+}
+
+// splitPerfFrame splits a stack line into its address, symbol name (offset
+// removed), and DSO.
+func splitPerfFrame(line string) (addr uint64, name, dso string, ok bool) {
+	m := perfFramePattern.FindStringSubmatch(strings.TrimSpace(line))
+	if m == nil {
+		return 0, "", "", false
+	}
+	addr, err := strconv.ParseUint(m[1], 16, 64)
+	if err != nil {
+		return 0, "", "", false
+	}
+	return addr, m[2], m[3], true
+}
+
+// ReadPerfScript reads the output of "perf script" (with call graphs, e.g.
+// from "perf record -g"). Go frames become symbols; kernel, C, and
+// unresolved frames are kept as unknown frames with their address removed.
+// If symtab is non-nil, frames whose name perf truncated or could not
+// resolve are repaired from the binary's symbol table by address.
+func ReadPerfScript(r io.Reader, symtab *SymbolTable) ([]PerfSample, error) {
+	var (
+		samples []PerfSample
+		current *PerfSample
+		frames  []string
+	)
+	flush := func() {
+		if current != nil {
+			current.Trace = gsrf.ParseTrace(frames, fromPerfName)
+			samples = append(samples, *current)
+		}
+		current, frames = nil, nil
+	}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line := scanner.Text()
+		if strings.TrimSpace(line) == "" {
+			flush()
+			continue
+		}
+		if line[0] != ' ' && line[0] != '\t' {
+			flush()
+			m := perfHeaderPattern.FindStringSubmatch(line)
+			if m == nil {
+				return nil, fmt.Errorf("perf script: line %d: unrecognized sample header %q", lineNo, line)
+			}
+			current = &PerfSample{Command: m[1], Event: m[4]}
+			current.PID, _ = strconv.Atoi(m[2])
+			current.TID, _ = strconv.Atoi(m[3])
+			continue
+		}
+		if current == nil {
+			return nil, fmt.Errorf("perf script: line %d: stack frame before sample header", lineNo)
+		}
+		addr, name, dso, ok := splitPerfFrame(line)
+		if !ok {
+			frames = append(frames, strings.TrimSpace(line))
+			continue
+		}
+		if symtab != nil {
+			name = symtab.Repair(addr, name)
+		}
+		if _, err := fromPerfName(name); err != nil && dso != "" {
+			name += " (" + dso + ")"
+		}
+		frames = append(frames, name)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	flush()
+	return samples, nil
+}
+
+// SymbolTable maps addresses to function names of one binary.
+type SymbolTable struct {
+	funcs []tableFunc // Sorted by address
+}
+
+type tableFunc struct {
+	addr, size uint64
+	name       string
+}
+
+// NewSymbolTable builds a table from function start addresses and sizes.
+func NewSymbolTable(names map[uint64]string, sizes map[uint64]uint64) *SymbolTable {
+	t := &SymbolTable{}
+	for addr, name := range names {
+		t.funcs = append(t.funcs, tableFunc{addr: addr, size: sizes[addr], name: name})
+	}
+	sort.Slice(t.funcs, func(i, j int) bool { return t.funcs[i].addr < t.funcs[j].addr })
+	return t
+}
+
+// ReadELFSymbols reads the function symbols of an ELF binary. Stripped
+// binaries have none and yield an empty table.
+func ReadELFSymbols(path string) (*SymbolTable, error) {
+	f, err := elf.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	syms, err := f.Symbols()
+	if err != nil && err != elf.ErrNoSymbols {
+		return nil, err
+	}
+	names := make(map[uint64]string)
+	sizes := make(map[uint64]uint64)
+	for _, s := range syms {
+		if elf.ST_TYPE(s.Info) == elf.STT_FUNC && s.Value != 0 {
+			names[s.Value] = s.Name
+			sizes[s.Value] = s.Size
+		}
+	}
+	return NewSymbolTable(names, sizes), nil
+}
+
+// Lookup returns the function containing addr.
+func (t *SymbolTable) Lookup(addr uint64) (string, bool) {
+	i := sort.Search(len(t.funcs), func(i int) bool { return t.funcs[i].addr > addr }) - 1
+	if i < 0 {
+		return "", false
+	}
+	f := t.funcs[i]
+	if f.size > 0 && addr >= f.addr+f.size {
+		return "", false
+	}
+	return f.name, true
+}
+
+// Repair returns the table's name for the function at addr when perf
+// reported it unresolved ("[unknown]" or empty) or truncated (a prefix of
+// the table's name). Otherwise name is returned unchanged.
+func (t *SymbolTable) Repair(addr uint64, name string) string {
+	full, ok := t.Lookup(addr)
+	if !ok {
+		return name
+	}
+	if name == "" || name == "[unknown]" || strings.HasPrefix(full, name) {
+		return full
+	}
+	return name
+}
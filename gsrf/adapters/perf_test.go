@@ -0,0 +1,94 @@
+package adapters
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const perfScript = `server 4211/4215 [003] 81234.567890:     250000 cpu-clock:uhH: 
+	ffffffff81a01234 do_syscall_64+0x5d ([kernel.kallsyms])
+	          4a5b3c main.(*Server).hand+0x1c (/srv/server)
+	          4a1000 [unknown] (/srv/server)
+	          46c2e1 runtime.goexit.abi0+0x1 (/srv/server)
+
+server 4211/4211 [001] 81234.568000:     250000 cpu-clock:uhH: 
+	          7f00aa __libc_write+0x10 (/usr/lib/libc.so.6)
+	          4a6000 main.main.func1+0x20 (/srv/server)
+`
+
+func TestFromPerfFrame(t *testing.T) {
+	sym, err := FromPerfFrame("4a5b3c main.(*Server).handle+0x1c (/srv/server)")
+	require.NoError(t, err)
+	assert.Equal(t, "main.(*Server).handle", sym.String())
+
+	sym, err = FromPerfFrame("46c2e1 runtime.goexit.abi0+0x1 (/srv/server)")
+	require.NoError(t, err)
+	assert.Equal(t, "runtime.goexit", sym.String())
+
+	_, err = FromPerfFrame("ffffffff81a01234 do_syscall_64+0x5d ([kernel.kallsyms])")
+	assert.Error(t, err)
+
+	out, err := Convert("perf", "gsrf", "4a5b3c main.(*Server).handle+0x1c (/srv/server)")
+	require.NoError(t, err)
+	assert.Equal(t, "main.(*Server).handle", out)
+}
+
+func TestReadPerfScript(t *testing.T) {
+	symtab := NewSymbolTable(
+		map[uint64]string{0x4a5b00: "main.(*Server).handle", 0x4a0f00: "main.(*Server).Serve"},
+		map[uint64]uint64{0x4a5b00: 0x100, 0x4a0f00: 0x200},
+	)
+	samples, err := ReadPerfScript(strings.NewReader(perfScript), symtab)
+	require.NoError(t, err)
+	require.Len(t, samples, 2)
+
+	s := samples[0]
+	assert.Equal(t, "server", s.Command)
+	assert.Equal(t, 4211, s.PID)
+	assert.Equal(t, 4215, s.TID)
+	assert.Equal(t, "cpu-clock:uhH", s.Event)
+	assert.Equal(t, []string{
+		"do_syscall_64 ([kernel.kallsyms])",
+		"main.(*Server).handle", // truncated by perf, repaired by address
+		"main.(*Server).Serve",  // unresolved by perf
+		"runtime.goexit",
+	}, s.Trace.Lines())
+	assert.Equal(t, 1, s.Trace.UnknownCount())
+
+	assert.Equal(t, []string{"__libc_write (/usr/lib/libc.so.6)", "main.main·lit"}, samples[1].Trace.Lines())
+
+	// Without a symbol table the truncated name is kept as perf printed it.
+	samples, err = ReadPerfScript(strings.NewReader(perfScript), nil)
+	require.NoError(t, err)
+	assert.Equal(t, "main.(*Server).hand", samples[0].Trace.Lines()[1])
+	assert.Equal(t, "[unknown] (/srv/server)", samples[0].Trace.Lines()[2])
+
+	_, err = ReadPerfScript(strings.NewReader("\tdeadbeef f+0x1 (x)\n"), nil)
+	assert.Error(t, err)
+}
+
+func TestReadELFSymbols(t *testing.T) {
+	exe, err := os.Executable()
+	require.NoError(t, err)
+	symtab, err := ReadELFSymbols(exe)
+	if err != nil {
+		t.Skipf("test binary is not ELF: %v", err)
+	}
+	if len(symtab.funcs) == 0 {
+		t.Skip("test binary has no symbol table")
+	}
+	found := false
+	for _, f := range symtab.funcs {
+		if f.name == "github.com/kis9a/gsrf/adapters.ReadELFSymbols" {
+			name, ok := symtab.Lookup(f.addr + 1)
+			assert.True(t, ok)
+			assert.Equal(t, f.name, name)
+			found = true
+		}
+	}
+	assert.True(t, found, "ReadELFSymbols did not find its own symbol")
+}
@@ -0,0 +1,153 @@
+package adapters
+
+import (
+	"bufio"
+	"encoding/binary"
+	"hash/fnv"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/kis9a/gsrf"
+)
+
+const (
+	raceWarning        = "WARNING: DATA RACE"
+	raceSeparator      = "=================="
+	raceLocationPrefix = "Location is "
+)
+
+var (
+	raceAccessPattern  = regexp.MustCompile(`(?i)^(previous )?((?:atomic )?(?:read|write))(?: of size \d+)? at (0x[0-9a-f]+) by (?:main goroutine|goroutine (\d+)):$`)
+	raceCreatedPattern = regexp.MustCompile(`^Goroutine (\d+) \([^)]*\) created at:$`)
+)
+
+// RaceAccess is one side of a data race: a memory access and its stack.
+type RaceAccess struct {
+	Op        string      // "read", "write", "atomic read" or "atomic write"
+	Addr      string      // e.g. "0x00c0000b4010"
+	Goroutine int         // Accessing goroutine; 1 is the main goroutine
+	Trace     *gsrf.Trace // Innermost first
+}
+
+// Symbol returns the innermost known frame of the access, or nil.
+func (a RaceAccess) Symbol() *gsrf.Symbol {
+	if a.Trace == nil {
+		return nil
+	}
+	for _, f := range a.Trace.Frames {
+		if f.Symbol != nil {
+			return f.Symbol
+		}
+	}
+	return nil
+}
+
+// RaceReport is one "WARNING: DATA RACE" report of the race detector.
+type RaceReport struct {
+	Access    RaceAccess          // The access that detected the race
+	Previous  RaceAccess          // The earlier conflicting access
+	Location  string              // e.g. "global 'hits' of size 8 at 0x..."; empty if not reported
+	CreatedAt map[int]*gsrf.Trace // Creation stacks of the goroutines involved, by ID
+}
+
+// Fingerprint identifies the race by the operations and innermost symbols
+// of both accesses, in either order, so repeated reports of the same race
+// group together however the racing code was reached.
+func (r *RaceReport) Fingerprint() uint64 {
+	sides := make([]string, 0, 2)
+	for _, a := range []RaceAccess{r.Access, r.Previous} {
+		side := a.Op
+		if sym := a.Symbol(); sym != nil {
+			side += "\x00" + strconv.FormatUint(sym.Fingerprint(), 16)
+		}
+		sides = append(sides, side)
+	}
+	sort.Strings(sides)
+
+	h := fnv.New64a()
+	h.Write([]byte("race"))
+	var buf [8]byte
+	for _, side := range sides {
+		binary.BigEndian.PutUint64(buf[:], uint64(len(side)))
+		h.Write(buf[:])
+		h.Write([]byte(side))
+	}
+	return h.Sum64()
+}
+
+// ReadRaceReports reads race detector output, such as the output of
+// "go test -race", and returns its reports in order. Lines outside reports
+// are ignored; frames that cannot be converted are kept as unknown frames.
+func ReadRaceReports(r io.Reader) ([]*RaceReport, error) {
+	var (
+		reports []*RaceReport
+		report  *RaceReport
+		target  func(*gsrf.Trace) // Stores the stack being read; nil while skipping
+		frames  []string
+	)
+	flush := func() {
+		if target != nil {
+			target(gsrf.ParseTrace(frames, FromStackTrace))
+		}
+		target, frames = nil, nil
+	}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		raw := scanner.Text()
+		line := strings.TrimSpace(raw)
+		switch {
+		case line == raceWarning:
+			flush()
+			report = &RaceReport{CreatedAt: make(map[int]*gsrf.Trace)}
+			reports = append(reports, report)
+			continue
+		case report == nil:
+			continue
+		case line == raceSeparator:
+			flush()
+			report = nil
+			continue
+		case line == "":
+			flush()
+			continue
+		}
+
+		if strings.HasPrefix(raw, " ") || strings.HasPrefix(raw, "\t") {
+			if target != nil && !isLocationLine(line) {
+				frames = append(frames, stripCallArgs(line))
+			}
+			continue
+		}
+
+		flush()
+		if m := raceAccessPattern.FindStringSubmatch(line); m != nil {
+			a := &report.Access
+			if m[1] != "" {
+				a = &report.Previous
+			}
+			a.Op = strings.ToLower(m[2])
+			a.Addr = m[3]
+			a.Goroutine = 1
+			if m[4] != "" {
+				a.Goroutine, _ = strconv.Atoi(m[4])
+			}
+			target = func(t *gsrf.Trace) { a.Trace = t }
+		} else if m := raceCreatedPattern.FindStringSubmatch(line); m != nil {
+			id, _ := strconv.Atoi(m[1])
+			created := report.CreatedAt
+			target = func(t *gsrf.Trace) { created[id] = t }
+		} else if loc, ok := strings.CutPrefix(line, raceLocationPrefix); ok {
+			report.Location = strings.TrimSuffix(loc, ":")
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	flush()
+	return reports, nil
+}
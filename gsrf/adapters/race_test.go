@@ -0,0 +1,94 @@
+package adapters
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const raceOutput = `=== RUN   TestCounter
+==================
+WARNING: DATA RACE
+Write at 0x00c0000b4010 by goroutine 7:
+  example.com/app.(*Counter).Inc()
+      /app/counter.go:12 +0x44
+  example.com/app.TestCounter.func1()
+      /app/counter_test.go:20 +0x3a
+
+Previous read at 0x00c0000b4010 by goroutine 6:
+  example.com/app.(*Counter).Get()
+      /app/counter.go:16 +0x3e
+  example.com/app.TestCounter.func2()
+      /app/counter_test.go:25 +0x3a
+
+Goroutine 7 (running) created at:
+  example.com/app.TestCounter()
+      /app/counter_test.go:19 +0x9c
+  testing.tRunner()
+      /usr/local/go/src/testing/testing.go:1446 +0x216
+
+Goroutine 6 (finished) created at:
+  example.com/app.TestCounter()
+      /app/counter_test.go:24 +0xd0
+==================
+==================
+WARNING: DATA RACE
+Read at 0x00c0000b4010 by main goroutine:
+  example.com/app.(*Counter).Get()
+      /app/counter.go:16 +0x3e
+  main.main()
+      /app/main.go:9 +0x3a
+
+Previous write at 0x00c0000b4010 by goroutine 8:
+  example.com/app.(*Counter).Inc()
+      /app/counter.go:12 +0x44
+
+Location is heap block of size 8 at 0x00c0000b4010 allocated by main goroutine:
+  example.com/app.NewCounter()
+      /app/counter.go:5 +0x2e
+
+Goroutine 8 (running) created at:
+  main.main()
+      /app/main.go:8 +0x2e
+==================
+    testing.go:1446: race detected during execution of test
+--- FAIL: TestCounter (0.00s)
+`
+
+func TestReadRaceReports(t *testing.T) {
+	reports, err := ReadRaceReports(strings.NewReader(raceOutput))
+	require.NoError(t, err)
+	require.Len(t, reports, 2)
+
+	r := reports[0]
+	assert.Equal(t, "write", r.Access.Op)
+	assert.Equal(t, "0x00c0000b4010", r.Access.Addr)
+	assert.Equal(t, 7, r.Access.Goroutine)
+	assert.Equal(t, []string{"example.com/app.(*Counter).Inc", "example.com/app.TestCounter·lit"}, r.Access.Trace.Lines())
+	assert.Equal(t, "read", r.Previous.Op)
+	assert.Equal(t, 6, r.Previous.Goroutine)
+	assert.Equal(t, "example.com/app.(*Counter).Get", r.Previous.Symbol().String())
+	require.Len(t, r.CreatedAt, 2)
+	assert.Equal(t, []string{"example.com/app.TestCounter", "testing.tRunner"}, r.CreatedAt[7].Lines())
+	assert.Empty(t, r.Location)
+
+	r = reports[1]
+	assert.Equal(t, 1, r.Access.Goroutine)
+	assert.Equal(t, "heap block of size 8 at 0x00c0000b4010 allocated by main goroutine", r.Location)
+	assert.Equal(t, []string{"example.com/app.(*Counter).Inc"}, r.Previous.Trace.Lines())
+	assert.Equal(t, []string{"main.main"}, r.CreatedAt[8].Lines())
+}
+
+func TestRaceReport_Fingerprint(t *testing.T) {
+	reports, err := ReadRaceReports(strings.NewReader(raceOutput))
+	require.NoError(t, err)
+
+	// Same pair of accesses in the opposite order, reached differently.
+	assert.Equal(t, reports[0].Fingerprint(), reports[1].Fingerprint())
+
+	other := *reports[1]
+	other.Access.Op = "write"
+	assert.NotEqual(t, reports[0].Fingerprint(), other.Fingerprint())
+}
@@ -0,0 +1,111 @@
+package adapters
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/kis9a/gsrf"
+)
+
+// docBaseURL is the documentation site ToDocURL links to.
+const docBaseURL = "https://pkg.go.dev/"
+
+// ToDocURL converts GSRF to the documentation URL of the symbol, such as
+// "https://pkg.go.dev/net/http#Server.Serve". Anonymous functions link to
+// their enclosing function and init functions to their package. Symbols
+// without a Go package have no documentation and return "".
+func ToDocURL(sym *gsrf.Symbol) string {
+	if sym.IsForeign() {
+		return ""
+	}
+	url := docBaseURL + sym.PackagePath
+	switch {
+	case sym.IsInit:
+		return url
+	case sym.IsAnonymous:
+		// Closures in methods are named "(*T).M" when converted from
+		// runtime names.
+		name := strings.NewReplacer("(", "", ")", "", "*", "").Replace(sym.Name)
+		if sym.Receiver != nil {
+			name = sym.Receiver.TypeName + "." + name
+		}
+		if name == "" || name == "init" {
+			return url
+		}
+		return url + "#" + name
+	case sym.Receiver != nil:
+		return url + "#" + sym.Receiver.TypeName + "." + sym.Name
+	}
+	return url + "#" + sym.Name
+}
+
+// ToPromLabel converts GSRF to a Prometheus label pair, such as
+// symbol="net/http.(*Server).Serve", escaped for the text exposition format.
+// Metadata is dropped so that positions do not multiply label cardinality.
+func ToPromLabel(sym *gsrf.Symbol) string {
+	value := sym.Format(gsrf.WithOptions(gsrf.FormatOptions{OmitMetadata: true}))
+	return `symbol="` + promEscaper.Replace(value) + `"`
+}
+
+var promEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// Representation is a symbol rendered in one registered format.
+type Representation struct {
+	Format string
+	Text   string
+	Lost   []Feature // Features the symbol uses that the format cannot carry, sorted
+}
+
+// Representations renders sym in every registered format that can be
+// written, sorted by format name. Formats with no rendering for the symbol,
+// like documentation URLs for C functions, are left out.
+func Representations(sym *gsrf.Symbol) []Representation {
+	used := symbolFeatures(sym)
+	var reps []Representation
+	for _, name := range Formats() {
+		c, _ := Lookup(name)
+		if c.To == nil {
+			continue
+		}
+		text := c.To(sym)
+		if text == "" {
+			continue
+		}
+		rep := Representation{Format: c.Name, Text: text}
+		for _, f := range c.Loses {
+			if used[f] {
+				rep.Lost = append(rep.Lost, f)
+			}
+		}
+		sort.Slice(rep.Lost, func(i, j int) bool { return rep.Lost[i] < rep.Lost[j] })
+		reps = append(reps, rep)
+	}
+	return reps
+}
+
+// AllRepresentations returns sym rendered in every writable registered
+// format, keyed by format name. Use Representations to also learn which
+// parts of the symbol each format drops.
+func AllRepresentations(sym *gsrf.Symbol) map[string]string {
+	reps := Representations(sym)
+	out := make(map[string]string, len(reps))
+	for _, r := range reps {
+		out[r.Format] = r.Text
+	}
+	return out
+}
+
+// symbolFeatures returns the features sym makes use of.
+func symbolFeatures(sym *gsrf.Symbol) map[Feature]bool {
+	m := sym.Metadata
+	return map[Feature]bool{
+		FeatureReceiverPointer:  sym.Receiver != nil,
+		FeatureReceiverTypeArgs: sym.Receiver != nil && len(sym.Receiver.TypeArgs) > 0,
+		FeatureTypeArgs:         len(sym.TypeArgs) > 0,
+		FeatureTypeParams:       len(sym.TypeParams) > 0,
+		FeatureContext:          sym.Context != "",
+		FeatureMetadata:         len(m.Via) > 0 || m.Alias != "" || len(m.Custom) > 0,
+		FeaturePosition:         m.Position != "",
+		FeatureAnonIndex:        sym.IsAnonymous && sym.AnonIndex > 0,
+	}
+}
@@ -0,0 +1,65 @@
+package adapters
+
+import (
+	"testing"
+
+	"github.com/kis9a/gsrf"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAllRepresentations(t *testing.T) {
+	sym := gsrf.MustParse("net/http.(*Server).Serve{pos:server.go:3:1}")
+	got := AllRepresentations(sym)
+	want := map[string]string{
+		"doc":        "https://pkg.go.dev/net/http#Server.Serve",
+		"gsrf":       "net/http.(*Server).Serve{pos:server.go:3:1}",
+		"linker":     "net/http.(*Server).Serve",
+		"pprof":      "net/http.(*Server).Serve",
+		"prom":       `symbol="net/http.(*Server).Serve"`,
+		"ssa":        "net/http.(*Server).Serve@server.go:3:1",
+		"stacktrace": "net/http.(*Server).Serve",
+	}
+	// Other tests may register further formats.
+	for format, text := range want {
+		assert.Equal(t, text, got[format], format)
+	}
+}
+
+func TestRepresentations_Lost(t *testing.T) {
+	reps := Representations(gsrf.MustParse("slices.Sort[int]@linux"))
+	lost := map[string][]Feature{}
+	for _, r := range reps {
+		lost[r.Format] = r.Lost
+	}
+	assert.Empty(t, lost["gsrf"])
+	assert.Equal(t, []Feature{FeatureContext}, lost["stacktrace"])
+	assert.Equal(t, []Feature{FeatureContext, FeatureTypeArgs}, lost["pprof"])
+	assert.Empty(t, lost["prom"])
+}
+
+func TestToDocURL(t *testing.T) {
+	tests := map[string]string{
+		"fmt.Println":                "https://pkg.go.dev/fmt#Println",
+		"time.(Time).String":         "https://pkg.go.dev/time#Time.String",
+		"database/sql.init":          "https://pkg.go.dev/database/sql",
+		"main.run·lit1":              "https://pkg.go.dev/main#run",
+		"C.malloc":                   "",
+		"container.(*List[T]).PushB": "https://pkg.go.dev/container#List.PushB",
+	}
+	for in, want := range tests {
+		assert.Equal(t, want, ToDocURL(gsrf.MustParse(in)), in)
+	}
+}
+
+func TestToPprof(t *testing.T) {
+	sym, err := gsrf.FromRuntimeName("main.Map[...]")
+	require.NoError(t, err)
+	assert.Equal(t, "main.Map[...]", ToPprof(sym))
+	assert.Equal(t, "main.(*List[...]).Push", ToPprof(gsrf.MustParse("main.(*List[int]).Push")))
+	assert.Equal(t, "runtime.memmove", ToPprof(gsrf.MustParse("runtime.memmove@asm")))
+}
+
+func TestToPromLabel(t *testing.T) {
+	assert.Equal(t, `symbol="\"a b\".F"`, ToPromLabel(gsrf.MustParse(`"a b".F`)))
+}
@@ -19,13 +19,24 @@ var (
 	ssaLocationPattern = regexp.MustCompile(`^(.+)@([^:]+):(\d+):(\d+)$`)
 )
 
+// SSAOptions controls FromSSAWith and ToSSAWith.
+type SSAOptions struct {
+	PathStyle gsrf.PathStyle // Separators of the location
+}
+
 // FromSSA converts SSA format to GSRF.
 func FromSSA(ssa string) (*gsrf.Symbol, error) {
+	return FromSSAWith(ssa, SSAOptions{})
+}
+
+// FromSSAWith converts SSA format to GSRF, with the location written to the
+// position metadata per opts.
+func FromSSAWith(ssa string, opts SSAOptions) (*gsrf.Symbol, error) {
 	// Remove location info if present
 	location := ""
 	if matches := ssaLocationPattern.FindStringSubmatch(ssa); matches != nil {
 		ssa = matches[1]
-		location = fmt.Sprintf("%s:%s:%s", matches[2], matches[3], matches[4])
+		location = fmt.Sprintf("%s:%s:%s", opts.PathStyle.Apply(matches[2]), matches[3], matches[4])
 	}
 
 	// Try init pattern
@@ -104,6 +115,11 @@ func FromSSA(ssa string) (*gsrf.Symbol, error) {
 
 // ToSSA converts GSRF to SSA format.
 func ToSSA(sym *gsrf.Symbol) string {
+	return ToSSAWith(sym, SSAOptions{})
+}
+
+// ToSSAWith converts GSRF to SSA format, with the location written per opts.
+func ToSSAWith(sym *gsrf.Symbol, opts SSAOptions) string {
 	var result strings.Builder
 
 	result.WriteString(sym.PackagePath)
@@ -137,7 +153,7 @@ func ToSSA(sym *gsrf.Symbol) string {
 	// Add location metadata if available
 	if sym.Metadata.Position != "" {
 		result.WriteByte('@')
-		result.WriteString(sym.Metadata.Position)
+		result.WriteString(opts.PathStyle.Apply(sym.Metadata.Position))
 	}
 
 	return result.String()
@@ -237,3 +237,14 @@ func TestSSARoundTrip(t *testing.T) {
 		})
 	}
 }
+
+func TestSSAPathStyle(t *testing.T) {
+	opts := SSAOptions{PathStyle: gsrf.PathSlash}
+	sym, err := FromSSAWith(`pkg.Function@src\file.go:10:5`, opts)
+	require.NoError(t, err)
+	assert.Equal(t, "src/file.go:10:5", sym.Metadata.Position)
+
+	sym.Metadata.Position = `src\file.go:10:5`
+	assert.Equal(t, "pkg.Function@src/file.go:10:5", ToSSAWith(sym, opts))
+	assert.Equal(t, `pkg.Function@src\file.go:10:5`, ToSSA(sym))
+}
@@ -214,8 +214,36 @@ func parseTypeParams(s string) []string {
 	return result
 }
 
+// GenericMode selects how ToStackTraceWith writes type arguments.
+type GenericMode int
+
+const (
+	// GenericsAsIs writes type arguments as recorded in the symbol, which for
+	// symbols taken from source are declared parameters like [T] that never
+	// appear at runtime.
+	GenericsAsIs GenericMode = iota
+	// GenericsElide writes [...], as runtime tracebacks and
+	// runtime.Frame.Function do.
+	GenericsElide
+	// GenericsShape writes GC shapes, as in the binary's symbol table:
+	// [go.shape.int,go.shape.*uint8]. Shapes are known for basic types and
+	// pointers only; lists with other types are elided.
+	GenericsShape
+)
+
+// StackTraceOptions controls ToStackTraceWith.
+type StackTraceOptions struct {
+	Generics GenericMode
+}
+
 // ToStackTrace converts GSRF to Go runtime stack trace format.
 func ToStackTrace(sym *gsrf.Symbol) string {
+	return ToStackTraceWith(sym, StackTraceOptions{})
+}
+
+// ToStackTraceWith converts GSRF to Go runtime stack trace format, with
+// type arguments written per opts.
+func ToStackTraceWith(sym *gsrf.Symbol, opts StackTraceOptions) string {
 	var result strings.Builder
 
 	if sym.IsAnonymous {
@@ -241,21 +269,120 @@ func ToStackTrace(sym *gsrf.Symbol) string {
 		// Stack traces always use pointer notation
 		result.WriteString("(*")
 		result.WriteString(sym.Receiver.TypeName)
-		if len(sym.Receiver.TypeArgs) > 0 {
-			result.WriteByte('[')
-			result.WriteString(strings.Join(sym.Receiver.TypeArgs, ", "))
-			result.WriteByte(']')
-		}
+		writeStackTypeArgs(&result, sym.Receiver.TypeArgs, opts.Generics)
 		result.WriteString(").")
 		result.WriteString(sym.Name)
 	} else {
 		result.WriteString(sym.Name)
-		if len(sym.TypeArgs) > 0 {
-			result.WriteByte('[')
-			result.WriteString(strings.Join(sym.TypeArgs, ", "))
-			result.WriteByte(']')
-		}
+		writeStackTypeArgs(&result, sym.TypeArgs, opts.Generics)
 	}
 
 	return result.String()
 }
+
+// writeStackTypeArgs writes a type argument list in the given mode.
+func writeStackTypeArgs(b *strings.Builder, args []string, mode GenericMode) {
+	if len(args) == 0 {
+		return
+	}
+	b.WriteByte('[')
+	switch mode {
+	case GenericsElide:
+		b.WriteString("...")
+	case GenericsShape:
+		shapes := make([]string, len(args))
+		for i, arg := range args {
+			shape, ok := gcShape(arg)
+			if !ok {
+				shapes = []string{"..."}
+				break
+			}
+			shapes[i] = shape
+		}
+		b.WriteString(strings.Join(shapes, ","))
+	default:
+		b.WriteString(strings.Join(args, ", "))
+	}
+	b.WriteByte(']')
+}
+
+// shapeAliases maps predeclared aliases to the types the compiler shapes
+// them as.
+var shapeAliases = map[string]string{
+	"byte": "uint8", "rune": "int32", "any": "interface {}", "interface{}": "interface {}",
+}
+
+var basicTypes = map[string]bool{
+	"bool": true, "string": true, "uintptr": true,
+	"int": true, "int8": true, "int16": true, "int32": true, "int64": true,
+	"uint": true, "uint8": true, "uint16": true, "uint32": true, "uint64": true,
+	"float32": true, "float64": true, "complex64": true, "complex128": true,
+	"interface {}": true,
+}
+
+// gcShape returns the GC shape name of a type argument, if it can be told
+// from the type's spelling: all pointer types share the *uint8 shape.
+func gcShape(arg string) (string, bool) {
+	arg = strings.TrimSpace(arg)
+	if strings.HasPrefix(arg, "go.shape.") {
+		return arg, true
+	}
+	if strings.HasPrefix(arg, "*") || arg == "unsafe.Pointer" {
+		return "go.shape.*uint8", true
+	}
+	if alias, ok := shapeAliases[arg]; ok {
+		arg = alias
+	}
+	if basicTypes[arg] {
+		return "go.shape." + arg, true
+	}
+	return "", false
+}
+
+// TraceFromStackTrace converts the lines of a Go runtime stack dump into a
+// Trace. Goroutine headers and file:line location lines are skipped and
+// call argument lists are dropped; frames that still cannot be converted
+// are kept as unknown frames so the trace keeps its length and order.
+func TraceFromStackTrace(lines []string) *gsrf.Trace {
+	var frames []string
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "goroutine ") || isLocationLine(line) {
+			continue
+		}
+		frames = append(frames, stripCallArgs(line))
+	}
+	return gsrf.ParseTrace(frames, FromStackTrace)
+}
+
+// isLocationLine reports whether a stack dump line is a "file.go:123 +0x1d"
+// location line rather than a function frame.
+func isLocationLine(line string) bool {
+	file, _, _ := strings.Cut(line, " ")
+	idx := strings.LastIndex(file, ".go:")
+	return idx > 0 && strings.Trim(file[idx+len(".go:"):], "0123456789") == ""
+}
+
+// stripCallArgs removes a trailing call argument list such as "(0xc000010000, 0x1)".
+func stripCallArgs(frame string) string {
+	if !strings.HasSuffix(frame, ")") {
+		return frame
+	}
+	depth := 0
+	for i := len(frame) - 1; i >= 0; i-- {
+		switch frame[i] {
+		case ')':
+			depth++
+		case '(':
+			depth--
+			if depth == 0 {
+				// A receiver like "(*T)" is followed by ".", an argument list is not.
+				if i > 0 && frame[i-1] != '.' {
+					return frame[:i]
+				}
+				return frame
+			}
+		}
+	}
+	return frame
+}
@@ -253,3 +253,56 @@ func TestStackTraceRoundTrip(t *testing.T) {
 		})
 	}
 }
+
+func TestToStackTraceWith(t *testing.T) {
+	tests := []struct {
+		symbol string
+		mode   GenericMode
+		want   string
+	}{
+		{"pkg.(*List[T]).Add", GenericsAsIs, "pkg.(*List[T]).Add"},
+		{"pkg.(*List[T]).Add", GenericsElide, "pkg.(*List[...]).Add"},
+		{"pkg.(*List[T]).Add", GenericsShape, "pkg.(*List[...]).Add"},
+		{"pkg.(*List[int]).Add", GenericsShape, "pkg.(*List[go.shape.int]).Add"},
+		{"pkg.Map[byte, *Node]", GenericsShape, "pkg.Map[go.shape.uint8,go.shape.*uint8]"},
+		{"pkg.Map[string, Node]", GenericsShape, "pkg.Map[...]"},
+		{"pkg.Keys[any]", GenericsShape, "pkg.Keys[go.shape.interface {}]"},
+		{"pkg.Map[int, string]", GenericsElide, "pkg.Map[...]"},
+		{"pkg.Function", GenericsElide, "pkg.Function"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.symbol, func(t *testing.T) {
+			sym, err := gsrf.Parse(tt.symbol)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, ToStackTraceWith(sym, StackTraceOptions{Generics: tt.mode}))
+		})
+	}
+
+	// Shapes read back from a trace are written unchanged.
+	sym, err := FromStackTrace("main.(*List[go.shape.int]).Add")
+	require.NoError(t, err)
+	assert.Equal(t, "main.(*List[go.shape.int]).Add", ToStackTraceWith(sym, StackTraceOptions{Generics: GenericsShape}))
+}
+
+func TestTraceFromStackTrace(t *testing.T) {
+	dump := []string{
+		"goroutine 1 [running]:",
+		"main.(*Server).handle(0xc000010000, {0x1, 0x2})",
+		"\t/home/user/app/server.go:42 +0x1d",
+		"github.com/user/repo.Process[...](0x0)",
+		"\t/home/user/app/process.go:10 +0x25",
+		"?? corrupted frame",
+		"main.main()",
+		"\t/home/user/app/main.go:12 +0x3a",
+	}
+
+	trace := TraceFromStackTrace(dump)
+	require.Equal(t, 4, trace.Len())
+	assert.Equal(t, 1, trace.UnknownCount())
+	assert.Equal(t, []string{
+		"main.(*Server).handle",
+		"github.com/user/repo.Process[...]",
+		"?? corrupted frame",
+		"main.main",
+	}, trace.Lines())
+}
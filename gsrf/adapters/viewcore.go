@@ -0,0 +1,110 @@
+package adapters
+
+import (
+	"bufio"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/kis9a/gsrf"
+)
+
+var (
+	// "goroutine c000007380:"
+	viewcoreGoroutinePattern = regexp.MustCompile(`^goroutine ([0-9a-f]+):$`)
+	// "000000c000045f58 000000c000045f80 runtime.gopark+240"
+	viewcoreFramePattern = regexp.MustCompile(`^([0-9a-f]+)\s+([0-9a-f]+)\s+(\S+?)([+-]\d+)?$`)
+)
+
+// ViewcoreGoroutine is one goroutine of "viewcore CORE goroutines" output
+// (golang.org/x/debug/cmd/viewcore), read from a core dump.
+type ViewcoreGoroutine struct {
+	Addr   uint64          // Address of the goroutine's g struct
+	Trace  *gsrf.Trace     // Stack, innermost first
+	Frames []ViewcoreFrame // Stack frame extents, parallel to Trace.Frames
+}
+
+// ViewcoreFrame is the stack extent of one frame and the offset of its PC
+// from the function entry.
+type ViewcoreFrame struct {
+	Min, Max uint64
+	Offset   int64
+}
+
+// ReadViewcoreGoroutines reads the output of "viewcore CORE goroutines".
+// Function names become symbols; names that do not parse are kept as
+// unknown frames. Other lines are ignored.
+func ReadViewcoreGoroutines(r io.Reader) ([]*ViewcoreGoroutine, error) {
+	var (
+		goroutines []*ViewcoreGoroutine
+		current    *ViewcoreGoroutine
+		names      []string
+	)
+	flush := func() {
+		if current != nil {
+			current.Trace = gsrf.ParseTrace(names, gsrf.FromRuntimeName)
+		}
+		names = nil
+	}
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if m := viewcoreGoroutinePattern.FindStringSubmatch(line); m != nil {
+			flush()
+			addr, _ := strconv.ParseUint(m[1], 16, 64)
+			current = &ViewcoreGoroutine{Addr: addr}
+			goroutines = append(goroutines, current)
+			continue
+		}
+		m := viewcoreFramePattern.FindStringSubmatch(line)
+		if current == nil || m == nil {
+			continue
+		}
+		var f ViewcoreFrame
+		f.Min, _ = strconv.ParseUint(m[1], 16, 64)
+		f.Max, _ = strconv.ParseUint(m[2], 16, 64)
+		if m[4] != "" {
+			f.Offset, _ = strconv.ParseInt(m[4], 10, 64)
+		}
+		current.Frames = append(current.Frames, f)
+		names = append(names, m[3])
+	}
+	flush()
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return goroutines, nil
+}
+
+// ViewcoreCommand returns a shell command running "viewcore CORE goroutines"
+// and printing, under their goroutine's header line, the frames of the
+// given symbols. Symbols are matched by their runtime names, so all
+// instantiations of a generic function match.
+func ViewcoreCommand(core string, syms ...*gsrf.Symbol) string {
+	names := make([]string, len(syms))
+	for i, sym := range syms {
+		names[i] = awkQuoteMeta(ToPprof(sym))
+	}
+	program := `/^goroutine /{g=$0; next} $3 ~ /^(` + strings.Join(names, "|") + `)([+-][0-9]+)?$/ {if (g != "") print g; g=""; print}`
+	return "viewcore " + shellQuote(core) + " goroutines | awk " + shellQuote(program)
+}
+
+// awkQuoteMeta escapes the characters of s that are special in an awk
+// regular expression literal.
+func awkQuoteMeta(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		if strings.ContainsRune(`\^$.[]|()*+?{}/`, r) {
+			b.WriteByte('\\')
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// shellQuote quotes s for a POSIX shell.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
@@ -0,0 +1,58 @@
+package adapters
+
+import (
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kis9a/gsrf"
+)
+
+const viewcoreGoroutines = `goroutine c000000180:
+  000000c00004c6f8 000000c00004c718 runtime.gopark+240
+  000000c00004c718 000000c00004c748 runtime.chanrecv1+18
+  000000c00004c748 000000c00004c7c0 main.(*Pool).dispatch+87
+  000000c00004c7c0 000000c00004c7d0 runtime.goexit+1
+goroutine c000001380:
+  000000c0000586f0 000000c000058750 main.process[...]+44
+  000000c000058750 000000c000058778 ?
+`
+
+func TestReadViewcoreGoroutines(t *testing.T) {
+	gs, err := ReadViewcoreGoroutines(strings.NewReader(viewcoreGoroutines))
+	require.NoError(t, err)
+	require.Len(t, gs, 2)
+
+	g := gs[0]
+	assert.Equal(t, uint64(0xc000000180), g.Addr)
+	require.Equal(t, 4, g.Trace.Len())
+	require.Len(t, g.Frames, 4)
+	assert.Equal(t, "main.(*Pool).dispatch", g.Trace.Frames[2].Symbol.String())
+	assert.Equal(t, ViewcoreFrame{Min: 0xc00004c748, Max: 0xc00004c7c0, Offset: 87}, g.Frames[2])
+
+	assert.Equal(t, "main.process[...]", gs[1].Trace.Frames[0].Symbol.String())
+	assert.True(t, gs[1].Trace.Frames[1].IsUnknown())
+}
+
+func TestViewcoreCommand(t *testing.T) {
+	cmd := ViewcoreCommand("/tmp/core's", gsrf.MustParse("main.(*Pool).dispatch"), gsrf.MustParse("main.process[int]"))
+	assert.True(t, strings.HasPrefix(cmd, `viewcore '/tmp/core'\''s' goroutines | awk `), cmd)
+
+	if _, err := exec.LookPath("awk"); err != nil {
+		t.Skip("awk not available")
+	}
+	// Run the awk part of the pipeline on recorded viewcore output.
+	_, pipeline, _ := strings.Cut(cmd, " | ")
+	sh := exec.Command("sh", "-c", pipeline)
+	sh.Stdin = strings.NewReader(viewcoreGoroutines)
+	out, err := sh.Output()
+	require.NoError(t, err)
+	assert.Equal(t, `goroutine c000000180:
+  000000c00004c748 000000c00004c7c0 main.(*Pool).dispatch+87
+goroutine c000001380:
+  000000c0000586f0 000000c000058750 main.process[...]+44
+`, string(out))
+}
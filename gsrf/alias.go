@@ -0,0 +1,54 @@
+package gsrf
+
+import (
+	"fmt"
+	"strings"
+)
+
+// AliasSeparator separates the hops of an alias chain in Metadata.Alias:
+// "pkg.F{alias:A>B}" was referenced through A, which aliases B.
+const AliasSeparator = ">"
+
+// maxAliasHops bounds ResolveAlias on resolvers that keep inventing names.
+const maxAliasHops = 64
+
+// AliasChain returns the alias hops recorded in Alias, outermost first, or
+// nil if there are none.
+func (m Metadata) AliasChain() []string {
+	if m.Alias == "" {
+		return nil
+	}
+	return strings.Split(m.Alias, AliasSeparator)
+}
+
+// ResolveAlias follows the symbol's alias chain to the original definition.
+// resolver returns the symbol an alias name refers to; resolution starts at
+// the innermost recorded hop and continues while the returned symbol carries
+// alias metadata of its own. A symbol without an alias resolves to itself.
+func (s *Symbol) ResolveAlias(resolver func(string) (*Symbol, error)) (*Symbol, error) {
+	seen := make(map[string]bool)
+	cur := s
+	for hops := 0; ; hops++ {
+		chain := cur.Metadata.AliasChain()
+		if len(chain) == 0 {
+			return cur, nil
+		}
+		name := chain[len(chain)-1]
+		if seen[name] {
+			return nil, fmt.Errorf("resolve alias of %s: cycle at %q", s, name)
+		}
+		if hops == maxAliasHops {
+			return nil, fmt.Errorf("resolve alias of %s: more than %d hops", s, maxAliasHops)
+		}
+		seen[name] = true
+
+		next, err := resolver(name)
+		if err != nil {
+			return nil, fmt.Errorf("resolve alias %q: %w", name, err)
+		}
+		if next == nil {
+			return nil, fmt.Errorf("resolve alias %q: not found", name)
+		}
+		cur = next
+	}
+}
@@ -0,0 +1,61 @@
+package gsrf
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestMetadata_AliasChain(t *testing.T) {
+	sym := MustParse("pkg.Open{alias:compat.Open>v1.Open}")
+	if got := sym.Metadata.AliasChain(); !reflect.DeepEqual(got, []string{"compat.Open", "v1.Open"}) {
+		t.Errorf("AliasChain() = %q", got)
+	}
+	if got := sym.String(); got != "pkg.Open{alias:compat.Open>v1.Open}" {
+		t.Errorf("String() = %q", got)
+	}
+	if got := NewBuilder("pkg").Func("Open").Alias("A", "B").MustBuild().Metadata.Alias; got != "A>B" {
+		t.Errorf("Builder Alias = %q", got)
+	}
+	if chain := MustParse("pkg.Open").Metadata.AliasChain(); chain != nil {
+		t.Errorf("AliasChain() without alias = %q", chain)
+	}
+	if err := MustParse("pkg.Open{alias:A>>B}").Validate(); err == nil {
+		t.Error("Validate() accepted an empty alias hop")
+	}
+}
+
+func TestSymbol_ResolveAlias(t *testing.T) {
+	defs := map[string]*Symbol{
+		"B":    MustParse("pkg.B{alias:C}"),
+		"C":    MustParse("orig.Open{pos:open.go:1:1}"),
+		"loop": MustParse("pkg.L{alias:loop}"),
+	}
+	resolver := func(name string) (*Symbol, error) {
+		if name == "broken" {
+			return nil, errors.New("index unavailable")
+		}
+		return defs[name], nil
+	}
+
+	got, err := MustParse("pkg.F{alias:A>B}").ResolveAlias(resolver)
+	if err != nil || got.String() != "orig.Open{pos:open.go:1:1}" {
+		t.Errorf("ResolveAlias() = %v, %v", got, err)
+	}
+
+	plain := MustParse("pkg.F")
+	if got, err := plain.ResolveAlias(resolver); err != nil || got != plain {
+		t.Errorf("ResolveAlias() of unaliased symbol = %v, %v", got, err)
+	}
+
+	for _, tt := range []struct{ input, want string }{
+		{"pkg.F{alias:loop}", "cycle"},
+		{"pkg.F{alias:missing}", "not found"},
+		{"pkg.F{alias:broken}", "index unavailable"},
+	} {
+		if _, err := MustParse(tt.input).ResolveAlias(resolver); err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("ResolveAlias(%s) error = %v, want %q", tt.input, err, tt.want)
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package gsrf
+
+import (
+	"errors"
+	"fmt"
+)
+
+// BatchOptions controls ParseAllWith.
+type BatchOptions struct {
+	ParseOptions      // Applied to every input
+	StopOnError  bool // Return at the first failure instead of parsing every input
+}
+
+// ParseError reports an input of a batch that failed to parse.
+type ParseError struct {
+	Index int    // Position of the input in the batch
+	Input string // The rejected input
+	Err   error  // The parser's error
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("input %d (%q): %v", e.Index, e.Input, e.Err)
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// ParseAll parses every input and returns the symbols in input order. Inputs
+// that fail leave a nil entry, and their errors are combined with
+// errors.Join as *ParseError values carrying the index, so callers can use
+// the parsed symbols and report the failures together.
+func ParseAll(inputs []string) ([]*Symbol, error) {
+	return ParseAllWith(inputs, BatchOptions{})
+}
+
+// ParseAllWith is ParseAll with options. With StopOnError, it returns nil
+// and the first failure's *ParseError instead of continuing.
+func ParseAllWith(inputs []string, opts BatchOptions) ([]*Symbol, error) {
+	syms := make([]*Symbol, len(inputs))
+	var errs []error
+	for i, input := range inputs {
+		sym, err := ParseWith(input, opts.ParseOptions)
+		if err != nil {
+			perr := &ParseError{Index: i, Input: input, Err: err}
+			if opts.StopOnError {
+				return nil, perr
+			}
+			errs = append(errs, perr)
+			continue
+		}
+		syms[i] = sym
+	}
+	return syms, errors.Join(errs...)
+}
@@ -0,0 +1,52 @@
+package gsrf
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestParseAll(t *testing.T) {
+	inputs := []string{"fmt.Println", "", "net/http.(*Server).Serve", "nodot"}
+
+	syms, err := ParseAll(inputs)
+	if len(syms) != len(inputs) {
+		t.Fatalf("ParseAll() returned %d symbols, want %d", len(syms), len(inputs))
+	}
+	if syms[0].String() != "fmt.Println" || syms[1] != nil || syms[2].String() != "net/http.(*Server).Serve" || syms[3] != nil {
+		t.Errorf("ParseAll() = %v", syms)
+	}
+
+	var joined interface{ Unwrap() []error }
+	if !errors.As(err, &joined) || len(joined.Unwrap()) != 2 {
+		t.Fatalf("ParseAll() error = %v, want 2 joined errors", err)
+	}
+	var perr *ParseError
+	if !errors.As(joined.Unwrap()[1], &perr) || perr.Index != 3 || perr.Input != "nodot" {
+		t.Errorf("second error = %#v, want index 3", joined.Unwrap()[1])
+	}
+	if !strings.Contains(err.Error(), `input 1 ("")`) {
+		t.Errorf("Error() = %q, want the index of the empty input", err.Error())
+	}
+
+	if syms, err := ParseAll(inputs[:1]); err != nil || len(syms) != 1 {
+		t.Errorf("ParseAll(valid) = %v, %v", syms, err)
+	}
+}
+
+func TestParseAllWith(t *testing.T) {
+	inputs := []string{"fmt.Println", "pkg.(int).String", "x"}
+
+	syms, err := ParseAllWith(inputs, BatchOptions{StopOnError: true})
+	var perr *ParseError
+	if syms != nil || !errors.As(err, &perr) || perr.Index != 2 {
+		t.Errorf("StopOnError: %v, %v", syms, err)
+	}
+
+	_, err = ParseAllWith(inputs, BatchOptions{ParseOptions: ParseOptions{Strict: true}})
+	var verr *ValidationError
+	var serr *StyleError
+	if !errors.As(err, &serr) && !errors.As(err, &verr) {
+		t.Errorf("Strict: error = %v, want a validation or style error", err)
+	}
+}
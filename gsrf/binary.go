@@ -0,0 +1,231 @@
+package gsrf
+
+import (
+	"encoding/binary"
+	"encoding/gob"
+	"fmt"
+	"sort"
+)
+
+// BinaryVersion is the wire format version written by MarshalBinary.
+// UnmarshalBinary also reads version 1, which stored a single via entry,
+// and rejects any other version.
+const BinaryVersion = 2
+
+// Presence flags in the binary header.
+const (
+	binReceiver = 1 << iota
+	binReceiverPointer
+	binInit
+	binAnonymous
+	binMetadata
+)
+
+func init() {
+	gob.Register(&Symbol{})
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler. The format is a version
+// byte and a flags varint followed by length-prefixed strings and varint
+// counts; optional sections are present only when their flag is set:
+//
+//	version flags package name
+//	[receiver: typeName typeArgs]
+//	[anonymous: parent index]
+//	typeArgs typeParams context
+//	[metadata: via alias position custom]
+//
+// via is a string list (a single string in version 1).
+//
+// Custom metadata is written in key order so equal symbols encode to equal bytes.
+func (s *Symbol) MarshalBinary() ([]byte, error) {
+	var flags uint64
+	if s.Receiver != nil {
+		flags |= binReceiver
+		if s.Receiver.IsPointer {
+			flags |= binReceiverPointer
+		}
+	}
+	if s.IsInit {
+		flags |= binInit
+	}
+	if s.IsAnonymous {
+		flags |= binAnonymous
+	}
+	if hasMetadata(s.Metadata) {
+		flags |= binMetadata
+	}
+
+	buf := make([]byte, 0, 2+len(s.PackagePath)+len(s.Name)+16)
+	buf = append(buf, BinaryVersion)
+	buf = binary.AppendUvarint(buf, flags)
+	buf = appendString(buf, s.PackagePath)
+	buf = appendString(buf, s.Name)
+	if s.Receiver != nil {
+		buf = appendString(buf, s.Receiver.TypeName)
+		buf = appendStrings(buf, s.Receiver.TypeArgs)
+	}
+	if s.IsAnonymous {
+		buf = appendString(buf, s.AnonParent)
+		buf = binary.AppendUvarint(buf, uint64(s.AnonIndex))
+	}
+	buf = appendStrings(buf, s.TypeArgs)
+	buf = binary.AppendUvarint(buf, uint64(len(s.TypeParams)))
+	for _, tp := range s.TypeParams {
+		buf = appendString(buf, tp.Name)
+		buf = appendString(buf, tp.Constraint)
+	}
+	buf = appendString(buf, s.Context)
+	if flags&binMetadata != 0 {
+		buf = appendStrings(buf, s.Metadata.Via)
+		buf = appendString(buf, s.Metadata.Alias)
+		buf = appendString(buf, s.Metadata.Position)
+		keys := make([]string, 0, len(s.Metadata.Custom))
+		for k := range s.Metadata.Custom {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		buf = binary.AppendUvarint(buf, uint64(len(keys)))
+		for _, k := range keys {
+			buf = appendString(buf, k)
+			buf = appendString(buf, s.Metadata.Custom[k])
+		}
+	}
+	return buf, nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler for data produced by
+// MarshalBinary.
+func (s *Symbol) UnmarshalBinary(data []byte) error {
+	if len(data) == 0 {
+		return fmt.Errorf("invalid GSRF binary: empty input")
+	}
+	version := data[0]
+	if version != BinaryVersion && version != 1 {
+		return fmt.Errorf("invalid GSRF binary: unsupported version %d", data[0])
+	}
+
+	r := binaryReader{buf: data[1:]}
+	flags := r.uvarint()
+	sym := Symbol{
+		PackagePath: r.string(),
+		Name:        r.string(),
+		IsInit:      flags&binInit != 0,
+		IsAnonymous: flags&binAnonymous != 0,
+	}
+	if flags&binReceiver != 0 {
+		sym.Receiver = &Receiver{
+			TypeName:  r.string(),
+			IsPointer: flags&binReceiverPointer != 0,
+			TypeArgs:  r.strings(),
+		}
+	}
+	if sym.IsAnonymous {
+		sym.AnonParent = r.string()
+		sym.AnonIndex = int(r.uvarint())
+	}
+	sym.TypeArgs = r.strings()
+	if n := r.count(); n > 0 {
+		sym.TypeParams = make([]TypeParam, n)
+		for i := range sym.TypeParams {
+			sym.TypeParams[i] = TypeParam{Name: r.string(), Constraint: r.string()}
+		}
+	}
+	sym.Context = r.string()
+	if flags&binMetadata != 0 {
+		if version == 1 {
+			if via := r.string(); via != "" {
+				sym.Metadata.Via = []string{via}
+			}
+		} else {
+			sym.Metadata.Via = r.strings()
+		}
+		sym.Metadata.Alias = r.string()
+		sym.Metadata.Position = r.string()
+		if n := r.count(); n > 0 {
+			sym.Metadata.Custom = make(map[string]string, n)
+			for i := 0; i < n; i++ {
+				k := r.string()
+				sym.Metadata.Custom[k] = r.string()
+			}
+		}
+	}
+
+	if r.err != nil {
+		return r.err
+	}
+	if len(r.buf) != 0 {
+		return fmt.Errorf("invalid GSRF binary: %d trailing bytes", len(r.buf))
+	}
+	if sym.PackagePath == "" || (sym.Name == "" && !sym.IsAnonymous) {
+		return fmt.Errorf("invalid GSRF binary: empty package or symbol name")
+	}
+	*s = sym
+	return nil
+}
+
+func appendString(buf []byte, s string) []byte {
+	buf = binary.AppendUvarint(buf, uint64(len(s)))
+	return append(buf, s...)
+}
+
+func appendStrings(buf []byte, list []string) []byte {
+	buf = binary.AppendUvarint(buf, uint64(len(list)))
+	for _, s := range list {
+		buf = appendString(buf, s)
+	}
+	return buf
+}
+
+// binaryReader decodes the MarshalBinary format. The first error is sticky;
+// later reads return zero values.
+type binaryReader struct {
+	buf []byte
+	err error
+}
+
+func (r *binaryReader) uvarint() uint64 {
+	if r.err != nil {
+		return 0
+	}
+	v, n := binary.Uvarint(r.buf)
+	if n <= 0 {
+		r.err = fmt.Errorf("invalid GSRF binary: malformed varint")
+		return 0
+	}
+	r.buf = r.buf[n:]
+	return v
+}
+
+// count reads a length and checks it against the remaining input, so corrupt
+// data cannot trigger large allocations.
+func (r *binaryReader) count() int {
+	n := r.uvarint()
+	if r.err == nil && n > uint64(len(r.buf)) {
+		r.err = fmt.Errorf("invalid GSRF binary: length %d exceeds input", n)
+		return 0
+	}
+	return int(n)
+}
+
+func (r *binaryReader) string() string {
+	n := r.count()
+	if r.err != nil {
+		return ""
+	}
+	s := string(r.buf[:n])
+	r.buf = r.buf[n:]
+	return s
+}
+
+func (r *binaryReader) strings() []string {
+	n := r.count()
+	if n == 0 {
+		return nil
+	}
+	list := make([]string, n)
+	for i := range list {
+		list[i] = r.string()
+	}
+	return list
+}
@@ -0,0 +1,130 @@
+package gsrf
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/gob"
+	"encoding/json"
+	"testing"
+)
+
+var (
+	_ encoding.BinaryMarshaler   = (*Symbol)(nil)
+	_ encoding.BinaryUnmarshaler = (*Symbol)(nil)
+)
+
+var binaryInputs = []string{
+	"fmt.Println",
+	"database/sql.init",
+	"pkg.(*Cache[K, V]).Get[string]@linux",
+	"main.main·lit2",
+	"pkg.·lit",
+	"pkg.Map[K comparable, V any]",
+	"io.(*MultiWriter).Write{via:Writer,pos:multi.go:25:1,team:io,area:core}",
+	"pkg.(*T).Close{via:Outer,via:Inner}",
+}
+
+func TestSymbol_UnmarshalBinaryVersion1(t *testing.T) {
+	// Version 1 stored via as a single string.
+	data := []byte{1, binMetadata, 3, 'p', 'k', 'g', 1, 'F', 0, 0, 0, 6, 'W', 'r', 'i', 't', 'e', 'r', 0, 0, 0}
+	var sym Symbol
+	if err := sym.UnmarshalBinary(data); err != nil {
+		t.Fatalf("UnmarshalBinary() error = %v", err)
+	}
+	if got := sym.String(); got != "pkg.F{via:Writer}" {
+		t.Errorf("UnmarshalBinary() = %q, want %q", got, "pkg.F{via:Writer}")
+	}
+}
+
+func TestSymbol_BinaryRoundTrip(t *testing.T) {
+	for _, in := range binaryInputs {
+		t.Run(in, func(t *testing.T) {
+			sym := MustParse(in)
+			data, err := sym.MarshalBinary()
+			if err != nil {
+				t.Fatalf("MarshalBinary() error = %v", err)
+			}
+			if data[0] != BinaryVersion {
+				t.Errorf("version byte = %d, want %d", data[0], BinaryVersion)
+			}
+
+			var back Symbol
+			if err := back.UnmarshalBinary(data); err != nil {
+				t.Fatalf("UnmarshalBinary() error = %v", err)
+			}
+			if !back.Equal(sym) {
+				t.Errorf("round trip = %+v, want %+v", back, sym)
+			}
+
+			again, _ := back.MarshalBinary()
+			if !bytes.Equal(data, again) {
+				t.Errorf("re-encoding differs: %x vs %x", data, again)
+			}
+
+			js, _ := json.Marshal(sym)
+			if len(data) >= len(js) {
+				t.Errorf("binary size %d not smaller than JSON size %d", len(data), len(js))
+			}
+		})
+	}
+}
+
+func TestSymbol_UnmarshalBinaryErrors(t *testing.T) {
+	valid, _ := MustParse("pkg.(*T).M@linux").MarshalBinary()
+
+	tests := map[string][]byte{
+		"empty":       nil,
+		"bad version": append([]byte{99}, valid[1:]...),
+		"truncated":   valid[:len(valid)-2],
+		"trailing":    append(append([]byte{}, valid...), 0),
+		"huge length": {BinaryVersion, 0, 0xff, 0xff, 0xff, 0xff, 0x0f},
+		"empty name":  {BinaryVersion, 0, 1, 'p', 0, 0, 0, 0},
+	}
+	for name, data := range tests {
+		t.Run(name, func(t *testing.T) {
+			var sym Symbol
+			if err := sym.UnmarshalBinary(data); err == nil {
+				t.Errorf("UnmarshalBinary(%x) expected error", data)
+			}
+		})
+	}
+}
+
+func TestSymbol_Gob(t *testing.T) {
+	var buf bytes.Buffer
+	enc := gob.NewEncoder(&buf)
+	var frames []*Symbol
+	for _, in := range binaryInputs {
+		frames = append(frames, MustParse(in))
+	}
+	if err := enc.Encode(frames); err != nil {
+		t.Fatalf("Encode() error = %v", err)
+	}
+
+	var got []*Symbol
+	if err := gob.NewDecoder(&buf).Decode(&got); err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+	if len(got) != len(frames) {
+		t.Fatalf("decoded %d symbols, want %d", len(got), len(frames))
+	}
+	for i := range frames {
+		if !got[i].Equal(frames[i]) {
+			t.Errorf("symbol %d = %+v, want %+v", i, got[i], frames[i])
+		}
+	}
+
+	// Registered for use behind interface values.
+	buf.Reset()
+	var v any = MustParse("fmt.Println")
+	if err := gob.NewEncoder(&buf).Encode(&v); err != nil {
+		t.Fatalf("Encode(interface) error = %v", err)
+	}
+	var out any
+	if err := gob.NewDecoder(&buf).Decode(&out); err != nil {
+		t.Fatalf("Decode(interface) error = %v", err)
+	}
+	if sym, ok := out.(*Symbol); !ok || sym.Format() != "fmt.Println" {
+		t.Errorf("Decode(interface) = %#v", out)
+	}
+}
@@ -0,0 +1,145 @@
+// Package budget checks per-symbol performance budgets (latency, allocation,
+// size) against measurements, so CI can enforce cost limits keyed by GSRF
+// symbols.
+package budget
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/kis9a/gsrf"
+)
+
+// Custom metadata keys read by FromMetadata, e.g.
+// "pkg.Handle{budget.latency:50ms,budget.alloc:1048576}".
+const (
+	KeyLatency = "budget.latency" // Go duration
+	KeyAlloc   = "budget.alloc"   // Bytes
+	KeySize    = "budget.size"    // Bytes
+)
+
+// Costs holds a symbol's latency, allocation, and size, either as budgets or
+// as measured values. Zero fields are unset.
+type Costs struct {
+	Latency time.Duration `yaml:"latency,omitempty"`
+	Alloc   int64         `yaml:"alloc,omitempty"`
+	Size    int64         `yaml:"size,omitempty"`
+}
+
+// Table maps symbol keys to costs.
+type Table map[gsrf.SymbolKey]Costs
+
+// FromMetadata reads budgets from the symbol's custom metadata.
+func FromMetadata(sym *gsrf.Symbol) (Costs, error) {
+	var c Costs
+	if v, ok := sym.Metadata.Custom[KeyLatency]; ok {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			return Costs{}, fmt.Errorf("%s: %s: %w", sym, KeyLatency, err)
+		}
+		c.Latency = d
+	}
+	for key, dst := range map[string]*int64{KeyAlloc: &c.Alloc, KeySize: &c.Size} {
+		if v, ok := sym.Metadata.Custom[key]; ok {
+			n, err := strconv.ParseInt(v, 10, 64)
+			if err != nil {
+				return Costs{}, fmt.Errorf("%s: %s: %w", sym, key, err)
+			}
+			*dst = n
+		}
+	}
+	return c, nil
+}
+
+// TableFromMetadata collects the budgets of symbols annotated with budget
+// metadata. Symbols without any budget key are skipped.
+func TableFromMetadata(syms []*gsrf.Symbol) (Table, error) {
+	t := make(Table)
+	for _, sym := range syms {
+		c, err := FromMetadata(sym)
+		if err != nil {
+			return nil, err
+		}
+		if c != (Costs{}) {
+			t[sym.Key()] = c
+		}
+	}
+	return t, nil
+}
+
+// ReadYAML reads a sidecar file mapping GSRF symbols to costs:
+//
+//	net/http.(*Server).Serve:
+//	  latency: 50ms
+//	  alloc: 1048576
+func ReadYAML(r io.Reader) (Table, error) {
+	var raw map[string]Costs
+	if err := yaml.NewDecoder(r).Decode(&raw); err != nil && err != io.EOF {
+		return nil, fmt.Errorf("budget file: %w", err)
+	}
+	t := make(Table, len(raw))
+	for text, c := range raw {
+		sym, err := gsrf.Parse(text)
+		if err != nil {
+			return nil, fmt.Errorf("budget file: %w", err)
+		}
+		t[sym.Key()] = c
+	}
+	return t, nil
+}
+
+// Violation is a measured cost over its budget.
+type Violation struct {
+	Symbol   *gsrf.Symbol
+	Kind     string // "latency", "alloc" or "size"
+	Budget   int64  // Nanoseconds for latency, bytes otherwise
+	Measured int64
+}
+
+func (v Violation) String() string {
+	format := func(n int64) string {
+		if v.Kind == "latency" {
+			return time.Duration(n).String()
+		}
+		return strconv.FormatInt(n, 10) + " bytes"
+	}
+	return fmt.Sprintf("%s: %s %s exceeds budget %s", v.Symbol, v.Kind, format(v.Measured), format(v.Budget))
+}
+
+// Check compares measurements against budgets and returns the violations,
+// sorted by symbol then kind. Unset budgets and unmeasured symbols are not
+// checked.
+func Check(budgets, measured Table) []Violation {
+	var violations []Violation
+	for key, b := range budgets {
+		m, ok := measured[key]
+		if !ok {
+			continue
+		}
+		for _, c := range []struct {
+			kind             string
+			budget, measured int64
+		}{
+			{"latency", int64(b.Latency), int64(m.Latency)},
+			{"alloc", b.Alloc, m.Alloc},
+			{"size", b.Size, m.Size},
+		} {
+			if c.budget > 0 && c.measured > c.budget {
+				violations = append(violations, Violation{Symbol: key.Symbol(), Kind: c.kind, Budget: c.budget, Measured: c.measured})
+			}
+		}
+	}
+	sort.Slice(violations, func(i, j int) bool {
+		a, b := violations[i].Symbol.String(), violations[j].Symbol.String()
+		if a != b {
+			return a < b
+		}
+		return violations[i].Kind < violations[j].Kind
+	})
+	return violations
+}
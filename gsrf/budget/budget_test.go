@@ -0,0 +1,150 @@
+package budget
+
+import (
+	"bytes"
+	"runtime"
+	runtimepprof "runtime/pprof"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kis9a/gsrf"
+	"github.com/kis9a/gsrf/internal/pprof"
+)
+
+func key(s string) gsrf.SymbolKey {
+	return gsrf.MustParse(s).Key()
+}
+
+func TestFromMetadata(t *testing.T) {
+	c, err := FromMetadata(gsrf.MustParse("pkg.Handle{budget.latency:50ms,budget.alloc:1024,owner:web}"))
+	if err != nil {
+		t.Fatalf("FromMetadata() error = %v", err)
+	}
+	if want := (Costs{Latency: 50 * time.Millisecond, Alloc: 1024}); c != want {
+		t.Errorf("FromMetadata() = %+v, want %+v", c, want)
+	}
+
+	if _, err := FromMetadata(gsrf.MustParse("pkg.Handle{budget.size:big}")); err == nil {
+		t.Error("FromMetadata() accepted a non-numeric size")
+	}
+
+	table, err := TableFromMetadata([]*gsrf.Symbol{
+		gsrf.MustParse("pkg.A{budget.size:10}"),
+		gsrf.MustParse("pkg.B{owner:web}"),
+	})
+	if err != nil || len(table) != 1 || table[key("pkg.A")].Size != 10 {
+		t.Errorf("TableFromMetadata() = %v, %v", table, err)
+	}
+}
+
+func TestReadYAML(t *testing.T) {
+	table, err := ReadYAML(strings.NewReader(`
+net/http.(*Server).Serve:
+  latency: 50ms
+  alloc: 1048576
+pkg.Small:
+  size: 4096
+`))
+	if err != nil {
+		t.Fatalf("ReadYAML() error = %v", err)
+	}
+	if got := table[key("net/http.(*Server).Serve")]; got != (Costs{Latency: 50 * time.Millisecond, Alloc: 1 << 20}) {
+		t.Errorf("Serve budget = %+v", got)
+	}
+	if got := table[key("pkg.Small")]; got.Size != 4096 {
+		t.Errorf("Small budget = %+v", got)
+	}
+
+	if _, err := ReadYAML(strings.NewReader("pkg:\n  size: 1\n")); err == nil {
+		t.Error("ReadYAML() accepted an invalid symbol")
+	}
+}
+
+func TestCheck(t *testing.T) {
+	budgets := Table{
+		key("pkg.A"): {Latency: time.Millisecond, Alloc: 100},
+		key("pkg.B"): {Size: 10},
+		key("pkg.C"): {Alloc: 1},
+	}
+	measured := Table{
+		key("pkg.A"): {Latency: 2 * time.Millisecond, Alloc: 200},
+		key("pkg.B"): {Size: 10, Alloc: 1 << 30},
+	}
+	got := Check(budgets, measured)
+	want := []string{
+		"pkg.A: alloc 200 bytes exceeds budget 100 bytes",
+		"pkg.A: latency 2ms exceeds budget 1ms",
+	}
+	if len(got) != len(want) {
+		t.Fatalf("Check() = %v, want %v", got, want)
+	}
+	for i, v := range got {
+		if v.String() != want[i] {
+			t.Errorf("Check()[%d] = %q, want %q", i, v, want[i])
+		}
+	}
+}
+
+func TestReadProfile(t *testing.T) {
+	// A recursive handle and a closure under it.
+	p := &pprof.Profile{
+		SampleTypes: []pprof.ValueType{{Type: "samples", Unit: "count"}, {Type: "cpu", Unit: "nanoseconds"}},
+		Samples: []pprof.Sample{
+			{Locations: []uint64{10, 10, 20}, Values: []int64{1, 3e6}},
+			{Locations: []uint64{30, 10, 20}, Values: []int64{1, 2e6}},
+		},
+		Locations: map[uint64][]uint64{10: {1}, 20: {2}, 30: {3}},
+		Functions: map[uint64]string{1: "main.(*Server).handle", 2: "main.main", 3: "main.(*Server).handle.func1"},
+	}
+	var buf bytes.Buffer
+	if err := pprof.Write(&buf, p); err != nil {
+		t.Fatal(err)
+	}
+
+	table, err := ReadProfile(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatalf("ReadProfile() error = %v", err)
+	}
+	if got := table[key("main.(*Server).handle")].Latency; got != 5*time.Millisecond {
+		t.Errorf("handle latency = %v, want 5ms", got)
+	}
+	if got := table[key("main.main")].Latency; got != 5*time.Millisecond {
+		t.Errorf("main latency = %v, want 5ms", got)
+	}
+	if got := table[key("main.(*Server).handle·lit1")].Latency; got != 2*time.Millisecond {
+		t.Errorf("closure latency = %v, want 2ms", got)
+	}
+
+	if _, err := ReadProfile(bytes.NewReader(buf.Bytes()[:buf.Len()-3])); err == nil {
+		t.Error("ReadProfile() accepted a truncated profile")
+	}
+}
+
+var sink [][]byte
+
+//go:noinline
+func allocateForProfile() {
+	for i := 0; i < 64; i++ {
+		sink = append(sink, make([]byte, 4096))
+	}
+}
+
+func TestReadProfile_Heap(t *testing.T) {
+	defer func(rate int) { runtime.MemProfileRate = rate }(runtime.MemProfileRate)
+	runtime.MemProfileRate = 1
+	allocateForProfile()
+	runtime.GC()
+
+	var buf bytes.Buffer
+	if err := runtimepprof.Lookup("allocs").WriteTo(&buf, 0); err != nil {
+		t.Fatal(err)
+	}
+	table, err := ReadProfile(&buf)
+	if err != nil {
+		t.Fatalf("ReadProfile() error = %v", err)
+	}
+	if got := table[key("github.com/kis9a/gsrf/budget.allocateForProfile")].Alloc; got < 64*4096 {
+		t.Errorf("allocateForProfile alloc = %d, want at least %d", got, 64*4096)
+	}
+}
@@ -0,0 +1,51 @@
+package budget
+
+import (
+	"io"
+	"time"
+
+	"github.com/kis9a/gsrf"
+	"github.com/kis9a/gsrf/internal/pprof"
+)
+
+// ReadProfile reads a pprof profile (gzip-compressed or not) and returns each
+// function's cumulative cost: Latency from the first nanosecond sample type
+// (cpu or wall time) and Alloc from alloc_space. A function is charged once
+// per sample however often it appears on the stack. Function names that are
+// not Go runtime names are skipped. Profiles carry no size information.
+func ReadProfile(r io.Reader) (Table, error) {
+	p, err := pprof.Read(r)
+	if err != nil {
+		return nil, err
+	}
+	latency := p.SampleIndex(func(vt pprof.ValueType) bool { return vt.Unit == "nanoseconds" })
+	alloc := p.SampleIndex(func(vt pprof.ValueType) bool { return vt.Type == "alloc_space" })
+
+	keys := make(map[string]gsrf.SymbolKey)
+	for _, name := range p.Functions {
+		if sym, err := gsrf.FromRuntimeName(name); err == nil {
+			keys[name] = sym.Key()
+		}
+	}
+
+	t := make(Table)
+	for _, s := range p.Samples {
+		seen := make(map[gsrf.SymbolKey]bool)
+		for _, name := range p.Stack(s) {
+			key, ok := keys[name]
+			if !ok || seen[key] {
+				continue
+			}
+			seen[key] = true
+			c := t[key]
+			if latency >= 0 && latency < len(s.Values) {
+				c.Latency += time.Duration(s.Values[latency])
+			}
+			if alloc >= 0 && alloc < len(s.Values) {
+				c.Alloc += s.Values[alloc]
+			}
+			t[key] = c
+		}
+	}
+	return t, nil
+}
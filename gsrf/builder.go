@@ -0,0 +1,199 @@
+package gsrf
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ReceiverKind selects a value or pointer receiver in SymbolBuilder.Receiver.
+type ReceiverKind int
+
+const (
+	ValueReceiver   ReceiverKind = iota // (T)
+	PointerReceiver                     // (*T)
+)
+
+// SymbolBuilder constructs symbols step by step and checks that the result
+// is consistent, which hand-written Symbol literals do not:
+//
+//	sym, err := gsrf.NewBuilder("pkg").
+//		Receiver("T", gsrf.PointerReceiver).
+//		Method("Do").
+//		TypeArgs("int").
+//		Context("linux").
+//		Build()
+//
+// Derived fields (IsInit, AnonParent) are filled in by Build. The first
+// error is reported by Build; later calls are still recorded.
+type SymbolBuilder struct {
+	sym      Symbol
+	kind     string // "func", "method" or "init"; empty until named
+	problems []string
+}
+
+// NewBuilder starts a symbol in the given package.
+func NewBuilder(pkg string) *SymbolBuilder {
+	return &SymbolBuilder{sym: Symbol{PackagePath: pkg}}
+}
+
+func (b *SymbolBuilder) fail(format string, args ...any) *SymbolBuilder {
+	b.problems = append(b.problems, fmt.Sprintf(format, args...))
+	return b
+}
+
+func (b *SymbolBuilder) setName(kind, name string) *SymbolBuilder {
+	if b.kind != "" {
+		return b.fail("name already set to %q", b.sym.Name)
+	}
+	b.kind = kind
+	b.sym.Name = name
+	return b
+}
+
+// Func names a package-level function.
+func (b *SymbolBuilder) Func(name string) *SymbolBuilder {
+	return b.setName("func", name)
+}
+
+// Method names a method; a receiver must also be set.
+func (b *SymbolBuilder) Method(name string) *SymbolBuilder {
+	return b.setName("method", name)
+}
+
+// Init names the package initializer.
+func (b *SymbolBuilder) Init() *SymbolBuilder {
+	return b.setName("init", "init")
+}
+
+// Receiver sets the method receiver type, its kind and, for generic types,
+// its type arguments.
+func (b *SymbolBuilder) Receiver(typeName string, kind ReceiverKind, typeArgs ...string) *SymbolBuilder {
+	if b.sym.Receiver != nil {
+		return b.fail("receiver already set to %q", b.sym.Receiver.TypeName)
+	}
+	b.sym.Receiver = &Receiver{TypeName: typeName, IsPointer: kind == PointerReceiver, TypeArgs: typeArgs}
+	return b
+}
+
+// Anonymous marks the symbol as the index-th function literal inside the
+// named function or method. Index 0 means unnumbered.
+func (b *SymbolBuilder) Anonymous(index int) *SymbolBuilder {
+	if index < 0 {
+		return b.fail("negative anonymous index %d", index)
+	}
+	b.sym.IsAnonymous = true
+	b.sym.AnonIndex = index
+	return b
+}
+
+// TypeArgs sets the type arguments of a generic instantiation.
+func (b *SymbolBuilder) TypeArgs(args ...string) *SymbolBuilder {
+	b.sym.TypeArgs = append(b.sym.TypeArgs, args...)
+	return b
+}
+
+// TypeParam adds a type parameter with an optional constraint.
+func (b *SymbolBuilder) TypeParam(name, constraint string) *SymbolBuilder {
+	b.sym.TypeParams = append(b.sym.TypeParams, TypeParam{Name: name, Constraint: constraint})
+	return b
+}
+
+// Context sets the build context modifier, e.g. "linux".
+func (b *SymbolBuilder) Context(ctx string) *SymbolBuilder {
+	b.sym.Context = ctx
+	return b
+}
+
+// Via sets the embedding path the method was promoted through, outermost
+// embedded type first.
+func (b *SymbolBuilder) Via(path ...string) *SymbolBuilder {
+	b.sym.Metadata.Via = path
+	return b
+}
+
+// Alias sets the alias the symbol was referenced through. Further names
+// record an alias chain: each alias aliases the next.
+func (b *SymbolBuilder) Alias(names ...string) *SymbolBuilder {
+	b.sym.Metadata.Alias = strings.Join(names, AliasSeparator)
+	return b
+}
+
+// Position sets the source position, e.g. "file.go:10:1".
+func (b *SymbolBuilder) Position(pos string) *SymbolBuilder {
+	b.sym.Metadata.Position = pos
+	return b
+}
+
+// Meta sets a custom metadata entry.
+func (b *SymbolBuilder) Meta(key, value string) *SymbolBuilder {
+	if b.sym.Metadata.Custom == nil {
+		b.sym.Metadata.Custom = make(map[string]string)
+	}
+	b.sym.Metadata.Custom[key] = value
+	return b
+}
+
+// Build validates the accumulated fields and returns the symbol. The result
+// formats to a string that parses and formats back unchanged.
+func (b *SymbolBuilder) Build() (*Symbol, error) {
+	problems := append([]string(nil), b.problems...)
+	check := func(ok bool, format string, args ...any) {
+		if !ok {
+			problems = append(problems, fmt.Sprintf(format, args...))
+		}
+	}
+
+	s := b.sym.Clone()
+	check(s.PackagePath != "", "empty package path")
+	check(b.kind != "", "no function, method or init name set")
+	check(b.kind == "" || s.Name != "", "empty name")
+	check(b.kind != "method" || s.Receiver != nil, "method %q has no receiver", s.Name)
+	check(b.kind == "method" || s.Receiver == nil, "receiver set on %s %q; use Method", b.kind, s.Name)
+	check(len(s.TypeArgs) == 0 || len(s.TypeParams) == 0, "both type arguments and type parameters set")
+	check(b.kind != "init" || (len(s.TypeArgs) == 0 && len(s.TypeParams) == 0), "init cannot be generic")
+	check(b.kind != "func" || s.Name != "init", "use Init for the package initializer")
+	check(!strings.ContainsAny(s.Context, "@{}"), "context %q contains reserved characters", s.Context)
+	if s.Receiver != nil {
+		check(s.Receiver.TypeName != "", "empty receiver type name")
+	}
+	for _, name := range []string{s.Name, receiverTypeName(s.Receiver)} {
+		check(!strings.ContainsAny(name, "()[]{}@,.·"), "name %q contains reserved characters", name)
+	}
+	if len(problems) > 0 {
+		return nil, fmt.Errorf("invalid GSRF symbol: %s", problems[0])
+	}
+
+	s.IsInit = b.kind == "init" && !s.IsAnonymous
+	if s.IsAnonymous {
+		if s.Receiver != nil {
+			// Anonymous functions in methods keep the receiver in the name,
+			// matching how Parse reads "pkg.(*T).M·lit".
+			s.Name = s.Receiver.Format() + "." + s.Name
+			s.Receiver = nil
+		}
+		s.AnonParent = s.PackagePath + "." + s.Name
+	}
+
+	text := s.Format()
+	if back, err := Parse(text); err != nil || back.Format() != text {
+		return nil, fmt.Errorf("invalid GSRF symbol: %q does not round-trip", text)
+	}
+	return s, nil
+}
+
+// MustBuild is like Build but panics on error. It is intended for
+// package-level symbol tables.
+func (b *SymbolBuilder) MustBuild() *Symbol {
+	s, err := b.Build()
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
+func receiverTypeName(r *Receiver) string {
+	if r == nil {
+		return ""
+	}
+	return r.TypeName
+}
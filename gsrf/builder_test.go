@@ -0,0 +1,107 @@
+package gsrf
+
+import (
+	"testing"
+)
+
+func TestSymbolBuilder(t *testing.T) {
+	tests := []struct {
+		name    string
+		build   *SymbolBuilder
+		want    string
+		wantErr bool
+	}{
+		{
+			name:  "method",
+			build: NewBuilder("pkg").Receiver("T", PointerReceiver).Method("Do").TypeArgs("int").Context("linux"),
+			want:  "pkg.(*T).Do[int]@linux",
+		},
+		{
+			name:  "function",
+			build: NewBuilder("fmt").Func("Println"),
+			want:  "fmt.Println",
+		},
+		{
+			name:  "generic receiver",
+			build: NewBuilder("pkg").Receiver("Cache", ValueReceiver, "K", "V").Method("Get"),
+			want:  "pkg.(Cache[K, V]).Get",
+		},
+		{
+			name:  "type params",
+			build: NewBuilder("pkg").Func("Map").TypeParam("K", "comparable").TypeParam("V", "any"),
+			want:  "pkg.Map[K comparable, V]",
+		},
+		{
+			name:  "init",
+			build: NewBuilder("database/sql").Init(),
+			want:  "database/sql.init",
+		},
+		{
+			name:  "closure",
+			build: NewBuilder("main").Func("main").Anonymous(2),
+			want:  "main.main·lit2",
+		},
+		{
+			name:  "closure in method",
+			build: NewBuilder("main").Receiver("T", PointerReceiver).Method("Run").Anonymous(1),
+			want:  "main.(*T).Run·lit1",
+		},
+		{
+			name:  "metadata",
+			build: NewBuilder("io").Receiver("MultiWriter", PointerReceiver).Method("Write").Via("Writer").Position("multi.go:25:1").Meta("team", "io"),
+			want:  "io.(*MultiWriter).Write{via:Writer,pos:multi.go:25:1,team:io}",
+		},
+		{name: "no package", build: NewBuilder("").Func("F"), wantErr: true},
+		{name: "no name", build: NewBuilder("pkg"), wantErr: true},
+		{name: "method without receiver", build: NewBuilder("pkg").Method("Do"), wantErr: true},
+		{name: "function with receiver", build: NewBuilder("pkg").Receiver("T", ValueReceiver).Func("Do"), wantErr: true},
+		{name: "name twice", build: NewBuilder("pkg").Func("A").Func("B"), wantErr: true},
+		{name: "receiver twice", build: NewBuilder("pkg").Receiver("A", ValueReceiver).Receiver("B", ValueReceiver).Method("M"), wantErr: true},
+		{name: "args and params", build: NewBuilder("pkg").Func("Map").TypeArgs("int").TypeParam("T", ""), wantErr: true},
+		{name: "generic init", build: NewBuilder("pkg").Init().TypeArgs("int"), wantErr: true},
+		{name: "init via Func", build: NewBuilder("pkg").Func("init"), wantErr: true},
+		{name: "reserved name", build: NewBuilder("pkg").Func("a.b"), wantErr: true},
+		{name: "reserved context", build: NewBuilder("pkg").Func("F").Context("a@b"), wantErr: true},
+		{name: "negative index", build: NewBuilder("pkg").Func("F").Anonymous(-1), wantErr: true},
+		{name: "empty receiver", build: NewBuilder("pkg").Receiver("", PointerReceiver).Method("M"), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sym, err := tt.build.Build()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Build() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if got := sym.Format(); got != tt.want {
+				t.Errorf("Build() = %q, want %q", got, tt.want)
+			}
+			// Parse reads type parameters as type arguments.
+			if len(sym.TypeParams) == 0 && !sym.Equal(MustParse(tt.want)) {
+				t.Errorf("Build() = %+v, differs from Parse(%q)", sym, tt.want)
+			}
+		})
+	}
+}
+
+func TestSymbolBuilder_Reuse(t *testing.T) {
+	b := NewBuilder("pkg").Func("F").Meta("k", "v")
+	first := b.MustBuild()
+	first.Metadata.Custom["k"] = "changed"
+
+	second := b.MustBuild()
+	if second.Metadata.Custom["k"] != "v" {
+		t.Errorf("Build() shares state between results: %v", second.Metadata.Custom)
+	}
+}
+
+func TestSymbolBuilder_MustBuildPanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("MustBuild() did not panic")
+		}
+	}()
+	NewBuilder("pkg").MustBuild()
+}
@@ -0,0 +1,163 @@
+// Package callgraph computes which GSRF symbols are reachable from entry
+// points through a static call graph, for dead-code and attack-surface
+// analyses.
+package callgraph
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/kis9a/gsrf"
+)
+
+// Record is one line of the NDJSON graph format: an edge with Caller and
+// Callee, or a lone Symbol declaring a function that may have no edges.
+//
+//	{"caller":"main.main","callee":"example.com/app.(*Server).Start"}
+//	{"symbol":"example.com/app.unused"}
+type Record struct {
+	Caller string `json:"caller,omitempty"`
+	Callee string `json:"callee,omitempty"`
+	Symbol string `json:"symbol,omitempty"`
+}
+
+// Graph is a directed call graph. Nodes are identified by gsrf.SymbolKey, so
+// metadata such as positions does not split a function into several nodes.
+type Graph struct {
+	nodes map[gsrf.SymbolKey]*node
+	order []*node
+}
+
+type node struct {
+	sym     *gsrf.Symbol
+	callees []*node
+}
+
+// New returns an empty graph.
+func New() *Graph {
+	return &Graph{nodes: make(map[gsrf.SymbolKey]*node)}
+}
+
+// Read reads a graph in the NDJSON format described by Record. Blank lines
+// are skipped.
+func Read(r io.Reader) (*Graph, error) {
+	g := New()
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+		var rec Record
+		if err := json.Unmarshal(line, &rec); err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNo, err)
+		}
+		if err := g.add(rec); err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNo, err)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return g, nil
+}
+
+func (g *Graph) add(rec Record) error {
+	switch {
+	case rec.Symbol != "" && rec.Caller == "" && rec.Callee == "":
+		sym, err := gsrf.Parse(rec.Symbol)
+		if err != nil {
+			return err
+		}
+		g.AddNode(sym)
+	case rec.Caller != "" && rec.Callee != "" && rec.Symbol == "":
+		caller, err := gsrf.Parse(rec.Caller)
+		if err != nil {
+			return fmt.Errorf("caller: %w", err)
+		}
+		callee, err := gsrf.Parse(rec.Callee)
+		if err != nil {
+			return fmt.Errorf("callee: %w", err)
+		}
+		g.AddEdge(caller, callee)
+	default:
+		return fmt.Errorf("want either caller and callee, or symbol")
+	}
+	return nil
+}
+
+// AddNode adds sym to the graph if it is not present yet.
+func (g *Graph) AddNode(sym *gsrf.Symbol) {
+	g.node(sym)
+}
+
+// AddEdge records that caller calls callee, adding both as needed.
+func (g *Graph) AddEdge(caller, callee *gsrf.Symbol) {
+	from, to := g.node(caller), g.node(callee)
+	from.callees = append(from.callees, to)
+}
+
+func (g *Graph) node(sym *gsrf.Symbol) *node {
+	k := sym.Key()
+	n, ok := g.nodes[k]
+	if !ok {
+		n = &node{sym: sym}
+		g.nodes[k] = n
+		g.order = append(g.order, n)
+	}
+	return n
+}
+
+// Len returns the number of symbols in the graph.
+func (g *Graph) Len() int {
+	return len(g.order)
+}
+
+// Reachability splits the symbols of a graph by whether a root reaches them.
+// Both lists are in the order symbols were added to the graph.
+type Reachability struct {
+	Reachable   []*gsrf.Symbol // Including the roots
+	Unreachable []*gsrf.Symbol
+}
+
+// Reachable walks the graph from roots. A root missing from the graph is an
+// error, as it usually means the graph was built from other packages.
+func (g *Graph) Reachable(roots ...*gsrf.Symbol) (*Reachability, error) {
+	seen := make(map[*node]bool, len(g.order))
+	var stack []*node
+	for _, root := range roots {
+		n, ok := g.nodes[root.Key()]
+		if !ok {
+			return nil, fmt.Errorf("root %s is not in the call graph", root)
+		}
+		if !seen[n] {
+			seen[n] = true
+			stack = append(stack, n)
+		}
+	}
+	for len(stack) > 0 {
+		n := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		for _, c := range n.callees {
+			if !seen[c] {
+				seen[c] = true
+				stack = append(stack, c)
+			}
+		}
+	}
+
+	r := &Reachability{}
+	for _, n := range g.order {
+		if seen[n] {
+			r.Reachable = append(r.Reachable, n.sym)
+		} else {
+			r.Unreachable = append(r.Unreachable, n.sym)
+		}
+	}
+	return r, nil
+}
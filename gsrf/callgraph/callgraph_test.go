@@ -0,0 +1,74 @@
+package callgraph
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/kis9a/gsrf"
+)
+
+const graphNDJSON = `{"caller":"main.main","callee":"example.com/app.(*Server).Start"}
+{"caller":"example.com/app.(*Server).Start","callee":"example.com/app.(*Server).handle{pos:server.go:40:1}"}
+{"caller":"example.com/app.(*Server).handle","callee":"example.com/app.(*Server).Start"}
+
+{"caller":"example.com/app.Legacy","callee":"example.com/app.helper"}
+{"symbol":"example.com/app.Unused"}
+{"symbol":"main.main"}
+`
+
+func symbols(syms []*gsrf.Symbol) string {
+	var names []string
+	for _, s := range syms {
+		names = append(names, s.Format(gsrf.WithOptions(gsrf.FormatOptions{OmitMetadata: true})))
+	}
+	return strings.Join(names, " ")
+}
+
+func TestReachable(t *testing.T) {
+	g, err := Read(strings.NewReader(graphNDJSON))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if g.Len() != 6 {
+		t.Errorf("Len() = %d, want 6", g.Len())
+	}
+
+	r, err := g.Reachable(gsrf.MustParse("main.main"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "main.main example.com/app.(*Server).Start example.com/app.(*Server).handle"
+	if got := symbols(r.Reachable); got != want {
+		t.Errorf("Reachable = %s, want %s", got, want)
+	}
+	want = "example.com/app.Legacy example.com/app.helper example.com/app.Unused"
+	if got := symbols(r.Unreachable); got != want {
+		t.Errorf("Unreachable = %s, want %s", got, want)
+	}
+
+	r, err = g.Reachable(gsrf.MustParse("main.main"), gsrf.MustParse("example.com/app.Legacy"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := symbols(r.Unreachable); got != "example.com/app.Unused" {
+		t.Errorf("Unreachable with two roots = %s", got)
+	}
+
+	if _, err := g.Reachable(gsrf.MustParse("main.run")); err == nil {
+		t.Error("Reachable() from a missing root succeeded")
+	}
+}
+
+func TestReadErrors(t *testing.T) {
+	inputs := []string{
+		`{"caller":"main.main"}`,
+		`{"caller":"main.main","callee":"x","symbol":"main.main"}`,
+		`{"symbol":"nodot"}`,
+		`not json`,
+	}
+	for _, input := range inputs {
+		if _, err := Read(strings.NewReader("\n" + input)); err == nil || !strings.Contains(err.Error(), "line 2") {
+			t.Errorf("Read(%q) error = %v, want a line 2 error", input, err)
+		}
+	}
+}
@@ -1,24 +1,132 @@
 package main
 
 import (
+	"bufio"
 	"encoding/json"
 	"fmt"
 	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
 
 	"github.com/kis9a/gsrf"
 	"github.com/kis9a/gsrf/adapters"
+	"github.com/kis9a/gsrf/budget"
+	"github.com/kis9a/gsrf/callgraph"
+	"github.com/kis9a/gsrf/garble"
+	"github.com/kis9a/gsrf/pgo"
+	"github.com/kis9a/gsrf/provenance"
+	"github.com/kis9a/gsrf/query"
+	"github.com/kis9a/gsrf/rewrite"
 	"github.com/spf13/cobra"
 )
 
 var (
-	outputJSON  bool
-	inputFormat string
+	outputJSON    bool
+	jsonFields    []string
+	inputFormat   string
+	profileName   string
+	profile       gsrf.FormatProfile
+	pathStyleName string
+	pathStyle     gsrf.PathStyle
+	leftFormat    string
+	rightFormat   string
+	foldCase      bool
+
+	licenseBinary string
+	licenseFile   string
+	licenseOnly   string
+
+	moduleOlderThan string
+
+	chainFrom string
+	chainTo   string
+
+	lintStrict   bool
+	parsePartial bool
+	parseVersion string
+
+	specJSONSchema bool
+
+	cohortDepth    int
+	cohortDistance int
+	cohortMinSize  int
+
+	ctxA string
+	ctxB string
+
+	garbleMapFile string
+	garbleMap     *garble.ReverseMap
+	inPlace       bool
+	backupSuffix  string
+
+	rewriteRulesFile string
+
+	filterTags string
+	tagMatch   string
+
+	redactKeyFile string
+	redactPrivate []string
+	redactMapFile string
+	redactRestore bool
+
+	fpModules     []string
+	fpContext     int
+	fpJoinWrapped bool
+	fpWrapWidth   int
+
+	budgetMeasurements string
+	budgetFile         string
+
+	pgoProfile  string
+	pgoInlining string
+	pgoPackage  string
+	pgoCDF      float64
+	pgoHotOnly  bool
+
+	gtreeMin int
+
+	viewcoreCore string
+
+	perfBinary string
+
+	coverDir       string
+	coverUncovered bool
+	coverMinSize   int
+
+	reachableRoots       []string
+	reachableUnreachable bool
+	reachableExported    bool
 )
 
 var rootCmd = &cobra.Command{
 	Use:   "gsrf",
 	Short: "Go Symbol Representation Format tool",
 	Long:  `A CLI tool for parsing and formatting Go symbols according to the GSRF specification.`,
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		p, err := gsrf.ParseProfile(profileName)
+		if err != nil {
+			return err
+		}
+		profile = p
+
+		if pathStyle, err = gsrf.ParsePathStyle(pathStyleName); err != nil {
+			return err
+		}
+
+		if garbleMapFile != "" {
+			f, err := os.Open(garbleMapFile)
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+			if garbleMap, err = garble.ReadReverseMap(f); err != nil {
+				return err
+			}
+		}
+		return nil
+	},
 }
 
 var parseCmd = &cobra.Command{
@@ -29,15 +137,35 @@ var parseCmd = &cobra.Command{
 	RunE: func(cmd *cobra.Command, args []string) error {
 		input := args[0]
 
-		sym, err := gsrf.Parse(input)
-		if err != nil {
-			return fmt.Errorf("parse error: %w", err)
+		var sym *gsrf.Symbol
+		if parsePartial {
+			var diags []gsrf.Diagnostic
+			sym, diags = gsrf.ParsePartial(input)
+			for _, d := range diags {
+				fmt.Fprintf(os.Stderr, "warning: %s\n", d)
+			}
+			if sym == nil {
+				return fmt.Errorf("parse error: nothing recovered from %q", input)
+			}
+			sym.Metadata.Position = pathStyle.Apply(sym.Metadata.Position)
+		} else {
+			if parseVersion != "" {
+				if _, err := gsrf.ParseVersion(input, gsrf.Version(parseVersion)); err != nil {
+					return fmt.Errorf("parse error: %w", err)
+				}
+			}
+			var err error
+			sym, err = gsrf.ParseWith(input, gsrf.ParseOptions{Strict: lintStrict, PathStyle: pathStyle})
+			if err != nil {
+				return fmt.Errorf("parse error: %w", err)
+			}
+		}
+		if garbleMap != nil {
+			sym, _ = garbleMap.Deobfuscate(sym)
 		}
 
 		if outputJSON {
-			encoder := json.NewEncoder(os.Stdout)
-			encoder.SetIndent("", "  ")
-			return encoder.Encode(sym)
+			return printJSON(sym)
 		}
 
 		// Human-readable output
@@ -72,10 +200,10 @@ var parseCmd = &cobra.Command{
 			fmt.Printf("Context: %s\n", sym.Context)
 		}
 		// Display metadata if present
-		if sym.Metadata.Via != "" || sym.Metadata.Alias != "" || sym.Metadata.Position != "" || len(sym.Metadata.Custom) > 0 {
+		if len(sym.Metadata.Via) > 0 || sym.Metadata.Alias != "" || sym.Metadata.Position != "" || len(sym.Metadata.Custom) > 0 {
 			fmt.Println("Metadata:")
-			if sym.Metadata.Via != "" {
-				fmt.Printf("  Via: %s\n", sym.Metadata.Via)
+			if len(sym.Metadata.Via) > 0 {
+				fmt.Printf("  Via: %s\n", strings.Join(sym.Metadata.Via, " > "))
 			}
 			if sym.Metadata.Alias != "" {
 				fmt.Printf("  Alias: %s\n", sym.Metadata.Alias)
@@ -87,6 +215,7 @@ var parseCmd = &cobra.Command{
 				fmt.Printf("  Custom: %v\n", sym.Metadata.Custom)
 			}
 		}
+		fmt.Printf("Spec Version: %s\n", sym.MinSpecVersion())
 
 		return nil
 	},
@@ -100,33 +229,18 @@ var formatCmd = &cobra.Command{
 	RunE: func(cmd *cobra.Command, args []string) error {
 		input := args[0]
 
-		var sym *gsrf.Symbol
-		var err error
-
-		switch inputFormat {
-		case "gsrf":
-			sym, err = gsrf.Parse(input)
-		case "ssa":
-			sym, err = adapters.FromSSA(input)
-		case "stacktrace", "stack":
-			sym, err = adapters.FromStackTrace(input)
-		default:
-			return fmt.Errorf("unknown input format: %s", inputFormat)
-		}
-
+		sym, err := parseFrom(inputFormat, input)
 		if err != nil {
-			return fmt.Errorf("conversion error: %w", err)
+			return err
 		}
 
 		if outputJSON {
-			encoder := json.NewEncoder(os.Stdout)
-			encoder.SetIndent("", "  ")
-			return encoder.Encode(map[string]string{
-				"gsrf": sym.Format(),
+			return printJSON(map[string]string{
+				"gsrf": sym.Format(gsrf.WithProfile(profile)),
 			})
 		}
 
-		fmt.Println(sym.Format())
+		fmt.Println(sym.Format(gsrf.WithProfile(profile)))
 		return nil
 	},
 }
@@ -134,54 +248,1784 @@ var formatCmd = &cobra.Command{
 var convertCmd = &cobra.Command{
 	Use:   "convert [symbol]",
 	Short: "Convert between different symbol formats",
-	Long:  `Convert a GSRF symbol to other formats (SSA, stack trace).`,
-	Args:  cobra.ExactArgs(1),
+	Long: `Convert a GSRF symbol to every registered format that can be written
+(SSA, stack trace, pprof, linker name, documentation URL, Prometheus label, ...).
+Parts of the symbol a format cannot carry are listed after it.`,
+	Args: cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		input := args[0]
 
-		sym, err := gsrf.Parse(input)
+		sym, err := gsrf.ParseWith(input, gsrf.ParseOptions{PathStyle: pathStyle})
 		if err != nil {
 			return fmt.Errorf("parse error: %w", err)
 		}
 
-		result := map[string]string{
-			"gsrf":       sym.Format(),
-			"ssa":        adapters.ToSSA(sym),
-			"stacktrace": adapters.ToStackTrace(sym),
+		reps := adapters.Representations(sym)
+		for i := range reps {
+			if reps[i].Format == "gsrf" {
+				reps[i].Text = sym.Format(gsrf.WithProfile(profile))
+			}
 		}
 
 		if outputJSON {
-			encoder := json.NewEncoder(os.Stdout)
-			encoder.SetIndent("", "  ")
-			return encoder.Encode(result)
+			result := make(map[string]string, len(reps))
+			for _, r := range reps {
+				result[r.Format] = r.Text
+			}
+			return printJSON(result)
 		}
 
-		fmt.Printf("GSRF:       %s\n", result["gsrf"])
-		fmt.Printf("SSA:        %s\n", result["ssa"])
-		fmt.Printf("Stack Trace: %s\n", result["stacktrace"])
+		for _, r := range reps {
+			fmt.Printf("%-11s %s", r.Format+":", r.Text)
+			if len(r.Lost) > 0 {
+				fmt.Printf("  (lost: %v)", r.Lost)
+			}
+			fmt.Println()
+		}
 
 		return nil
 	},
 }
 
-var versionCmd = &cobra.Command{
-	Use:   "version",
-	Short: "Print version information",
-	Run: func(cmd *cobra.Command, args []string) {
-		fmt.Println("gsrf version 1.0.0")
-		fmt.Println("GSRF Specification - Latest")
+var reconcileCmd = &cobra.Command{
+	Use:   "reconcile [a.txt] [b.txt]",
+	Short: "Match symbols across two corpora",
+	Long: `Match symbols across two files produced by different tools, one symbol per line.
+Symbols are paired exactly first, then after canonicalization, ignoring anonymous
+indices, collapsing generic shapes, and finally by fuzzy name matching.
+With --fold-case, canonical forms are also matched ignoring case, and symbols
+that differ only in case are reported as warnings.`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		left, err := readCorpus(args[0], leftFormat)
+		if err != nil {
+			return err
+		}
+		right, err := readCorpus(args[1], rightFormat)
+		if err != nil {
+			return err
+		}
+
+		report := gsrf.ReconcileWith(left, right, gsrf.ReconcileOptions{FoldCase: foldCase})
+		for _, c := range report.CaseCollisions {
+			names := make([]string, len(c.Symbols))
+			for i, sym := range c.Symbols {
+				names[i] = sym.Format(gsrf.WithProfile(profile))
+			}
+			fmt.Fprintf(os.Stderr, "warning: symbols differ only in case: %s\n", strings.Join(names, ", "))
+		}
+
+		if outputJSON {
+			type jsonMatch struct {
+				Left     string `json:"left"`
+				Right    string `json:"right"`
+				Reason   string `json:"reason"`
+				Distance int    `json:"distance,omitempty"`
+			}
+			type jsonUnmatched struct {
+				Symbol string `json:"symbol"`
+				Reason string `json:"reason"`
+			}
+			out := struct {
+				Matched        []jsonMatch     `json:"matched"`
+				UnmatchedLeft  []jsonUnmatched `json:"unmatched_left"`
+				UnmatchedRight []jsonUnmatched `json:"unmatched_right"`
+			}{
+				Matched:        []jsonMatch{},
+				UnmatchedLeft:  []jsonUnmatched{},
+				UnmatchedRight: []jsonUnmatched{},
+			}
+			for _, m := range report.Matched {
+				out.Matched = append(out.Matched, jsonMatch{m.Left.Format(gsrf.WithProfile(profile)), m.Right.Format(gsrf.WithProfile(profile)), string(m.Reason), m.Distance})
+			}
+			for _, u := range report.UnmatchedLeft {
+				out.UnmatchedLeft = append(out.UnmatchedLeft, jsonUnmatched{u.Symbol.Format(gsrf.WithProfile(profile)), u.Reason})
+			}
+			for _, u := range report.UnmatchedRight {
+				out.UnmatchedRight = append(out.UnmatchedRight, jsonUnmatched{u.Symbol.Format(gsrf.WithProfile(profile)), u.Reason})
+			}
+			return printJSON(out)
+		}
+
+		for _, m := range report.Matched {
+			fmt.Printf("MATCH   [%s] %s -> %s\n", m.Reason, m.Left.Format(gsrf.WithProfile(profile)), m.Right.Format(gsrf.WithProfile(profile)))
+		}
+		for _, u := range report.UnmatchedLeft {
+			fmt.Printf("ONLY-A  %s (%s)\n", u.Symbol.Format(gsrf.WithProfile(profile)), u.Reason)
+		}
+		for _, u := range report.UnmatchedRight {
+			fmt.Printf("ONLY-B  %s (%s)\n", u.Symbol.Format(gsrf.WithProfile(profile)), u.Reason)
+		}
+		fmt.Printf("\nMatched: %d, only in A: %d, only in B: %d\n",
+			len(report.Matched), len(report.UnmatchedLeft), len(report.UnmatchedRight))
+
+		return nil
 	},
 }
 
-func init() {
-	rootCmd.PersistentFlags().BoolVar(&outputJSON, "json", false, "Output in JSON format")
+var licenseCmd = &cobra.Command{
+	Use:   "license [symbols.txt]",
+	Short: "Tag symbols with their module and license",
+	Long: `Attribute each symbol to its Go module and tag it with the module's license.
+Modules come from a binary's build info (--binary); licenses come from a mapping
+file (--licenses) of "module SPDX" lines, a JSON object, or deps.dev records.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if licenseBinary == "" {
+			return fmt.Errorf("--binary is required")
+		}
+		modules, err := provenance.ModulesFromBinary(licenseBinary)
+		if err != nil {
+			return err
+		}
+		if licenseFile != "" {
+			f, err := os.Open(licenseFile)
+			if err != nil {
+				return err
+			}
+			licenses, err := provenance.ReadLicenses(f)
+			f.Close()
+			if err != nil {
+				return fmt.Errorf("%s: %w", licenseFile, err)
+			}
+			provenance.ApplyLicenses(modules, licenses)
+		}
 
-	formatCmd.Flags().StringVar(&inputFormat, "from", "gsrf", "Input format (gsrf, ssa, stacktrace)")
+		syms, err := readCorpus(args[0], inputFormat)
+		if err != nil {
+			return err
+		}
 
-	rootCmd.AddCommand(parseCmd)
-	rootCmd.AddCommand(formatCmd)
-	rootCmd.AddCommand(convertCmd)
-	rootCmd.AddCommand(versionCmd)
+		var cutoff time.Time
+		if moduleOlderThan != "" {
+			age, err := provenance.ParseAge(moduleOlderThan)
+			if err != nil {
+				return err
+			}
+			cutoff = time.Now().Add(-age)
+		}
+
+		resolver := provenance.NewResolver(modules)
+		var tagged []*gsrf.Symbol
+		for _, sym := range syms {
+			resolver.Tag(sym)
+			license := sym.Metadata.Custom[provenance.KeyLicense]
+			if licenseOnly != "" && !strings.Contains(license, licenseOnly) {
+				continue
+			}
+			if !cutoff.IsZero() {
+				m, ok := resolver.Lookup(sym.PackagePath)
+				if !ok || !m.HasCommitTime() || !m.CommitTime.Before(cutoff) {
+					continue
+				}
+			}
+			tagged = append(tagged, sym)
+		}
+
+		if outputJSON {
+			type jsonTagged struct {
+				Symbol     string `json:"symbol"`
+				Module     string `json:"module,omitempty"`
+				License    string `json:"license,omitempty"`
+				Commit     string `json:"commit,omitempty"`
+				CommitTime string `json:"commit_time,omitempty"`
+			}
+			out := []jsonTagged{}
+			for _, sym := range tagged {
+				out = append(out, jsonTagged{
+					Symbol:     sym.FormatWith(gsrf.FormatOptions{OmitMetadata: true}),
+					Module:     sym.Metadata.Custom[provenance.KeyModule],
+					License:    sym.Metadata.Custom[provenance.KeyLicense],
+					Commit:     sym.Metadata.Custom[provenance.KeyCommit],
+					CommitTime: sym.Metadata.Custom[provenance.KeyCommitTime],
+				})
+			}
+			return printJSON(out)
+		}
+
+		for _, sym := range tagged {
+			fmt.Println(sym.Format(gsrf.WithProfile(profile)))
+		}
+		return nil
+	},
+}
+
+var chainCmd = &cobra.Command{
+	Use:   "chain [symbol]",
+	Short: "Convert a symbol between any two registered formats",
+	Long: `Convert a symbol between any two registered formats, routing through GSRF,
+and report which symbol features the conversion cannot preserve.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		plan, err := adapters.PlanConversion(chainFrom, chainTo)
+		if err != nil {
+			return err
+		}
+		out, err := adapters.Convert(chainFrom, chainTo, args[0])
+		if err != nil {
+			return fmt.Errorf("conversion error: %w", err)
+		}
+
+		if outputJSON {
+			lost := []string{}
+			for _, f := range plan.Lost {
+				lost = append(lost, string(f))
+			}
+			return printJSON(map[string]interface{}{
+				"output": out,
+				"route":  plan.Steps,
+				"lost":   lost,
+			})
+		}
+
+		fmt.Println(out)
+		if len(plan.Lost) > 0 {
+			fmt.Fprintf(os.Stderr, "route: %s; lost: %v\n", plan, plan.Lost)
+		}
+		return nil
+	},
+}
+
+var lintCmd = &cobra.Command{
+	Use:   "lint [symbols.txt]",
+	Short: "Check symbol names against Go naming rules",
+	Long: `Check that each symbol's names are valid Go identifiers, are not keywords,
+and that receiver types are not predeclared types. Warnings are printed per line;
+with --strict, any warning or parse error makes the command fail.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		f, err := os.Open(args[0])
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+
+		problems := 0
+		scanner := bufio.NewScanner(f)
+		lineNo := 0
+		for scanner.Scan() {
+			lineNo++
+			line := strings.TrimSpace(scanner.Text())
+			if line == "" || strings.HasPrefix(line, "#") {
+				continue
+			}
+			sym, err := parseFrom(inputFormat, line)
+			if err != nil {
+				problems++
+				fmt.Printf("%s:%d: %v\n", args[0], lineNo, err)
+				continue
+			}
+			for _, w := range sym.Lint() {
+				problems++
+				fmt.Printf("%s:%d: %s: %s\n", args[0], lineNo, sym.Format(), w)
+			}
+		}
+		if err := scanner.Err(); err != nil {
+			return err
+		}
+
+		if lintStrict && problems > 0 {
+			return fmt.Errorf("%d problem(s) found", problems)
+		}
+		return nil
+	},
+}
+
+var cohortCmd = &cobra.Command{
+	Use:   "cohort [symbols.txt]",
+	Short: "Group symbols into families by structural similarity",
+	Long: `Group symbols, one per line, into cohorts: closures with their parent function,
+and symbols under a shared package prefix whose names are within a small edit
+distance. Useful for triaging many distinct but related symbols at once.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		syms, err := readCorpus(args[0], inputFormat)
+		if err != nil {
+			return err
+		}
+
+		cohorts := gsrf.Cohorts(syms, gsrf.CohortOptions{PrefixDepth: cohortDepth, MaxDistance: cohortDistance})
+		var shown []gsrf.Cohort
+		for _, c := range cohorts {
+			if len(c.Members) >= cohortMinSize {
+				shown = append(shown, c)
+			}
+		}
+
+		if outputJSON {
+			type jsonCohort struct {
+				Pattern string   `json:"pattern"`
+				Size    int      `json:"size"`
+				Members []string `json:"members"`
+			}
+			out := []jsonCohort{}
+			for _, c := range shown {
+				jc := jsonCohort{Pattern: c.Pattern, Size: len(c.Members)}
+				for _, m := range c.Members {
+					jc.Members = append(jc.Members, m.Format(gsrf.WithProfile(profile)))
+				}
+				out = append(out, jc)
+			}
+			return printJSON(out)
+		}
+
+		for _, c := range shown {
+			fmt.Printf("%d\t%s\n", len(c.Members), c.Pattern)
+			for _, m := range c.Members {
+				fmt.Printf("\t%s\n", m.Format(gsrf.WithProfile(profile)))
+			}
+		}
+		fmt.Printf("\nCohorts: %d shown, %d total, %d symbols\n", len(shown), len(cohorts), len(syms))
+		return nil
+	},
+}
+
+var ctxdiffCmd = &cobra.Command{
+	Use:   "ctxdiff [symbols.txt]",
+	Short: "List symbols that exist in only one build context",
+	Long: `Split a corpus into platform-independent symbols and per-context overlays
+(@linux, @windows, ...), resolve both contexts as base plus overlay, and list the
+symbols that exist in only one of them. An empty context means the base set.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		syms, err := readCorpus(args[0], inputFormat)
+		if err != nil {
+			return err
+		}
+
+		overlay := gsrf.NewContextOverlay(syms)
+		onlyA, onlyB := overlay.Diff(ctxA, ctxB)
+
+		if outputJSON {
+			out := struct {
+				A     string   `json:"a"`
+				B     string   `json:"b"`
+				OnlyA []string `json:"only_a"`
+				OnlyB []string `json:"only_b"`
+			}{A: ctxA, B: ctxB, OnlyA: []string{}, OnlyB: []string{}}
+			for _, s := range onlyA {
+				out.OnlyA = append(out.OnlyA, s.Format(gsrf.WithProfile(profile)))
+			}
+			for _, s := range onlyB {
+				out.OnlyB = append(out.OnlyB, s.Format(gsrf.WithProfile(profile)))
+			}
+			return printJSON(out)
+		}
+
+		for _, s := range onlyA {
+			fmt.Printf("ONLY-A  %s\n", s.Format(gsrf.WithProfile(profile)))
+		}
+		for _, s := range onlyB {
+			fmt.Printf("ONLY-B  %s\n", s.Format(gsrf.WithProfile(profile)))
+		}
+		fmt.Printf("\nOnly in %s: %d, only in %s: %d\n", contextLabel(ctxA), len(onlyA), contextLabel(ctxB), len(onlyB))
+		return nil
+	},
+}
+
+var garbleCmd = &cobra.Command{
+	Use:   "garble [symbols.txt]",
+	Short: "Detect or reverse garble-obfuscated symbols",
+	Long: `Read symbols, one per line. With the global --garble-map flag, print each symbol
+de-obfuscated; without it, list the symbols whose names look like garble hashes.
+With -i, the de-obfuscated symbols replace those in the file, keeping blank and
+comment lines; the file is replaced atomically and keeps its permissions.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if inPlace {
+			if garbleMap == nil {
+				return fmt.Errorf("-i requires --garble-map")
+			}
+			return rewriteFile(args[0], backupSuffix, func(line string) (string, error) {
+				sym, err := parseLine(inputFormat, line)
+				if err != nil {
+					return "", err
+				}
+				return sym.Format(gsrf.WithProfile(profile)), nil
+			})
+		}
+
+		syms, err := readCorpus(args[0], inputFormat)
+		if err != nil {
+			return err
+		}
+
+		flagged := 0
+		for _, sym := range syms {
+			if garbleMap != nil {
+				fmt.Println(sym.Format(gsrf.WithProfile(profile)))
+				continue
+			}
+			if garble.IsObfuscated(sym) {
+				flagged++
+				fmt.Printf("OBFUSCATED  %s\n", sym.Format(gsrf.WithProfile(profile)))
+			}
+		}
+		if garbleMap == nil {
+			fmt.Printf("\nObfuscated: %d of %d (pass --garble-map to reverse)\n", flagged, len(syms))
+		}
+		return nil
+	},
+}
+
+var filterCmd = &cobra.Command{
+	Use:   "filter [expression] symbols.txt",
+	Short: "Print the symbols matching a filter expression",
+	Long: `Read symbols, one per line, and print those matching the expression, such as
+
+  pkg =~ "github.com/org/*" && receiver == "*Server" && has(meta.via)
+
+Fields: symbol, pkg, name, receiver, context, meta.via, meta.alias, meta.pos
+and meta.<key> compare with ==, != or glob-match with =~, !~; method,
+anonymous, init, generic, exported and stdlib are conditions on their own.
+
+With --tag, symbols must also satisfy a tag expression, such as
+
+  team:payments && !deprecated
+
+and the filter expression may be omitted.`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var filters []query.Filter
+		if len(args) == 2 {
+			filter, err := query.Compile(args[0])
+			if err != nil {
+				return err
+			}
+			filters = append(filters, filter)
+		} else if filterTags == "" {
+			return fmt.Errorf("a filter expression or --tag is required")
+		}
+		if filterTags != "" {
+			filter, err := query.CompileTags(filterTags)
+			if err != nil {
+				return err
+			}
+			filters = append(filters, filter)
+		}
+		syms, err := readCorpus(args[len(args)-1], inputFormat)
+		if err != nil {
+			return err
+		}
+
+		out := []string{}
+	symbols:
+		for _, sym := range syms {
+			for _, filter := range filters {
+				if !filter(sym) {
+					continue symbols
+				}
+			}
+			out = append(out, sym.Format(gsrf.WithProfile(profile)))
+		}
+		return printLines(out)
+	},
+}
+
+var tagCmd = &cobra.Command{
+	Use:   "tag",
+	Short: "Attach tags to symbols",
+	Long: `Add or remove tags, such as team:payments or deprecated, on the symbols of a
+corpus. Tags are stored as the "tags" metadata entry; select tagged symbols
+with filter --tag.`,
+}
+
+var tagAddCmd = &cobra.Command{
+	Use:   "add [tags] [symbols.txt]",
+	Short: "Tag the symbols matching a pattern",
+	Long: `Read symbols, one per line, and print them with the comma-separated tags added
+to those matching --match, or to all of them without it. --match takes a
+symbol pattern, such as "github.com/org/**.(*Server).*", or a package pattern
+ending in "/...". With -i, the tagged symbols replace those in the file,
+keeping blank and comment lines.`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runTag(args[0], args[1], (*gsrf.Symbol).WithTags)
+	},
+}
+
+var tagRemoveCmd = &cobra.Command{
+	Use:   "remove [tags] [symbols.txt]",
+	Short: "Remove tags from the symbols matching a pattern",
+	Long: `Read symbols, one per line, and print them with the comma-separated tags removed
+from those matching --match, or from all of them without it. With -i, the
+result replaces the symbols in the file.`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runTag(args[0], args[1], (*gsrf.Symbol).WithoutTags)
+	},
+}
+
+// runTag applies update with the tags in list to the symbols of path
+// matching --match.
+func runTag(list, path string, update func(*gsrf.Symbol, ...string) *gsrf.Symbol) error {
+	tags := strings.Split(list, ",")
+	for i, tag := range tags {
+		tags[i] = strings.TrimSpace(tag)
+		if err := gsrf.ValidateTag(tags[i]); err != nil {
+			return err
+		}
+	}
+	var pattern *gsrf.Pattern
+	if tagMatch != "" {
+		var err error
+		if pattern, err = compileMatch(tagMatch); err != nil {
+			return err
+		}
+	}
+	apply := func(sym *gsrf.Symbol) *gsrf.Symbol {
+		if pattern == nil || pattern.MatchSymbol(sym) {
+			return update(sym, tags...)
+		}
+		return sym
+	}
+
+	if inPlace {
+		return rewriteFile(path, backupSuffix, func(line string) (string, error) {
+			sym, err := parseLine(inputFormat, line)
+			if err != nil {
+				return "", err
+			}
+			return apply(sym).Format(gsrf.WithProfile(profile)), nil
+		})
+	}
+
+	syms, err := readCorpus(path, inputFormat)
+	if err != nil {
+		return err
+	}
+	out := make([]string, len(syms))
+	for i, sym := range syms {
+		out[i] = apply(sym).Format(gsrf.WithProfile(profile))
+	}
+	return printLines(out)
+}
+
+// compileMatch compiles a symbol pattern, accepting Go package patterns
+// like "github.com/org/payments/..." for every symbol at or below a path.
+func compileMatch(pattern string) (*gsrf.Pattern, error) {
+	if pkg, ok := strings.CutSuffix(pattern, "/..."); ok {
+		pattern = pkg + "/**.**"
+	}
+	return gsrf.CompilePattern(pattern)
+}
+
+var rewriteCmd = &cobra.Command{
+	Use:   "rewrite [symbols.txt]",
+	Short: "Map symbols across refactors with rewrite rules",
+	Long: `Read symbols, one per line, and print each one rewritten by the rules of the
+--rules YAML file: package moves, receiver type renames and function or method
+renames with "*" wildcards, applied in order. With -i, the rewritten symbols
+replace those in the file, keeping blank and comment lines.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if rewriteRulesFile == "" {
+			return fmt.Errorf("--rules is required")
+		}
+		f, err := os.Open(rewriteRulesFile)
+		if err != nil {
+			return err
+		}
+		rules, err := rewrite.ReadYAML(f)
+		f.Close()
+		if err != nil {
+			return err
+		}
+
+		if inPlace {
+			return rewriteFile(args[0], backupSuffix, func(line string) (string, error) {
+				sym, err := parseLine(inputFormat, line)
+				if err != nil {
+					return "", err
+				}
+				sym, _ = rules.Apply(sym)
+				return sym.Format(gsrf.WithProfile(profile)), nil
+			})
+		}
+
+		syms, err := readCorpus(args[0], inputFormat)
+		if err != nil {
+			return err
+		}
+		out := make([]string, len(syms))
+		for i, sym := range syms {
+			sym, _ = rules.Apply(sym)
+			out[i] = sym.Format(gsrf.WithProfile(profile))
+		}
+		return printLines(out)
+	},
+}
+
+var redactCmd = &cobra.Command{
+	Use:   "redact [symbols.txt]",
+	Short: "Redact private symbols with salted-hash placeholders",
+	Long: `Read symbols, one per line, and print each one with the package paths and names
+of the --private modules replaced by placeholders keyed by --key-file, keeping
+receivers, type argument counts and exportedness. With --map, the placeholders
+are written there for restoring; keep that file private. With --restore, the
+placeholders are mapped back using --map, which must match the key.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if redactKeyFile == "" {
+			return fmt.Errorf("--key-file is required")
+		}
+		key, err := os.ReadFile(redactKeyFile)
+		if err != nil {
+			return err
+		}
+		key = []byte(strings.TrimSpace(string(key)))
+
+		syms, err := readCorpus(args[0], inputFormat)
+		if err != nil {
+			return err
+		}
+
+		convert := func(sym *gsrf.Symbol) *gsrf.Symbol { return sym }
+		var redactor *gsrf.Redactor
+		if redactRestore {
+			if redactMapFile == "" {
+				return fmt.Errorf("--restore requires --map")
+			}
+			f, err := os.Open(redactMapFile)
+			if err != nil {
+				return err
+			}
+			m, err := gsrf.ReadRedactionMap(f, key)
+			f.Close()
+			if err != nil {
+				return err
+			}
+			convert = m.Restore
+		} else {
+			if len(redactPrivate) == 0 {
+				return fmt.Errorf("--private is required")
+			}
+			redactor = gsrf.NewRedactor(key, redactPrivate...)
+			convert = redactor.Redact
+		}
+
+		out := make([]string, len(syms))
+		for i, sym := range syms {
+			out[i] = convert(sym).Format(gsrf.WithProfile(profile))
+		}
+
+		if redactor != nil && redactMapFile != "" {
+			f, err := os.OpenFile(redactMapFile, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o600)
+			if err != nil {
+				return err
+			}
+			if _, err := redactor.RedactionMap().WriteTo(f); err != nil {
+				f.Close()
+				return err
+			}
+			if err := f.Close(); err != nil {
+				return err
+			}
+		}
+
+		return printLines(out)
+	},
+}
+
+var fingerprintCmd = &cobra.Command{
+	Use:   "fingerprint [trace.txt]",
+	Short: "Fingerprint a stack trace for crash grouping",
+	Long: `Read a stack trace, one frame per line, innermost first, and print its
+fingerprint. With --module, the trace is first cut to the innermost run of
+first-party frames plus --context frames it called into, so crashes in the
+same code group together however they were reached. Lines that do not parse
+are kept as unknown frames; with --join-wrapped, frames a log shipper split
+across lines are joined back first.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		data, err := os.ReadFile(args[0])
+		if err != nil {
+			return err
+		}
+		trace := gsrf.ParseTraceWith(strings.Split(string(data), "\n"), gsrf.TraceOptions{
+			Parse: func(line string) (*gsrf.Symbol, error) {
+				return parseFrom(inputFormat, line)
+			},
+			JoinWrapped: fpJoinWrapped,
+			WrapWidth:   fpWrapWidth,
+		})
+		if len(fpModules) > 0 {
+			trace = trace.PruneToModules(fpModules, fpContext)
+		}
+		fp := trace.Fingerprint(gsrf.UnknownCollapse)
+
+		frames := make([]string, len(trace.Frames))
+		for i, f := range trace.Frames {
+			if f.Symbol != nil {
+				frames[i] = f.Symbol.Format(gsrf.WithProfile(profile))
+			} else {
+				frames[i] = f.String()
+			}
+		}
+
+		if outputJSON {
+			out := struct {
+				Fingerprint string   `json:"fingerprint"`
+				Frames      []string `json:"frames"`
+			}{Fingerprint: fmt.Sprintf("%016x", fp), Frames: frames}
+			return printJSON(out)
+		}
+
+		for _, f := range frames {
+			fmt.Printf("\t%s\n", f)
+		}
+		fmt.Printf("Fingerprint: %016x\n", fp)
+		return nil
+	},
+}
+
+var budgetCmd = &cobra.Command{
+	Use:   "budget",
+	Short: "Check per-symbol cost budgets against measurements",
+	Long: `Compare measured latency, allocation, and size per symbol with budgets and
+report every symbol over budget, exiting non-zero if any is. Measurements come
+from a pprof profile (cumulative time and alloc_space) or a YAML costs file.
+Budgets come from a YAML file or a symbol file whose symbols carry
+budget.latency, budget.alloc, and budget.size metadata.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if budgetMeasurements == "" || budgetFile == "" {
+			return fmt.Errorf("--measurements and --budgets are required")
+		}
+		measured, err := readCosts(budgetMeasurements)
+		if err != nil {
+			return err
+		}
+		var budgets budget.Table
+		if isYAML(budgetFile) {
+			budgets, err = readCosts(budgetFile)
+		} else {
+			var syms []*gsrf.Symbol
+			if syms, err = gsrf.ParseFile(budgetFile, gsrf.ParseOptions{PathStyle: pathStyle}); err == nil {
+				budgets, err = budget.TableFromMetadata(syms)
+			}
+		}
+		if err != nil {
+			return err
+		}
+
+		violations := budget.Check(budgets, measured)
+		if outputJSON {
+			type jsonViolation struct {
+				Symbol   string `json:"symbol"`
+				Kind     string `json:"kind"`
+				Budget   int64  `json:"budget"`
+				Measured int64  `json:"measured"`
+			}
+			out := []jsonViolation{}
+			for _, v := range violations {
+				out = append(out, jsonViolation{Symbol: v.Symbol.Format(gsrf.WithProfile(profile)), Kind: v.Kind, Budget: v.Budget, Measured: v.Measured})
+			}
+			if err := printJSON(out); err != nil {
+				return err
+			}
+		} else {
+			for _, v := range violations {
+				fmt.Println(v)
+			}
+			fmt.Printf("\nBudgets: %d checked, %d violations\n", len(budgets), len(violations))
+		}
+		if len(violations) > 0 {
+			return fmt.Errorf("%d budget violation(s)", len(violations))
+		}
+		return nil
+	},
+}
+
+var pgoCmd = &cobra.Command{
+	Use:   "pgo",
+	Short: "Report hot functions and their inlining decisions",
+	Long: `Join a PGO CPU profile (--cpu-profile) with the compiler's inlining
+diagnostics (go build -gcflags=-m=2 2> build.log) and list, per symbol, its
+share of profile samples, whether PGO considers it hot, and whether it was
+inlined. The global --profile flag selects how symbols are formatted.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if pgoProfile == "" || pgoInlining == "" {
+			return fmt.Errorf("--cpu-profile and --inlining are required")
+		}
+		pf, err := os.Open(pgoProfile)
+		if err != nil {
+			return err
+		}
+		defer pf.Close()
+		heat, err := pgo.ReadProfile(pf, pgoCDF)
+		if err != nil {
+			return fmt.Errorf("%s: %w", pgoProfile, err)
+		}
+		lf, err := os.Open(pgoInlining)
+		if err != nil {
+			return err
+		}
+		defer lf.Close()
+		decisions, err := pgo.ReadInlining(lf, pgoPackage)
+		if err != nil {
+			return fmt.Errorf("%s: %w", pgoInlining, err)
+		}
+
+		var entries []pgo.Entry
+		for _, e := range pgo.Report(heat, decisions) {
+			if !pgoHotOnly || e.Hot {
+				entries = append(entries, e)
+			}
+		}
+
+		if outputJSON {
+			type jsonEntry struct {
+				Symbol   string  `json:"symbol"`
+				Share    float64 `json:"share"`
+				Hot      bool    `json:"hot"`
+				Inlining string  `json:"inlining"`
+				Reason   string  `json:"reason,omitempty"`
+			}
+			out := []jsonEntry{}
+			for _, e := range entries {
+				out = append(out, jsonEntry{
+					Symbol:   e.Symbol.Format(gsrf.WithProfile(profile)),
+					Share:    e.Share,
+					Hot:      e.Hot,
+					Inlining: e.Inlining.String(),
+					Reason:   e.Reason,
+				})
+			}
+			return printJSON(out)
+		}
+
+		for _, e := range entries {
+			heat := "cold"
+			if e.Hot {
+				heat = "HOT"
+			}
+			fmt.Printf("%-4s  %6.2f%%  %-13s  %s", heat, e.Share, e.Inlining, e.Symbol.Format(gsrf.WithProfile(profile)))
+			if e.Reason != "" {
+				fmt.Printf("  (%s)", e.Reason)
+			}
+			fmt.Println()
+		}
+		return nil
+	},
+}
+
+var coverCmd = &cobra.Command{
+	Use:   "cover [coverage.out]",
+	Short: "Report test coverage per symbol",
+	Long: `Attribute a coverage profile (go test -coverprofile=coverage.out) to the
+functions and methods of the module in --dir and print each symbol's statement
+coverage. With --uncovered, the blocks never executed are listed below each
+symbol; --min-size hides blocks with fewer statements, and symbols left without
+any, so review can focus on substantial untested regions.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		f, err := os.Open(args[0])
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		blocks, err := adapters.ReadCoverProfile(f)
+		if err != nil {
+			return fmt.Errorf("%s: %w", args[0], err)
+		}
+		modulePath, err := readModulePath(filepath.Join(coverDir, "go.mod"))
+		if err != nil {
+			return err
+		}
+		covs, err := adapters.CoverageBySymbol(blocks, func(name string) ([]byte, error) {
+			rel, ok := strings.CutPrefix(name, modulePath+"/")
+			if !ok {
+				return nil, fmt.Errorf("%s is outside module %s", name, modulePath)
+			}
+			return os.ReadFile(filepath.Join(coverDir, filepath.FromSlash(rel)))
+		})
+		if err != nil {
+			return err
+		}
+
+		type entry struct {
+			cov  *adapters.SymbolCoverage
+			gaps []adapters.CoverBlock
+		}
+		var entries []entry
+		for _, c := range covs {
+			var gaps []adapters.CoverBlock
+			for _, b := range c.Uncovered {
+				if b.NumStmt >= coverMinSize {
+					gaps = append(gaps, b)
+				}
+			}
+			if coverUncovered && len(gaps) == 0 {
+				continue
+			}
+			entries = append(entries, entry{c, gaps})
+		}
+
+		if outputJSON {
+			type jsonBlock struct {
+				Pos        string `json:"pos"`
+				End        string `json:"end"`
+				Statements int    `json:"statements"`
+			}
+			type jsonEntry struct {
+				Symbol     string      `json:"symbol"`
+				Statements int         `json:"statements"`
+				Covered    int         `json:"covered"`
+				Percent    float64     `json:"percent"`
+				Uncovered  []jsonBlock `json:"uncovered,omitempty"`
+			}
+			out := []jsonEntry{}
+			for _, e := range entries {
+				je := jsonEntry{
+					Symbol:     e.cov.Symbol.Format(gsrf.WithProfile(profile)),
+					Statements: e.cov.Statements,
+					Covered:    e.cov.Covered,
+					Percent:    e.cov.Percent(),
+				}
+				if coverUncovered {
+					for _, b := range e.gaps {
+						je.Uncovered = append(je.Uncovered, jsonBlock{b.Position(), fmt.Sprintf("%d:%d", b.EndLine, b.EndCol), b.NumStmt})
+					}
+				}
+				out = append(out, je)
+			}
+			return printJSON(out)
+		}
+
+		for _, e := range entries {
+			fmt.Printf("%6.1f%%  %s\n", e.cov.Percent(), e.cov.Symbol.Format(gsrf.WithProfile(profile)))
+			if coverUncovered {
+				for _, b := range e.gaps {
+					fmt.Printf("         %s-%d:%d  %d statements\n", b.Position(), b.EndLine, b.EndCol, b.NumStmt)
+				}
+			}
+		}
+		return nil
+	},
+}
+
+var reachableCmd = &cobra.Command{
+	Use:   "reachable [graph.ndjson]",
+	Short: "List symbols reachable from entry points in a call graph",
+	Long: `Read a call graph as NDJSON, one {"caller":...,"callee":...} edge or
+{"symbol":...} node per line, and list the symbols reachable from the --root
+entry points. With --unreachable, list the complement instead, as candidates
+for dead code; --exported keeps only exported symbols, the package-level API
+a binary actually exposes.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		f, err := os.Open(args[0])
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		graph, err := callgraph.Read(f)
+		if err != nil {
+			return fmt.Errorf("%s: %w", args[0], err)
+		}
+		var roots []*gsrf.Symbol
+		for _, r := range reachableRoots {
+			sym, err := parseFrom(inputFormat, r)
+			if err != nil {
+				return fmt.Errorf("root %q: %w", r, err)
+			}
+			roots = append(roots, sym)
+		}
+		result, err := graph.Reachable(roots...)
+		if err != nil {
+			return err
+		}
+
+		filter := func(syms []*gsrf.Symbol) []string {
+			out := []string{}
+			for _, sym := range syms {
+				if !reachableExported || sym.IsExported() {
+					out = append(out, sym.Format(gsrf.WithProfile(profile)))
+				}
+			}
+			return out
+		}
+
+		if outputJSON {
+			out := struct {
+				Reachable   []string `json:"reachable"`
+				Unreachable []string `json:"unreachable"`
+			}{filter(result.Reachable), filter(result.Unreachable)}
+			return printJSON(out)
+		}
+
+		syms := result.Reachable
+		if reachableUnreachable {
+			syms = result.Unreachable
+		}
+		for _, s := range filter(syms) {
+			fmt.Println(s)
+		}
+		return nil
+	},
+}
+
+var gtreeCmd = &cobra.Command{
+	Use:   "gtree [dump.txt]",
+	Short: "Show which functions spawned the goroutines of a stack dump",
+	Long: `Read a goroutine dump (a panic with GOTRACEBACK=all, debug.Stack(true), or
+/debug/pprof/goroutine?debug=2) and print the goroutines as a tree of creation
+chains: each line is a function that ran a go statement, with the number of
+goroutines created through it. "created by ... in goroutine N" lines and
+GODEBUG=tracebackancestors sections link creators across goroutines, so the
+symbol that ultimately spawned a goroutine explosion is at the top.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		data, err := os.ReadFile(args[0])
+		if err != nil {
+			return err
+		}
+		goroutines := adapters.GoroutinesFromStackTrace(strings.Split(string(data), "\n"))
+		if len(goroutines) == 0 {
+			return fmt.Errorf("no goroutines found in %s", args[0])
+		}
+		tree := adapters.BuildGoroutineTree(goroutines)
+
+		if outputJSON {
+			type jsonNode struct {
+				Creator    string      `json:"creator,omitempty"`
+				Count      int         `json:"count"`
+				Goroutines []int       `json:"goroutines,omitempty"`
+				Children   []*jsonNode `json:"children,omitempty"`
+			}
+			var convert func(t *adapters.GoroutineTree) *jsonNode
+			convert = func(t *adapters.GoroutineTree) *jsonNode {
+				n := &jsonNode{Count: t.Count()}
+				if t.Creator != nil {
+					n.Creator = t.Creator.Format(gsrf.WithProfile(profile))
+				}
+				for _, g := range t.Goroutines {
+					n.Goroutines = append(n.Goroutines, g.ID)
+				}
+				for _, c := range t.Children {
+					if c.Count() >= gtreeMin {
+						n.Children = append(n.Children, convert(c))
+					}
+				}
+				return n
+			}
+			return printJSON(convert(tree))
+		}
+
+		fmt.Printf("%d goroutines\n", tree.Count())
+		var render func(t *adapters.GoroutineTree, depth int)
+		render = func(t *adapters.GoroutineTree, depth int) {
+			for _, c := range t.Children {
+				if c.Count() < gtreeMin {
+					continue
+				}
+				fmt.Printf("%6d  %s%s\n", c.Count(), strings.Repeat("  ", depth), c.Creator.Format(gsrf.WithProfile(profile)))
+				render(c, depth+1)
+			}
+		}
+		render(tree, 0)
+		return nil
+	},
+}
+
+var viewcoreCmd = &cobra.Command{
+	Use:   "viewcore [goroutines.txt | symbol...]",
+	Short: "Read viewcore goroutine listings or target symbols in a core dump",
+	Long: `Read the output of "viewcore CORE goroutines" (golang.org/x/debug) and print
+each goroutine's stack as GSRF symbols, innermost first. With --core, the
+arguments are GSRF symbols instead, and the viewcore command listing their
+frames in that core dump, under their goroutines, is printed.`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if viewcoreCore != "" {
+			syms := make([]*gsrf.Symbol, len(args))
+			for i, arg := range args {
+				sym, err := gsrf.Parse(arg)
+				if err != nil {
+					return err
+				}
+				syms[i] = sym
+			}
+			fmt.Println(adapters.ViewcoreCommand(viewcoreCore, syms...))
+			return nil
+		}
+		if len(args) != 1 {
+			return fmt.Errorf("expected one goroutines file, or --core with symbols")
+		}
+
+		f, err := os.Open(args[0])
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		goroutines, err := adapters.ReadViewcoreGoroutines(f)
+		if err != nil {
+			return err
+		}
+		if len(goroutines) == 0 {
+			return fmt.Errorf("no goroutines found in %s", args[0])
+		}
+
+		frameText := func(fr gsrf.Frame) string {
+			if fr.IsUnknown() {
+				return fr.Unknown.Raw
+			}
+			return fr.Symbol.Format(gsrf.WithProfile(profile))
+		}
+		if outputJSON {
+			type jsonGoroutine struct {
+				Addr   string   `json:"addr"`
+				Frames []string `json:"frames"`
+			}
+			out := []jsonGoroutine{}
+			for _, g := range goroutines {
+				jg := jsonGoroutine{Addr: fmt.Sprintf("%x", g.Addr), Frames: []string{}}
+				for _, fr := range g.Trace.Frames {
+					jg.Frames = append(jg.Frames, frameText(fr))
+				}
+				out = append(out, jg)
+			}
+			return printJSON(out)
+		}
+		for _, g := range goroutines {
+			fmt.Printf("goroutine %x:\n", g.Addr)
+			for _, fr := range g.Trace.Frames {
+				fmt.Printf("  %s\n", frameText(fr))
+			}
+		}
+		return nil
+	},
+}
+
+var perfCmd = &cobra.Command{
+	Use:   "perf [script.txt]",
+	Short: "Aggregate perf script stacks by GSRF symbols",
+	Long: `Read the output of "perf script" for a Go program, convert Go frames to GSRF,
+and print each distinct stack once in collapsed form (outermost first, frames
+joined by ";", followed by the sample count), ready for flame graph tools.
+Kernel and C frames are kept as written. With --binary, function names perf
+truncated or could not resolve are repaired from the binary's symbol table.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var symtab *adapters.SymbolTable
+		if perfBinary != "" {
+			t, err := adapters.ReadELFSymbols(perfBinary)
+			if err != nil {
+				return err
+			}
+			symtab = t
+		}
+		f, err := os.Open(args[0])
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		samples, err := adapters.ReadPerfScript(f, symtab)
+		if err != nil {
+			return err
+		}
+
+		counts := make(map[string]int)
+		for _, s := range samples {
+			frames := make([]string, len(s.Trace.Frames))
+			for i, fr := range s.Trace.Frames {
+				name := fr.String()
+				if fr.Symbol != nil {
+					name = fr.Symbol.Format(gsrf.WithProfile(profile))
+				}
+				frames[len(frames)-1-i] = name
+			}
+			counts[strings.Join(frames, ";")]++
+		}
+		stacks := make([]string, 0, len(counts))
+		for stack := range counts {
+			stacks = append(stacks, stack)
+		}
+		sort.Slice(stacks, func(i, j int) bool {
+			if counts[stacks[i]] != counts[stacks[j]] {
+				return counts[stacks[i]] > counts[stacks[j]]
+			}
+			return stacks[i] < stacks[j]
+		})
+
+		if outputJSON {
+			type jsonStack struct {
+				Frames []string `json:"frames"`
+				Count  int      `json:"count"`
+			}
+			out := []jsonStack{}
+			for _, stack := range stacks {
+				out = append(out, jsonStack{Frames: strings.Split(stack, ";"), Count: counts[stack]})
+			}
+			return printJSON(out)
+		}
+
+		for _, stack := range stacks {
+			fmt.Printf("%s %d\n", stack, counts[stack])
+		}
+		return nil
+	},
+}
+
+var racesCmd = &cobra.Command{
+	Use:   "races [report.txt]",
+	Short: "Group data race reports by the symbols of the racing accesses",
+	Long: `Read race detector output (e.g. from "go test -race") and group its reports by
+a fingerprint of the racing accesses: the operation and innermost symbol of
+each side, in either order. Each group is listed once with its count, most
+frequent first, so hundreds of reports reduce to the distinct races.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		f, err := os.Open(args[0])
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		reports, err := adapters.ReadRaceReports(f)
+		if err != nil {
+			return err
+		}
+
+		type group struct {
+			fingerprint uint64
+			report      *adapters.RaceReport // First report of the group
+			count       int
+		}
+		var groups []*group
+		byFP := make(map[uint64]*group)
+		for _, r := range reports {
+			fp := r.Fingerprint()
+			g, ok := byFP[fp]
+			if !ok {
+				g = &group{fingerprint: fp, report: r}
+				byFP[fp] = g
+				groups = append(groups, g)
+			}
+			g.count++
+		}
+		sort.SliceStable(groups, func(i, j int) bool { return groups[i].count > groups[j].count })
+
+		describe := func(a adapters.RaceAccess) string {
+			if sym := a.Symbol(); sym != nil {
+				return sym.Format(gsrf.WithProfile(profile))
+			}
+			return "?"
+		}
+
+		if outputJSON {
+			type jsonAccess struct {
+				Op     string `json:"op"`
+				Symbol string `json:"symbol"`
+			}
+			type jsonRace struct {
+				Fingerprint string     `json:"fingerprint"`
+				Count       int        `json:"count"`
+				Access      jsonAccess `json:"access"`
+				Previous    jsonAccess `json:"previous"`
+				Location    string     `json:"location,omitempty"`
+			}
+			out := []jsonRace{}
+			for _, g := range groups {
+				r := g.report
+				out = append(out, jsonRace{
+					Fingerprint: fmt.Sprintf("%016x", g.fingerprint),
+					Count:       g.count,
+					Access:      jsonAccess{Op: r.Access.Op, Symbol: describe(r.Access)},
+					Previous:    jsonAccess{Op: r.Previous.Op, Symbol: describe(r.Previous)},
+					Location:    r.Location,
+				})
+			}
+			return printJSON(out)
+		}
+
+		for _, g := range groups {
+			r := g.report
+			fmt.Printf("%d\t%016x\t%s %s vs %s %s\n", g.count, g.fingerprint,
+				r.Access.Op, describe(r.Access), r.Previous.Op, describe(r.Previous))
+		}
+		fmt.Printf("\nRaces: %d distinct, %d reports\n", len(groups), len(reports))
+		return nil
+	},
+}
+
+var specCmd = &cobra.Command{
+	Use:   "spec [construct]",
+	Short: "Print the grammar rule and examples for a construct",
+	Long: `Print the normative grammar rule and canonical examples of a GSRF construct
+(symbol, package, function, receiver, generics, anon, context, metadata), or
+list all constructs when none is given. Examples are checked against the parser.
+With --json-schema, print the JSON Schema of the JSON symbol encoding instead.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if specJSONSchema {
+			if len(args) > 0 {
+				return fmt.Errorf("--json-schema takes no construct")
+			}
+			_, err := os.Stdout.Write(gsrf.JSONSchema())
+			return err
+		}
+
+		constructs := gsrf.Constructs()
+		if len(args) == 1 {
+			c, ok := gsrf.LookupConstruct(args[0])
+			if !ok {
+				var names []string
+				for _, c := range constructs {
+					names = append(names, c.Name)
+				}
+				return fmt.Errorf("unknown construct %q (want one of %s)", args[0], strings.Join(names, ", "))
+			}
+			constructs = []gsrf.Construct{c}
+		}
+
+		if outputJSON {
+			type jsonExample struct {
+				Symbol string       `json:"symbol"`
+				Parsed *gsrf.Symbol `json:"parsed"`
+			}
+			type jsonConstruct struct {
+				Name     string        `json:"name"`
+				Summary  string        `json:"summary"`
+				Rule     string        `json:"rule"`
+				Examples []jsonExample `json:"examples"`
+			}
+			out := []jsonConstruct{}
+			for _, c := range constructs {
+				jc := jsonConstruct{Name: c.Name, Summary: c.Summary, Rule: c.Rule}
+				for _, ex := range c.Examples {
+					sym, err := gsrf.Parse(ex)
+					if err != nil {
+						return fmt.Errorf("%s example %q: %w", c.Name, ex, err)
+					}
+					jc.Examples = append(jc.Examples, jsonExample{Symbol: ex, Parsed: sym})
+				}
+				out = append(out, jc)
+			}
+			return printJSON(out)
+		}
+
+		if len(args) == 0 {
+			for _, c := range constructs {
+				fmt.Printf("%-10s %s\n", c.Name, c.Summary)
+			}
+			return nil
+		}
+		c := constructs[0]
+		fmt.Printf("%s: %s\n\n", c.Name, c.Summary)
+		for _, line := range strings.Split(c.Rule, "\n") {
+			fmt.Printf("  %s\n", line)
+		}
+		fmt.Println("\nExamples:")
+		for _, ex := range c.Examples {
+			fmt.Printf("  %s\n", ex)
+		}
+		return nil
+	},
+}
+
+var versionCmd = &cobra.Command{
+	Use:   "version",
+	Short: "Print version information",
+	Run: func(cmd *cobra.Command, args []string) {
+		fmt.Println("gsrf version 1.0.0")
+		fmt.Println("GSRF Specification - Latest")
+		fmt.Printf("Normalization rules: %s\n", gsrf.RulesHash)
+	},
+}
+
+func init() {
+	rootCmd.PersistentFlags().BoolVar(&outputJSON, "json", false, "Output in JSON format")
+	rootCmd.PersistentFlags().StringSliceVar(&jsonFields, "fields", nil, "Only output these dot-separated JSON key paths of each record (e.g. package,name,metadata.pos)")
+	rootCmd.PersistentFlags().StringVar(&profileName, "profile", "default", "Formatting profile (default, ascii, human, compact, machine, debug)")
+	rootCmd.PersistentFlags().StringVar(&pathStyleName, "path-style", "preserve", "Separators of source positions (slash, native, preserve)")
+	rootCmd.PersistentFlags().StringVar(&garbleMapFile, "garble-map", "", "Garble reverse map used to de-obfuscate parsed symbols")
+
+	formatCmd.Flags().StringVar(&inputFormat, "from", "gsrf", "Input format (gsrf, ssa, stacktrace)")
+
+	reconcileCmd.Flags().StringVar(&leftFormat, "from-a", "gsrf", "Input format of the first file (gsrf, ssa, stacktrace)")
+	reconcileCmd.Flags().StringVar(&rightFormat, "from-b", "gsrf", "Input format of the second file (gsrf, ssa, stacktrace)")
+	reconcileCmd.Flags().BoolVar(&foldCase, "fold-case", false, "Also match symbols ignoring case, for lowercased corpora")
+
+	licenseCmd.Flags().StringVar(&inputFormat, "from", "gsrf", "Input format (gsrf, ssa, stacktrace)")
+	licenseCmd.Flags().StringVar(&licenseBinary, "binary", "", "Go binary to read module build info from")
+	licenseCmd.Flags().StringVar(&licenseFile, "licenses", "", "Module license mapping file")
+	licenseCmd.Flags().StringVar(&licenseOnly, "only", "", "Only output symbols whose license contains this string (e.g. GPL)")
+	licenseCmd.Flags().StringVar(&moduleOlderThan, "module-older-than", "", "Only output symbols from pseudo-versioned modules whose commit is older than this age (e.g. 1y, 6mo, 30d)")
+
+	chainCmd.Flags().StringVar(&chainFrom, "from", "gsrf", "Input format ("+strings.Join(adapters.Formats(), ", ")+")")
+	chainCmd.Flags().StringVar(&chainTo, "to", "gsrf", "Output format ("+strings.Join(adapters.Formats(), ", ")+")")
+
+	lintCmd.Flags().StringVar(&inputFormat, "from", "gsrf", "Input format (gsrf, ssa, stacktrace)")
+	lintCmd.Flags().BoolVar(&lintStrict, "strict", false, "Exit with an error if any problem is found")
+	parseCmd.Flags().BoolVar(&lintStrict, "strict", false, "Reject symbols whose names violate Go naming rules")
+	parseCmd.Flags().BoolVar(&parsePartial, "partial", false, "Show what can be recovered from malformed symbols, with warnings")
+	parseCmd.Flags().StringVar(&parseVersion, "spec-version", "", "Reject symbols using constructs newer than this GSRF version (e.g. 1.0)")
+
+	cohortCmd.Flags().StringVar(&inputFormat, "from", "gsrf", "Input format (gsrf, ssa, stacktrace)")
+	cohortCmd.Flags().IntVar(&cohortDepth, "depth", gsrf.DefaultCohortPrefixDepth, "Package path segments symbols must share to be compared by name")
+	cohortCmd.Flags().IntVar(&cohortDistance, "max-distance", 0, "Maximum name edit distance (0 scales with name length)")
+	cohortCmd.Flags().IntVar(&cohortMinSize, "min-size", 2, "Only show cohorts with at least this many members")
+
+	ctxdiffCmd.Flags().StringVar(&inputFormat, "from", "gsrf", "Input format (gsrf, ssa, stacktrace)")
+	ctxdiffCmd.Flags().StringVar(&ctxA, "a", "", "First context (e.g. linux)")
+	ctxdiffCmd.Flags().StringVar(&ctxB, "b", "", "Second context (e.g. darwin)")
+
+	garbleCmd.Flags().StringVar(&inputFormat, "from", "gsrf", "Input format (gsrf, ssa, stacktrace)")
+	garbleCmd.Flags().BoolVarP(&inPlace, "in-place", "i", false, "Rewrite the file with the de-obfuscated symbols instead of printing them")
+	garbleCmd.Flags().StringVar(&backupSuffix, "backup", "", "With -i, keep the original file with this suffix (e.g. .bak)")
+
+	filterCmd.Flags().StringVar(&inputFormat, "from", "gsrf", "Input format (gsrf, ssa, stacktrace)")
+	filterCmd.Flags().StringVar(&filterTags, "tag", "", "Tag expression symbols must satisfy (e.g. 'team:payments && !deprecated')")
+
+	for _, c := range []*cobra.Command{tagAddCmd, tagRemoveCmd} {
+		c.Flags().StringVar(&inputFormat, "from", "gsrf", "Input format (gsrf, ssa, stacktrace)")
+		c.Flags().StringVar(&tagMatch, "match", "", "Only tag symbols matching this pattern")
+		c.Flags().BoolVarP(&inPlace, "in-place", "i", false, "Rewrite the file instead of printing the tagged symbols")
+		c.Flags().StringVar(&backupSuffix, "backup", "", "With -i, keep the original file with this suffix (e.g. .bak)")
+		tagCmd.AddCommand(c)
+	}
+
+	rewriteCmd.Flags().StringVar(&inputFormat, "from", "gsrf", "Input format (gsrf, ssa, stacktrace)")
+	rewriteCmd.Flags().StringVar(&rewriteRulesFile, "rules", "", "YAML file of rewrite rules")
+	rewriteCmd.Flags().BoolVarP(&inPlace, "in-place", "i", false, "Rewrite the file instead of printing the rewritten symbols")
+	rewriteCmd.Flags().StringVar(&backupSuffix, "backup", "", "With -i, keep the original file with this suffix (e.g. .bak)")
+
+	redactCmd.Flags().StringVar(&inputFormat, "from", "gsrf", "Input format (gsrf, ssa, stacktrace)")
+	redactCmd.Flags().StringVar(&redactKeyFile, "key-file", "", "File holding the secret redaction key")
+	redactCmd.Flags().StringSliceVar(&redactPrivate, "private", nil, "Private module path to redact (repeatable)")
+	redactCmd.Flags().StringVar(&redactMapFile, "map", "", "Redaction map to write, or with --restore to read")
+	redactCmd.Flags().BoolVar(&redactRestore, "restore", false, "Map placeholders back to the original names")
+
+	fingerprintCmd.Flags().StringVar(&inputFormat, "from", "gsrf", "Input format (gsrf, ssa, stacktrace)")
+	fingerprintCmd.Flags().StringSliceVar(&fpModules, "module", nil, "First-party module path to cut the trace to (repeatable)")
+	fingerprintCmd.Flags().IntVar(&fpContext, "context", 1, "Frames outside first-party modules to keep below the first-party run")
+	fingerprintCmd.Flags().BoolVar(&fpJoinWrapped, "join-wrapped", false, "Re-assemble frames wrapped across lines by log shippers")
+	fingerprintCmd.Flags().IntVar(&fpWrapWidth, "wrap-width", 0, "Line width the shipper wraps at, to also join lines that parse on their own")
+
+	budgetCmd.Flags().StringVar(&budgetMeasurements, "measurements", "", "pprof profile or YAML costs file with measured values")
+	budgetCmd.Flags().StringVar(&budgetFile, "budgets", "", "YAML budgets file or symbol file with budget metadata")
+
+	pgoCmd.Flags().StringVar(&pgoProfile, "cpu-profile", "", "PGO CPU profile (e.g. default.pgo)")
+	pgoCmd.Flags().StringVar(&pgoInlining, "inlining", "", "Compiler output of go build -gcflags=-m=2")
+	pgoCmd.Flags().StringVar(&pgoPackage, "package", "", "Package of diagnostics before the first \"# package\" header")
+	pgoCmd.Flags().Float64Var(&pgoCDF, "hot-cdf", pgo.DefaultHotCDF, "Percentage of samples covered by hot functions")
+	pgoCmd.Flags().BoolVar(&pgoHotOnly, "hot", false, "Only list hot functions")
+
+	coverCmd.Flags().StringVar(&coverDir, "dir", ".", "Module root containing go.mod and the profiled sources")
+	coverCmd.Flags().BoolVar(&coverUncovered, "uncovered", false, "List uncovered blocks and only symbols that have them")
+	coverCmd.Flags().IntVar(&coverMinSize, "min-size", 1, "Only list uncovered blocks with at least this many statements")
+
+	reachableCmd.Flags().StringSliceVar(&reachableRoots, "root", []string{"main.main"}, "Entry point symbols (repeatable)")
+	reachableCmd.Flags().BoolVar(&reachableUnreachable, "unreachable", false, "List symbols no root reaches")
+	reachableCmd.Flags().BoolVar(&reachableExported, "exported", false, "Only list exported symbols")
+
+	specCmd.Flags().BoolVar(&specJSONSchema, "json-schema", false, "Print the JSON Schema of the JSON symbol encoding")
+
+	gtreeCmd.Flags().IntVar(&gtreeMin, "min", 1, "Hide creators with fewer goroutines")
+
+	viewcoreCmd.Flags().StringVar(&viewcoreCore, "core", "", "Core dump to generate a viewcore command for, taking symbols as arguments")
+
+	perfCmd.Flags().StringVar(&perfBinary, "binary", "", "Go binary whose symbol table repairs truncated or unresolved frames")
+
+	rootCmd.AddCommand(parseCmd)
+	rootCmd.AddCommand(formatCmd)
+	rootCmd.AddCommand(convertCmd)
+	rootCmd.AddCommand(reconcileCmd)
+	rootCmd.AddCommand(licenseCmd)
+	rootCmd.AddCommand(chainCmd)
+	rootCmd.AddCommand(lintCmd)
+	rootCmd.AddCommand(cohortCmd)
+	rootCmd.AddCommand(ctxdiffCmd)
+	rootCmd.AddCommand(garbleCmd)
+	rootCmd.AddCommand(filterCmd)
+	rootCmd.AddCommand(tagCmd)
+	rootCmd.AddCommand(rewriteCmd)
+	rootCmd.AddCommand(redactCmd)
+	rootCmd.AddCommand(fingerprintCmd)
+	rootCmd.AddCommand(budgetCmd)
+	rootCmd.AddCommand(pgoCmd)
+	rootCmd.AddCommand(gtreeCmd)
+	rootCmd.AddCommand(viewcoreCmd)
+	rootCmd.AddCommand(coverCmd)
+	rootCmd.AddCommand(reachableCmd)
+	rootCmd.AddCommand(perfCmd)
+	rootCmd.AddCommand(racesCmd)
+	rootCmd.AddCommand(specCmd)
+	rootCmd.AddCommand(versionCmd)
+}
+
+// readModulePath returns the module path declared in a go.mod file.
+func readModulePath(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		if mod, ok := strings.CutPrefix(strings.TrimSpace(line), "module "); ok {
+			return strings.Trim(strings.TrimSpace(mod), `"`), nil
+		}
+	}
+	return "", fmt.Errorf("%s: no module directive", path)
+}
+
+// parseFrom converts input in the named format to a symbol.
+func parseFrom(format, input string) (*gsrf.Symbol, error) {
+	conv, ok := adapters.Lookup(format)
+	if !ok || conv.From == nil {
+		return nil, fmt.Errorf("unknown input format: %s", format)
+	}
+
+	sym, err := conv.From(input)
+	if err != nil {
+		return nil, fmt.Errorf("conversion error: %w", err)
+	}
+	if garbleMap != nil {
+		sym, _ = garbleMap.Deobfuscate(sym)
+	}
+	sym.Metadata.Position = pathStyle.Apply(sym.Metadata.Position)
+	return sym, nil
+}
+
+// projectFields reduces JSON output to the key paths in --fields, such as
+// "metadata.pos". Arrays are projected element by element, and objects
+// holding none of the requested top-level keys, like report wrappers, are
+// descended into, so paths select fields of each record.
+func projectFields(v any) any {
+	if len(jsonFields) == 0 {
+		return v
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return v // Encode reports the error
+	}
+	dec := json.NewDecoder(strings.NewReader(string(data)))
+	dec.UseNumber()
+	var doc any
+	if err := dec.Decode(&doc); err != nil {
+		return v
+	}
+	paths := make([][]string, len(jsonFields))
+	for i, f := range jsonFields {
+		paths[i] = strings.Split(f, ".")
+	}
+	return project(doc, paths)
+}
+
+func project(v any, paths [][]string) any {
+	switch v := v.(type) {
+	case []any:
+		for i := range v {
+			v[i] = project(v[i], paths)
+		}
+		return v
+	case map[string]any:
+		record := false
+		for _, p := range paths {
+			if _, ok := v[p[0]]; ok {
+				record = true
+				break
+			}
+		}
+		if !record {
+			for k, x := range v {
+				v[k] = project(x, paths)
+			}
+			return v
+		}
+		out := make(map[string]any)
+		for _, p := range paths {
+			if x, ok := lookupPath(v, p); ok {
+				setPath(out, p, x)
+			}
+		}
+		return out
+	}
+	return v
+}
+
+func lookupPath(v any, path []string) (any, bool) {
+	for _, key := range path {
+		m, ok := v.(map[string]any)
+		if !ok {
+			return nil, false
+		}
+		if v, ok = m[key]; !ok {
+			return nil, false
+		}
+	}
+	return v, true
+}
+
+func setPath(m map[string]any, path []string, v any) {
+	for _, key := range path[:len(path)-1] {
+		next, ok := m[key].(map[string]any)
+		if !ok {
+			next = make(map[string]any)
+			m[key] = next
+		}
+		m = next
+	}
+	m[path[len(path)-1]] = v
+}
+
+// contextLabel names a context for display, "base" for the empty context.
+func contextLabel(ctx string) string {
+	if ctx == "" {
+		return "base"
+	}
+	return "@" + ctx
+}
+
+// readCorpus reads one symbol per line from path, skipping blank lines
+// and # comments.
+func readCorpus(path, format string) ([]*gsrf.Symbol, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var syms []*gsrf.Symbol
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		sym, err := parseLine(format, line)
+		if err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", path, lineNo, err)
+		}
+		syms = append(syms, sym)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return syms, nil
+}
+
+// printJSON writes v as indented JSON, reduced to the --fields key paths.
+func printJSON(v any) error {
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(projectFields(v))
+}
+
+// printLines writes formatted symbols one per line, or as a JSON array
+// with --json.
+func printLines(out []string) error {
+	if outputJSON {
+		return printJSON(out)
+	}
+	for _, s := range out {
+		fmt.Println(s)
+	}
+	return nil
+}
+
+// parseLine converts one corpus line in the named format to a symbol. A
+// line that is a JSON string is unquoted first, as gsrf.Decoder does; only
+// such a line both starts and ends with a quote, since a quoted package path
+// is followed by the symbol name.
+func parseLine(format, line string) (*gsrf.Symbol, error) {
+	if len(line) > 1 && line[0] == '"' && line[len(line)-1] == '"' {
+		var text string
+		if err := json.Unmarshal([]byte(line), &text); err != nil {
+			return nil, err
+		}
+		line = text
+	}
+	return parseFrom(format, line)
+}
+
+// rewriteFile replaces each symbol line of a corpus file by rewrite's
+// result, keeping blank and comment lines as readCorpus skips them. The new
+// content is written to a temporary file in the same directory and renamed
+// over the original, so readers never see a partial file; the original's
+// permissions are kept. With a backup suffix, the original is first kept
+// under path+suffix.
+func rewriteFile(path, backup string, rewrite func(line string) (string, error)) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	lines := strings.SplitAfter(string(data), "\n")
+	var b strings.Builder
+	for i, line := range lines {
+		content := strings.TrimRight(line, "\r\n")
+		trimmed := strings.TrimSpace(content)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			b.WriteString(line)
+			continue
+		}
+		out, err := rewrite(trimmed)
+		if err != nil {
+			return fmt.Errorf("%s:%d: %w", path, i+1, err)
+		}
+		b.WriteString(out)
+		b.WriteString(line[len(content):])
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name()) // No-op once renamed
+	if _, err := tmp.WriteString(b.String()); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Chmod(info.Mode().Perm()); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if backup != "" {
+		if err := os.WriteFile(path+backup, data, info.Mode().Perm()); err != nil {
+			return err
+		}
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// readCosts reads a YAML costs file or a pprof profile.
+func readCosts(path string) (budget.Table, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	read := budget.ReadProfile
+	if isYAML(path) {
+		read = budget.ReadYAML
+	}
+	table, err := read(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return table, nil
+}
+
+func isYAML(path string) bool {
+	ext := filepath.Ext(path)
+	return ext == ".yaml" || ext == ".yml"
 }
 
 func main() {
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/kis9a/gsrf/garble"
+)
+
+func writeCorpus(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "symbols.txt")
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestReadCorpus(t *testing.T) {
+	long := "pkg.Fn{note:" + strings.Repeat("x", 100*1024) + "}"
+	path := writeCorpus(t, "# corpus\n"+
+		`"example.com/a@b".Run`+"\n"+
+		`"net/http.(*Server).Serve"`+"\n"+
+		`"\"example.com/a@b\".Run"`+"\n\n"+
+		long+"\n")
+
+	syms, err := readCorpus(path, "gsrf")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{`"example.com/a@b".Run`, "net/http.(*Server).Serve", `"example.com/a@b".Run`, long}
+	if len(syms) != len(want) {
+		t.Fatalf("readCorpus() = %d symbols, want %d", len(syms), len(want))
+	}
+	for i, sym := range syms {
+		if got := sym.String(); got != want[i] {
+			t.Errorf("symbol %d = %.60q, want %.60q", i, got, want[i])
+		}
+	}
+}
+
+func TestGarbleInPlace(t *testing.T) {
+	m, err := garble.ReadReverseMap(strings.NewReader("aNgHz8Kb example.com/app\nKF8sdnP1 Handle\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	garbleMap, inPlace, inputFormat = m, true, "gsrf"
+	t.Cleanup(func() { garbleMap, inPlace = nil, false })
+
+	path := writeCorpus(t, "# corpus\n\"example.com/a@b\".Run\naNgHz8Kb.KF8sdnP1\n")
+	if err := garbleCmd.RunE(garbleCmd, []string{path}); err != nil {
+		t.Fatal(err)
+	}
+	got, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "# corpus\n\"example.com/a@b\".Run\nexample.com/app.Handle\n"; string(got) != want {
+		t.Errorf("file = %q, want %q", got, want)
+	}
+}
+
+func TestRewriteInPlace(t *testing.T) {
+	rules := filepath.Join(t.TempDir(), "rules.yaml")
+	if err := os.WriteFile(rules, []byte("- kind: package\n  from: example.com/old\n  to: example.com/new\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	rewriteRulesFile, inPlace, inputFormat = rules, true, "gsrf"
+	t.Cleanup(func() { rewriteRulesFile, inPlace = "", false })
+
+	path := writeCorpus(t, "\"example.com/a@b\".Run\nexample.com/old.F\n")
+	if err := rewriteCmd.RunE(rewriteCmd, []string{path}); err != nil {
+		t.Fatal(err)
+	}
+	got, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "\"example.com/a@b\".Run\nexample.com/new.F\n"; string(got) != want {
+		t.Errorf("file = %q, want %q", got, want)
+	}
+}
+
+func TestTagInPlace(t *testing.T) {
+	inPlace, inputFormat, tagMatch = true, "gsrf", "example.com/..."
+	t.Cleanup(func() { inPlace, tagMatch = false, "" })
+
+	path := writeCorpus(t, "\"example.com/a@b\".Run\n\"example.com/a.b\".Run\nfmt.Println\n")
+	if err := tagAddCmd.RunE(tagAddCmd, []string{"deprecated", path}); err != nil {
+		t.Fatal(err)
+	}
+	got, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "\"example.com/a@b\".Run{tags:deprecated}\nexample.com/a.b.Run{tags:deprecated}\nfmt.Println\n"
+	if string(got) != want {
+		t.Errorf("file = %q, want %q", got, want)
+	}
+}
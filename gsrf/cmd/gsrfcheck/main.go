@@ -0,0 +1,14 @@
+// Command gsrfcheck runs the gsrfcheck analyzer as a go vet tool:
+//
+//	go vet -vettool=$(which gsrfcheck) ./...
+package main
+
+import (
+	"golang.org/x/tools/go/analysis/unitchecker"
+
+	"github.com/kis9a/gsrf/gsrfcheck"
+)
+
+func main() {
+	unitchecker.Main(gsrfcheck.Analyzer)
+}
@@ -0,0 +1,120 @@
+//go:build js && wasm
+
+// Command gsrfwasm exposes the GSRF parser to JavaScript, so web dashboards
+// can parse and pretty-print symbols client-side:
+//
+//	GOOS=js GOARCH=wasm go build -o gsrf.wasm ./cmd/gsrfwasm
+//	cp "$(go env GOROOT)/lib/wasm/wasm_exec.js" .  # misc/wasm before Go 1.24
+//
+// Once started, it sets globalThis.gsrf to an object of functions. Each
+// returns {result} on success and {error} on failure:
+//
+//	parse(input)            result is the JSON object of the symbol
+//	format(input, profile?) result is the symbol formatted with a named profile
+//	convert(input, from, to) result is the symbol converted between adapter formats
+//	lint(input)             result is the list of lint warnings
+//
+// gsrf.formats lists the adapter formats and gsrf.specVersion the
+// implemented GSRF version. Input is parsed with the limits of
+// gsrf.ParseUntrusted. index.html is an example page.
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"syscall/js"
+
+	"github.com/kis9a/gsrf"
+	"github.com/kis9a/gsrf/adapters"
+)
+
+func main() {
+	formats := []any{}
+	for _, name := range adapters.Formats() {
+		formats = append(formats, name)
+	}
+	js.Global().Set("gsrf", js.ValueOf(map[string]any{
+		"parse":       binding(parse),
+		"format":      binding(format),
+		"convert":     binding(convert),
+		"lint":        binding(lint),
+		"formats":     formats,
+		"specVersion": string(gsrf.SpecVersion),
+	}))
+	select {}
+}
+
+// binding wraps fn as a JavaScript function taking string arguments; missing
+// arguments are empty strings.
+func binding(fn func(args []string) (any, error)) js.Func {
+	return js.FuncOf(func(this js.Value, args []js.Value) any {
+		strs := make([]string, 4)
+		for i := 0; i < len(args) && i < len(strs); i++ {
+			if args[i].Type() == js.TypeString {
+				strs[i] = args[i].String()
+			}
+		}
+		result, err := fn(strs)
+		if err != nil {
+			return map[string]any{"error": err.Error()}
+		}
+		return map[string]any{"result": result}
+	})
+}
+
+func parse(args []string) (any, error) {
+	sym, err := gsrf.ParseUntrusted(args[0])
+	if err != nil {
+		return nil, err
+	}
+	data, err := json.Marshal(sym)
+	if err != nil {
+		return nil, err
+	}
+	return js.Global().Get("JSON").Call("parse", string(data)), nil
+}
+
+func format(args []string) (any, error) {
+	sym, err := gsrf.ParseUntrusted(args[0])
+	if err != nil {
+		return nil, err
+	}
+	profile := gsrf.ProfileDefault
+	if args[1] != "" {
+		if profile, err = gsrf.ParseProfile(args[1]); err != nil {
+			return nil, err
+		}
+	}
+	return sym.Format(gsrf.WithProfile(profile)), nil
+}
+
+func convert(args []string) (any, error) {
+	from, ok := adapters.Lookup(args[1])
+	if !ok || from.From == nil {
+		return nil, fmt.Errorf("unknown input format: %s", args[1])
+	}
+	to, ok := adapters.Lookup(args[2])
+	if !ok || to.To == nil {
+		return nil, fmt.Errorf("unknown output format: %s", args[2])
+	}
+	if len(args[0]) > gsrf.DefaultMaxLength {
+		return nil, &gsrf.TooLongError{Length: len(args[0]), Max: gsrf.DefaultMaxLength}
+	}
+	sym, err := from.From(args[0])
+	if err != nil {
+		return nil, err
+	}
+	return to.To(sym), nil
+}
+
+func lint(args []string) (any, error) {
+	sym, err := gsrf.ParseUntrusted(args[0])
+	if err != nil {
+		return nil, err
+	}
+	warnings := []any{}
+	for _, w := range sym.Lint() {
+		warnings = append(warnings, w.String())
+	}
+	return warnings, nil
+}
@@ -0,0 +1,214 @@
+package gsrf
+
+import (
+	"sort"
+	"strings"
+)
+
+// DefaultCohortPrefixDepth is the number of package path segments symbols
+// must share to be compared by name, e.g. "github.com/org/repo".
+const DefaultCohortPrefixDepth = 3
+
+// CohortOptions controls how Cohorts groups symbols.
+type CohortOptions struct {
+	PrefixDepth int // Package path segments that must match (0 = DefaultCohortPrefixDepth)
+	MaxDistance int // Name edit distance threshold (0 = scaled with name length)
+}
+
+// Cohort is a family of structurally similar symbols.
+type Cohort struct {
+	Pattern string // Common package and name shape, with * for the varying part
+	Members []*Symbol
+}
+
+// Cohorts groups symbols by structural similarity. Closures are grouped with
+// their parent function and sibling closures; symbols sharing a package
+// prefix are grouped when their receiver-qualified names are within the edit
+// distance threshold. Grouping is transitive. Every symbol appears in exactly
+// one cohort; cohorts are ordered by size, largest first, then by first
+// appearance, and members keep input order.
+func Cohorts(syms []*Symbol, opts CohortOptions) []Cohort {
+	if opts.PrefixDepth <= 0 {
+		opts.PrefixDepth = DefaultCohortPrefixDepth
+	}
+
+	parent := make([]int, len(syms))
+	for i := range parent {
+		parent[i] = i
+	}
+	var find func(int) int
+	find = func(i int) int {
+		if parent[i] != i {
+			parent[i] = find(parent[i])
+		}
+		return parent[i]
+	}
+	union := func(a, b int) {
+		ra, rb := find(a), find(b)
+		if ra < rb {
+			parent[rb] = ra
+		} else if rb < ra {
+			parent[ra] = rb
+		}
+	}
+
+	// Closures and their enclosing function share a parent key.
+	families := make(map[string]int)
+	for i, sym := range syms {
+		k := closureParentKey(sym)
+		if first, ok := families[k]; ok {
+			union(first, i)
+		} else {
+			families[k] = i
+		}
+	}
+
+	// Similar names within the same package prefix.
+	buckets := make(map[string][]int)
+	var bucketOrder []string
+	for i, sym := range syms {
+		b := packagePrefix(stripVendor(sym.PackagePath), opts.PrefixDepth)
+		if _, ok := buckets[b]; !ok {
+			bucketOrder = append(bucketOrder, b)
+		}
+		buckets[b] = append(buckets[b], i)
+	}
+	names := make([]string, len(syms))
+	for i, sym := range syms {
+		names[i] = qualifiedName(sym)
+	}
+	for _, b := range bucketOrder {
+		idx := buckets[b]
+		for x := 0; x < len(idx); x++ {
+			for y := x + 1; y < len(idx); y++ {
+				i, j := idx[x], idx[y]
+				if find(i) == find(j) {
+					continue
+				}
+				limit := opts.MaxDistance
+				if limit <= 0 {
+					limit = fuzzyThreshold(names[i])
+				}
+				if levenshtein(names[i], names[j]) <= limit {
+					union(i, j)
+				}
+			}
+		}
+	}
+
+	groups := make(map[int][]*Symbol)
+	var roots []int
+	for i, sym := range syms {
+		r := find(i)
+		if _, ok := groups[r]; !ok {
+			roots = append(roots, r)
+		}
+		groups[r] = append(groups[r], sym)
+	}
+	sort.SliceStable(roots, func(a, b int) bool {
+		return len(groups[roots[a]]) > len(groups[roots[b]])
+	})
+
+	cohorts := make([]Cohort, 0, len(roots))
+	for _, r := range roots {
+		cohorts = append(cohorts, Cohort{Pattern: cohortPattern(groups[r]), Members: groups[r]})
+	}
+	return cohorts
+}
+
+// closureParentKey is the canonical key of the function enclosing an
+// anonymous function, or of the symbol itself otherwise.
+func closureParentKey(s *Symbol) string {
+	if !s.IsAnonymous {
+		return canonicalKey(s)
+	}
+	c := *s
+	c.IsAnonymous = false
+	c.AnonIndex = 0
+	c.AnonParent = ""
+	return canonicalKey(&c)
+}
+
+// qualifiedName is the symbol name prefixed with its receiver type, if any.
+func qualifiedName(s *Symbol) string {
+	if s.Receiver == nil {
+		return s.Name
+	}
+	return s.Receiver.TypeName + "." + s.Name
+}
+
+// packagePrefix returns the first depth segments of a package path.
+func packagePrefix(path string, depth int) string {
+	parts := strings.SplitN(path, "/", depth+1)
+	if len(parts) > depth {
+		parts = parts[:depth]
+	}
+	return strings.Join(parts, "/")
+}
+
+// cohortPattern summarizes members as their common package path and the
+// common prefix and suffix of their local names around a "*".
+func cohortPattern(members []*Symbol) string {
+	pkgs := make([]string, len(members))
+	locals := make([]string, len(members))
+	for i, m := range members {
+		pkgs[i] = stripVendor(m.PackagePath)
+		locals[i] = strings.TrimPrefix(canonicalKey(m), pkgs[i]+".")
+	}
+
+	pkg := pkgs[0]
+	for _, p := range pkgs[1:] {
+		for pkg != p && !strings.HasPrefix(p, pkg+"/") {
+			i := strings.LastIndex(pkg, "/")
+			if i < 0 {
+				pkg = ""
+				break
+			}
+			pkg = pkg[:i]
+		}
+	}
+	switch {
+	case pkg == "":
+		pkg = "*"
+	case !allEqual(pkgs):
+		pkg += "/*"
+	}
+
+	return pkg + "." + wildcardPattern(locals)
+}
+
+// wildcardPattern returns the common rune prefix and suffix of values joined
+// by "*", or the value itself when all are equal.
+func wildcardPattern(values []string) string {
+	if allEqual(values) {
+		return values[0]
+	}
+	first := []rune(values[0])
+	prefix, suffix := len(first), len(first)
+	for _, v := range values[1:] {
+		r := []rune(v)
+		n := 0
+		for n < prefix && n < len(r) && r[n] == first[n] {
+			n++
+		}
+		prefix = n
+		m := 0
+		for m < suffix && m < len(r) && r[len(r)-1-m] == first[len(first)-1-m] {
+			m++
+		}
+		suffix = m
+		if shortest := min(len(r), len(first)); prefix+suffix > shortest {
+			suffix = shortest - prefix
+		}
+	}
+	return string(first[:prefix]) + "*" + string(first[len(first)-suffix:])
+}
+
+func allEqual(values []string) bool {
+	for _, v := range values[1:] {
+		if v != values[0] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,90 @@
+package gsrf
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCohorts(t *testing.T) {
+	input := []string{
+		"example.com/api/pb._Greeter_SayHello_Handler",
+		"main.main",
+		"example.com/api/pb._Greeter_SayBye_Handler",
+		"main.main·lit1",
+		"fmt.Println",
+		"example.com/api/pb._Greeter_SayHi_Handler",
+		"main.main·lit2",
+		"example.com/api/server.(*Server).Start",
+	}
+	var syms []*Symbol
+	for _, in := range input {
+		syms = append(syms, MustParse(in))
+	}
+
+	want := []struct {
+		pattern string
+		members []string
+	}{
+		{"example.com/api/pb._Greeter_Say*_Handler", []string{
+			"example.com/api/pb._Greeter_SayHello_Handler",
+			"example.com/api/pb._Greeter_SayBye_Handler",
+			"example.com/api/pb._Greeter_SayHi_Handler",
+		}},
+		{"main.main*", []string{"main.main", "main.main·lit1", "main.main·lit2"}},
+		{"fmt.Println", []string{"fmt.Println"}},
+		{"example.com/api/server.(*Server).Start", []string{"example.com/api/server.(*Server).Start"}},
+	}
+
+	cohorts := Cohorts(syms, CohortOptions{})
+	if len(cohorts) != len(want) {
+		t.Fatalf("Cohorts() returned %d cohorts, want %d", len(cohorts), len(want))
+	}
+	for i, c := range cohorts {
+		var members []string
+		for _, m := range c.Members {
+			members = append(members, m.Format())
+		}
+		if c.Pattern != want[i].pattern {
+			t.Errorf("cohort %d pattern = %q, want %q", i, c.Pattern, want[i].pattern)
+		}
+		if !reflect.DeepEqual(members, want[i].members) {
+			t.Errorf("cohort %d members = %v, want %v", i, members, want[i].members)
+		}
+	}
+}
+
+func TestCohorts_PrefixDepth(t *testing.T) {
+	syms := []*Symbol{
+		MustParse("example.com/org/a.(*Store).Load"),
+		MustParse("example.com/org/b.(*Store).Save"),
+	}
+
+	if got := Cohorts(syms, CohortOptions{MaxDistance: 4}); len(got) != 2 {
+		t.Errorf("depth 3: got %d cohorts, want 2", len(got))
+	}
+
+	got := Cohorts(syms, CohortOptions{PrefixDepth: 2, MaxDistance: 4})
+	if len(got) != 1 {
+		t.Fatalf("depth 2: got %d cohorts, want 1", len(got))
+	}
+	if got[0].Pattern != "example.com/org/*.(*Store).*" {
+		t.Errorf("Pattern = %q", got[0].Pattern)
+	}
+}
+
+func TestWildcardPattern(t *testing.T) {
+	tests := []struct {
+		values []string
+		want   string
+	}{
+		{[]string{"Get", "Get"}, "Get"},
+		{[]string{"GetUser", "GetOrder"}, "Get*er"},
+		{[]string{"aa", "aaa"}, "aa*"},
+		{[]string{"Name", "Other"}, "*"},
+	}
+	for _, tt := range tests {
+		if got := wildcardPattern(tt.values); got != tt.want {
+			t.Errorf("wildcardPattern(%q) = %q, want %q", tt.values, got, tt.want)
+		}
+	}
+}
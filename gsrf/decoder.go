@@ -0,0 +1,97 @@
+package gsrf
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// DefaultMaxLineLength is the longest line a Decoder reads unless
+// ParseOptions.MaxLength sets a different limit.
+const DefaultMaxLineLength = 1024 * 1024
+
+// Decoder reads symbols one at a time from a newline-delimited stream. Each
+// line is a GSRF string or an NDJSON record: a JSON object in the schema of
+// Symbol.MarshalJSON, or a JSON string holding GSRF. Blank lines and lines
+// starting with # are skipped. Only one line is held in memory at a time.
+type Decoder struct {
+	scanner *bufio.Scanner
+	opts    ParseOptions
+	line    int
+}
+
+// NewDecoder returns a decoder reading from r.
+func NewDecoder(r io.Reader) *Decoder {
+	return NewDecoderWith(r, ParseOptions{})
+}
+
+// NewDecoderWith returns a decoder that applies opts to every line.
+// MaxLength, if set, also bounds the line buffer; longer lines end decoding
+// with bufio.ErrTooLong.
+func NewDecoderWith(r io.Reader, opts ParseOptions) *Decoder {
+	max := DefaultMaxLineLength
+	if opts.MaxLength > 0 {
+		// Leave room for surrounding whitespace and JSON quoting; the
+		// symbol itself is checked against MaxLength when parsed.
+		max = 2*opts.MaxLength + 64
+	}
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, min(max, 64*1024)), max)
+	return &Decoder{scanner: scanner, opts: opts}
+}
+
+// Next returns the next symbol, or io.EOF at the end of the stream. A line
+// that fails to parse returns an error naming its line number; decoding can
+// continue past it with another call to Next. Read errors are final.
+func (d *Decoder) Next() (*Symbol, error) {
+	for d.scanner.Scan() {
+		d.line++
+		line := strings.TrimSpace(d.scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		sym, err := d.decodeLine(line)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", d.line, err)
+		}
+		return sym, nil
+	}
+	if err := d.scanner.Err(); err != nil {
+		return nil, err
+	}
+	return nil, io.EOF
+}
+
+// Line returns the number of the line last read.
+func (d *Decoder) Line() int {
+	return d.line
+}
+
+// decodeLine parses one GSRF or NDJSON line. GSRF never starts with "{", and
+// a quoted package path is followed by the symbol name, so only a JSON
+// string both starts and ends with a quote.
+func (d *Decoder) decodeLine(line string) (*Symbol, error) {
+	isObject := line[0] == '{'
+	isString := len(line) > 1 && line[0] == '"' && line[len(line)-1] == '"'
+	if !isObject && !isString {
+		return ParseWith(line, d.opts)
+	}
+
+	if isString {
+		var text string
+		if err := json.Unmarshal([]byte(line), &text); err != nil {
+			return nil, err
+		}
+		return ParseWith(text, d.opts)
+	}
+	if d.opts.MaxLength > 0 && len(line) > d.opts.MaxLength {
+		return nil, &TooLongError{Length: len(line), Max: d.opts.MaxLength}
+	}
+	sym := &Symbol{}
+	if err := json.Unmarshal([]byte(line), sym); err != nil {
+		return nil, err
+	}
+	return finishParse(sym, line, d.opts)
+}
@@ -0,0 +1,75 @@
+package gsrf
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestDecoder(t *testing.T) {
+	input := `# symbols
+fmt.Println
+
+{"package":"net/http","name":"Serve","receiver":"Server","receiver_pointer":true}
+"main.main·lit2"
+"example.com/a@b".Run
+not-a-symbol
+  pkg.(*T).Close{via:Conn,via:net.Conn}
+`
+	d := NewDecoder(strings.NewReader(input))
+	var got []string
+	var errs []error
+	for {
+		sym, err := d.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		got = append(got, sym.String())
+	}
+
+	want := []string{
+		"fmt.Println",
+		"net/http.(*Server).Serve",
+		"main.main·lit2",
+		`"example.com/a@b".Run`,
+		"pkg.(*T).Close{via:Conn,via:net.Conn}",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("decoded:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+	if len(errs) != 1 || !strings.HasPrefix(errs[0].Error(), "line 7: ") {
+		t.Errorf("errors = %v, want one error on line 7", errs)
+	}
+	if d.Line() != 8 {
+		t.Errorf("Line() = %d, want 8", d.Line())
+	}
+}
+
+func TestDecoderWith(t *testing.T) {
+	in := NewInterner()
+	d := NewDecoderWith(strings.NewReader("pkg.(int).String\n{\"package\":\"pkg\",\"name\":\"F\"}\n"), ParseOptions{Strict: true, Interner: in})
+	var style *StyleError
+	if _, err := d.Next(); !errors.As(err, &style) {
+		t.Errorf("Next() error = %v, want *StyleError", err)
+	}
+	if sym, err := d.Next(); err != nil || sym.String() != "pkg.F" || in.Len() != 1 {
+		t.Errorf("Next() = %v, %v; interned %d", sym, err, in.Len())
+	}
+
+	long := strings.Repeat("x", 200)
+	d = NewDecoderWith(strings.NewReader("pkg."+long+"\n"), ParseOptions{MaxLength: 50})
+	if _, err := d.Next(); !errors.Is(err, bufio.ErrTooLong) {
+		t.Errorf("Next() error = %v, want bufio.ErrTooLong", err)
+	}
+	d = NewDecoderWith(strings.NewReader("pkg."+long[:80]+"\n"), ParseOptions{MaxLength: 50})
+	var tooLong *TooLongError
+	if _, err := d.Next(); !errors.As(err, &tooLong) {
+		t.Errorf("Next() error = %v, want *TooLongError", err)
+	}
+}
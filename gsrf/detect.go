@@ -0,0 +1,119 @@
+package gsrf
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Format identifies a textual symbol format recognized by Detect. The values
+// match the converter names of the adapters package.
+type Format string
+
+const (
+	FormatGSRF       Format = "gsrf"       // pkg.(*T).M·lit2@linux{pos:f.go:1:1}
+	FormatSSA        Format = "ssa"        // pkg.F$1, pkg.init#1, pkg.F@f.go:1:1
+	FormatStackTrace Format = "stacktrace" // pkg.(*T).M(0xc000010000) /src/f.go:12 +0x1d
+	FormatPprof      Format = "pprof"      // pkg.(*T).M.func1, pkg.Map[...], gopkg.in/yaml%2ev3.F
+)
+
+var (
+	ssaMarkerPattern     = regexp.MustCompile(`(\$\d+|\.init#\d+|@[^@\s]+\.go:\d+:\d+)$`)
+	ssaClosurePattern    = regexp.MustCompile(`\$(\d+)((?:@.*)?)$`)
+	ssaInitPattern       = regexp.MustCompile(`\.init#\d+((?:@.*)?)$`)
+	ssaPositionPattern   = regexp.MustCompile(`^(.+)@([^@\s]+\.go:\d+:\d+)$`)
+	stackOffsetPattern   = regexp.MustCompile(`\s+\+0x[0-9a-fA-F]+$`)
+	stackLocationPattern = regexp.MustCompile(`\s+\S+\.go:\d+$`)
+	runtimeMarkerPattern = regexp.MustCompile(`\.(func|gowrap)\d+(\.\d+)*$|-fm$|\.init\.\d+$|\[\.\.\.\]|%2[eE]`)
+)
+
+// Detect reports which format input is written in. Recognition is by the
+// markers only one format produces: call arguments, file locations, or
+// program counter offsets for stack traces; "$N" closures, "init#N", or
+// "@file.go:line:col" positions for SSA; and "funcN" closures, "-fm"
+// wrappers, "init.N", "[...]" instantiations, or escaped package paths for
+// pprof (runtime) names. Input without such markers, like "fmt.Println",
+// reads the same in every format and is reported as GSRF. An error is
+// returned if the detected format cannot parse the input.
+func Detect(input string) (Format, error) {
+	_, f, err := ParseAny(input)
+	return f, err
+}
+
+// ParseAny detects the format of input, as Detect does, and parses it with
+// that format's parser, so callers processing mixed logs do not need to
+// know the format of each line in advance.
+func ParseAny(input string) (*Symbol, Format, error) {
+	s := strings.TrimSpace(input)
+	if s == "" {
+		return nil, "", fmt.Errorf("invalid GSRF symbol: empty string")
+	}
+
+	if frame, ok := stackTraceFrame(s); ok {
+		sym, err := FromRuntimeName(frame)
+		return sym, FormatStackTrace, err
+	}
+	if ssaMarkerPattern.MatchString(s) {
+		sym, err := parseSSA(s)
+		return sym, FormatSSA, err
+	}
+	if runtimeMarkerPattern.MatchString(s) {
+		sym, err := FromRuntimeName(s)
+		return sym, FormatPprof, err
+	}
+
+	sym, err := Parse(s)
+	if err == nil {
+		return sym, FormatGSRF, nil
+	}
+	// Value receivers ("pkg.T.M") are written differently by the runtime.
+	if sym, rerr := FromRuntimeName(s); rerr == nil {
+		return sym, FormatPprof, nil
+	}
+	return nil, "", err
+}
+
+// stackTraceFrame strips the file location, program counter offset, and call
+// arguments of a stack trace frame. It reports false if s has none of them.
+func stackTraceFrame(s string) (string, bool) {
+	frame := stackOffsetPattern.ReplaceAllString(s, "")
+	frame = stackLocationPattern.ReplaceAllString(frame, "")
+	found := frame != s
+	if !strings.HasSuffix(frame, ")") {
+		return frame, found
+	}
+	depth := 0
+	for i := len(frame) - 1; i >= 0; i-- {
+		switch frame[i] {
+		case ')':
+			depth++
+		case '(':
+			depth--
+			if depth == 0 {
+				// A receiver like "(*T)" is followed by ".", an argument list is not.
+				if i > 0 && frame[i-1] != '.' {
+					return frame[:i], true
+				}
+				return frame, found
+			}
+		}
+	}
+	return frame, found
+}
+
+// parseSSA parses the SSA forms that differ from GSRF: "$N" closures,
+// "init#N" initializers, and "@file.go:line:col" positions.
+func parseSSA(s string) (*Symbol, error) {
+	s = ssaClosurePattern.ReplaceAllString(s, "·lit$1$2")
+	s = ssaInitPattern.ReplaceAllString(s, ".init$1")
+	pos := ""
+	if m := ssaPositionPattern.FindStringSubmatch(s); m != nil {
+		s, pos = m[1], m[2]
+	}
+	sym, err := Parse(s)
+	if err != nil {
+		return nil, fmt.Errorf("invalid SSA symbol %q: %w", s, err)
+	}
+	sym.Metadata.Position = pos
+	return sym, nil
+}
@@ -0,0 +1,50 @@
+package gsrf
+
+import "testing"
+
+func TestParseAny(t *testing.T) {
+	tests := []struct {
+		input  string
+		format Format
+		want   string
+	}{
+		{"fmt.Println", FormatGSRF, "fmt.Println"},
+		{"pkg.(*Cache[K, V]).Get@linux{via:Base,pos:cache.go:3:1}", FormatGSRF, "pkg.(*Cache[K, V]).Get@linux{via:Base,pos:cache.go:3:1}"},
+		{"main.main·lit2", FormatGSRF, "main.main·lit2"},
+		{"main.handler$2", FormatSSA, "main.handler·lit2"},
+		{"database/sql.init#1", FormatSSA, "database/sql.init"},
+		{"net/http.(*Server).Serve@server.go:10:2", FormatSSA, "net/http.(*Server).Serve{pos:server.go:10:2}"},
+		{"main.(*Server).handle(0xc000010000, {0x1, 0x2})", FormatStackTrace, "main.(*Server).handle"},
+		{"main.main()", FormatStackTrace, "main.main"},
+		{"main.run.func1 /src/app/main.go:12 +0x1d", FormatStackTrace, "main.run·lit1"},
+		{"main.(*Server).handle.func2", FormatPprof, "main.(*Server).handle·lit2"},
+		{"pkg.Map[...]", FormatPprof, "pkg.Map[...]"},
+		{"gopkg.in/yaml%2ev3.Marshal", FormatPprof, "gopkg.in/yaml.v3.Marshal"},
+		{"pkg.init.0", FormatPprof, "pkg.init"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			sym, format, err := ParseAny(tt.input)
+			if err != nil {
+				t.Fatalf("ParseAny() error = %v", err)
+			}
+			if format != tt.format {
+				t.Errorf("ParseAny() format = %q, want %q", format, tt.format)
+			}
+			if got := sym.String(); got != tt.want {
+				t.Errorf("ParseAny() = %q, want %q", got, tt.want)
+			}
+			if f, _ := Detect(tt.input); f != tt.format {
+				t.Errorf("Detect() = %q, want %q", f, tt.format)
+			}
+		})
+	}
+}
+
+func TestParseAny_Invalid(t *testing.T) {
+	for _, input := range []string{"", "   ", "nodot", "$1"} {
+		if _, _, err := ParseAny(input); err == nil {
+			t.Errorf("ParseAny(%q) succeeded, want error", input)
+		}
+	}
+}
@@ -0,0 +1,137 @@
+package gsrf
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// Digest placeholders stand in for type argument lists too long to emit.
+// A placeholder is the only element of its bracket list, e.g.
+// pkg.Fn[⟪sha256:0123456789abcdef⟫]; the ASCII form uses << and >>.
+const (
+	digestOpen       = "⟪"
+	digestClose      = "⟫"
+	digestOpenASCII  = "<<"
+	digestCloseASCII = ">>"
+	digestAlgorithm  = "sha256:"
+	digestHexLen     = 16
+)
+
+// TypeArgsDigest returns the digest identifying a type argument list, e.g.
+// "sha256:0123456789abcdef". It is computed over the normalized arguments,
+// so spacing differences do not change the digest.
+func TypeArgsDigest(args []string) string {
+	sum := sha256.Sum256([]byte(strings.Join(normalizeTypeList(args), ", ")))
+	return digestAlgorithm + hex.EncodeToString(sum[:])[:digestHexLen]
+}
+
+// ParseDigest reports whether the type argument is a digest placeholder
+// and, if so, returns the digest it holds.
+func ParseDigest(arg string) (string, bool) {
+	for _, delim := range [][2]string{{digestOpen, digestClose}, {digestOpenASCII, digestCloseASCII}} {
+		if strings.HasPrefix(arg, delim[0]) && strings.HasSuffix(arg, delim[1]) {
+			inner := arg[len(delim[0]) : len(arg)-len(delim[1])]
+			if strings.HasPrefix(inner, digestAlgorithm) && len(inner) > len(digestAlgorithm) {
+				return inner, true
+			}
+		}
+	}
+	return "", false
+}
+
+// HasDigest reports whether any type argument list of the symbol is a
+// digest placeholder.
+func (s *Symbol) HasDigest() bool {
+	if isDigestList(s.TypeArgs) {
+		return true
+	}
+	return s.Receiver != nil && isDigestList(s.Receiver.TypeArgs)
+}
+
+// ExpandDigests replaces digest placeholders with the type arguments that
+// lookup returns for them. It fails if a digest is unknown.
+func (s *Symbol) ExpandDigests(lookup func(digest string) ([]string, bool)) error {
+	expand := func(args []string) ([]string, error) {
+		if !isDigestList(args) {
+			return args, nil
+		}
+		digest, _ := ParseDigest(args[0])
+		full, ok := lookup(digest)
+		if !ok {
+			return nil, fmt.Errorf("unknown type argument digest: %s", digest)
+		}
+		return append([]string(nil), full...), nil
+	}
+
+	args, err := expand(s.TypeArgs)
+	if err != nil {
+		return err
+	}
+	var recvArgs []string
+	if s.Receiver != nil {
+		if recvArgs, err = expand(s.Receiver.TypeArgs); err != nil {
+			return err
+		}
+	}
+
+	s.TypeArgs = args
+	if s.Receiver != nil {
+		s.Receiver.TypeArgs = recvArgs
+	}
+	return nil
+}
+
+// DigestTable remembers full type argument lists by digest so that digest
+// placeholders can be expanded later. Its Lookup method can be passed to
+// Symbol.ExpandDigests.
+type DigestTable map[string][]string
+
+// Add records the type argument lists of a full-form symbol.
+func (t DigestTable) Add(s *Symbol) {
+	if len(s.TypeArgs) > 0 && !isDigestList(s.TypeArgs) {
+		t[TypeArgsDigest(s.TypeArgs)] = append([]string(nil), s.TypeArgs...)
+	}
+	if s.Receiver != nil && len(s.Receiver.TypeArgs) > 0 && !isDigestList(s.Receiver.TypeArgs) {
+		t[TypeArgsDigest(s.Receiver.TypeArgs)] = append([]string(nil), s.Receiver.TypeArgs...)
+	}
+}
+
+// Lookup returns the type arguments recorded for digest.
+func (t DigestTable) Lookup(digest string) ([]string, bool) {
+	args, ok := t[digest]
+	return args, ok
+}
+
+// withDigests returns a shallow copy of s with every type argument list
+// replaced by its digest placeholder.
+func (s *Symbol) withDigests(ascii bool) *Symbol {
+	placeholder := func(args []string) []string {
+		if len(args) == 0 || isDigestList(args) {
+			return args
+		}
+		if ascii {
+			return []string{digestOpenASCII + TypeArgsDigest(args) + digestCloseASCII}
+		}
+		return []string{digestOpen + TypeArgsDigest(args) + digestClose}
+	}
+
+	c := *s
+	c.TypeArgs = placeholder(s.TypeArgs)
+	if s.Receiver != nil {
+		r := *s.Receiver
+		r.TypeArgs = placeholder(s.Receiver.TypeArgs)
+		c.Receiver = &r
+	}
+	return &c
+}
+
+// isDigestList reports whether args is a single digest placeholder.
+func isDigestList(args []string) bool {
+	if len(args) != 1 {
+		return false
+	}
+	_, ok := ParseDigest(args[0])
+	return ok
+}
@@ -0,0 +1,133 @@
+package gsrf
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestParseWith_MaxLength(t *testing.T) {
+	input := "pkg.Fn[" + strings.Repeat("Map[K, ", 20) + "V" + strings.Repeat("]", 20) + "]"
+
+	_, err := ParseWith(input, ParseOptions{MaxLength: 64})
+	var tooLong *TooLongError
+	if !errors.As(err, &tooLong) {
+		t.Fatalf("ParseWith() error = %v, want *TooLongError", err)
+	}
+	if tooLong.Length != len(input) || tooLong.Max != 64 {
+		t.Errorf("TooLongError = %+v", tooLong)
+	}
+
+	if _, err := ParseWith(input, ParseOptions{}); err != nil {
+		t.Errorf("ParseWith() without limit error = %v", err)
+	}
+}
+
+func TestParseWith_MaxBracketDepth(t *testing.T) {
+	input := "pkg.Fn[" + strings.Repeat("[]Map[K, ", 10) + "V" + strings.Repeat("]", 10) + "]"
+
+	_, err := ParseWith(input, ParseOptions{MaxBracketDepth: 8})
+	var tooDeep *TooDeepError
+	if !errors.As(err, &tooDeep) {
+		t.Fatalf("ParseWith() error = %v, want *TooDeepError", err)
+	}
+	if tooDeep.Max != 8 || input[tooDeep.Offset] != '[' {
+		t.Errorf("TooDeepError = %+v", tooDeep)
+	}
+
+	if _, err := ParseWith(input, ParseOptions{MaxBracketDepth: 32}); err != nil {
+		t.Errorf("ParseWith() within limit error = %v", err)
+	}
+}
+
+func TestParseUntrusted(t *testing.T) {
+	if _, err := ParseUntrusted("net/http.(*Server).Serve@linux{pos:server.go:10:1}"); err != nil {
+		t.Errorf("ParseUntrusted() error = %v", err)
+	}
+
+	var tooLong *TooLongError
+	if _, err := ParseUntrusted("pkg." + strings.Repeat("A", DefaultMaxLength)); !errors.As(err, &tooLong) {
+		t.Errorf("ParseUntrusted() long input error = %v, want *TooLongError", err)
+	}
+
+	var tooDeep *TooDeepError
+	nested := "pkg.F[" + strings.Repeat("[", 1000) + strings.Repeat("]", 1000) + "]"
+	if _, err := ParseUntrusted(nested); !errors.As(err, &tooDeep) {
+		t.Errorf("ParseUntrusted() nested input error = %v, want *TooDeepError", err)
+	}
+
+	// Unmatched closing brackets, here in a quoted package path, do not
+	// lower the depth.
+	bypass := `"` + strings.Repeat(")", 45) + `".F[` + strings.Repeat("[", 40) + "int" + strings.Repeat("]", 41)
+	if _, err := ParseUntrusted(bypass); !errors.As(err, &tooDeep) {
+		t.Errorf("ParseUntrusted() with unmatched closers error = %v, want *TooDeepError", err)
+	}
+	unmatched := "pkg.F" + strings.Repeat(")", 45) + "[" + strings.Repeat("[", 40) + "int" + strings.Repeat("]", 41)
+	if _, err := ParseWith(unmatched, ParseOptions{MaxBracketDepth: 32}); !errors.As(err, &tooDeep) {
+		t.Errorf("ParseWith() with unmatched closers error = %v, want *TooDeepError", err)
+	}
+}
+
+func TestFormatWith_DigestOver(t *testing.T) {
+	sym := &Symbol{
+		PackagePath: "pkg",
+		Name:        "Fn",
+		TypeArgs:    []string{"map[string][]Pair[int, float64]", "chan<- func(context.Context) error"},
+	}
+	full := sym.Format()
+
+	if got := sym.FormatWith(FormatOptions{DigestOver: len(full)}); got != full {
+		t.Errorf("FormatWith() under limit = %q, want %q", got, full)
+	}
+
+	digested := sym.FormatWith(FormatOptions{DigestOver: 20})
+	want := "pkg.Fn[⟪" + TypeArgsDigest(sym.TypeArgs) + "⟫]"
+	if digested != want {
+		t.Errorf("FormatWith() = %q, want %q", digested, want)
+	}
+
+	ascii := sym.FormatWith(FormatOptions{DigestOver: 20, ASCII: true})
+	if ascii != "pkg.Fn[<<"+TypeArgsDigest(sym.TypeArgs)+">>]" {
+		t.Errorf("FormatWith(ASCII) = %q", ascii)
+	}
+}
+
+func TestDigestRoundTrip(t *testing.T) {
+	full := MustParse("pkg.(*Cache[string, map[string][]int]).Get[Key[string], Value[map[string][]int]]")
+
+	table := DigestTable{}
+	table.Add(full)
+
+	for _, ascii := range []bool{false, true} {
+		short := full.FormatWith(FormatOptions{DigestOver: 10, ASCII: ascii})
+		sym, err := Parse(short)
+		if err != nil {
+			t.Fatalf("Parse(%q) error = %v", short, err)
+		}
+		if !sym.HasDigest() {
+			t.Fatalf("HasDigest() = false for %q", short)
+		}
+		if err := sym.ExpandDigests(table.Lookup); err != nil {
+			t.Fatalf("ExpandDigests() error = %v", err)
+		}
+		if !sym.Equal(full) {
+			t.Errorf("round trip = %q, want %q", sym.Format(), full.Format())
+		}
+	}
+
+	unknown := MustParse("pkg.Fn[⟪sha256:0000000000000000⟫]")
+	if err := unknown.ExpandDigests(table.Lookup); err == nil {
+		t.Error("ExpandDigests() with unknown digest expected error")
+	}
+}
+
+func TestTypeArgsDigest_IgnoresSpacing(t *testing.T) {
+	a := TypeArgsDigest([]string{"Map[K,V]", "int"})
+	b := TypeArgsDigest([]string{"Map[K, V]", " int"})
+	if a != b {
+		t.Errorf("TypeArgsDigest() differs by spacing: %q vs %q", a, b)
+	}
+	if !strings.HasPrefix(a, "sha256:") || len(a) != len("sha256:")+16 {
+		t.Errorf("TypeArgsDigest() = %q", a)
+	}
+}
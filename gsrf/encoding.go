@@ -0,0 +1,31 @@
+package gsrf
+
+// MarshalText implements encoding.TextMarshaler using the GSRF string form.
+func (s *Symbol) MarshalText() ([]byte, error) {
+	return []byte(s.Format()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler by parsing a GSRF string.
+func (s *Symbol) UnmarshalText(text []byte) error {
+	sym, err := Parse(string(text))
+	if err != nil {
+		return err
+	}
+	*s = *sym
+	return nil
+}
+
+// MarshalText implements encoding.TextMarshaler using the GSRF string form.
+func (k SymbolKey) MarshalText() ([]byte, error) {
+	return []byte(k.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler by parsing a GSRF string.
+func (k *SymbolKey) UnmarshalText(text []byte) error {
+	key, err := KeyOf(string(text))
+	if err != nil {
+		return err
+	}
+	*k = key
+	return nil
+}
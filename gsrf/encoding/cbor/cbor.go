@@ -0,0 +1,252 @@
+// Package cbor encodes GSRF symbols as CBOR (RFC 8949) maps using the keys of
+// the JSON object schema. Map keys are written in byte order, so equal
+// symbols encode to equal bytes.
+package cbor
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+
+	"github.com/kis9a/gsrf"
+	"github.com/kis9a/gsrf/internal/schema"
+)
+
+// CBOR major types.
+const (
+	majorUint   = 0
+	majorNegInt = 1
+	majorBytes  = 2
+	majorText   = 3
+	majorArray  = 4
+	majorMap    = 5
+	majorTag    = 6
+	majorSimple = 7
+)
+
+// Marshal encodes the symbol as a CBOR map.
+func Marshal(s *gsrf.Symbol) ([]byte, error) {
+	v, err := schema.ToValue(s)
+	if err != nil {
+		return nil, err
+	}
+	return appendValue(nil, v)
+}
+
+// Unmarshal decodes a symbol from a CBOR map or text string. Tags are
+// skipped; indefinite-length items are not supported.
+func Unmarshal(data []byte) (*gsrf.Symbol, error) {
+	d := decoder{buf: data}
+	v, err := d.value(0)
+	if err != nil {
+		return nil, err
+	}
+	if len(d.buf) != 0 {
+		return nil, fmt.Errorf("cbor: %d trailing bytes", len(d.buf))
+	}
+	return schema.FromValue(v)
+}
+
+func appendHeader(buf []byte, major byte, n uint64) []byte {
+	m := major << 5
+	switch {
+	case n < 24:
+		return append(buf, m|byte(n))
+	case n <= math.MaxUint8:
+		return append(buf, m|24, byte(n))
+	case n <= math.MaxUint16:
+		return binary.BigEndian.AppendUint16(append(buf, m|25), uint16(n))
+	case n <= math.MaxUint32:
+		return binary.BigEndian.AppendUint32(append(buf, m|26), uint32(n))
+	}
+	return binary.BigEndian.AppendUint64(append(buf, m|27), n)
+}
+
+func appendValue(buf []byte, v any) ([]byte, error) {
+	switch x := v.(type) {
+	case nil:
+		return append(buf, 0xf6), nil
+	case bool:
+		if x {
+			return append(buf, 0xf5), nil
+		}
+		return append(buf, 0xf4), nil
+	case int64:
+		if x < 0 {
+			return appendHeader(buf, majorNegInt, uint64(-1-x)), nil
+		}
+		return appendHeader(buf, majorUint, uint64(x)), nil
+	case float64:
+		return binary.BigEndian.AppendUint64(append(buf, 0xfb), math.Float64bits(x)), nil
+	case string:
+		return append(appendHeader(buf, majorText, uint64(len(x))), x...), nil
+	case []any:
+		buf = appendHeader(buf, majorArray, uint64(len(x)))
+		for _, e := range x {
+			var err error
+			if buf, err = appendValue(buf, e); err != nil {
+				return nil, err
+			}
+		}
+		return buf, nil
+	case map[string]any:
+		buf = appendHeader(buf, majorMap, uint64(len(x)))
+		for _, k := range schema.SortedKeys(x) {
+			buf = append(appendHeader(buf, majorText, uint64(len(k))), k...)
+			var err error
+			if buf, err = appendValue(buf, x[k]); err != nil {
+				return nil, err
+			}
+		}
+		return buf, nil
+	}
+	return nil, fmt.Errorf("cbor: unsupported type %T", v)
+}
+
+type decoder struct {
+	buf []byte
+}
+
+func (d *decoder) header() (major byte, info byte, n uint64, err error) {
+	if len(d.buf) == 0 {
+		return 0, 0, 0, fmt.Errorf("cbor: unexpected end of input")
+	}
+	major, info = d.buf[0]>>5, d.buf[0]&0x1f
+	d.buf = d.buf[1:]
+	var size int
+	switch {
+	case info < 24:
+		return major, info, uint64(info), nil
+	case info == 24:
+		size = 1
+	case info == 25:
+		size = 2
+	case info == 26:
+		size = 4
+	case info == 27:
+		size = 8
+	default:
+		return 0, 0, 0, fmt.Errorf("cbor: unsupported additional info %d", info)
+	}
+	if len(d.buf) < size {
+		return 0, 0, 0, fmt.Errorf("cbor: unexpected end of input")
+	}
+	for _, b := range d.buf[:size] {
+		n = n<<8 | uint64(b)
+	}
+	d.buf = d.buf[size:]
+	return major, info, n, nil
+}
+
+// length checks a string or container length against the remaining input,
+// so corrupt data cannot trigger large allocations.
+func (d *decoder) length(n uint64) (int, error) {
+	if n > uint64(len(d.buf)) {
+		return 0, fmt.Errorf("cbor: length %d exceeds input", n)
+	}
+	return int(n), nil
+}
+
+func (d *decoder) value(depth int) (any, error) {
+	if depth > schema.MaxDepth {
+		return nil, fmt.Errorf("cbor: nesting exceeds %d levels", schema.MaxDepth)
+	}
+	major, info, n, err := d.header()
+	if err != nil {
+		return nil, err
+	}
+
+	switch major {
+	case majorUint:
+		if n > math.MaxInt64 {
+			return float64(n), nil
+		}
+		return int64(n), nil
+	case majorNegInt:
+		if n > math.MaxInt64 {
+			return -1 - float64(n), nil
+		}
+		return -1 - int64(n), nil
+	case majorBytes, majorText:
+		size, err := d.length(n)
+		if err != nil {
+			return nil, err
+		}
+		s := string(d.buf[:size])
+		d.buf = d.buf[size:]
+		return s, nil
+	case majorArray:
+		size, err := d.length(n)
+		if err != nil {
+			return nil, err
+		}
+		list := make([]any, size)
+		for i := range list {
+			if list[i], err = d.value(depth + 1); err != nil {
+				return nil, err
+			}
+		}
+		return list, nil
+	case majorMap:
+		size, err := d.length(n)
+		if err != nil {
+			return nil, err
+		}
+		m := make(map[string]any, size)
+		for i := 0; i < size; i++ {
+			k, err := d.value(depth + 1)
+			if err != nil {
+				return nil, err
+			}
+			key, ok := k.(string)
+			if !ok {
+				return nil, fmt.Errorf("cbor: map key of type %T", k)
+			}
+			if m[key], err = d.value(depth + 1); err != nil {
+				return nil, err
+			}
+		}
+		return m, nil
+	case majorTag:
+		return d.value(depth + 1)
+	}
+
+	switch info {
+	case 20:
+		return false, nil
+	case 21:
+		return true, nil
+	case 22, 23:
+		return nil, nil
+	case 25:
+		return halfToFloat(uint16(n)), nil
+	case 26:
+		return float64(math.Float32frombits(uint32(n))), nil
+	case 27:
+		return math.Float64frombits(n), nil
+	}
+	return nil, fmt.Errorf("cbor: unsupported simple value %d", n)
+}
+
+// halfToFloat converts an IEEE 754 half-precision value.
+func halfToFloat(h uint16) float64 {
+	exp := int(h>>10) & 0x1f
+	mant := float64(h & 0x3ff)
+	var f float64
+	switch exp {
+	case 0:
+		f = math.Ldexp(mant, -24)
+	case 31:
+		if mant == 0 {
+			f = math.Inf(1)
+		} else {
+			f = math.NaN()
+		}
+	default:
+		f = math.Ldexp(mant+1024, exp-25)
+	}
+	if h&0x8000 != 0 {
+		return -f
+	}
+	return f
+}
@@ -0,0 +1,268 @@
+// Package msgpack encodes GSRF symbols as MessagePack maps using the keys of
+// the JSON object schema. Map keys are written in byte order, so equal
+// symbols encode to equal bytes.
+package msgpack
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+
+	"github.com/kis9a/gsrf"
+	"github.com/kis9a/gsrf/internal/schema"
+)
+
+// Marshal encodes the symbol as a MessagePack map.
+func Marshal(s *gsrf.Symbol) ([]byte, error) {
+	v, err := schema.ToValue(s)
+	if err != nil {
+		return nil, err
+	}
+	return appendValue(nil, v)
+}
+
+// Unmarshal decodes a symbol from a MessagePack map or string. Extension
+// types are not supported.
+func Unmarshal(data []byte) (*gsrf.Symbol, error) {
+	d := decoder{buf: data}
+	v, err := d.value(0)
+	if err != nil {
+		return nil, err
+	}
+	if len(d.buf) != 0 {
+		return nil, fmt.Errorf("msgpack: %d trailing bytes", len(d.buf))
+	}
+	return schema.FromValue(v)
+}
+
+// appendLength writes a fix, 8-, 16- or 32-bit length header. fixMax is the
+// largest length the fix form holds; code8 is 0 for types without an 8-bit form.
+func appendLength(buf []byte, n int, fix byte, fixMax int, code8, code16, code32 byte) []byte {
+	switch {
+	case n <= fixMax:
+		return append(buf, fix|byte(n))
+	case code8 != 0 && n <= math.MaxUint8:
+		return append(buf, code8, byte(n))
+	case n <= math.MaxUint16:
+		return binary.BigEndian.AppendUint16(append(buf, code16), uint16(n))
+	}
+	return binary.BigEndian.AppendUint32(append(buf, code32), uint32(n))
+}
+
+func appendString(buf []byte, s string) []byte {
+	return append(appendLength(buf, len(s), 0xa0, 31, 0xd9, 0xda, 0xdb), s...)
+}
+
+func appendValue(buf []byte, v any) ([]byte, error) {
+	switch x := v.(type) {
+	case nil:
+		return append(buf, 0xc0), nil
+	case bool:
+		if x {
+			return append(buf, 0xc3), nil
+		}
+		return append(buf, 0xc2), nil
+	case int64:
+		switch {
+		case x >= 0 && x <= math.MaxInt8:
+			return append(buf, byte(x)), nil
+		case x < 0 && x >= -32:
+			return append(buf, byte(int8(x))), nil
+		case x >= math.MinInt32 && x <= math.MaxInt32:
+			return binary.BigEndian.AppendUint32(append(buf, 0xd2), uint32(int32(x))), nil
+		}
+		return binary.BigEndian.AppendUint64(append(buf, 0xd3), uint64(x)), nil
+	case float64:
+		return binary.BigEndian.AppendUint64(append(buf, 0xcb), math.Float64bits(x)), nil
+	case string:
+		return appendString(buf, x), nil
+	case []any:
+		buf = appendLength(buf, len(x), 0x90, 15, 0, 0xdc, 0xdd)
+		for _, e := range x {
+			var err error
+			if buf, err = appendValue(buf, e); err != nil {
+				return nil, err
+			}
+		}
+		return buf, nil
+	case map[string]any:
+		buf = appendLength(buf, len(x), 0x80, 15, 0, 0xde, 0xdf)
+		for _, k := range schema.SortedKeys(x) {
+			buf = appendString(buf, k)
+			var err error
+			if buf, err = appendValue(buf, x[k]); err != nil {
+				return nil, err
+			}
+		}
+		return buf, nil
+	}
+	return nil, fmt.Errorf("msgpack: unsupported type %T", v)
+}
+
+type decoder struct {
+	buf []byte
+}
+
+func (d *decoder) read(n int) ([]byte, error) {
+	if n > len(d.buf) {
+		return nil, fmt.Errorf("msgpack: unexpected end of input")
+	}
+	b := d.buf[:n]
+	d.buf = d.buf[n:]
+	return b, nil
+}
+
+// uint reads a big-endian unsigned integer of size bytes.
+func (d *decoder) uint(size int) (uint64, error) {
+	b, err := d.read(size)
+	if err != nil {
+		return 0, err
+	}
+	var n uint64
+	for _, c := range b {
+		n = n<<8 | uint64(c)
+	}
+	return n, nil
+}
+
+// length checks a string or container length against the remaining input,
+// so corrupt data cannot trigger large allocations.
+func (d *decoder) length(n uint64) (int, error) {
+	if n > uint64(len(d.buf)) {
+		return 0, fmt.Errorf("msgpack: length %d exceeds input", n)
+	}
+	return int(n), nil
+}
+
+func (d *decoder) value(depth int) (any, error) {
+	if depth > schema.MaxDepth {
+		return nil, fmt.Errorf("msgpack: nesting exceeds %d levels", schema.MaxDepth)
+	}
+	b, err := d.read(1)
+	if err != nil {
+		return nil, err
+	}
+	c := b[0]
+
+	switch {
+	case c <= 0x7f:
+		return int64(c), nil
+	case c >= 0xe0:
+		return int64(int8(c)), nil
+	case c&0xe0 == 0xa0:
+		return d.str(uint64(c & 0x1f))
+	case c&0xf0 == 0x90:
+		return d.array(uint64(c&0x0f), depth)
+	case c&0xf0 == 0x80:
+		return d.mapping(uint64(c&0x0f), depth)
+	}
+
+	switch c {
+	case 0xc0:
+		return nil, nil
+	case 0xc2:
+		return false, nil
+	case 0xc3:
+		return true, nil
+	case 0xcc, 0xcd, 0xce, 0xcf:
+		n, err := d.uint(1 << (c - 0xcc))
+		if err != nil {
+			return nil, err
+		}
+		if n > math.MaxInt64 {
+			return float64(n), nil
+		}
+		return int64(n), nil
+	case 0xd0, 0xd1, 0xd2, 0xd3:
+		size := 1 << (c - 0xd0)
+		n, err := d.uint(size)
+		if err != nil {
+			return nil, err
+		}
+		shift := 64 - 8*size
+		return int64(n<<shift) >> shift, nil
+	case 0xca:
+		n, err := d.uint(4)
+		if err != nil {
+			return nil, err
+		}
+		return float64(math.Float32frombits(uint32(n))), nil
+	case 0xcb:
+		n, err := d.uint(8)
+		if err != nil {
+			return nil, err
+		}
+		return math.Float64frombits(n), nil
+	case 0xd9, 0xda, 0xdb, 0xc4, 0xc5, 0xc6:
+		size := 1
+		switch c {
+		case 0xda, 0xc5:
+			size = 2
+		case 0xdb, 0xc6:
+			size = 4
+		}
+		n, err := d.uint(size)
+		if err != nil {
+			return nil, err
+		}
+		return d.str(n)
+	case 0xdc, 0xdd:
+		n, err := d.uint(2 << (c - 0xdc))
+		if err != nil {
+			return nil, err
+		}
+		return d.array(n, depth)
+	case 0xde, 0xdf:
+		n, err := d.uint(2 << (c - 0xde))
+		if err != nil {
+			return nil, err
+		}
+		return d.mapping(n, depth)
+	}
+	return nil, fmt.Errorf("msgpack: unsupported type code 0x%02x", c)
+}
+
+func (d *decoder) str(n uint64) (any, error) {
+	size, err := d.length(n)
+	if err != nil {
+		return nil, err
+	}
+	b, _ := d.read(size)
+	return string(b), nil
+}
+
+func (d *decoder) array(n uint64, depth int) (any, error) {
+	size, err := d.length(n)
+	if err != nil {
+		return nil, err
+	}
+	list := make([]any, size)
+	for i := range list {
+		if list[i], err = d.value(depth + 1); err != nil {
+			return nil, err
+		}
+	}
+	return list, nil
+}
+
+func (d *decoder) mapping(n uint64, depth int) (any, error) {
+	size, err := d.length(n)
+	if err != nil {
+		return nil, err
+	}
+	m := make(map[string]any, size)
+	for i := 0; i < size; i++ {
+		k, err := d.value(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		key, ok := k.(string)
+		if !ok {
+			return nil, fmt.Errorf("msgpack: map key of type %T", k)
+		}
+		if m[key], err = d.value(depth + 1); err != nil {
+			return nil, err
+		}
+	}
+	return m, nil
+}
@@ -0,0 +1,121 @@
+package msgpack
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/kis9a/gsrf"
+)
+
+var seeds = []string{
+	"fmt.Println",
+	"database/sql.init",
+	"pkg.(*Cache[K, V]).Get[string]@linux",
+	"main.main·lit2",
+	"pkg.Map[K comparable, V any]",
+	"io.(*MultiWriter).Write{via:Writer,pos:multi.go:25:1,team:io}",
+}
+
+func TestMarshal(t *testing.T) {
+	data, err := Marshal(gsrf.MustParse("fmt.Println"))
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	if got, want := hex.EncodeToString(data), "82a46e616d65a75072696e746c6ea77061636b616765a3666d74"; got != want {
+		t.Errorf("Marshal() = %s, want %s", got, want)
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	for _, in := range seeds {
+		t.Run(in, func(t *testing.T) {
+			sym := gsrf.MustParse(in)
+			data, err := Marshal(sym)
+			if err != nil {
+				t.Fatalf("Marshal() error = %v", err)
+			}
+			js, _ := json.Marshal(sym)
+			if len(data) >= len(js) {
+				t.Errorf("encoded size %d not smaller than JSON size %d", len(data), len(js))
+			}
+			back, err := Unmarshal(data)
+			if err != nil {
+				t.Fatalf("Unmarshal() error = %v", err)
+			}
+			if !back.Equal(sym) {
+				t.Errorf("round trip = %q, want %q", back.Format(), in)
+			}
+		})
+	}
+}
+
+func TestUnmarshalErrors(t *testing.T) {
+	valid, _ := Marshal(gsrf.MustParse("pkg.(*T).M@linux"))
+
+	tests := map[string][]byte{
+		"empty":     nil,
+		"truncated": valid[:len(valid)-1],
+		"trailing":  append(append([]byte{}, valid...), valid[0]),
+		"not a map": {0x01},
+	}
+	for name, data := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := Unmarshal(data); err == nil {
+				t.Errorf("Unmarshal(%x) expected error", data)
+			}
+		})
+	}
+}
+
+func FuzzRoundTrip(f *testing.F) {
+	for _, s := range seeds {
+		f.Add(s)
+	}
+	f.Fuzz(func(t *testing.T, input string) {
+		// Strings pass through the JSON schema, which requires valid UTF-8.
+		if !utf8.ValidString(input) {
+			return
+		}
+		sym, err := gsrf.Parse(input)
+		if err != nil {
+			return
+		}
+		// An empty receiver type name has no representation in the schema.
+		if sym.Receiver != nil && sym.Receiver.TypeName == "" {
+			return
+		}
+		data, err := Marshal(sym)
+		if err != nil {
+			t.Fatalf("Marshal(%q) error = %v", input, err)
+		}
+		back, err := Unmarshal(data)
+		if err != nil {
+			t.Fatalf("Unmarshal(Marshal(%q)) error = %v", input, err)
+		}
+		if !back.Equal(sym) {
+			t.Errorf("round trip of %q = %+v, want %+v", input, back, sym)
+		}
+	})
+}
+
+func FuzzUnmarshal(f *testing.F) {
+	for _, s := range seeds {
+		data, _ := Marshal(gsrf.MustParse(s))
+		f.Add(data)
+	}
+	f.Fuzz(func(t *testing.T, data []byte) {
+		sym, err := Unmarshal(data)
+		if err != nil {
+			return
+		}
+		again, err := Marshal(sym)
+		if err != nil {
+			t.Fatalf("Marshal() error = %v", err)
+		}
+		if _, err := Unmarshal(again); err != nil {
+			t.Fatalf("Unmarshal(Marshal()) error = %v", err)
+		}
+	})
+}
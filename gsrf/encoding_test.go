@@ -0,0 +1,75 @@
+package gsrf
+
+import (
+	"encoding"
+	"encoding/json"
+	"testing"
+)
+
+var (
+	_ encoding.TextMarshaler   = (*Symbol)(nil)
+	_ encoding.TextUnmarshaler = (*Symbol)(nil)
+	_ encoding.TextMarshaler   = SymbolKey{}
+	_ encoding.TextUnmarshaler = (*SymbolKey)(nil)
+)
+
+func TestSymbol_TextRoundTrip(t *testing.T) {
+	inputs := []string{
+		"fmt.Println",
+		"pkg.(*Cache[K, V]).Get@linux{via:Base}",
+		"main.main·lit2",
+	}
+
+	for _, in := range inputs {
+		t.Run(in, func(t *testing.T) {
+			text, err := MustParse(in).MarshalText()
+			if err != nil {
+				t.Fatalf("MarshalText() error = %v", err)
+			}
+			if string(text) != in {
+				t.Errorf("MarshalText() = %q, want %q", text, in)
+			}
+
+			var sym Symbol
+			if err := sym.UnmarshalText(text); err != nil {
+				t.Fatalf("UnmarshalText() error = %v", err)
+			}
+			if !sym.Equal(MustParse(in)) {
+				t.Errorf("UnmarshalText() = %+v", sym)
+			}
+		})
+	}
+
+	var sym Symbol
+	if err := sym.UnmarshalText([]byte("invalid")); err == nil {
+		t.Error("UnmarshalText(\"invalid\") expected error")
+	}
+}
+
+func TestSymbol_TextInJSON(t *testing.T) {
+	type config struct {
+		Entry  *Symbol           `json:"entry"`
+		Counts map[SymbolKey]int `json:"counts"`
+	}
+
+	in := config{
+		Entry:  MustParse("net/http.(*Server).Serve"),
+		Counts: map[SymbolKey]int{MustParse("fmt.Println").Key(): 3},
+	}
+	data, err := json.Marshal(in)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	want := `{"entry":{"package":"net/http","name":"Serve","receiver":"Server","receiver_pointer":true},"counts":{"fmt.Println":3}}`
+	if string(data) != want {
+		t.Errorf("Marshal() = %s, want %s", data, want)
+	}
+
+	var out config
+	if err := json.Unmarshal(data, &out); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	if !out.Entry.Equal(in.Entry) || out.Counts[MustParse("fmt.Println").Key()] != 3 {
+		t.Errorf("Unmarshal() = %+v", out)
+	}
+}
@@ -0,0 +1,161 @@
+package gsrf
+
+import (
+	"slices"
+	"strings"
+)
+
+// Equal reports whether two symbols are structurally identical. Nil and
+// empty slices or maps are treated as equal; everything else, including
+// metadata and the spelling of type arguments and constraints, must match
+// exactly. Equivalent compares type expressions and constraints loosely.
+func (s *Symbol) Equal(other *Symbol) bool {
+	if s == nil || other == nil {
+		return s == other
+	}
+	return s.PackagePath == other.PackagePath &&
+		s.Name == other.Name &&
+		s.IsInit == other.IsInit &&
+		s.IsAnonymous == other.IsAnonymous &&
+		s.AnonParent == other.AnonParent &&
+		s.AnonIndex == other.AnonIndex &&
+		s.Context == other.Context &&
+		s.Receiver.Equal(other.Receiver) &&
+		slices.Equal(s.TypeArgs, other.TypeArgs) &&
+		slices.Equal(s.TypeParams, other.TypeParams) &&
+		s.Metadata.Equal(other.Metadata)
+}
+
+// Equal reports whether two receivers are structurally identical.
+func (r *Receiver) Equal(other *Receiver) bool {
+	if r == nil || other == nil {
+		return r == other
+	}
+	return r.TypeName == other.TypeName &&
+		r.IsPointer == other.IsPointer &&
+		slices.Equal(r.TypeArgs, other.TypeArgs)
+}
+
+// Equal reports whether two metadata values hold the same entries.
+func (m Metadata) Equal(other Metadata) bool {
+	if !slices.Equal(m.Via, other.Via) || m.Alias != other.Alias || m.Position != other.Position {
+		return false
+	}
+	if len(m.Custom) != len(other.Custom) {
+		return false
+	}
+	for k, v := range m.Custom {
+		if ov, ok := other.Custom[k]; !ok || ov != v {
+			return false
+		}
+	}
+	return true
+}
+
+// equivalence holds the relaxations applied by Equivalent.
+type equivalence struct {
+	compareMetadata bool
+	ignorePointer   bool
+	ignoreContext   bool
+	ignoreAnonIndex bool
+}
+
+// EquivalenceOption relaxes or tightens the comparison done by Equivalent.
+type EquivalenceOption func(*equivalence)
+
+// CompareMetadata makes Equivalent also require equal metadata.
+func CompareMetadata() EquivalenceOption {
+	return func(e *equivalence) { e.compareMetadata = true }
+}
+
+// IgnoreReceiverPointer treats (T) and (*T) receivers as the same.
+func IgnoreReceiverPointer() EquivalenceOption {
+	return func(e *equivalence) { e.ignorePointer = true }
+}
+
+// IgnoreContext ignores the @context modifier.
+func IgnoreContext() EquivalenceOption {
+	return func(e *equivalence) { e.ignoreContext = true }
+}
+
+// IgnoreAnonIndex treats anonymous functions of the same parent as the same.
+func IgnoreAnonIndex() EquivalenceOption {
+	return func(e *equivalence) { e.ignoreAnonIndex = true }
+}
+
+// Equivalent reports whether two symbols denote the same logical symbol.
+// Metadata and whitespace inside type arguments are always ignored unless
+// CompareMetadata is given; further differences can be ignored via opts.
+func (s *Symbol) Equivalent(other *Symbol, opts ...EquivalenceOption) bool {
+	if s == nil || other == nil {
+		return s == other
+	}
+	var e equivalence
+	for _, opt := range opts {
+		opt(&e)
+	}
+
+	if s.PackagePath != other.PackagePath ||
+		s.Name != other.Name ||
+		s.IsInit != other.IsInit ||
+		s.IsAnonymous != other.IsAnonymous {
+		return false
+	}
+	if !e.ignoreAnonIndex && s.AnonIndex != other.AnonIndex {
+		return false
+	}
+	if !e.ignoreContext && s.Context != other.Context {
+		return false
+	}
+	if e.compareMetadata && !s.Metadata.Equal(other.Metadata) {
+		return false
+	}
+	if !equalTypeExprs(s.TypeArgs, other.TypeArgs) || !equalTypeParams(s.TypeParams, other.TypeParams) {
+		return false
+	}
+
+	if s.Receiver == nil || other.Receiver == nil {
+		return s.Receiver == nil && other.Receiver == nil
+	}
+	return s.Receiver.TypeName == other.Receiver.TypeName &&
+		(e.ignorePointer || s.Receiver.IsPointer == other.Receiver.IsPointer) &&
+		equalTypeExprs(s.Receiver.TypeArgs, other.Receiver.TypeArgs)
+}
+
+// equalTypeExprs compares type expressions with EqualTypeExpr.
+func equalTypeExprs(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !EqualTypeExpr(a[i], b[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// equalTypeParams compares type parameters, treating an empty constraint
+// as "any".
+func equalTypeParams(a, b []TypeParam) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	constraint := func(c string) string {
+		if c == "" {
+			return "any"
+		}
+		return stripSpace(c)
+	}
+	for i := range a {
+		if a[i].Name != b[i].Name || constraint(a[i].Constraint) != constraint(b[i].Constraint) {
+			return false
+		}
+	}
+	return true
+}
+
+// stripSpace removes all whitespace from a type expression.
+func stripSpace(s string) string {
+	return strings.Join(strings.Fields(s), "")
+}
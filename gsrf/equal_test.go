@@ -0,0 +1,101 @@
+package gsrf
+
+import (
+	"testing"
+)
+
+func TestSymbol_Equal(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b *Symbol
+		want bool
+	}{
+		{
+			name: "identical",
+			a:    MustParse("pkg.(*T[K, V]).M@linux{via:Base}"),
+			b:    MustParse("pkg.(*T[K, V]).M@linux{via:Base}"),
+			want: true,
+		},
+		{
+			name: "nil and empty custom metadata",
+			a:    &Symbol{PackagePath: "pkg", Name: "F", Metadata: Metadata{Custom: map[string]string{}}},
+			b:    &Symbol{PackagePath: "pkg", Name: "F"},
+			want: true,
+		},
+		{
+			name: "nil and empty type args",
+			a:    &Symbol{PackagePath: "pkg", Name: "F", TypeArgs: []string{}},
+			b:    &Symbol{PackagePath: "pkg", Name: "F"},
+			want: true,
+		},
+		{
+			name: "different custom metadata",
+			a:    MustParse("pkg.F{team:a}"),
+			b:    MustParse("pkg.F{team:b}"),
+			want: false,
+		},
+		{
+			name: "different pointerness",
+			a:    MustParse("pkg.(*T).M"),
+			b:    MustParse("pkg.(T).M"),
+			want: false,
+		},
+		{
+			name: "type arg whitespace",
+			a:    &Symbol{PackagePath: "pkg", Name: "F", TypeArgs: []string{"map[K]V"}},
+			b:    &Symbol{PackagePath: "pkg", Name: "F", TypeArgs: []string{"map[K] V"}},
+			want: false,
+		},
+		{
+			name: "implicit and explicit any constraint",
+			a:    &Symbol{PackagePath: "pkg", Name: "F", TypeParams: []TypeParam{{Name: "T"}}},
+			b:    &Symbol{PackagePath: "pkg", Name: "F", TypeParams: []TypeParam{{Name: "T", Constraint: "any"}}},
+			want: false,
+		},
+		{
+			name: "constraint whitespace",
+			a:    &Symbol{PackagePath: "pkg", Name: "F", TypeParams: []TypeParam{{Name: "T", Constraint: "~int|~string"}}},
+			b:    &Symbol{PackagePath: "pkg", Name: "F", TypeParams: []TypeParam{{Name: "T", Constraint: "~int | ~string"}}},
+			want: false,
+		},
+		{name: "both nil", a: nil, b: nil, want: true},
+		{name: "one nil", a: MustParse("pkg.F"), b: nil, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.a.Equal(tt.b); got != tt.want {
+				t.Errorf("Equal() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSymbol_Equivalent(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b string
+		opts []EquivalenceOption
+		want bool
+	}{
+		{name: "metadata ignored", a: "pkg.F{pos:a.go:1:1}", b: "pkg.F{pos:b.go:2:2}", want: true},
+		{name: "metadata compared", a: "pkg.F{pos:a.go:1:1}", b: "pkg.F{pos:b.go:2:2}", opts: []EquivalenceOption{CompareMetadata()}, want: false},
+		{name: "type arg whitespace", a: "pkg.Map[string,int]", b: "pkg.Map[string, int]", want: true},
+		{name: "receiver type arg whitespace", a: "pkg.(*T[K,V]).M", b: "pkg.(*T[K, V]).M", want: true},
+		{name: "pointer differs", a: "pkg.(*T).M", b: "pkg.(T).M", want: false},
+		{name: "pointer ignored", a: "pkg.(*T).M", b: "pkg.(T).M", opts: []EquivalenceOption{IgnoreReceiverPointer()}, want: true},
+		{name: "context differs", a: "pkg.F@linux", b: "pkg.F@windows", want: false},
+		{name: "context ignored", a: "pkg.F@linux", b: "pkg.F", opts: []EquivalenceOption{IgnoreContext()}, want: true},
+		{name: "anon index ignored", a: "main.main·lit1", b: "main.main·lit2", opts: []EquivalenceOption{IgnoreAnonIndex()}, want: true},
+		{name: "different names", a: "pkg.F", b: "pkg.G", want: false},
+		{name: "method vs function", a: "pkg.(T).M", b: "pkg.M", opts: []EquivalenceOption{IgnoreReceiverPointer()}, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := MustParse(tt.a).Equivalent(MustParse(tt.b), tt.opts...); got != tt.want {
+				t.Errorf("Equivalent() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,137 @@
+package gsrf
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+const (
+	upperhex = "0123456789ABCDEF"
+	lowerhex = "0123456789abcdef"
+)
+
+// windowsDeviceNames are reserved as file names on Windows, with any
+// extension.
+var windowsDeviceNames = map[string]bool{
+	"con": true, "prn": true, "aux": true, "nul": true,
+	"com1": true, "com2": true, "com3": true, "com4": true, "com5": true,
+	"com6": true, "com7": true, "com8": true, "com9": true,
+	"lpt1": true, "lpt2": true, "lpt3": true, "lpt4": true, "lpt5": true,
+	"lpt6": true, "lpt7": true, "lpt8": true, "lpt9": true,
+}
+
+// EncodeURL returns the GSRF string of the symbol with every byte other
+// than letters, digits, "-", ".", "_" and "~" percent-encoded, so it fits
+// in a URL path segment, query value or fragment without further escaping:
+// "net/http.(*Server).Serve" becomes "net%2Fhttp.%28%2AServer%29.Serve".
+// DecodeURL reverses it.
+func (s *Symbol) EncodeURL() string {
+	str := s.String()
+	var b strings.Builder
+	b.Grow(len(str) * 3 / 2)
+	for i := 0; i < len(str); i++ {
+		if c := str[i]; isURLUnreserved(c) {
+			b.WriteByte(c)
+		} else {
+			writePercent(&b, c, upperhex)
+		}
+	}
+	return b.String()
+}
+
+// DecodeURL parses a symbol encoded by EncodeURL. Other percent-encodings
+// of the same string, such as one leaving "/" or "@" as is, are accepted.
+// A "+" stands for itself, not a space.
+func DecodeURL(encoded string) (*Symbol, error) {
+	s, err := url.PathUnescape(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("invalid URL-encoded symbol %q: %w", encoded, err)
+	}
+	return Parse(s)
+}
+
+// EncodeFilename returns a file name standing for the symbol, safe on
+// case-insensitive file systems and on Windows, e.g. for cache files:
+// "net/http.(*Server).Serve" becomes "net%2fhttp.%28%2a!server%29.!serve".
+// Lower-case letters, digits, "-", "." and "_" are kept; an upper-case
+// letter is written as "!" and its lower-case form, as in the Go module
+// cache; other bytes are percent-encoded in lower-case hex, as is a leading
+// dot or a first character that would make a Windows device name. Long
+// symbols can exceed file system name limits; use the fingerprint for
+// those. DecodeFilename reverses it.
+func (s *Symbol) EncodeFilename() string {
+	str := s.String()
+	var b strings.Builder
+	b.Grow(len(str) * 3 / 2)
+	stem, _, _ := strings.Cut(strings.ToLower(str), ".")
+	for i := 0; i < len(str); i++ {
+		c := str[i]
+		switch {
+		case i == 0 && (c == '.' || windowsDeviceNames[stem]):
+			writePercent(&b, c, lowerhex)
+		case 'A' <= c && c <= 'Z':
+			b.WriteByte('!')
+			b.WriteByte(c + 'a' - 'A')
+		case 'a' <= c && c <= 'z', '0' <= c && c <= '9', c == '-', c == '.', c == '_':
+			b.WriteByte(c)
+		default:
+			writePercent(&b, c, lowerhex)
+		}
+	}
+	return b.String()
+}
+
+// DecodeFilename parses a symbol encoded by EncodeFilename. Upper-case
+// letters must be escaped, so each symbol has a single file name.
+func DecodeFilename(name string) (*Symbol, error) {
+	var b strings.Builder
+	b.Grow(len(name))
+	for i := 0; i < len(name); i++ {
+		c := name[i]
+		switch {
+		case c == '!':
+			if i+1 >= len(name) || name[i+1] < 'a' || name[i+1] > 'z' {
+				return nil, fmt.Errorf("invalid symbol file name %q: \"!\" not followed by a lower-case letter", name)
+			}
+			b.WriteByte(name[i+1] - 'a' + 'A')
+			i++
+		case c == '%':
+			if i+2 >= len(name) || !isHex(name[i+1]) || !isHex(name[i+2]) {
+				return nil, fmt.Errorf("invalid symbol file name %q: bad escape at offset %d", name, i)
+			}
+			b.WriteByte(unhex(name[i+1])<<4 | unhex(name[i+2]))
+			i += 2
+		case 'A' <= c && c <= 'Z':
+			return nil, fmt.Errorf("invalid symbol file name %q: unescaped upper-case letter", name)
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return Parse(b.String())
+}
+
+func isURLUnreserved(c byte) bool {
+	return 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || '0' <= c && c <= '9' ||
+		c == '-' || c == '.' || c == '_' || c == '~'
+}
+
+func writePercent(b *strings.Builder, c byte, digits string) {
+	b.WriteByte('%')
+	b.WriteByte(digits[c>>4])
+	b.WriteByte(digits[c&15])
+}
+
+func isHex(c byte) bool {
+	return '0' <= c && c <= '9' || 'a' <= c && c <= 'f' || 'A' <= c && c <= 'F'
+}
+
+func unhex(c byte) byte {
+	switch {
+	case '0' <= c && c <= '9':
+		return c - '0'
+	case 'a' <= c && c <= 'f':
+		return c - 'a' + 10
+	}
+	return c - 'A' + 10
+}
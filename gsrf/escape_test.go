@@ -0,0 +1,76 @@
+package gsrf
+
+import (
+	"strings"
+	"testing"
+)
+
+var escapeSymbols = []string{
+	"net/http.(*Server).Serve",
+	"github.com/user/repo.(*List[int]).Push@linux",
+	"slices.Sort[S ~[]E, E cmp.Ordered]",
+	"main.main·lit2",
+	`"github.com/a.b/c".F`,
+	"pkg.(T).M{via:A,via:B,pos:file.go:10:2,owner:a+b!}",
+	"aux.Handle",
+}
+
+func TestSymbol_EncodeURL(t *testing.T) {
+	if got := MustParse("net/http.(*Server).Serve").EncodeURL(); got != "net%2Fhttp.%28%2AServer%29.Serve" {
+		t.Errorf("EncodeURL() = %q", got)
+	}
+	for _, in := range escapeSymbols {
+		sym := MustParse(in)
+		enc := sym.EncodeURL()
+		if strings.ContainsAny(enc, "/*[]{}@()?#&=+ ,:!") {
+			t.Errorf("EncodeURL(%q) = %q, contains reserved characters", in, enc)
+		}
+		got, err := DecodeURL(enc)
+		if err != nil || got.String() != sym.String() {
+			t.Errorf("DecodeURL(%q) = %v, %v, want %q", enc, got, err, sym)
+		}
+	}
+
+	if got, err := DecodeURL("net/http.%28*Server%29.Serve@linux"); err != nil || got.String() != "net/http.(*Server).Serve@linux" {
+		t.Errorf("DecodeURL(partially encoded) = %v, %v", got, err)
+	}
+	if _, err := DecodeURL("pkg.F%2"); err == nil {
+		t.Error("DecodeURL() accepted a truncated escape")
+	}
+}
+
+func TestSymbol_EncodeFilename(t *testing.T) {
+	tests := map[string]string{
+		"net/http.(*Server).Serve": "net%2fhttp.%28%2a!server%29.!serve",
+		"aux.Handle":               "%61ux.!handle",
+		"pkg.f":                    "pkg.f",
+	}
+	for in, want := range tests {
+		if got := MustParse(in).EncodeFilename(); got != want {
+			t.Errorf("EncodeFilename(%q) = %q, want %q", in, got, want)
+		}
+	}
+
+	seen := map[string]string{}
+	for _, in := range append(escapeSymbols, "pkg.handle", "pkg.Handle") {
+		sym := MustParse(in)
+		enc := sym.EncodeFilename()
+		if strings.ContainsAny(enc, `/\:*?"<>| `) || strings.ToLower(enc) != enc {
+			t.Errorf("EncodeFilename(%q) = %q, not a portable file name", in, enc)
+		}
+		if prev, ok := seen[enc]; ok {
+			t.Errorf("EncodeFilename(%q) = EncodeFilename(%q) = %q", in, prev, enc)
+		}
+		seen[enc] = in
+		got, err := DecodeFilename(enc)
+		if err != nil || got.String() != sym.String() {
+			t.Errorf("DecodeFilename(%q) = %v, %v, want %q", enc, got, err, sym)
+		}
+	}
+
+	for _, bad := range []string{"pkg.Handle", "pkg.!", "pkg.!1", "pkg.%2", "pkg.%zz"} {
+		if _, err := DecodeFilename(bad); err == nil {
+			t.Errorf("DecodeFilename(%q) succeeded", bad)
+		}
+	}
+}
@@ -0,0 +1,139 @@
+package gsrf
+
+import (
+	"bufio"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// symbolFileMagic starts the header comment of a file written by WriteFile.
+const symbolFileMagic = "# gsrf-symbols"
+
+// ParseFile reads a symbol file: one symbol per line, with blank lines and
+// # comments skipped. Files ending in .gz are decompressed. Each line is
+// parsed with ParseWith and opts; errors carry the file name and line number.
+//
+// If the file starts with the header written by WriteFile and records a
+// RulesHash other than this build's, opts.Rules decides: RulesRefuse returns
+// a *RulesMismatchError, and RulesMigrate parses the symbols under the
+// current rules. Files without a header are read as is.
+func ParseFile(path string, opts ParseOptions) ([]*Symbol, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var r io.Reader = f
+	if strings.HasSuffix(path, ".gz") {
+		zr, err := gzip.NewReader(f)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		defer zr.Close()
+		r = zr
+	}
+
+	var syms []*Symbol
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line := strings.TrimSpace(scanner.Text())
+		if lineNo == 1 && strings.HasPrefix(line, symbolFileMagic+" ") {
+			stored, ok := strings.CutPrefix(strings.TrimPrefix(line, symbolFileMagic+" "), "rules=")
+			if !ok {
+				return nil, fmt.Errorf("%s:1: invalid header %q", path, line)
+			}
+			if err := CheckRulesHash("symbol file "+path, stored); err != nil && opts.Rules != RulesMigrate {
+				return nil, err
+			}
+			continue
+		}
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		sym, err := ParseWith(line, opts)
+		if err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", path, lineNo, err)
+		}
+		syms = append(syms, sym)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return syms, nil
+}
+
+// ParseDir calls ParseFile for every regular file in dir accepted by filter
+// (all files if filter is nil) and returns the symbols keyed by file path.
+// Subdirectories are not descended into.
+func ParseDir(dir string, filter func(fs.FileInfo) bool, opts ParseOptions) (map[string][]*Symbol, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	files := make(map[string][]*Symbol)
+	for _, e := range entries {
+		if !e.Type().IsRegular() {
+			continue
+		}
+		if filter != nil {
+			info, err := e.Info()
+			if err != nil {
+				return nil, err
+			}
+			if !filter(info) {
+				continue
+			}
+		}
+		path := filepath.Join(dir, e.Name())
+		syms, err := ParseFile(path, opts)
+		if err != nil {
+			return nil, err
+		}
+		files[path] = syms
+	}
+	return files, nil
+}
+
+// WriteFile writes symbols to path in the format ParseFile reads: a header
+// comment recording RulesHash, then one symbol per line, formatted with
+// opts. Files ending in .gz are compressed.
+func WriteFile(path string, syms []*Symbol, opts ...FormatOption) (err error) {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+	}()
+
+	var w io.Writer = f
+	var zw *gzip.Writer
+	if strings.HasSuffix(path, ".gz") {
+		zw = gzip.NewWriter(f)
+		w = zw
+	}
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "%s rules=%s\n", symbolFileMagic, RulesHash)
+	for _, sym := range syms {
+		bw.WriteString(sym.Format(opts...))
+		bw.WriteByte('\n')
+	}
+	if err := bw.Flush(); err != nil {
+		return err
+	}
+	if zw != nil {
+		return zw.Close()
+	}
+	return nil
+}
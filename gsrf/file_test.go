@@ -0,0 +1,97 @@
+package gsrf
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestWriteFile_ParseFile(t *testing.T) {
+	syms := []*Symbol{
+		MustParse("fmt.Println"),
+		MustParse("net/http.(*Server).Serve@linux"),
+		MustParse("pkg.Map[string, int]{pos:map.go:3:1}"),
+	}
+	for _, name := range []string{"symbols.txt", "symbols.txt.gz"} {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), name)
+			if err := WriteFile(path, syms); err != nil {
+				t.Fatalf("WriteFile() error = %v", err)
+			}
+			got, err := ParseFile(path, ParseOptions{})
+			if err != nil {
+				t.Fatalf("ParseFile() error = %v", err)
+			}
+			if len(got) != len(syms) {
+				t.Fatalf("ParseFile() = %v, want %d symbols", got, len(syms))
+			}
+			for i := range syms {
+				if !got[i].Equal(syms[i]) {
+					t.Errorf("ParseFile()[%d] = %s, want %s", i, got[i], syms[i])
+				}
+			}
+		})
+	}
+}
+
+func TestParseFile_Errors(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "bad.txt")
+	os.WriteFile(path, []byte("# header\n\nfmt.Println\npkg.Do-It\n"), 0o644)
+
+	if _, err := ParseFile(path, ParseOptions{}); err != nil {
+		t.Errorf("ParseFile() error = %v", err)
+	}
+	_, err := ParseFile(path, ParseOptions{Strict: true})
+	var verr *ValidationError
+	if !errors.As(err, &verr) || !strings.HasPrefix(err.Error(), path+":4: ") {
+		t.Errorf("ParseFile() strict error = %v", err)
+	}
+	if _, err := ParseFile(filepath.Join(dir, "missing.txt"), ParseOptions{}); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("ParseFile() missing error = %v", err)
+	}
+}
+
+func TestParseFile_RulesHash(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "symbols.txt")
+	if err := WriteFile(path, []*Symbol{MustParse("fmt.Println")}); err != nil {
+		t.Fatal(err)
+	}
+	data, _ := os.ReadFile(path)
+	if !strings.HasPrefix(string(data), "# gsrf-symbols rules="+RulesHash+"\n") {
+		t.Fatalf("WriteFile() header = %q", data)
+	}
+
+	os.WriteFile(path, []byte("# gsrf-symbols rules=0000000000000000\nfmt.Println\n"), 0o644)
+	var mismatch *RulesMismatchError
+	if _, err := ParseFile(path, ParseOptions{}); !errors.As(err, &mismatch) || mismatch.Stored != "0000000000000000" {
+		t.Errorf("ParseFile() error = %v, want *RulesMismatchError", err)
+	}
+	if syms, err := ParseFile(path, ParseOptions{Rules: RulesMigrate}); err != nil || len(syms) != 1 {
+		t.Errorf("ParseFile() migrating = %v, %v", syms, err)
+	}
+
+	os.WriteFile(path, []byte("# gsrf-symbols 1\nfmt.Println\n"), 0o644)
+	if _, err := ParseFile(path, ParseOptions{}); err == nil {
+		t.Error("ParseFile() accepted an invalid header")
+	}
+}
+
+func TestParseDir(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "a.gsrf"), []byte("fmt.Println\n"), 0o644)
+	os.WriteFile(filepath.Join(dir, "b.gsrf"), []byte("pkg.A\npkg.B\n"), 0o644)
+	os.WriteFile(filepath.Join(dir, "README"), []byte("not symbols\n"), 0o644)
+	os.Mkdir(filepath.Join(dir, "sub"), 0o755)
+
+	files, err := ParseDir(dir, func(fi fs.FileInfo) bool { return strings.HasSuffix(fi.Name(), ".gsrf") }, ParseOptions{})
+	if err != nil {
+		t.Fatalf("ParseDir() error = %v", err)
+	}
+	if len(files) != 2 || len(files[filepath.Join(dir, "b.gsrf")]) != 2 {
+		t.Errorf("ParseDir() = %v", files)
+	}
+}
@@ -0,0 +1,47 @@
+package gsrf
+
+import (
+	"encoding/binary"
+	"hash/fnv"
+)
+
+// FingerprintVersion identifies the fingerprint algorithm. It is mixed into
+// every hash and bumped whenever the hashed form changes, so fingerprints
+// from different versions never collide by accident.
+const FingerprintVersion = 1
+
+// Fingerprint returns a stable 64-bit hash of the symbol's canonical form.
+// Metadata is excluded, so the same function compiled in different releases
+// (with different source positions) keeps its fingerprint.
+// Fingerprints depend on the normalization rules; store RulesHash with
+// persisted fingerprints, as FingerprintIndex does.
+func (s *Symbol) Fingerprint() uint64 {
+	h := fnv.New64a()
+	writeFingerprintHeader(h, 's')
+	h.Write([]byte(s.Normalize().FormatWith(FormatOptions{OmitMetadata: true})))
+	return h.Sum64()
+}
+
+// FingerprintStack returns a stable 64-bit hash of a stack of symbols,
+// ordered from innermost frame to outermost. Nil frames are hashed as
+// unknown frames.
+func FingerprintStack(stack []*Symbol) uint64 {
+	h := fnv.New64a()
+	writeFingerprintHeader(h, 'k')
+	var buf [8]byte
+	for _, s := range stack {
+		var fp uint64
+		if s != nil {
+			fp = s.Fingerprint()
+		}
+		binary.BigEndian.PutUint64(buf[:], fp)
+		h.Write(buf[:])
+	}
+	return h.Sum64()
+}
+
+// writeFingerprintHeader writes the version and kind prefix shared by all
+// fingerprints.
+func writeFingerprintHeader(h interface{ Write([]byte) (int, error) }, kind byte) {
+	h.Write([]byte{'g', 's', 'r', 'f', FingerprintVersion, kind, 0})
+}
@@ -0,0 +1,59 @@
+package gsrf
+
+import (
+	"testing"
+)
+
+func TestSymbol_Fingerprint(t *testing.T) {
+	base := MustParse("github.com/user/repo.(*Cache[K, V]).Get")
+
+	tests := []struct {
+		name  string
+		input string
+		same  bool
+	}{
+		{name: "identical", input: "github.com/user/repo.(*Cache[K, V]).Get", same: true},
+		{name: "metadata ignored", input: "github.com/user/repo.(*Cache[K, V]).Get{pos:cache.go:10:1}", same: true},
+		{name: "spacing ignored", input: "github.com/user/repo.(*Cache[K,V]).Get", same: true},
+		{name: "vendor ignored", input: "vendor/github.com/user/repo.(*Cache[K, V]).Get", same: true},
+		{name: "different method", input: "github.com/user/repo.(*Cache[K, V]).Put", same: false},
+		{name: "different context", input: "github.com/user/repo.(*Cache[K, V]).Get@linux", same: false},
+		{name: "different receiver", input: "github.com/user/repo.(Cache[K, V]).Get", same: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := MustParse(tt.input).Fingerprint() == base.Fingerprint()
+			if got != tt.same {
+				t.Errorf("fingerprints equal = %v, want %v", got, tt.same)
+			}
+		})
+	}
+}
+
+func TestSymbol_FingerprintStable(t *testing.T) {
+	// Fingerprints are persisted by callers; changing this value requires
+	// bumping FingerprintVersion.
+	const want uint64 = 0x5e1defbe55f77ce7
+	if got := MustParse("fmt.Println").Fingerprint(); got != want {
+		t.Errorf("Fingerprint() = %#x, want %#x", got, want)
+	}
+}
+
+func TestFingerprintStack(t *testing.T) {
+	a := MustParse("main.main")
+	b := MustParse("main.(*Server).Start")
+
+	if FingerprintStack([]*Symbol{a, b}) == FingerprintStack([]*Symbol{b, a}) {
+		t.Error("FingerprintStack() ignores frame order")
+	}
+	if FingerprintStack([]*Symbol{a}) == FingerprintStack([]*Symbol{a, a}) {
+		t.Error("FingerprintStack() ignores stack depth")
+	}
+	if FingerprintStack([]*Symbol{a, b}) != FingerprintStack([]*Symbol{MustParse("main.main{pos:a.go:1:1}"), b}) {
+		t.Error("FingerprintStack() depends on metadata")
+	}
+	if FingerprintStack(nil) == a.Fingerprint() {
+		t.Error("empty stack collides with symbol fingerprint")
+	}
+}
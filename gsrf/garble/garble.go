@@ -0,0 +1,194 @@
+// Package garble de-obfuscates GSRF symbols from binaries built with garble,
+// which replaces package paths and identifiers with short hashes.
+package garble
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"unicode"
+
+	"github.com/kis9a/gsrf"
+)
+
+// KeyObfuscated is the custom metadata key set by Flag on symbols that look
+// obfuscated.
+const KeyObfuscated = "obfuscated"
+
+// ReverseMap maps obfuscated package paths and identifiers back to their
+// original names.
+type ReverseMap struct {
+	names map[string]string
+}
+
+// NewReverseMap creates a reverse map from obfuscated to original names.
+func NewReverseMap(names map[string]string) *ReverseMap {
+	m := &ReverseMap{names: make(map[string]string, len(names))}
+	for k, v := range names {
+		m.names[k] = v
+	}
+	return m
+}
+
+// Len returns the number of mapped names.
+func (m *ReverseMap) Len() int {
+	return len(m.names)
+}
+
+// ReadReverseMap reads a reverse map either as a JSON object of obfuscated
+// to original names, or as text with one "obfuscated original" pair per line
+// (whitespace separated). Blank lines and # comments are ignored.
+func ReadReverseMap(r io.Reader) (*ReverseMap, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '{' {
+		var names map[string]string
+		if err := json.Unmarshal(trimmed, &names); err != nil {
+			return nil, fmt.Errorf("garble reverse map: %w", err)
+		}
+		return NewReverseMap(names), nil
+	}
+
+	names := make(map[string]string)
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("garble reverse map: line %d: expected 2 fields, got %d", lineNo, len(fields))
+		}
+		names[fields[0]] = fields[1]
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return &ReverseMap{names: names}, nil
+}
+
+// Deobfuscate returns a copy of sym with mapped package paths, names, and
+// receiver types replaced. A package path is looked up whole, then segment by
+// segment. Unmapped parts are kept. It reports whether anything changed.
+func (m *ReverseMap) Deobfuscate(sym *gsrf.Symbol) (*gsrf.Symbol, bool) {
+	out := sym.Clone()
+	changed := false
+	replace := func(s *string) {
+		if orig, ok := m.names[*s]; ok && orig != *s {
+			*s = orig
+			changed = true
+		}
+	}
+
+	if orig, ok := m.names[out.PackagePath]; ok {
+		out.PackagePath = orig
+		changed = true
+	} else {
+		segments := strings.Split(out.PackagePath, "/")
+		for i := range segments {
+			replace(&segments[i])
+		}
+		out.PackagePath = strings.Join(segments, "/")
+	}
+
+	if out.Receiver != nil {
+		replace(&out.Receiver.TypeName)
+	}
+	if out.IsAnonymous && strings.HasPrefix(out.Name, "(") {
+		// Anonymous functions in methods carry the receiver in the name.
+		recv, method, _ := strings.Cut(out.Name, ").")
+		ptr := strings.HasPrefix(recv, "(*")
+		typeName := strings.TrimPrefix(strings.TrimPrefix(recv, "("), "*")
+		replace(&typeName)
+		replace(&method)
+		if ptr {
+			typeName = "*" + typeName
+		}
+		out.Name = "(" + typeName + ")." + method
+	} else {
+		replace(&out.Name)
+	}
+	if out.IsAnonymous {
+		out.AnonParent = out.PackagePath + "." + out.Name
+	}
+	return out, changed
+}
+
+// Parse parses a GSRF symbol and de-obfuscates it.
+func (m *ReverseMap) Parse(input string) (*gsrf.Symbol, error) {
+	sym, err := gsrf.Parse(input)
+	if err != nil {
+		return nil, err
+	}
+	out, _ := m.Deobfuscate(sym)
+	return out, nil
+}
+
+// IsObfuscated reports whether the symbol's package path, receiver type, or
+// name looks like a garble hash. It is a heuristic for use when no reverse
+// map is available: hashes are short and switch between lower case, upper
+// case, and digits far more often than Go identifiers do.
+func IsObfuscated(sym *gsrf.Symbol) bool {
+	if sym.PackagePath != "main" && !strings.ContainsAny(sym.PackagePath, "./") && looksHashed(sym.PackagePath) {
+		return true
+	}
+	if sym.Receiver != nil && looksHashed(sym.Receiver.TypeName) {
+		return true
+	}
+	return !sym.IsAnonymous && looksHashed(sym.Name)
+}
+
+// Flag marks the symbol with KeyObfuscated custom metadata if IsObfuscated
+// reports true, and returns the result.
+func Flag(sym *gsrf.Symbol) bool {
+	if !IsObfuscated(sym) {
+		return false
+	}
+	if sym.Metadata.Custom == nil {
+		sym.Metadata.Custom = make(map[string]string)
+	}
+	sym.Metadata.Custom[KeyObfuscated] = "true"
+	return true
+}
+
+// looksHashed scores character-class changes in s, ignoring upper to lower
+// case changes, which start every CamelCase word. "KF8sdnP1" changes four
+// times in eight runes; "X509KeyPair" three times in eleven.
+func looksHashed(s string) bool {
+	runes := []rune(s)
+	if len(runes) < 5 || len(runes) > 16 {
+		return false
+	}
+	class := func(r rune) int {
+		switch {
+		case unicode.IsDigit(r):
+			return 0
+		case unicode.IsUpper(r):
+			return 1
+		case unicode.IsLower(r):
+			return 2
+		}
+		return 3
+	}
+	changes, digits := 0, 0
+	for i, r := range runes {
+		if class(r) == 0 {
+			digits++
+		}
+		if class(r) == 3 {
+			return false
+		}
+		if i > 0 && class(r) != class(runes[i-1]) && !(class(runes[i-1]) == 1 && class(r) == 2) {
+			changes++
+		}
+	}
+	return digits > 0 && changes*100 >= len(runes)*40
+}
@@ -0,0 +1,100 @@
+package garble
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/kis9a/gsrf"
+)
+
+const textMap = `# garble reverse map
+aNgHz8Kb  github.com/acme/billing
+KF8sdnP1  Charge
+Qx7pLm2w  Invoice
+`
+
+func TestReadReverseMap(t *testing.T) {
+	m, err := ReadReverseMap(strings.NewReader(textMap))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.Len() != 3 {
+		t.Errorf("Len() = %d, want 3", m.Len())
+	}
+
+	m, err = ReadReverseMap(strings.NewReader(`{"aNgHz8Kb": "github.com/acme/billing"}`))
+	if err != nil || m.Len() != 1 {
+		t.Errorf("ReadReverseMap(JSON) = %v, %v", m, err)
+	}
+
+	for _, bad := range []string{"a b c\n", `{"a": 1}`} {
+		if _, err := ReadReverseMap(strings.NewReader(bad)); err == nil {
+			t.Errorf("ReadReverseMap(%q) expected error", bad)
+		}
+	}
+}
+
+func TestReverseMap_Parse(t *testing.T) {
+	m, _ := ReadReverseMap(strings.NewReader(textMap))
+
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"aNgHz8Kb.KF8sdnP1", "github.com/acme/billing.Charge"},
+		{"aNgHz8Kb.(*Qx7pLm2w).KF8sdnP1", "github.com/acme/billing.(*Invoice).Charge"},
+		{"aNgHz8Kb.(*Qx7pLm2w).KF8sdnP1·lit2", "github.com/acme/billing.(*Invoice).Charge·lit2"},
+		{"aNgHz8Kb.KF8sdnP1·lit1", "github.com/acme/billing.Charge·lit1"},
+		{"vendor/aNgHz8Kb.Other", "vendor/github.com/acme/billing.Other"},
+		{"fmt.Println", "fmt.Println"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			sym, err := m.Parse(tt.input)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			if got := sym.Format(); got != tt.want {
+				t.Errorf("Parse() = %q, want %q", got, tt.want)
+			}
+			if sym.IsAnonymous && !sym.Equal(gsrf.MustParse(tt.want)) {
+				t.Errorf("Parse() = %+v, differs from Parse(%q)", sym, tt.want)
+			}
+		})
+	}
+
+	orig := gsrf.MustParse("aNgHz8Kb.KF8sdnP1")
+	if _, changed := m.Deobfuscate(orig); !changed {
+		t.Error("Deobfuscate() reported no change")
+	}
+	if orig.PackagePath != "aNgHz8Kb" {
+		t.Error("Deobfuscate() modified its argument")
+	}
+}
+
+func TestIsObfuscated(t *testing.T) {
+	tests := map[string]bool{
+		"aNgHz8Kb.KF8sdnP1":              true,
+		"main.KF8sdnP1":                  true,
+		"main.(*Qx7pLm2w).Run":           true,
+		"main.main":                      false,
+		"crypto/x509.X509KeyPair":        false,
+		"net/http.(*HTTP2Server).Serve":  false,
+		"encoding/base64.Base64Encode":   false,
+		"strconv.int64ToStr":             false,
+		"github.com/acme/billing.Charge": false,
+		"main.main·lit1":                 false,
+	}
+	for input, want := range tests {
+		t.Run(input, func(t *testing.T) {
+			sym := gsrf.MustParse(input)
+			if got := IsObfuscated(sym); got != want {
+				t.Errorf("IsObfuscated() = %v, want %v", got, want)
+			}
+			if got := Flag(sym); got != want || (sym.Metadata.Custom[KeyObfuscated] == "true") != want {
+				t.Errorf("Flag() = %v, metadata %v", got, sym.Metadata.Custom)
+			}
+		})
+	}
+}
@@ -0,0 +1,149 @@
+// Package gsrfcheck defines an analyzer that validates GSRF symbols written
+// as string constants, so malformed symbols are reported at build time
+// instead of making MustParse panic at run time.
+//
+// It checks constant arguments of gsrf.Parse and gsrf.MustParse, and of
+// functions whose doc comment has a directive naming the string parameter
+// that holds a symbol:
+//
+//	//gsrf:symbol name
+//	func Track(name string, n int) { ... }
+//
+// Without a parameter name, the first string parameter is checked. The
+// directive also applies to calls from other packages.
+//
+// Run it with go vet:
+//
+//	go install github.com/kis9a/gsrf/cmd/gsrfcheck
+//	go vet -vettool=$(which gsrfcheck) ./...
+package gsrfcheck
+
+import (
+	"fmt"
+	"go/ast"
+	"go/constant"
+	"go/types"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+	"golang.org/x/tools/go/types/typeutil"
+
+	"github.com/kis9a/gsrf"
+)
+
+const gsrfPath = "github.com/kis9a/gsrf"
+
+// directive marks a function taking a GSRF symbol.
+const directive = "//gsrf:symbol"
+
+// Analyzer reports string constants that are not valid GSRF symbols.
+var Analyzer = &analysis.Analyzer{
+	Name:      "gsrf",
+	Doc:       "check that string constants passed as GSRF symbols parse",
+	URL:       "https://pkg.go.dev/github.com/kis9a/gsrf/gsrfcheck",
+	Requires:  []*analysis.Analyzer{inspect.Analyzer},
+	Run:       run,
+	FactTypes: []analysis.Fact{new(symbolParam)},
+}
+
+// symbolParam is the fact that a function takes a GSRF symbol as the
+// parameter at Index.
+type symbolParam struct {
+	Index int
+}
+
+func (*symbolParam) AFact() {}
+
+func (p *symbolParam) String() string {
+	return fmt.Sprintf("gsrf symbol parameter %d", p.Index)
+}
+
+func run(pass *analysis.Pass) (any, error) {
+	exportDirectives(pass)
+
+	insp := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+	insp.Preorder([]ast.Node{(*ast.CallExpr)(nil)}, func(n ast.Node) {
+		call := n.(*ast.CallExpr)
+		fn := typeutil.StaticCallee(pass.TypesInfo, call)
+		if fn == nil {
+			return
+		}
+		index := -1
+		if fn.Pkg() != nil && fn.Pkg().Path() == gsrfPath && (fn.Name() == "Parse" || fn.Name() == "MustParse") {
+			index = 0
+		} else if p := new(symbolParam); pass.ImportObjectFact(fn, p) {
+			index = p.Index
+		}
+		if index < 0 || index >= len(call.Args) {
+			return
+		}
+
+		arg := call.Args[index]
+		tv, ok := pass.TypesInfo.Types[arg]
+		if !ok || tv.Value == nil || tv.Value.Kind() != constant.String {
+			return
+		}
+		symbol := constant.StringVal(tv.Value)
+		if _, err := gsrf.Parse(symbol); err != nil {
+			pass.Reportf(arg.Pos(), "invalid GSRF symbol %q: %s", symbol, strings.TrimPrefix(err.Error(), "invalid GSRF symbol: "))
+		}
+	})
+	return nil, nil
+}
+
+// exportDirectives records a fact for each function of the package with a
+// symbol directive, and reports malformed directives.
+func exportDirectives(pass *analysis.Pass) {
+	for _, file := range pass.Files {
+		for _, decl := range file.Decls {
+			fd, ok := decl.(*ast.FuncDecl)
+			if !ok || fd.Doc == nil {
+				continue
+			}
+			for _, c := range fd.Doc.List {
+				rest, ok := strings.CutPrefix(c.Text, directive)
+				if !ok || (rest != "" && rest[0] != ' ' && rest[0] != '\t') {
+					continue
+				}
+				fn, ok := pass.TypesInfo.Defs[fd.Name].(*types.Func)
+				if !ok {
+					continue
+				}
+				name := ""
+				if fields := strings.Fields(rest); len(fields) > 0 {
+					name = fields[0]
+				}
+				index, err := symbolParamIndex(fn, name)
+				if err != nil {
+					pass.Reportf(c.Pos(), "%s: %v", directive, err)
+					continue
+				}
+				pass.ExportObjectFact(fn, &symbolParam{Index: index})
+			}
+		}
+	}
+}
+
+// symbolParamIndex returns the index of the string parameter name, or of
+// the first string parameter if name is empty.
+func symbolParamIndex(fn *types.Func, name string) (int, error) {
+	params := fn.Type().(*types.Signature).Params()
+	for i := 0; i < params.Len(); i++ {
+		p := params.At(i)
+		if name != "" && p.Name() != name {
+			continue
+		}
+		if b, ok := p.Type().Underlying().(*types.Basic); ok && b.Info()&types.IsString != 0 {
+			return i, nil
+		}
+		if name != "" {
+			return 0, fmt.Errorf("parameter %s is not a string", name)
+		}
+	}
+	if name != "" {
+		return 0, fmt.Errorf("no parameter %s", name)
+	}
+	return 0, fmt.Errorf("no string parameter")
+}
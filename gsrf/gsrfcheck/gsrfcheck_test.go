@@ -0,0 +1,138 @@
+package gsrfcheck
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"testing"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+)
+
+// The analyzer runs on packages under testdata/src, type-checked from
+// source, so the test does not depend on export data of the installed Go
+// version. Lines expecting a diagnostic end with a comment
+// "// want `regexp`".
+
+var wantPattern = regexp.MustCompile("// want `([^`]*)`")
+
+type testRunner struct {
+	fset  *token.FileSet
+	pkgs  map[string]*types.Package
+	facts map[types.Object]analysis.Fact
+	diags []string // "file:line: message"
+	wants map[string]*regexp.Regexp
+}
+
+func (r *testRunner) Import(path string) (*types.Package, error) {
+	if pkg, ok := r.pkgs[path]; ok {
+		return pkg, nil
+	}
+	return r.check(path)
+}
+
+// check type-checks the package at path and runs the analyzer on it.
+func (r *testRunner) check(path string) (*types.Package, error) {
+	dir := filepath.Join("testdata", "src", filepath.FromSlash(path))
+	names, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil || len(names) == 0 {
+		return nil, fmt.Errorf("no package %s", path)
+	}
+	sort.Strings(names)
+	var files []*ast.File
+	for _, name := range names {
+		f, err := parser.ParseFile(r.fset, name, nil, parser.ParseComments)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, f)
+		r.collectWants(f)
+	}
+
+	info := &types.Info{
+		Types: map[ast.Expr]types.TypeAndValue{},
+		Defs:  map[*ast.Ident]types.Object{},
+		Uses:  map[*ast.Ident]types.Object{},
+	}
+	pkg, err := (&types.Config{Importer: r}).Check(path, r.fset, files, info)
+	if err != nil {
+		return nil, err
+	}
+	r.pkgs[path] = pkg
+
+	pass := &analysis.Pass{
+		Analyzer:  Analyzer,
+		Fset:      r.fset,
+		Files:     files,
+		Pkg:       pkg,
+		TypesInfo: info,
+		ResultOf:  map[*analysis.Analyzer]any{inspect.Analyzer: inspector.New(files)},
+		Report: func(d analysis.Diagnostic) {
+			pos := r.fset.Position(d.Pos)
+			r.diags = append(r.diags, fmt.Sprintf("%s:%d: %s", filepath.Base(pos.Filename), pos.Line, d.Message))
+		},
+		ImportObjectFact: func(obj types.Object, fact analysis.Fact) bool {
+			f, ok := r.facts[obj]
+			if ok {
+				reflect.ValueOf(fact).Elem().Set(reflect.ValueOf(f).Elem())
+			}
+			return ok
+		},
+		ExportObjectFact: func(obj types.Object, fact analysis.Fact) {
+			r.facts[obj] = fact
+		},
+	}
+	if _, err := Analyzer.Run(pass); err != nil {
+		return nil, err
+	}
+	return pkg, nil
+}
+
+func (r *testRunner) collectWants(f *ast.File) {
+	for _, cg := range f.Comments {
+		for _, c := range cg.List {
+			if m := wantPattern.FindStringSubmatch(c.Text); m != nil {
+				pos := r.fset.Position(c.Pos())
+				r.wants[fmt.Sprintf("%s:%d", filepath.Base(pos.Filename), pos.Line)] = regexp.MustCompile(m[1])
+			}
+		}
+	}
+}
+
+func TestAnalyzer(t *testing.T) {
+	r := &testRunner{
+		fset:  token.NewFileSet(),
+		pkgs:  map[string]*types.Package{},
+		facts: map[types.Object]analysis.Fact{},
+		wants: map[string]*regexp.Regexp{},
+	}
+	for _, path := range []string{"a", "b"} {
+		if _, err := r.Import(path); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	matched := map[string]bool{}
+	for _, d := range r.diags {
+		loc, msg, _ := strings.Cut(d, ": ")
+		if re, ok := r.wants[loc]; ok && re.MatchString(msg) {
+			matched[loc] = true
+			continue
+		}
+		t.Errorf("unexpected diagnostic %s", d)
+	}
+	for loc, re := range r.wants {
+		if !matched[loc] {
+			t.Errorf("%s: no diagnostic matching %q", loc, re)
+		}
+	}
+}
@@ -0,0 +1,34 @@
+package a
+
+import "github.com/kis9a/gsrf"
+
+const handler = "net/http.(*Server).Serve"
+
+func parse(dynamic string) {
+	gsrf.MustParse("fmt.Println")
+	gsrf.MustParse(handler)
+	gsrf.MustParse("fmt.Println@") // want `invalid GSRF symbol "fmt.Println@": empty context after @`
+	gsrf.Parse("no package")       // want `invalid GSRF symbol`
+	gsrf.MustParse(dynamic)
+	gsrf.MustParse(("pkg" + "." + "(*T")) // want `invalid GSRF symbol "pkg.\(\*T": incomplete method receiver`
+}
+
+// Track counts calls of a symbol.
+//
+//gsrf:symbol sym
+func Track(n int, sym string) {}
+
+// Label names a symbol; the first string parameter holds it.
+//
+//gsrf:symbol
+func Label(sym, label string) {}
+
+//gsrf:symbol missing // want `gsrf:symbol: no parameter missing`
+func Bad(s string) {}
+
+func track() {
+	Track(1, "pkg.F")
+	Track(1, "pkg.") // want `invalid GSRF symbol`
+	Label("pkg.(T).M", "not checked")
+	Label("pkg", "x") // want `invalid GSRF symbol`
+}
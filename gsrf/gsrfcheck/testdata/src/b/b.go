@@ -0,0 +1,8 @@
+package b
+
+import "a"
+
+func track() {
+	a.Track(2, "example.com/b.Run")
+	a.Track(2, "example.com/b.Run@") // want `empty context`
+}
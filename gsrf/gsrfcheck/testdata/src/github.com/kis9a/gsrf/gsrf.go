@@ -0,0 +1,8 @@
+// Package gsrf is a stub of the real package's parsing API.
+package gsrf
+
+type Symbol struct{}
+
+func Parse(input string) (*Symbol, error) { return nil, nil }
+
+func MustParse(input string) *Symbol { return nil }
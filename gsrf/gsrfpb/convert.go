@@ -0,0 +1,99 @@
+// Package gsrfpb defines protocol buffer messages for GSRF symbols, in
+// gsrf.proto, so gRPC services can pass symbols in their APIs. The messages
+// mirror the JSON object schema; ToProto and FromProto convert them to and
+// from gsrf.Symbol.
+package gsrfpb
+
+import (
+	"fmt"
+
+	"github.com/kis9a/gsrf"
+)
+
+// ToProto converts a symbol to its message. Empty metadata is left unset.
+func ToProto(s *gsrf.Symbol) *Symbol {
+	m := &Symbol{
+		Package:    s.PackagePath,
+		Name:       s.Name,
+		Init:       s.IsInit,
+		Anonymous:  s.IsAnonymous,
+		AnonParent: s.AnonParent,
+		AnonIndex:  int32(s.AnonIndex),
+		TypeArgs:   append([]string(nil), s.TypeArgs...),
+		Context:    s.Context,
+	}
+	if s.Receiver != nil {
+		m.Receiver = &Receiver{
+			TypeName: s.Receiver.TypeName,
+			Pointer:  s.Receiver.IsPointer,
+			TypeArgs: append([]string(nil), s.Receiver.TypeArgs...),
+		}
+	}
+	for _, tp := range s.TypeParams {
+		m.TypeParams = append(m.TypeParams, &TypeParam{Name: tp.Name, Constraint: tp.Constraint})
+	}
+	md := s.Metadata
+	if len(md.Via) > 0 || md.Alias != "" || md.Position != "" || len(md.Custom) > 0 {
+		m.Metadata = &Metadata{
+			Via:      append([]string(nil), md.Via...),
+			Alias:    md.Alias,
+			Position: md.Position,
+		}
+		if len(md.Custom) > 0 {
+			m.Metadata.Custom = make(map[string]string, len(md.Custom))
+			for k, v := range md.Custom {
+				m.Metadata.Custom[k] = v
+			}
+		}
+	}
+	return m
+}
+
+// FromProto converts a message to a symbol. Like the JSON object schema, it
+// requires a package and, except for function literals, a name.
+func FromProto(m *Symbol) (*gsrf.Symbol, error) {
+	if m.GetPackage() == "" || (m.GetName() == "" && !m.GetAnonymous()) {
+		return nil, fmt.Errorf("invalid GSRF symbol: message requires package and name")
+	}
+	if m.GetAnonIndex() < 0 {
+		return nil, fmt.Errorf("invalid GSRF symbol: negative anon_index %d", m.GetAnonIndex())
+	}
+	if r := m.GetReceiver(); r != nil && r.GetTypeName() == "" {
+		return nil, fmt.Errorf("invalid GSRF symbol: receiver requires type_name")
+	}
+
+	s := &gsrf.Symbol{
+		PackagePath: m.GetPackage(),
+		Name:        m.GetName(),
+		IsInit:      m.GetInit(),
+		IsAnonymous: m.GetAnonymous(),
+		AnonParent:  m.GetAnonParent(),
+		AnonIndex:   int(m.GetAnonIndex()),
+		TypeArgs:    append([]string(nil), m.GetTypeArgs()...),
+		Context:     m.GetContext(),
+	}
+	if r := m.GetReceiver(); r != nil {
+		s.Receiver = &gsrf.Receiver{
+			TypeName:  r.GetTypeName(),
+			IsPointer: r.GetPointer(),
+			TypeArgs:  append([]string(nil), r.GetTypeArgs()...),
+		}
+	}
+	for _, tp := range m.GetTypeParams() {
+		s.TypeParams = append(s.TypeParams, gsrf.TypeParam{Name: tp.GetName(), Constraint: tp.GetConstraint()})
+	}
+	if md := m.GetMetadata(); md != nil {
+		s.Metadata = gsrf.Metadata{
+			Via:      append([]string(nil), md.GetVia()...),
+			Alias:    md.GetAlias(),
+			Position: md.GetPosition(),
+		}
+		if len(md.GetCustom()) > 0 {
+			s.Metadata.Custom = make(map[string]string, len(md.GetCustom()))
+			for k, v := range md.GetCustom() {
+				s.Metadata.Custom[k] = v
+			}
+		}
+	}
+	return s, nil
+}
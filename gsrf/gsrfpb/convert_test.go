@@ -0,0 +1,60 @@
+package gsrfpb
+
+import (
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+
+	"github.com/kis9a/gsrf"
+)
+
+func TestRoundTrip(t *testing.T) {
+	for _, in := range []string{
+		"fmt.Println",
+		"net/http.(*Server).Serve@linux",
+		"example.com/app.(*List[K, V]).Push",
+		"slices.Sort[S ~[]E, E cmp.Ordered]",
+		"example.com/app.main·lit2",
+		"example.com/app.init",
+		"example.com/app.(Outer).M{via:[Inner,Base],alias:A>B,pos:a.go:1:2,owner:web}",
+	} {
+		data, err := proto.Marshal(ToProto(gsrf.MustParse(in)))
+		if err != nil {
+			t.Fatalf("Marshal(%q) error = %v", in, err)
+		}
+		var m Symbol
+		if err := proto.Unmarshal(data, &m); err != nil {
+			t.Fatalf("Unmarshal(%q) error = %v", in, err)
+		}
+		got, err := FromProto(&m)
+		if err != nil {
+			t.Fatalf("FromProto(%q) error = %v", in, err)
+		}
+		if got.String() != gsrf.MustParse(in).String() {
+			t.Errorf("round trip of %q = %q", in, got)
+		}
+	}
+}
+
+func TestToProto(t *testing.T) {
+	m := ToProto(gsrf.MustParse("net/http.(*Server).Serve"))
+	if m.GetPackage() != "net/http" || m.GetName() != "Serve" || m.GetReceiver().GetTypeName() != "Server" || !m.GetReceiver().GetPointer() {
+		t.Errorf("ToProto() = %v", m)
+	}
+	if m.Metadata != nil {
+		t.Errorf("ToProto() set empty metadata: %v", m.Metadata)
+	}
+}
+
+func TestFromProto_Invalid(t *testing.T) {
+	for _, m := range []*Symbol{
+		{Name: "F"},
+		{Package: "pkg"},
+		{Package: "pkg", Name: "F", AnonIndex: -1},
+		{Package: "pkg", Name: "M", Receiver: &Receiver{Pointer: true}},
+	} {
+		if _, err := FromProto(m); err == nil {
+			t.Errorf("FromProto(%v) succeeded", m)
+		}
+	}
+}
@@ -0,0 +1,179 @@
+// Package gsrftest provides golden-file tests for symbol format adapters, so
+// packages registering their own adapters.Converter can test it against
+// recorded expectations with little boilerplate:
+//
+//	func TestMyFormat(t *testing.T) {
+//		gsrftest.RunAdapterGolden(t, myformat.Converter, "testdata")
+//	}
+//
+// Each NAME.in file in the directory holds one input per line; blank lines
+// and lines starting with # are skipped. The results are compared with
+// NAME.golden, which is written instead when the test runs with the
+// -gsrftest.update flag:
+//
+//	go test -run TestMyFormat -gsrftest.update
+package gsrftest
+
+import (
+	"bufio"
+	"bytes"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/kis9a/gsrf"
+	"github.com/kis9a/gsrf/adapters"
+)
+
+var update = flag.Bool("gsrftest.update", false, "Rewrite gsrftest golden files with the current results")
+
+// RunAdapterGolden runs a subtest for each NAME.in file in dir. Every input
+// becomes one golden case of up to three lines:
+//
+//	in:   the input line
+//	gsrf: the symbol conv.From parses, or "error: ..." if it fails
+//	out:  conv.To of that symbol, which must parse back to the same output
+//
+// A converter without From reads GSRF symbols, and one without To has no
+// out line. Mismatched cases are reported as a diff, with golden lines
+// prefixed by "-" and current ones by "+".
+func RunAdapterGolden(t *testing.T, conv adapters.Converter, dir string) {
+	t.Helper()
+	if conv.From == nil && conv.To == nil {
+		t.Fatalf("converter %q can neither read nor write symbols", conv.Name)
+	}
+	inputs, err := filepath.Glob(filepath.Join(dir, "*.in"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(inputs) == 0 {
+		t.Fatalf("no *.in files in %s", dir)
+	}
+	sort.Strings(inputs)
+
+	for _, in := range inputs {
+		name := strings.TrimSuffix(filepath.Base(in), ".in")
+		t.Run(name, func(t *testing.T) {
+			lines, err := readInputs(in)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := make([]string, len(lines))
+			for i, line := range lines {
+				got[i] = goldenCase(t, conv, line)
+			}
+
+			golden := strings.TrimSuffix(in, ".in") + ".golden"
+			if *update {
+				if err := os.WriteFile(golden, []byte(strings.Join(got, "\n")), 0o644); err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+			data, err := os.ReadFile(golden)
+			if err != nil {
+				t.Fatalf("%v (run with -gsrftest.update to create it)", err)
+			}
+			compare(t, splitCases(string(data)), got)
+		})
+	}
+}
+
+func readInputs(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var lines []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		lines = append(lines, line)
+	}
+	return lines, scanner.Err()
+}
+
+// goldenCase renders the case for one input, ending with a newline.
+func goldenCase(t *testing.T, conv adapters.Converter, input string) string {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "in:   %s\n", input)
+	from := conv.From
+	if from == nil {
+		from = gsrf.Parse
+	}
+	sym, err := from(input)
+	if err != nil {
+		fmt.Fprintf(&b, "gsrf: error: %v\n", err)
+		return b.String()
+	}
+	fmt.Fprintf(&b, "gsrf: %s\n", sym.Format())
+	if conv.To == nil {
+		return b.String()
+	}
+	out := conv.To(sym)
+	fmt.Fprintf(&b, "out:  %s\n", out)
+
+	// A format that can be read back must reproduce its own output.
+	if conv.From != nil {
+		back, err := conv.From(out)
+		if err != nil {
+			t.Errorf("%s: output %q does not parse: %v", input, out, err)
+		} else if again := conv.To(back); again != out {
+			t.Errorf("%s: output %q does not round-trip, got %q", input, out, again)
+		}
+	}
+	return b.String()
+}
+
+// splitCases splits a golden file into cases separated by blank lines.
+func splitCases(data string) []string {
+	var cases []string
+	for _, c := range strings.Split(strings.TrimSpace(data), "\n\n") {
+		if c != "" {
+			cases = append(cases, c+"\n")
+		}
+	}
+	return cases
+}
+
+func compare(t *testing.T, want, got []string) {
+	t.Helper()
+	for i := 0; i < len(want) || i < len(got); i++ {
+		var w, g string
+		if i < len(want) {
+			w = want[i]
+		}
+		if i < len(got) {
+			g = got[i]
+		}
+		if w == g {
+			continue
+		}
+		// Cases have the same line layout, so lines are compared in place.
+		var diff strings.Builder
+		wl, gl := strings.Split(strings.TrimSuffix(w, "\n"), "\n"), strings.Split(strings.TrimSuffix(g, "\n"), "\n")
+		for j := 0; j < len(wl) || j < len(gl); j++ {
+			switch {
+			case j < len(wl) && j < len(gl) && wl[j] == gl[j]:
+				diff.WriteString(" " + wl[j] + "\n")
+			default:
+				if j < len(wl) && wl[j] != "" {
+					diff.WriteString("-" + wl[j] + "\n")
+				}
+				if j < len(gl) && gl[j] != "" {
+					diff.WriteString("+" + gl[j] + "\n")
+				}
+			}
+		}
+		t.Errorf("case %d differs from golden (-want +got):\n%s", i+1, diff.String())
+	}
+}
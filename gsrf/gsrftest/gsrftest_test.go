@@ -0,0 +1,19 @@
+package gsrftest
+
+import (
+	"testing"
+
+	"github.com/kis9a/gsrf/adapters"
+)
+
+func TestRunAdapterGolden(t *testing.T) {
+	for _, name := range []string{"ssa", "stacktrace", "doc"} {
+		conv, ok := adapters.Lookup(name)
+		if !ok {
+			t.Fatalf("no %s converter", name)
+		}
+		t.Run(name, func(t *testing.T) {
+			RunAdapterGolden(t, *conv, "testdata/"+name)
+		})
+	}
+}
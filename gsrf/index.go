@@ -0,0 +1,131 @@
+package gsrf
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// fingerprintIndexMagic starts the header line of a persisted index.
+const fingerprintIndexMagic = "gsrf-fingerprints"
+
+// FingerprintIndex maps symbol fingerprints to symbols. Entries keep
+// insertion order; the first symbol added for a fingerprint wins.
+type FingerprintIndex struct {
+	order   []uint64
+	entries map[uint64]*Symbol
+}
+
+// NewFingerprintIndex returns an empty index.
+func NewFingerprintIndex() *FingerprintIndex {
+	return &FingerprintIndex{entries: make(map[uint64]*Symbol)}
+}
+
+// Add indexes the symbol and returns its fingerprint.
+func (x *FingerprintIndex) Add(sym *Symbol) uint64 {
+	fp := sym.Fingerprint()
+	x.put(fp, sym)
+	return fp
+}
+
+func (x *FingerprintIndex) put(fp uint64, sym *Symbol) {
+	if _, ok := x.entries[fp]; ok {
+		return
+	}
+	x.order = append(x.order, fp)
+	x.entries[fp] = sym
+}
+
+// Lookup returns the symbol indexed under fp.
+func (x *FingerprintIndex) Lookup(fp uint64) (*Symbol, bool) {
+	sym, ok := x.entries[fp]
+	return sym, ok
+}
+
+// Len returns the number of indexed fingerprints.
+func (x *FingerprintIndex) Len() int {
+	return len(x.order)
+}
+
+// WriteTo writes the index as text: a header line recording FingerprintVersion
+// and RulesHash, then one "<fingerprint hex>\t<symbol>" line per entry.
+func (x *FingerprintIndex) WriteTo(w io.Writer) (int64, error) {
+	bw := bufio.NewWriter(w)
+	var n int64
+	write := func(format string, args ...any) {
+		m, _ := fmt.Fprintf(bw, format, args...)
+		n += int64(m)
+	}
+	write("%s %d rules=%s\n", fingerprintIndexMagic, FingerprintVersion, RulesHash)
+	for _, fp := range x.order {
+		write("%016x\t%s\n", fp, x.entries[fp].Format())
+	}
+	return n, bw.Flush()
+}
+
+// ReadFingerprintIndex loads an index written by WriteTo. If the index was
+// written under different rules, RulesRefuse returns a *RulesMismatchError
+// and RulesMigrate recomputes every fingerprint from the stored symbols.
+func ReadFingerprintIndex(r io.Reader, policy RulesPolicy) (*FingerprintIndex, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	if !scanner.Scan() {
+		if err := scanner.Err(); err != nil {
+			return nil, err
+		}
+		return nil, fmt.Errorf("fingerprint index: missing header")
+	}
+	stored, err := parseIndexHeader(scanner.Text())
+	if err != nil {
+		return nil, err
+	}
+	migrate := false
+	if err := CheckRulesHash("fingerprint index", stored); err != nil {
+		if policy != RulesMigrate {
+			return nil, err
+		}
+		migrate = true
+	}
+
+	x := NewFingerprintIndex()
+	lineNo := 1
+	for scanner.Scan() {
+		lineNo++
+		line := scanner.Text()
+		if line == "" {
+			continue
+		}
+		hexFP, text, ok := strings.Cut(line, "\t")
+		if !ok {
+			return nil, fmt.Errorf("fingerprint index: line %d: missing tab separator", lineNo)
+		}
+		sym, err := Parse(text)
+		if err != nil {
+			return nil, fmt.Errorf("fingerprint index: line %d: %w", lineNo, err)
+		}
+		if migrate {
+			x.Add(sym)
+			continue
+		}
+		fp, err := strconv.ParseUint(hexFP, 16, 64)
+		if err != nil {
+			return nil, fmt.Errorf("fingerprint index: line %d: invalid fingerprint %q", lineNo, hexFP)
+		}
+		x.put(fp, sym)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// parseIndexHeader validates the header line and returns its rules hash.
+func parseIndexHeader(line string) (string, error) {
+	fields := strings.Fields(line)
+	if len(fields) != 3 || fields[0] != fingerprintIndexMagic || !strings.HasPrefix(fields[2], "rules=") {
+		return "", fmt.Errorf("fingerprint index: invalid header %q", line)
+	}
+	return strings.TrimPrefix(fields[2], "rules="), nil
+}
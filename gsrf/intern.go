@@ -0,0 +1,63 @@
+package gsrf
+
+import (
+	"strings"
+	"sync"
+)
+
+// Interner deduplicates strings that repeat across many symbols, such as
+// package paths, so that large symbol sets share one copy of each. Interned
+// strings are copied, so they do not keep the parsed input alive. An
+// Interner is safe for concurrent use; the zero value is ready to use.
+type Interner struct {
+	mu      sync.RWMutex
+	strings map[string]string
+}
+
+// NewInterner returns an empty Interner.
+func NewInterner() *Interner {
+	return &Interner{}
+}
+
+// Intern returns the interned copy of s, adding one if s is new.
+func (in *Interner) Intern(s string) string {
+	if s == "" {
+		return ""
+	}
+	in.mu.RLock()
+	v, ok := in.strings[s]
+	in.mu.RUnlock()
+	if ok {
+		return v
+	}
+
+	in.mu.Lock()
+	defer in.mu.Unlock()
+	if v, ok := in.strings[s]; ok {
+		return v
+	}
+	if in.strings == nil {
+		in.strings = make(map[string]string)
+	}
+	v = strings.Clone(s)
+	in.strings[v] = v
+	return v
+}
+
+// Len returns the number of distinct strings interned.
+func (in *Interner) Len() int {
+	in.mu.RLock()
+	defer in.mu.RUnlock()
+	return len(in.strings)
+}
+
+// InternSymbol replaces the symbol's package path, receiver type name,
+// anonymous parent, and context with their interned copies.
+func (in *Interner) InternSymbol(s *Symbol) {
+	s.PackagePath = in.Intern(s.PackagePath)
+	s.AnonParent = in.Intern(s.AnonParent)
+	s.Context = in.Intern(s.Context)
+	if s.Receiver != nil {
+		s.Receiver.TypeName = in.Intern(s.Receiver.TypeName)
+	}
+}
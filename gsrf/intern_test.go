@@ -0,0 +1,57 @@
+package gsrf
+
+import (
+	"sync"
+	"testing"
+	"unsafe"
+)
+
+func TestInterner(t *testing.T) {
+	var in Interner
+	a := in.Intern(string([]byte("net/http")))
+	b := in.Intern(string([]byte("net/http")))
+	if a != "net/http" || unsafe.StringData(a) != unsafe.StringData(b) {
+		t.Errorf("Intern() returned distinct copies %q and %q", a, b)
+	}
+	if in.Intern("") != "" || in.Len() != 1 {
+		t.Errorf("Len() = %d, want 1", in.Len())
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			in.Intern("fmt")
+		}()
+	}
+	wg.Wait()
+	if in.Len() != 2 {
+		t.Errorf("Len() = %d after concurrent use, want 2", in.Len())
+	}
+}
+
+func TestParseWith_Interner(t *testing.T) {
+	in := NewInterner()
+	opts := ParseOptions{Interner: in}
+	a, err := ParseWith("net/http.(*Server).Serve@linux", opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := ParseWith("net/http.(*Server).Close@linux", opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if unsafe.StringData(a.PackagePath) != unsafe.StringData(b.PackagePath) {
+		t.Error("PackagePath not shared")
+	}
+	if unsafe.StringData(a.Receiver.TypeName) != unsafe.StringData(b.Receiver.TypeName) {
+		t.Error("Receiver.TypeName not shared")
+	}
+	if a.String() != "net/http.(*Server).Serve@linux" {
+		t.Errorf("String() = %q", a.String())
+	}
+	if in.Len() != 3 {
+		t.Errorf("Len() = %d, want 3 (package, receiver, context)", in.Len())
+	}
+}
@@ -0,0 +1,341 @@
+// Package pprof decodes the parts of pprof profiles that symbol-level
+// reports need, without depending on the pprof module.
+package pprof
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+)
+
+// Profile holds the sample types, samples, and call stacks of a profile,
+// with string table references resolved.
+type Profile struct {
+	SampleTypes []ValueType
+	Samples     []Sample
+	Locations   map[uint64][]uint64 // location id -> function ids, inlined functions first
+	Functions   map[uint64]string   // function id -> name
+}
+
+// ValueType describes one value of every sample, e.g. cpu/nanoseconds.
+type ValueType struct {
+	Type string
+	Unit string
+}
+
+// Sample is a stack of location ids, innermost first, with one value per
+// sample type.
+type Sample struct {
+	Locations []uint64
+	Values    []int64
+}
+
+// SampleIndex returns the index of the first sample type accepted by match,
+// or -1.
+func (p *Profile) SampleIndex(match func(ValueType) bool) int {
+	for i, st := range p.SampleTypes {
+		if match(st) {
+			return i
+		}
+	}
+	return -1
+}
+
+// Stack returns the function names of a sample, innermost first.
+func (p *Profile) Stack(s Sample) []string {
+	var names []string
+	for _, loc := range s.Locations {
+		for _, fn := range p.Locations[loc] {
+			if name, ok := p.Functions[fn]; ok {
+				names = append(names, name)
+			}
+		}
+	}
+	return names
+}
+
+// Read decodes a profile, gzip-compressed or not.
+func Read(r io.Reader) (*Profile, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) >= 2 && data[0] == 0x1f && data[1] == 0x8b {
+		zr, err := gzip.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return nil, fmt.Errorf("pprof profile: %w", err)
+		}
+		if data, err = io.ReadAll(zr); err != nil {
+			return nil, fmt.Errorf("pprof profile: %w", err)
+		}
+	}
+	p, err := decode(data)
+	if err != nil {
+		return nil, fmt.Errorf("pprof profile: %w", err)
+	}
+	return p, nil
+}
+
+// Field numbers from github.com/google/pprof/proto/profile.proto.
+const (
+	fieldProfileSampleType  = 1
+	fieldProfileSample      = 2
+	fieldProfileLocation    = 4
+	fieldProfileFunction    = 5
+	fieldProfileStringTable = 6
+
+	fieldValueTypeType = 1
+	fieldValueTypeUnit = 2
+
+	fieldSampleLocationID = 1
+	fieldSampleValue      = 2
+
+	fieldLocationID   = 1
+	fieldLocationLine = 4
+	fieldLineFunction = 1
+
+	fieldFunctionID   = 1
+	fieldFunctionName = 2
+)
+
+func decode(data []byte) (*Profile, error) {
+	p := &Profile{Locations: make(map[uint64][]uint64), Functions: make(map[uint64]string)}
+	var sampleTypes [][2]uint64
+	functions := make(map[uint64]uint64)
+	var strs []string
+
+	err := eachField(data, func(num, wire int, v uint64, b []byte) error {
+		switch num {
+		case fieldProfileSampleType:
+			var st [2]uint64
+			err := eachField(b, func(num, wire int, v uint64, b []byte) error {
+				switch num {
+				case fieldValueTypeType:
+					st[0] = v
+				case fieldValueTypeUnit:
+					st[1] = v
+				}
+				return nil
+			})
+			sampleTypes = append(sampleTypes, st)
+			return err
+		case fieldProfileSample:
+			var s Sample
+			err := eachField(b, func(num, wire int, v uint64, b []byte) error {
+				switch num {
+				case fieldSampleLocationID:
+					return appendVarints(&s.Locations, wire, v, b, func(x uint64) uint64 { return x })
+				case fieldSampleValue:
+					return appendVarints(&s.Values, wire, v, b, func(x uint64) int64 { return int64(x) })
+				}
+				return nil
+			})
+			p.Samples = append(p.Samples, s)
+			return err
+		case fieldProfileLocation:
+			var id uint64
+			var fns []uint64
+			err := eachField(b, func(num, wire int, v uint64, b []byte) error {
+				switch num {
+				case fieldLocationID:
+					id = v
+				case fieldLocationLine:
+					return eachField(b, func(num, wire int, v uint64, b []byte) error {
+						if num == fieldLineFunction {
+							fns = append(fns, v)
+						}
+						return nil
+					})
+				}
+				return nil
+			})
+			p.Locations[id] = fns
+			return err
+		case fieldProfileFunction:
+			var id, name uint64
+			err := eachField(b, func(num, wire int, v uint64, b []byte) error {
+				switch num {
+				case fieldFunctionID:
+					id = v
+				case fieldFunctionName:
+					name = v
+				}
+				return nil
+			})
+			functions[id] = name
+			return err
+		case fieldProfileStringTable:
+			strs = append(strs, string(b))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	str := func(i uint64) string {
+		if i >= uint64(len(strs)) {
+			return ""
+		}
+		return strs[i]
+	}
+	for _, st := range sampleTypes {
+		p.SampleTypes = append(p.SampleTypes, ValueType{Type: str(st[0]), Unit: str(st[1])})
+	}
+	for id, name := range functions {
+		p.Functions[id] = str(name)
+	}
+	return p, nil
+}
+
+var errTruncated = errors.New("truncated message")
+
+// eachField calls fn for every field of a protobuf message. Varint fields
+// pass their value in v; length-delimited fields pass their bytes in b.
+// Fixed-width fields are skipped.
+func eachField(data []byte, fn func(num, wire int, v uint64, b []byte) error) error {
+	for len(data) > 0 {
+		tag, n := binary.Uvarint(data)
+		if n <= 0 {
+			return errTruncated
+		}
+		data = data[n:]
+		num, wire := int(tag>>3), int(tag&7)
+
+		var v uint64
+		var b []byte
+		switch wire {
+		case 0:
+			v, n = binary.Uvarint(data)
+			if n <= 0 {
+				return errTruncated
+			}
+			data = data[n:]
+		case 1, 5:
+			size := 8
+			if wire == 5 {
+				size = 4
+			}
+			if len(data) < size {
+				return errTruncated
+			}
+			data = data[size:]
+			continue
+		case 2:
+			length, n := binary.Uvarint(data)
+			if n <= 0 || length > uint64(len(data)-n) {
+				return errTruncated
+			}
+			b = data[n : n+int(length)]
+			data = data[n+int(length):]
+		default:
+			return fmt.Errorf("unsupported wire type %d", wire)
+		}
+		if err := fn(num, wire, v, b); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// appendVarints appends a repeated varint field, packed or not.
+func appendVarints[T any](dst *[]T, wire int, v uint64, b []byte, conv func(uint64) T) error {
+	if wire != 2 {
+		*dst = append(*dst, conv(v))
+		return nil
+	}
+	for len(b) > 0 {
+		x, n := binary.Uvarint(b)
+		if n <= 0 {
+			return errTruncated
+		}
+		*dst = append(*dst, conv(x))
+		b = b[n:]
+	}
+	return nil
+}
+
+// Write encodes the profile, uncompressed. Function names are interned in
+// the string table; ids are kept.
+func Write(w io.Writer, p *Profile) error {
+	strs := []string{""}
+	index := map[string]uint64{"": 0}
+	intern := func(s string) uint64 {
+		i, ok := index[s]
+		if !ok {
+			i = uint64(len(strs))
+			index[s] = i
+			strs = append(strs, s)
+		}
+		return i
+	}
+
+	var out message
+	for _, st := range p.SampleTypes {
+		var m message
+		m.varint(fieldValueTypeType, intern(st.Type))
+		m.varint(fieldValueTypeUnit, intern(st.Unit))
+		out.bytes(fieldProfileSampleType, m)
+	}
+	for _, s := range p.Samples {
+		var locs, vals message
+		for _, l := range s.Locations {
+			locs = binary.AppendUvarint(locs, l)
+		}
+		for _, v := range s.Values {
+			vals = binary.AppendUvarint(vals, uint64(v))
+		}
+		var m message
+		m.bytes(fieldSampleLocationID, locs)
+		m.bytes(fieldSampleValue, vals)
+		out.bytes(fieldProfileSample, m)
+	}
+	for _, id := range sortedIDs(p.Locations) {
+		var m message
+		m.varint(fieldLocationID, id)
+		for _, fn := range p.Locations[id] {
+			var line message
+			line.varint(fieldLineFunction, fn)
+			m.bytes(fieldLocationLine, line)
+		}
+		out.bytes(fieldProfileLocation, m)
+	}
+	for _, id := range sortedIDs(p.Functions) {
+		var m message
+		m.varint(fieldFunctionID, id)
+		m.varint(fieldFunctionName, intern(p.Functions[id]))
+		out.bytes(fieldProfileFunction, m)
+	}
+	for _, s := range strs {
+		out.bytes(fieldProfileStringTable, []byte(s))
+	}
+	_, err := w.Write(out)
+	return err
+}
+
+// message accumulates encoded protobuf fields.
+type message []byte
+
+func (m *message) varint(num int, v uint64) {
+	*m = binary.AppendUvarint(*m, uint64(num)<<3)
+	*m = binary.AppendUvarint(*m, v)
+}
+
+func (m *message) bytes(num int, b []byte) {
+	*m = binary.AppendUvarint(*m, uint64(num)<<3|2)
+	*m = binary.AppendUvarint(*m, uint64(len(b)))
+	*m = append(*m, b...)
+}
+
+func sortedIDs[V any](m map[uint64]V) []uint64 {
+	ids := make([]uint64, 0, len(m))
+	for id := range m {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids
+}
@@ -0,0 +1,78 @@
+// Package schema converts symbols to and from generic values following the
+// stable JSON object schema, so binary codecs share one field layout.
+package schema
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/kis9a/gsrf"
+)
+
+// MaxDepth bounds nesting accepted by decoders. The schema itself is at most
+// three levels deep.
+const MaxDepth = 16
+
+// ToValue returns the symbol as a generic value built from nil, bool, string,
+// int64, []any and map[string]any.
+func ToValue(s *gsrf.Symbol) (map[string]any, error) {
+	data, err := json.Marshal(s)
+	if err != nil {
+		return nil, err
+	}
+	var v map[string]any
+	if err := json.Unmarshal(data, &v); err != nil {
+		return nil, err
+	}
+	return integers(v).(map[string]any), nil
+}
+
+// FromValue rebuilds a symbol from a decoded generic value. Strings are
+// parsed as GSRF; maps must follow the object schema.
+func FromValue(v any) (*gsrf.Symbol, error) {
+	switch v.(type) {
+	case string, map[string]any:
+	default:
+		return nil, fmt.Errorf("invalid GSRF symbol: expected string or map, got %T", v)
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var sym gsrf.Symbol
+	if err := json.Unmarshal(data, &sym); err != nil {
+		return nil, err
+	}
+	return &sym, nil
+}
+
+// SortedKeys returns the keys of m in byte order, for deterministic output.
+func SortedKeys(m map[string]any) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// integers replaces integral JSON numbers with int64.
+func integers(v any) any {
+	switch x := v.(type) {
+	case float64:
+		if x == math.Trunc(x) && math.Abs(x) < 1<<53 {
+			return int64(x)
+		}
+	case []any:
+		for i := range x {
+			x[i] = integers(x[i])
+		}
+	case map[string]any:
+		for k := range x {
+			x[k] = integers(x[k])
+		}
+	}
+	return v
+}
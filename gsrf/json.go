@@ -0,0 +1,192 @@
+package gsrf
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"gopkg.in/yaml.v3"
+)
+
+// symbolJSON is the stable JSON schema for Symbol, also accepted in YAML.
+// Keys are lowercase, empty fields are omitted, and the receiver is
+// flattened into the symbol:
+//
+//	{
+//	  "package": "net/http",
+//	  "name": "Serve",
+//	  "receiver": "Server",
+//	  "receiver_pointer": true,
+//	  "receiver_type_args": ["T"],
+//	  "init": true,
+//	  "anonymous": true,
+//	  "anon_parent": "main.main",
+//	  "anon_index": 2,
+//	  "type_params": [{"name": "T", "constraint": "comparable"}],
+//	  "type_args": ["string"],
+//	  "context": "linux",
+//	  "metadata": {"via": "Base", "alias": "T", "pos": "a.go:1:1", "custom": {"k": "v"}}
+//	}
+//
+// A single via entry is a string; a promotion path through several embedded
+// types is an array, outermost first: "via": ["Outer", "Inner"].
+type symbolJSON struct {
+	Package          string          `json:"package" yaml:"package"`
+	Name             string          `json:"name" yaml:"name"`
+	Receiver         string          `json:"receiver,omitempty" yaml:"receiver,omitempty"`
+	ReceiverPointer  bool            `json:"receiver_pointer,omitempty" yaml:"receiver_pointer,omitempty"`
+	ReceiverTypeArgs []string        `json:"receiver_type_args,omitempty" yaml:"receiver_type_args,omitempty"`
+	Init             bool            `json:"init,omitempty" yaml:"init,omitempty"`
+	Anonymous        bool            `json:"anonymous,omitempty" yaml:"anonymous,omitempty"`
+	AnonParent       string          `json:"anon_parent,omitempty" yaml:"anon_parent,omitempty"`
+	AnonIndex        int             `json:"anon_index,omitempty" yaml:"anon_index,omitempty"`
+	TypeParams       []typeParamJSON `json:"type_params,omitempty" yaml:"type_params,omitempty"`
+	TypeArgs         []string        `json:"type_args,omitempty" yaml:"type_args,omitempty"`
+	Context          string          `json:"context,omitempty" yaml:"context,omitempty"`
+	Metadata         *metadataJSON   `json:"metadata,omitempty" yaml:"metadata,omitempty"`
+}
+
+type typeParamJSON struct {
+	Name       string `json:"name" yaml:"name"`
+	Constraint string `json:"constraint,omitempty" yaml:"constraint,omitempty"`
+}
+
+type metadataJSON struct {
+	Via      viaJSON           `json:"via,omitempty" yaml:"via,omitempty"`
+	Alias    string            `json:"alias,omitempty" yaml:"alias,omitempty"`
+	Position string            `json:"pos,omitempty" yaml:"pos,omitempty"`
+	Custom   map[string]string `json:"custom,omitempty" yaml:"custom,omitempty"`
+}
+
+// viaJSON is Metadata.Via in the object schema: a string for one entry, an
+// array for several.
+type viaJSON []string
+
+func (v viaJSON) MarshalJSON() ([]byte, error) {
+	if len(v) == 1 {
+		return json.Marshal(v[0])
+	}
+	return json.Marshal([]string(v))
+}
+
+func (v *viaJSON) UnmarshalJSON(data []byte) error {
+	var one string
+	if err := json.Unmarshal(data, &one); err == nil {
+		*v = nil
+		if one != "" {
+			*v = viaJSON{one}
+		}
+		return nil
+	}
+	return json.Unmarshal(data, (*[]string)(v))
+}
+
+func (v viaJSON) MarshalYAML() (interface{}, error) {
+	if len(v) == 1 {
+		return v[0], nil
+	}
+	return []string(v), nil
+}
+
+func (v *viaJSON) UnmarshalYAML(value *yaml.Node) error {
+	if value.Kind == yaml.ScalarNode {
+		*v = nil
+		if value.Value != "" {
+			*v = viaJSON{value.Value}
+		}
+		return nil
+	}
+	return value.Decode((*[]string)(v))
+}
+
+// MarshalJSON implements json.Marshaler using the stable object schema.
+func (s *Symbol) MarshalJSON() ([]byte, error) {
+	w := symbolJSON{
+		Package:    s.PackagePath,
+		Name:       s.Name,
+		Init:       s.IsInit,
+		Anonymous:  s.IsAnonymous,
+		AnonParent: s.AnonParent,
+		AnonIndex:  s.AnonIndex,
+		TypeArgs:   s.TypeArgs,
+		Context:    s.Context,
+	}
+	if s.Receiver != nil {
+		w.Receiver = s.Receiver.TypeName
+		w.ReceiverPointer = s.Receiver.IsPointer
+		w.ReceiverTypeArgs = s.Receiver.TypeArgs
+	}
+	for _, tp := range s.TypeParams {
+		w.TypeParams = append(w.TypeParams, typeParamJSON{Name: tp.Name, Constraint: tp.Constraint})
+	}
+	if hasMetadata(s.Metadata) {
+		w.Metadata = &metadataJSON{
+			Via:      viaJSON(s.Metadata.Via),
+			Alias:    s.Metadata.Alias,
+			Position: s.Metadata.Position,
+			Custom:   s.Metadata.Custom,
+		}
+	}
+	return json.Marshal(w)
+}
+
+// UnmarshalJSON implements json.Unmarshaler. It accepts both the object
+// schema produced by MarshalJSON and a GSRF string.
+func (s *Symbol) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if len(data) > 0 && data[0] == '"' {
+		var text string
+		if err := json.Unmarshal(data, &text); err != nil {
+			return err
+		}
+		return s.UnmarshalText([]byte(text))
+	}
+
+	var w symbolJSON
+	if err := json.Unmarshal(data, &w); err != nil {
+		return err
+	}
+	sym, err := w.symbol()
+	if err != nil {
+		return err
+	}
+	*s = sym
+	return nil
+}
+
+// symbol converts the object schema back to a Symbol.
+func (w *symbolJSON) symbol() (Symbol, error) {
+	if w.Package == "" || (w.Name == "" && !w.Anonymous) {
+		return Symbol{}, fmt.Errorf("invalid GSRF symbol: object requires package and name")
+	}
+
+	sym := Symbol{
+		PackagePath: w.Package,
+		Name:        w.Name,
+		IsInit:      w.Init,
+		IsAnonymous: w.Anonymous,
+		AnonParent:  w.AnonParent,
+		AnonIndex:   w.AnonIndex,
+		TypeArgs:    w.TypeArgs,
+		Context:     w.Context,
+	}
+	if w.Receiver != "" {
+		sym.Receiver = &Receiver{
+			TypeName:  w.Receiver,
+			IsPointer: w.ReceiverPointer,
+			TypeArgs:  w.ReceiverTypeArgs,
+		}
+	}
+	for _, tp := range w.TypeParams {
+		sym.TypeParams = append(sym.TypeParams, TypeParam{Name: tp.Name, Constraint: tp.Constraint})
+	}
+	if w.Metadata != nil {
+		sym.Metadata = Metadata{
+			Via:      []string(w.Metadata.Via),
+			Alias:    w.Metadata.Alias,
+			Position: w.Metadata.Position,
+			Custom:   w.Metadata.Custom,
+		}
+	}
+	return sym, nil
+}
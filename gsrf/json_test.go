@@ -0,0 +1,108 @@
+package gsrf
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestSymbol_MarshalJSON(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{
+			name:  "function",
+			input: "fmt.Println",
+			want:  `{"package":"fmt","name":"Println"}`,
+		},
+		{
+			name:  "method",
+			input: "pkg.(*Cache[K, V]).Get[string]@linux",
+			want:  `{"package":"pkg","name":"Get","receiver":"Cache","receiver_pointer":true,"receiver_type_args":["K","V"],"type_args":["string"],"context":"linux"}`,
+		},
+		{
+			name:  "anonymous",
+			input: "main.main·lit2",
+			want:  `{"package":"main","name":"main","anonymous":true,"anon_parent":"main.main","anon_index":2}`,
+		},
+		{
+			name:  "metadata",
+			input: "io.(*MultiWriter).Write{via:Writer,pos:multi.go:25:1,team:io}",
+			want:  `{"package":"io","name":"Write","receiver":"MultiWriter","receiver_pointer":true,"metadata":{"via":"Writer","pos":"multi.go:25:1","custom":{"team":"io"}}}`,
+		},
+		{
+			name:  "promotion path",
+			input: "pkg.(*T).Close{via:Outer,via:Inner}",
+			want:  `{"package":"pkg","name":"Close","receiver":"T","receiver_pointer":true,"metadata":{"via":["Outer","Inner"]}}`,
+		},
+		{
+			name:  "package-level anonymous",
+			input: "pkg.·lit",
+			want:  `{"package":"pkg","name":"","anonymous":true,"anon_parent":"pkg."}`,
+		},
+		{
+			name:  "init",
+			input: "database/sql.init",
+			want:  `{"package":"database/sql","name":"init","init":true}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sym := MustParse(tt.input)
+			data, err := json.Marshal(sym)
+			if err != nil {
+				t.Fatalf("Marshal() error = %v", err)
+			}
+			if string(data) != tt.want {
+				t.Errorf("Marshal() = %s, want %s", data, tt.want)
+			}
+
+			var back Symbol
+			if err := json.Unmarshal(data, &back); err != nil {
+				t.Fatalf("Unmarshal() error = %v", err)
+			}
+			if !back.Equal(sym) {
+				t.Errorf("round trip = %+v, want %+v", back, sym)
+			}
+		})
+	}
+}
+
+func TestSymbol_UnmarshalJSON(t *testing.T) {
+	t.Run("string form", func(t *testing.T) {
+		var sym Symbol
+		if err := json.Unmarshal([]byte(`"net/http.(*Server).Serve"`), &sym); err != nil {
+			t.Fatalf("Unmarshal() error = %v", err)
+		}
+		if !sym.Equal(MustParse("net/http.(*Server).Serve")) {
+			t.Errorf("Unmarshal() = %+v", sym)
+		}
+	})
+
+	t.Run("type params", func(t *testing.T) {
+		var sym Symbol
+		data := `{"package":"pkg","name":"Map","type_params":[{"name":"K","constraint":"comparable"},{"name":"V"}]}`
+		if err := json.Unmarshal([]byte(data), &sym); err != nil {
+			t.Fatalf("Unmarshal() error = %v", err)
+		}
+		if got := sym.Format(); got != "pkg.Map[K comparable, V]" {
+			t.Errorf("Format() = %q", got)
+		}
+	})
+
+	errorCases := map[string]string{
+		"missing name":   `{"package":"pkg"}`,
+		"invalid string": `"invalid"`,
+		"wrong type":     `42`,
+	}
+	for name, data := range errorCases {
+		t.Run(name, func(t *testing.T) {
+			var sym Symbol
+			if err := json.Unmarshal([]byte(data), &sym); err == nil {
+				t.Errorf("Unmarshal(%s) expected error", data)
+			}
+		})
+	}
+}
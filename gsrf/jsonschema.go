@@ -0,0 +1,86 @@
+package gsrf
+
+// jsonSchema describes the JSON encoding of Symbol: the object schema of
+// MarshalJSON, or a GSRF string, which UnmarshalJSON also accepts.
+const jsonSchema = `{
+  "$schema": "https://json-schema.org/draft/2020-12/schema",
+  "title": "GSRF Symbol",
+  "description": "A Go symbol in the GSRF object schema, or its GSRF string form.",
+  "oneOf": [
+    {
+      "type": "string",
+      "description": "GSRF notation, e.g. net/http.(*Server).Serve",
+      "minLength": 1
+    },
+    {"$ref": "#/$defs/symbol"}
+  ],
+  "$defs": {
+    "symbol": {
+      "type": "object",
+      "properties": {
+        "package": {"type": "string", "minLength": 1, "description": "Full package import path"},
+        "name": {"type": "string", "description": "Function or method name; for anonymous functions, the enclosing function's"},
+        "receiver": {"type": "string", "minLength": 1, "description": "Receiver type name of a method"},
+        "receiver_pointer": {"type": "boolean", "description": "Pointer receiver"},
+        "receiver_type_args": {"$ref": "#/$defs/types", "description": "Type arguments of a generic receiver"},
+        "init": {"type": "boolean", "description": "Package init function"},
+        "anonymous": {"type": "boolean", "description": "Function literal"},
+        "anon_parent": {"type": "string", "description": "Symbol of the enclosing function"},
+        "anon_index": {"type": "integer", "minimum": 1, "description": "Index of the function literal"},
+        "type_params": {
+          "type": "array",
+          "items": {
+            "type": "object",
+            "properties": {
+              "name": {"type": "string", "minLength": 1},
+              "constraint": {"type": "string", "description": "Constraint; any if omitted"}
+            },
+            "required": ["name"],
+            "additionalProperties": false
+          }
+        },
+        "type_args": {"$ref": "#/$defs/types", "description": "Type arguments of an instantiation"},
+        "context": {"type": "string", "minLength": 1, "description": "Build context, e.g. linux or cgo"},
+        "metadata": {"$ref": "#/$defs/metadata"}
+      },
+      "required": ["package"],
+      "if": {
+        "properties": {"anonymous": {"const": true}},
+        "required": ["anonymous"]
+      },
+      "else": {
+        "properties": {"name": {"minLength": 1}},
+        "required": ["name"]
+      },
+      "additionalProperties": false
+    },
+    "metadata": {
+      "type": "object",
+      "properties": {
+        "via": {
+          "description": "Embedding path of a promoted method, outermost embedded type first",
+          "oneOf": [
+            {"type": "string"},
+            {"type": "array", "items": {"type": "string"}}
+          ]
+        },
+        "alias": {"type": "string", "description": "Alias sources, outermost first, joined by >"},
+        "pos": {"type": "string", "description": "Source position (file:line:col)"},
+        "custom": {"type": "object", "additionalProperties": {"type": "string"}}
+      },
+      "additionalProperties": false
+    },
+    "types": {
+      "type": "array",
+      "items": {"type": "string", "minLength": 1}
+    }
+  }
+}
+`
+
+// JSONSchema returns a JSON Schema (draft 2020-12) document describing the
+// JSON encoding of Symbol, for validating and generating bindings in other
+// languages.
+func JSONSchema() []byte {
+	return []byte(jsonSchema)
+}
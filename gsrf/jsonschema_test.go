@@ -0,0 +1,65 @@
+package gsrf
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestJSONSchema(t *testing.T) {
+	var schema struct {
+		Defs map[string]struct {
+			Properties map[string]struct {
+				Items struct {
+					Properties map[string]any `json:"properties"`
+				} `json:"items"`
+			} `json:"properties"`
+		} `json:"$defs"`
+	}
+	if err := json.Unmarshal(JSONSchema(), &schema); err != nil {
+		t.Fatalf("JSONSchema() is not valid JSON: %v", err)
+	}
+
+	// The schema must list exactly the keys the encoding uses.
+	keys := func(m map[string]any) []string {
+		var out []string
+		for k := range m {
+			out = append(out, k)
+		}
+		sort.Strings(out)
+		return out
+	}
+	tags := func(v any) []string {
+		var out []string
+		typ := reflect.TypeOf(v)
+		for i := 0; i < typ.NumField(); i++ {
+			name, _, _ := strings.Cut(typ.Field(i).Tag.Get("json"), ",")
+			out = append(out, name)
+		}
+		sort.Strings(out)
+		return out
+	}
+	props := func(def string) map[string]any {
+		m := map[string]any{}
+		for k := range schema.Defs[def].Properties {
+			m[k] = nil
+		}
+		return m
+	}
+
+	for _, tt := range []struct {
+		name string
+		got  []string
+		want []string
+	}{
+		{"symbol", keys(props("symbol")), tags(symbolJSON{})},
+		{"metadata", keys(props("metadata")), tags(metadataJSON{})},
+		{"type_params", keys(schema.Defs["symbol"].Properties["type_params"].Items.Properties), tags(typeParamJSON{})},
+	} {
+		if !reflect.DeepEqual(tt.got, tt.want) {
+			t.Errorf("%s properties = %v, want %v", tt.name, tt.got, tt.want)
+		}
+	}
+}
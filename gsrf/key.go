@@ -0,0 +1,99 @@
+package gsrf
+
+import (
+	"strings"
+)
+
+// SymbolKey is a flattened, comparable form of a Symbol's identity, suitable
+// as a Go map key. Metadata is not part of the key. List fields hold the
+// elements joined by ", ".
+type SymbolKey struct {
+	PackagePath      string
+	Name             string
+	ReceiverType     string // Empty for functions
+	ReceiverPointer  bool
+	ReceiverTypeArgs string
+	IsInit           bool
+	IsAnonymous      bool
+	AnonIndex        int
+	TypeArgs         string
+	TypeParams       string // Type parameters as formatted, e.g. "K comparable, V"
+	Context          string
+}
+
+// Key returns the comparable key for the symbol.
+func (s *Symbol) Key() SymbolKey {
+	k := SymbolKey{
+		PackagePath: s.PackagePath,
+		Name:        s.Name,
+		IsInit:      s.IsInit,
+		IsAnonymous: s.IsAnonymous,
+		AnonIndex:   s.AnonIndex,
+		TypeArgs:    joinTypes(s.TypeArgs),
+		Context:     s.Context,
+	}
+	if s.Receiver != nil {
+		k.ReceiverType = s.Receiver.TypeName
+		k.ReceiverPointer = s.Receiver.IsPointer
+		k.ReceiverTypeArgs = joinTypes(s.Receiver.TypeArgs)
+	}
+	if len(s.TypeParams) > 0 {
+		b := appendTypeList(nil, nil, s.TypeParams)
+		k.TypeParams = string(b[1 : len(b)-1])
+	}
+	return k
+}
+
+// KeyOf parses a GSRF string and returns its key.
+func KeyOf(input string) (SymbolKey, error) {
+	sym, err := Parse(input)
+	if err != nil {
+		return SymbolKey{}, err
+	}
+	return sym.Key(), nil
+}
+
+// Symbol reconstructs a symbol from the key. The result has no metadata.
+func (k SymbolKey) Symbol() *Symbol {
+	s := &Symbol{
+		PackagePath: k.PackagePath,
+		Name:        k.Name,
+		IsInit:      k.IsInit,
+		IsAnonymous: k.IsAnonymous,
+		AnonIndex:   k.AnonIndex,
+		TypeArgs:    splitFieldList(k.TypeArgs),
+		Context:     k.Context,
+	}
+	if k.IsAnonymous {
+		s.AnonParent = k.PackagePath + "." + k.Name
+	}
+	if k.ReceiverType != "" {
+		s.Receiver = &Receiver{
+			TypeName:  k.ReceiverType,
+			IsPointer: k.ReceiverPointer,
+			TypeArgs:  splitFieldList(k.ReceiverTypeArgs),
+		}
+	}
+	for _, p := range splitFieldList(k.TypeParams) {
+		name, constraint, _ := strings.Cut(p, " ")
+		s.TypeParams = append(s.TypeParams, TypeParam{Name: name, Constraint: strings.TrimSpace(constraint)})
+	}
+	return s
+}
+
+// String returns the GSRF form of the key.
+func (k SymbolKey) String() string {
+	return k.Symbol().Format()
+}
+
+// joinTypes joins a type list without allocating for zero or one element.
+func joinTypes(types []string) string {
+	switch len(types) {
+	case 0:
+		return ""
+	case 1:
+		return types[0]
+	default:
+		return strings.Join(types, ", ")
+	}
+}
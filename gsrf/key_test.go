@@ -0,0 +1,69 @@
+package gsrf
+
+import (
+	"testing"
+)
+
+func TestSymbol_Key(t *testing.T) {
+	inputs := []string{
+		"fmt.Println",
+		"net/http.(*Server).Serve",
+		"pkg.(*Cache[K, V]).Get[string]@linux",
+		"main.main·lit2",
+		"database/sql.init",
+	}
+
+	seen := make(map[SymbolKey]string)
+	for _, in := range inputs {
+		k, err := KeyOf(in)
+		if err != nil {
+			t.Fatalf("KeyOf(%q) error = %v", in, err)
+		}
+		if prev, dup := seen[k]; dup {
+			t.Errorf("KeyOf(%q) collides with %q", in, prev)
+		}
+		seen[k] = in
+		if got := k.String(); got != in {
+			t.Errorf("KeyOf(%q).String() = %q", in, got)
+		}
+	}
+
+	a := MustParse("pkg.F{pos:a.go:1:1}").Key()
+	b := MustParse("pkg.F{pos:b.go:2:2,team:x}").Key()
+	if a != b {
+		t.Errorf("keys differ by metadata: %+v vs %+v", a, b)
+	}
+
+	if MustParse("pkg.(*T).M").Key() == MustParse("pkg.(T).M").Key() {
+		t.Error("pointer and value receivers share a key")
+	}
+}
+
+func TestSymbolKey_TypeParams(t *testing.T) {
+	sym := &Symbol{
+		PackagePath: "pkg",
+		Name:        "Map",
+		TypeParams:  []TypeParam{{Name: "K", Constraint: "comparable"}, {Name: "V"}},
+	}
+	k := sym.Key()
+	if k.TypeParams != "K comparable, V" {
+		t.Errorf("TypeParams = %q", k.TypeParams)
+	}
+	if back := k.Symbol(); !back.Equal(sym) {
+		t.Errorf("Symbol() = %+v, want %+v", back, sym)
+	}
+}
+
+func TestKeyOf_Error(t *testing.T) {
+	if _, err := KeyOf("invalid"); err == nil {
+		t.Error("KeyOf(\"invalid\") expected error")
+	}
+}
+
+func BenchmarkSymbol_Key(b *testing.B) {
+	sym := MustParse("github.com/user/repo.(*Server[T]).Handle[string]@linux")
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = sym.Key()
+	}
+}
@@ -0,0 +1,115 @@
+package gsrf
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// Conventions for symbol table entries that are not ordinary Go functions.
+// Entries without a Go package, like C functions and linker stubs, are placed
+// in the pseudo-package "C", as cgo does. When the GSRF form cannot spell the
+// original name, it is kept in the "raw" metadata entry.
+//
+//	runtime.memmove.abi0        runtime.memmove@asm
+//	_cgoexp_1a2b3c_Hello        C.Hello@cexport{raw:_cgoexp_1a2b3c_Hello}
+//	__libc_start_main@GLIBC_2.34  C.__libc_start_main_GLIBC_2_34{raw:__libc_start_main@GLIBC_2.34}
+const (
+	ContextAsm     = "asm"     // Go function implemented in assembly (ABI0)
+	ContextCExport = "cexport" // C ABI entry point of a cgo //export function
+	ForeignPackage = "C"       // Pseudo-package of entries without a Go package
+	MetadataRaw    = "raw"     // Original linker name, when the GSRF form differs
+)
+
+const (
+	abi0Suffix   = ".abi0"
+	cgoExpPrefix = "_cgoexp_"
+)
+
+// rawEscaper escapes the characters metadata values cannot contain.
+var (
+	rawEscaper   = strings.NewReplacer("%", "%25", ",", "%2C", "{", "%7B", "}", "%7D")
+	rawUnescaper = strings.NewReplacer("%25", "%", "%2C", ",", "%7B", "{", "%7D", "}")
+)
+
+// FromLinkerName converts any entry of a binary's symbol table to a symbol.
+// Go functions convert as with FromRuntimeName; assembly functions (the
+// ".abi0" suffix) get the asm context; cgo export wrappers become
+// C.Name@cexport; and anything else is placed in package C with its name
+// reduced to an identifier. Only an empty name is an error.
+func FromLinkerName(name string) (*Symbol, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, fmt.Errorf("invalid linker name: empty string")
+	}
+
+	if base, ok := strings.CutSuffix(name, abi0Suffix); ok {
+		if sym, ok := goLinkerSymbol(base); ok {
+			sym.Context = ContextAsm
+			return sym, nil
+		}
+		sym := foreignSymbol(name, base)
+		sym.Context = ContextAsm
+		return sym, nil
+	}
+
+	if rest, ok := strings.CutPrefix(name, cgoExpPrefix); ok {
+		// _cgoexp_<hash>_<Name>
+		if _, export, ok := strings.Cut(rest, "_"); ok && export != "" {
+			sym := foreignSymbol(name, export)
+			sym.Context = ContextCExport
+			return sym, nil
+		}
+	}
+
+	if sym, ok := goLinkerSymbol(name); ok {
+		return sym, nil
+	}
+	return foreignSymbol(name, name), nil
+}
+
+// goLinkerSymbol converts a Go function name, reporting false for names
+// that are not valid Go symbols, such as "type:.eq.[2]int".
+func goLinkerSymbol(name string) (*Symbol, bool) {
+	sym, err := FromRuntimeName(name)
+	if err != nil || sym.Validate() != nil {
+		return nil, false
+	}
+	return sym, true
+}
+
+// foreignSymbol places name in the C pseudo-package, keeping raw in
+// metadata unless the name spells it exactly.
+func foreignSymbol(raw, name string) *Symbol {
+	var b strings.Builder
+	for i, r := range name {
+		switch {
+		case r == '_' || unicode.IsLetter(r):
+			b.WriteRune(r)
+		case unicode.IsDigit(r):
+			if i == 0 {
+				b.WriteByte('_')
+			}
+			b.WriteRune(r)
+		default:
+			b.WriteByte('_')
+		}
+	}
+	sym := &Symbol{PackagePath: ForeignPackage, Name: b.String()}
+	if sym.Name != raw {
+		sym.Metadata.Custom = map[string]string{MetadataRaw: rawEscaper.Replace(raw)}
+	}
+	return sym
+}
+
+// RawName returns the original linker name recorded in the "raw" metadata
+// entry, or "" if the symbol has none.
+func (s *Symbol) RawName() string {
+	return rawUnescaper.Replace(s.Metadata.Custom[MetadataRaw])
+}
+
+// IsForeign reports whether the symbol is in the C pseudo-package, i.e. it
+// has no Go package.
+func (s *Symbol) IsForeign() bool {
+	return s.PackagePath == ForeignPackage
+}
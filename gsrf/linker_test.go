@@ -0,0 +1,45 @@
+package gsrf
+
+import "testing"
+
+func TestFromLinkerName(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+		raw   string
+	}{
+		{"main.(*Server).handle", "main.(*Server).handle", ""},
+		{"gopkg.in/yaml%2ev3.Marshal", "gopkg.in/yaml.v3.Marshal", ""},
+		{"runtime.memmove.abi0", "runtime.memmove@asm", ""},
+		{"_rt0_amd64_linux.abi0", "C._rt0_amd64_linux@asm{raw:_rt0_amd64_linux.abi0}", "_rt0_amd64_linux.abi0"},
+		{"_cgoexp_1a2b3c_Hello", "C.Hello@cexport{raw:_cgoexp_1a2b3c_Hello}", "_cgoexp_1a2b3c_Hello"},
+		{"crosscall2", "C.crosscall2", ""},
+		{"__libc_start_main@GLIBC_2.34", "C.__libc_start_main_GLIBC_2_34{raw:__libc_start_main@GLIBC_2.34}", "__libc_start_main@GLIBC_2.34"},
+		{"type:.eq.[2]interface {}", "C.type__eq__2_interface___{raw:type:.eq.[2]interface %7B%7D}", "type:.eq.[2]interface {}"},
+		{"std::map<int, int>::at", "C.std__map_int__int___at{raw:std::map<int%2C int>::at}", "std::map<int, int>::at"},
+		{"3dnow_memcpy", "C._3dnow_memcpy{raw:3dnow_memcpy}", "3dnow_memcpy"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			sym, err := FromLinkerName(tt.input)
+			if err != nil {
+				t.Fatalf("FromLinkerName() error = %v", err)
+			}
+			got := sym.String()
+			if got != tt.want {
+				t.Errorf("FromLinkerName() = %q, want %q", got, tt.want)
+			}
+			if sym.RawName() != tt.raw {
+				t.Errorf("RawName() = %q, want %q", sym.RawName(), tt.raw)
+			}
+			back, err := Parse(got)
+			if err != nil || !back.Equal(sym) {
+				t.Errorf("Parse(%q) = %v, %v; does not round-trip", got, back, err)
+			}
+		})
+	}
+
+	if _, err := FromLinkerName(" "); err == nil {
+		t.Error("FromLinkerName(\" \") succeeded, want error")
+	}
+}
@@ -0,0 +1,128 @@
+package gsrf
+
+import (
+	"fmt"
+	"go/token"
+	"strings"
+	"unicode"
+)
+
+// Warning describes a name that does not follow Go naming rules.
+type Warning struct {
+	Field   string // Field path as accepted by Symbol.Get, e.g. "receiver.typeName"
+	Value   string // Offending value
+	Message string // Human-readable description
+}
+
+func (w Warning) String() string {
+	return fmt.Sprintf("%s %q: %s", w.Field, w.Value, w.Message)
+}
+
+// predeclaredTypes are the builtin type names, which cannot have methods.
+var predeclaredTypes = map[string]bool{
+	"any": true, "bool": true, "byte": true, "comparable": true,
+	"complex64": true, "complex128": true, "error": true,
+	"float32": true, "float64": true,
+	"int": true, "int8": true, "int16": true, "int32": true, "int64": true,
+	"rune": true, "string": true,
+	"uint": true, "uint8": true, "uint16": true, "uint32": true, "uint64": true, "uintptr": true,
+}
+
+// Lint checks the symbol's names against Go naming rules and returns a
+// warning for each violation: names must be identifiers made of letters,
+// digits and underscores, must not be keywords, and receiver types must not
+// be predeclared types. Corrupted input (truncated names, binary garbage)
+// typically fails these checks even when it parses.
+func (s *Symbol) Lint() []Warning {
+	var warnings []Warning
+	check := func(field, value string) {
+		if msg := identifierProblem(value); msg != "" {
+			warnings = append(warnings, Warning{Field: field, Value: value, Message: msg})
+		}
+	}
+
+	name, recv := s.Name, s.Receiver
+	if s.IsAnonymous && recv == nil {
+		// Anonymous functions inside methods keep the receiver in Name,
+		// e.g. "(*Server).Start" for main.(*Server).Start·lit2.
+		name, recv = splitAnonMethod(name)
+	}
+
+	check(FieldName, name)
+	if recv != nil {
+		check(FieldReceiverTypeName, recv.TypeName)
+		if predeclaredTypes[recv.TypeName] {
+			warnings = append(warnings, Warning{
+				Field:   FieldReceiverTypeName,
+				Value:   recv.TypeName,
+				Message: "predeclared type cannot have methods",
+			})
+		}
+	}
+	for _, tp := range s.TypeParams {
+		check("typeParams", tp.Name)
+	}
+	return warnings
+}
+
+// splitAnonMethod splits an anonymous function parent of the form
+// "(*T).Method" or "(T[K]).Method" into the method name and receiver.
+func splitAnonMethod(name string) (string, *Receiver) {
+	if !strings.HasPrefix(name, "(") {
+		return name, nil
+	}
+	end := strings.Index(name, ").")
+	if end < 0 {
+		return name, nil
+	}
+	recv := &Receiver{TypeName: name[1:end]}
+	if strings.HasPrefix(recv.TypeName, "*") {
+		recv.IsPointer = true
+		recv.TypeName = recv.TypeName[1:]
+	}
+	if idx := strings.Index(recv.TypeName, "["); idx > 0 {
+		recv.TypeName = recv.TypeName[:idx]
+	}
+	return name[end+2:], recv
+}
+
+// identifierProblem describes why name is not a valid Go identifier, or
+// returns the empty string if it is.
+func identifierProblem(name string) string {
+	if token.IsKeyword(name) {
+		return "Go keyword"
+	}
+	return identifierSyntaxProblem(name)
+}
+
+// identifierSyntaxProblem is identifierProblem without the keyword check:
+// name must be a letter or underscore followed by letters, digits and
+// underscores, where letters and digits are any Unicode letters and digits.
+func identifierSyntaxProblem(name string) string {
+	if name == "" {
+		return "empty identifier"
+	}
+	for i, r := range name {
+		switch {
+		case r == '_' || unicode.IsLetter(r):
+		case unicode.IsDigit(r):
+			if i == 0 {
+				return "identifier starts with a digit"
+			}
+		default:
+			return fmt.Sprintf("invalid character %q", r)
+		}
+	}
+	return ""
+}
+
+// StyleError is returned by ParseWith in strict mode when a symbol parses
+// but violates Go naming rules.
+type StyleError struct {
+	Input    string
+	Warnings []Warning
+}
+
+func (e *StyleError) Error() string {
+	return fmt.Sprintf("invalid GSRF symbol %q: %s", e.Input, e.Warnings[0])
+}
@@ -0,0 +1,66 @@
+package gsrf
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestSymbol_Lint(t *testing.T) {
+	tests := []struct {
+		name       string
+		input      string
+		wantFields []string
+	}{
+		{name: "function", input: "fmt.Println"},
+		{name: "method", input: "net/http.(*Server).Serve"},
+		{name: "unicode identifier", input: "pkg.Größe"},
+		{name: "init", input: "pkg.init"},
+		{name: "anonymous in method", input: "main.(*Server).Start·lit2"},
+		{name: "keyword name", input: "pkg.func", wantFields: []string{FieldName}},
+		{name: "leading digit", input: "pkg.9lives", wantFields: []string{FieldName}},
+		{name: "invalid rune", input: "pkg.Do-It", wantFields: []string{FieldName}},
+		{name: "builtin receiver", input: "pkg.(*string).Len", wantFields: []string{FieldReceiverTypeName}},
+		{name: "keyword receiver", input: "pkg.(map).Get", wantFields: []string{FieldReceiverTypeName}},
+		{name: "builtin receiver in anonymous", input: "pkg.(error).Error·lit", wantFields: []string{FieldReceiverTypeName}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			warnings := MustParse(tt.input).Lint()
+			if len(warnings) != len(tt.wantFields) {
+				t.Fatalf("Lint() = %v, want fields %v", warnings, tt.wantFields)
+			}
+			for i, w := range warnings {
+				if w.Field != tt.wantFields[i] {
+					t.Errorf("Lint()[%d].Field = %q, want %q", i, w.Field, tt.wantFields[i])
+				}
+			}
+		})
+	}
+
+	t.Run("type params", func(t *testing.T) {
+		sym := &Symbol{PackagePath: "pkg", Name: "Map", TypeParams: []TypeParam{{Name: "K"}, {Name: "1V"}}}
+		if warnings := sym.Lint(); len(warnings) != 1 || warnings[0].Value != "1V" {
+			t.Errorf("Lint() = %v", warnings)
+		}
+	})
+}
+
+func TestParseWith_Strict(t *testing.T) {
+	if _, err := ParseWith("pkg.func", ParseOptions{}); err != nil {
+		t.Errorf("ParseWith() non-strict error = %v", err)
+	}
+
+	_, err := ParseWith("pkg.func", ParseOptions{Strict: true})
+	var styleErr *StyleError
+	if !errors.As(err, &styleErr) {
+		t.Fatalf("ParseWith() error = %v, want *StyleError", err)
+	}
+	if len(styleErr.Warnings) != 1 || styleErr.Warnings[0].Value != "func" {
+		t.Errorf("StyleError.Warnings = %v", styleErr.Warnings)
+	}
+
+	if _, err := ParseWith("fmt.Println", ParseOptions{Strict: true}); err != nil {
+		t.Errorf("ParseWith() strict valid error = %v", err)
+	}
+}
@@ -0,0 +1,320 @@
+package gsrf
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Match reports whether the GSRF symbol matches pattern. Patterns are
+// written like symbols, with globs in place of names:
+//
+//	github.com/org/**.(*Server).*   methods of *Server in org and its subpackages
+//	net/http.*@linux                functions of net/http built for linux
+//	example.com/app.**{pos,!owner}  anything in app with a position and no owner
+//
+// The package path is matched element by element: "*", "?" and character
+// classes follow path.Match within an element, and a "**" element matches
+// any number of elements, including none. The function part is one of:
+//
+//	Name      a function, init or function literal whose name matches
+//	(*T).Name a method of pointer receiver T; (T).Name for value receivers
+//	(*).Name  a method of any receiver
+//	**        any symbol of the package
+//
+// Function literals in methods, such as (*T).M·lit1, match like methods
+// named M·lit1.
+//
+// Type arguments are not matched. Context and metadata constrain the match
+// only when given: "@glob" requires a context matching glob, and a metadata
+// block lists entries as "key" (present), "key:glob" (present with a matching
+// value; any via entry may match) or "!key" (absent).
+//
+// To match many symbols against the same pattern, compile it once with
+// CompilePattern.
+func Match(pattern, symbol string) (bool, error) {
+	p, err := CompilePattern(pattern)
+	if err != nil {
+		return false, err
+	}
+	sym, err := Parse(symbol)
+	if err != nil {
+		return false, err
+	}
+	return p.MatchSymbol(sym), nil
+}
+
+// Pattern is a compiled Match pattern. It is safe for concurrent use.
+type Pattern struct {
+	source   string
+	pkg      []string // Path elements, "**" for any number of elements
+	anyFunc  bool     // Function part "**"
+	recv     *receiverPattern
+	name     string
+	context  string // Empty for any context
+	metadata []metadataPattern
+
+	reOnce sync.Once
+	re     *regexp.Regexp
+}
+
+type receiverPattern struct {
+	any       bool // (*)
+	isPointer bool
+	typeName  string
+}
+
+type metadataPattern struct {
+	key    string
+	value  string // Glob; empty to only require presence
+	absent bool
+}
+
+// CompilePattern parses a pattern in the syntax described by Match.
+func CompilePattern(pattern string) (*Pattern, error) {
+	input := strings.TrimSpace(pattern)
+	if input == "" {
+		return nil, fmt.Errorf("invalid pattern: empty string")
+	}
+	p := &Pattern{source: input}
+
+	if strings.HasSuffix(input, "}") {
+		idx := strings.LastIndex(input, "{")
+		if idx <= 0 {
+			return nil, fmt.Errorf("invalid pattern %q: unbalanced metadata block", pattern)
+		}
+		for _, entry := range strings.Split(input[idx+1:len(input)-1], ",") {
+			m, err := parseMetadataPattern(strings.TrimSpace(entry))
+			if err != nil {
+				return nil, fmt.Errorf("invalid pattern %q: %w", pattern, err)
+			}
+			p.metadata = append(p.metadata, m)
+		}
+		input = input[:idx]
+	}
+
+	if idx := strings.LastIndex(input, "@"); idx >= 0 {
+		p.context = input[idx+1:]
+		if p.context == "" {
+			return nil, fmt.Errorf("invalid pattern %q: empty context after @", pattern)
+		}
+		input = input[:idx]
+	}
+
+	var pkg, fn string
+	if sep := strings.LastIndex(input, ".("); sep >= 0 && strings.Contains(input[sep:], ").") {
+		pkg, fn = input[:sep], input[sep+1:]
+	} else if sep := strings.LastIndex(input, "."); sep >= 0 {
+		pkg, fn = input[:sep], input[sep+1:]
+	}
+	if pkg == "" || fn == "" {
+		return nil, fmt.Errorf("invalid pattern %q: no package separator found", pattern)
+	}
+	p.pkg = strings.Split(pkg, "/")
+
+	switch {
+	case fn == "**":
+		p.anyFunc = true
+	case strings.HasPrefix(fn, "("):
+		end := strings.Index(fn, ").")
+		if end < 0 || end+2 == len(fn) {
+			return nil, fmt.Errorf("invalid pattern %q: receiver without method name", pattern)
+		}
+		recv := &receiverPattern{typeName: fn[1:end]}
+		if recv.typeName == "*" {
+			recv.any = true
+		} else if t, ok := strings.CutPrefix(recv.typeName, "*"); ok {
+			recv.isPointer, recv.typeName = true, t
+		}
+		if recv.typeName == "" {
+			return nil, fmt.Errorf("invalid pattern %q: empty receiver type", pattern)
+		}
+		p.recv = recv
+		p.name = fn[end+2:]
+	default:
+		p.name = fn
+	}
+
+	globs := append([]string{p.name, p.context}, p.pkg...)
+	if p.recv != nil {
+		globs = append(globs, p.recv.typeName)
+	}
+	for _, m := range p.metadata {
+		globs = append(globs, m.value)
+	}
+	for _, g := range globs {
+		if _, err := path.Match(g, ""); err != nil {
+			return nil, fmt.Errorf("invalid pattern %q: bad glob %q", pattern, g)
+		}
+	}
+	return p, nil
+}
+
+func parseMetadataPattern(entry string) (metadataPattern, error) {
+	if key, ok := strings.CutPrefix(entry, "!"); ok {
+		if key == "" {
+			return metadataPattern{}, fmt.Errorf("empty metadata key")
+		}
+		return metadataPattern{key: key, absent: true}, nil
+	}
+	key, value, _ := strings.Cut(entry, ":")
+	if key == "" {
+		return metadataPattern{}, fmt.Errorf("empty metadata key")
+	}
+	return metadataPattern{key: key, value: value}, nil
+}
+
+// MustCompilePattern is like CompilePattern but panics if the pattern is
+// invalid.
+func MustCompilePattern(pattern string) *Pattern {
+	p, err := CompilePattern(pattern)
+	if err != nil {
+		panic(err)
+	}
+	return p
+}
+
+// String returns the pattern source.
+func (p *Pattern) String() string {
+	return p.source
+}
+
+// Match reports whether the GSRF symbol matches the pattern.
+func (p *Pattern) Match(symbol string) (bool, error) {
+	sym, err := Parse(symbol)
+	if err != nil {
+		return false, err
+	}
+	return p.MatchSymbol(sym), nil
+}
+
+// MatchSymbol reports whether sym matches the pattern. It does not allocate
+// unless sym is a function literal.
+func (p *Pattern) MatchSymbol(sym *Symbol) bool {
+	if !matchPackage(p.pkg, sym.PackagePath) {
+		return false
+	}
+	if !p.anyFunc {
+		recv, isPointer, name := symbolParts(sym)
+		if (p.recv == nil) != (recv == "") {
+			return false
+		}
+		if p.recv != nil && !p.recv.any {
+			if p.recv.isPointer != isPointer || !globMatch(p.recv.typeName, recv) {
+				return false
+			}
+		}
+		if !globMatch(p.name, name) {
+			return false
+		}
+	}
+	if p.context != "" && (sym.Context == "" || !globMatch(p.context, sym.Context)) {
+		return false
+	}
+	for _, m := range p.metadata {
+		if !m.match(sym.Metadata) {
+			return false
+		}
+	}
+	return true
+}
+
+// symbolParts returns the receiver type name, empty for functions, the
+// receiver pointerness and the name part of sym. Function literals in
+// methods carry the receiver in their name, as in "(*T).M·lit1", and are
+// split like methods.
+func symbolParts(sym *Symbol) (recv string, isPointer bool, name string) {
+	name = symbolName(sym)
+	if sym.Receiver != nil {
+		return sym.Receiver.TypeName, sym.Receiver.IsPointer, name
+	}
+	if !sym.IsAnonymous || !strings.HasPrefix(name, "(") {
+		return "", false, name
+	}
+	r, method, ok := strings.Cut(name[1:], ").")
+	if !ok {
+		return "", false, name
+	}
+	r, isPointer = strings.CutPrefix(r, "*")
+	r, _, _ = strings.Cut(r, "[")
+	return r, isPointer, method
+}
+
+// symbolName returns the name part of sym as formatted, with the marker and
+// index of a function literal.
+func symbolName(sym *Symbol) string {
+	if !sym.IsAnonymous {
+		return sym.Name
+	}
+	name := sym.Name + anonMarker
+	if sym.AnonIndex > 0 {
+		name += strconv.Itoa(sym.AnonIndex)
+	}
+	return name
+}
+
+func (m metadataPattern) match(meta Metadata) bool {
+	var values []string
+	switch m.key {
+	case "via":
+		values = meta.Via
+	case "alias":
+		if meta.Alias != "" {
+			values = []string{meta.Alias}
+		}
+	case "pos":
+		if meta.Position != "" {
+			values = []string{meta.Position}
+		}
+	default:
+		if v, ok := meta.Custom[m.key]; ok {
+			values = []string{v}
+		}
+	}
+	if m.absent {
+		return len(values) == 0
+	}
+	if m.value == "" {
+		return len(values) > 0
+	}
+	for _, v := range values {
+		if globMatch(m.value, v) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchPackage matches the elements of a package path against pattern
+// elements, where "**" matches any number of elements. An empty pkg has no
+// elements left.
+func matchPackage(pattern []string, pkg string) bool {
+	if len(pattern) == 0 {
+		return pkg == ""
+	}
+	if pattern[0] == "**" {
+		for {
+			if matchPackage(pattern[1:], pkg) {
+				return true
+			}
+			if pkg == "" {
+				return false
+			}
+			_, pkg, _ = strings.Cut(pkg, "/")
+		}
+	}
+	if pkg == "" {
+		return false
+	}
+	elem, rest, _ := strings.Cut(pkg, "/")
+	return globMatch(pattern[0], elem) && matchPackage(pattern[1:], rest)
+}
+
+// globMatch is path.Match for globs validated by CompilePattern.
+func globMatch(glob, s string) bool {
+	ok, _ := path.Match(glob, s)
+	return ok
+}
@@ -0,0 +1,261 @@
+package gsrf
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Characters a glob never matches in each part of the canonical form,
+// besides "/", which path.Match wildcards never cross.
+const (
+	regexpPkgExcluded   = `()[]{}@,·"\`
+	regexpNameExcluded  = `.()[]{}@,`
+	regexpCtxExcluded   = `@{},`
+	regexpValueExcluded = `{},`
+	regexpKeyExcluded   = `{},:`
+)
+
+// Regexp returns an anchored regular expression matching the canonical
+// string form (Symbol.String) of the symbols the pattern matches, for
+// regexp-based filters such as log shippers and dashboards. Symbols whose
+// package path is written in quoted form are not matched. Metadata entries
+// required by the pattern must appear in canonical order, so a pattern
+// requiring the same key twice needs two entries.
+func (p *Pattern) Regexp() *regexp.Regexp {
+	p.reOnce.Do(func() {
+		p.re = regexp.MustCompile(p.regexpSource())
+	})
+	return p.re
+}
+
+func (p *Pattern) regexpSource() string {
+	var b strings.Builder
+	b.WriteByte('^')
+	b.WriteString(packageRegexp(p.pkg))
+	b.WriteString(`\.`)
+
+	typeArgs := `(?:\[.*\])?`
+	name := charClass(regexpNameExcluded, false)
+	switch {
+	case p.anyFunc:
+		fmt.Fprintf(&b, `(?:\(\*?%s+%s\)\.)?%s+%s`, name, typeArgs, name, typeArgs)
+	case p.recv != nil:
+		b.WriteString(`\(`)
+		switch {
+		case p.recv.any:
+			fmt.Fprintf(&b, `\*?%s+`, name)
+		case p.recv.isPointer:
+			b.WriteString(`\*` + globRegexp(p.recv.typeName, regexpNameExcluded))
+		default:
+			b.WriteString(globRegexp(p.recv.typeName, regexpNameExcluded))
+		}
+		b.WriteString(typeArgs + `\)\.`)
+		b.WriteString(globRegexp(p.name, regexpNameExcluded) + typeArgs)
+	default:
+		b.WriteString(globRegexp(p.name, regexpNameExcluded) + typeArgs)
+	}
+
+	if p.context != "" {
+		b.WriteString("@" + globRegexp(p.context, regexpCtxExcluded))
+	} else {
+		fmt.Fprintf(&b, `(?:@%s+)?`, charClass(regexpCtxExcluded, true))
+	}
+
+	b.WriteString(p.metadataRegexp())
+	b.WriteByte('$')
+	return b.String()
+}
+
+// packageRegexp translates package path elements, where "**" matches any
+// number of elements and a path has at least one.
+func packageRegexp(elems []string) string {
+	elem := charClass(regexpPkgExcluded, false) + "+"
+	var b strings.Builder
+	// sep is the separator owed before the next element: none at the start.
+	sep := ""
+	for _, e := range elems {
+		if e == "**" {
+			if sep == "" {
+				// Leading "**": any elements, each followed by a slash.
+				fmt.Fprintf(&b, `(?:%s/)*`, elem)
+			} else {
+				fmt.Fprintf(&b, `(?:/%s)*`, elem)
+			}
+			continue
+		}
+		b.WriteString(sep)
+		b.WriteString(globRegexp(e, regexpPkgExcluded))
+		sep = "/"
+	}
+	if sep == "" {
+		// Only "**" elements: any non-empty path.
+		return fmt.Sprintf(`%s(?:/%s)*`, elem, elem)
+	}
+	return b.String()
+}
+
+// globRegexp translates a path.Match glob validated by CompilePattern.
+// Wildcards do not match "/" or the excluded characters.
+func globRegexp(glob, excluded string) string {
+	var b strings.Builder
+	runes := []rune(glob)
+	for i := 0; i < len(runes); i++ {
+		switch r := runes[i]; r {
+		case '*':
+			b.WriteString(charClass(excluded, false) + "*")
+		case '?':
+			b.WriteString(charClass(excluded, false))
+		case '\\':
+			i++
+			b.WriteString(regexp.QuoteMeta(string(runes[i])))
+		case '[':
+			b.WriteByte('[')
+			i++
+			negated := runes[i] == '^'
+			if negated {
+				b.WriteByte('^')
+				i++
+			}
+			for ; runes[i] != ']'; i++ {
+				switch runes[i] {
+				case '-':
+					b.WriteByte('-')
+				case '\\':
+					i++
+					fmt.Fprintf(&b, `\x{%x}`, runes[i])
+				default:
+					fmt.Fprintf(&b, `\x{%x}`, runes[i])
+				}
+			}
+			if negated {
+				b.WriteString(classChars("/" + excluded))
+			}
+			b.WriteByte(']')
+		default:
+			b.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+	return b.String()
+}
+
+// charClass returns a negated character class of "/" and excluded. With
+// slash, "/" is allowed.
+func charClass(excluded string, slash bool) string {
+	if !slash {
+		excluded = "/" + excluded
+	}
+	return "[^" + classChars(excluded) + `\s]`
+}
+
+func classChars(chars string) string {
+	var b strings.Builder
+	for _, r := range chars {
+		fmt.Fprintf(&b, `\x{%x}`, r)
+	}
+	return b.String()
+}
+
+// metadataRegexp translates the metadata constraints. Required entries are
+// placed in canonical order (via, alias, pos, then custom keys sorted), and
+// other entries may not use a key the pattern requires to be absent.
+func (p *Pattern) metadataRegexp() string {
+	var required []metadataPattern
+	var absent []string
+	for _, m := range p.metadata {
+		if m.absent {
+			absent = append(absent, m.key)
+		} else {
+			required = append(required, m)
+		}
+	}
+	sort.SliceStable(required, func(i, j int) bool {
+		ri, rj := metadataRank(required[i].key), metadataRank(required[j].key)
+		if ri != rj {
+			return ri < rj
+		}
+		return required[i].key < required[j].key
+	})
+
+	value := charClass(regexpValueExcluded, true) + "*"
+	other := keyNotIn(absent) + ":" + value
+	if len(required) == 0 {
+		return fmt.Sprintf(`(?:\{%s(?:,%s)*\})?`, other, other)
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, `\{(?:%s,)*`, other)
+	for i, m := range required {
+		if i > 0 {
+			fmt.Fprintf(&b, `(?:,%s)*,`, other)
+		}
+		b.WriteString(regexp.QuoteMeta(m.key) + ":")
+		if m.value == "" {
+			b.WriteString(value)
+		} else {
+			b.WriteString(globRegexp(m.value, regexpValueExcluded))
+		}
+	}
+	fmt.Fprintf(&b, `(?:,%s)*\}`, other)
+	return b.String()
+}
+
+func metadataRank(key string) int {
+	switch key {
+	case "via":
+		return 0
+	case "alias":
+		return 1
+	case "pos":
+		return 2
+	}
+	return 3
+}
+
+// keyNotIn returns a regular expression for metadata keys not in keys,
+// built from the trie of keys since RE2 has no negative lookahead.
+func keyNotIn(keys []string) string {
+	type trie struct {
+		terminal bool
+		children map[rune]*trie
+	}
+	root := &trie{children: map[rune]*trie{}}
+	for _, k := range keys {
+		n := root
+		for _, r := range k {
+			c, ok := n.children[r]
+			if !ok {
+				c = &trie{children: map[rune]*trie{}}
+				n.children[r] = c
+			}
+			n = c
+		}
+		n.terminal = true
+	}
+
+	keyChar := "[^" + classChars("/"+regexpKeyExcluded) + `\s]`
+	var walk func(n *trie, atRoot bool) string
+	walk = func(n *trie, atRoot bool) string {
+		if len(n.children) == 0 {
+			// No keys, or a key to avoid that must be extended.
+			return keyChar + "+"
+		}
+		runes := make([]rune, 0, len(n.children))
+		for r := range n.children {
+			runes = append(runes, r)
+		}
+		sort.Slice(runes, func(i, j int) bool { return runes[i] < runes[j] })
+
+		var alts []string
+		if !atRoot && !n.terminal {
+			alts = append(alts, "")
+		}
+		alts = append(alts, "[^"+classChars(string(runes)+"/"+regexpKeyExcluded)+`\s]`+keyChar+"*")
+		for _, r := range runes {
+			alts = append(alts, regexp.QuoteMeta(string(r))+walk(n.children[r], false))
+		}
+		return "(?:" + strings.Join(alts, "|") + ")"
+	}
+	return walk(root, true)
+}
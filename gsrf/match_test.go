@@ -0,0 +1,201 @@
+package gsrf
+
+import "testing"
+
+func TestMatch(t *testing.T) {
+	tests := []struct {
+		pattern string
+		symbol  string
+		want    bool
+	}{
+		{"github.com/org/**.(*Server).*", "github.com/org/svc/api.(*Server).Start", true},
+		{"github.com/org/**.(*Server).*", "github.com/org.(*Server).Start", true},
+		{"github.com/org/**.(*Server).*", "github.com/org/svc.(Server).String", false},
+		{"github.com/org/**.(*Server).*", "github.com/other/svc.(*Server).Start", false},
+		{"github.com/org/**.(*Server).*", "github.com/org/svc.Start", false},
+		{"net/http.*@linux", "net/http.ListenAndServe@linux", true},
+		{"net/http.*@linux", "net/http.ListenAndServe", false},
+		{"net/http.*@linux", "net/http.ListenAndServe@darwin", false},
+		{"net/http.*@linux", "net/http.(*Server).Serve@linux", false},
+		{"net/http.*", "net/http.ListenAndServe@linux", true},
+		{"net/*.Serve*", "net/rpc.ServeConn", true},
+		{"net/*.Serve*", "net/http/fcgi.Serve", false},
+		{"net/http.(*).Serve", "net/http.(Server).Serve", true},
+		{"net/http.(*).Serve", "net/http.Serve", false},
+		{"pkg.(*List).Push", "pkg.(*List[int]).Push", true},
+		{"pkg.Map", "pkg.Map[string, int]", true},
+		{"pkg.**", "pkg.(*T).M", true},
+		{"pkg.**", "pkg.init", true},
+		{"pkg.**", "pkg/sub.F", false},
+		{"pkg.run·lit*", "pkg.run·lit2", true},
+		{"pkg.init", "pkg.init", true},
+		{"gopkg.in/yaml.v3.Marshal", "gopkg.in/yaml.v3.Marshal", true},
+		{"**.*@linux/*", "pkg.F@linux/amd64", true},
+		{"**.*@linux", "pkg.F@linux/amd64", false},
+		{"pkg.**{pos}", "pkg.F{pos:f.go:1:1}", true},
+		{"pkg.**{pos}", "pkg.F", false},
+		{"pkg.**{!owner}", "pkg.F{owner:core}", false},
+		{"pkg.**{!owner}", "pkg.F", true},
+		{"pkg.**{owner:co*}", "pkg.F{owner:core}", true},
+		{"pkg.**{owner:co*}", "pkg.F{owner:infra}", false},
+		{"pkg.**{via:Base}", "pkg.(*T).M{via:Outer,via:Base}", true},
+		{"pkg.**{pos,owner:core}", "pkg.F{owner:core}", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.pattern+" "+tt.symbol, func(t *testing.T) {
+			got, err := Match(tt.pattern, tt.symbol)
+			if err != nil {
+				t.Fatalf("Match() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Match() = %v, want %v", got, tt.want)
+			}
+			re := MustCompilePattern(tt.pattern).Regexp()
+			if got := re.MatchString(MustParse(tt.symbol).String()); got != tt.want {
+				t.Errorf("Regexp() %s matched %v, want %v", re, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMatch_Errors(t *testing.T) {
+	patterns := []string{
+		"",
+		"pkg",
+		"pkg.F@",
+		"pkg.(*T).",
+		"pkg.().M",
+		"pkg.[.F",
+		"pkg.F{!}",
+		"pkg.F{:x}",
+	}
+	for _, pattern := range patterns {
+		if _, err := Match(pattern, "pkg.F"); err == nil {
+			t.Errorf("Match(%q) succeeded, want error", pattern)
+		}
+	}
+	if _, err := Match("pkg.*", "pkg"); err == nil {
+		t.Error("Match() with invalid symbol succeeded, want error")
+	}
+}
+
+func TestCompilePattern(t *testing.T) {
+	p := MustCompilePattern(" github.com/org/**.(*Server).*@linux{pos} ")
+	if p.String() != "github.com/org/**.(*Server).*@linux{pos}" {
+		t.Errorf("String() = %q", p.String())
+	}
+
+	symbols := map[string]bool{
+		"github.com/org/api.(*Server).Start@linux{pos:server.go:10:1}": true,
+		"github.com/org/api.(*Server).Start@linux":                     false,
+		"github.com/org/api.(*Server).Start@darwin{pos:server.go:1:1}": false,
+		"github.com/org/a/b/c.(*Server).Stop@linux{pos:stop.go:1:1}":   true,
+	}
+	for symbol, want := range symbols {
+		if got := p.MatchSymbol(MustParse(symbol)); got != want {
+			t.Errorf("MatchSymbol(%q) = %v, want %v", symbol, got, want)
+		}
+		if got, err := p.Match(symbol); err != nil || got != want {
+			t.Errorf("Match(%q) = %v, %v; want %v", symbol, got, err, want)
+		}
+	}
+
+	sym := MustParse("github.com/org/a/b/c.(*Server).Stop@linux{pos:stop.go:1:1,owner:core}")
+	allocs := testing.AllocsPerRun(100, func() {
+		p.MatchSymbol(sym)
+	})
+	if allocs != 0 {
+		t.Errorf("MatchSymbol() allocated %v times, want 0", allocs)
+	}
+
+	if _, err := CompilePattern("pkg.(*T)."); err == nil {
+		t.Error("CompilePattern() of an invalid pattern succeeded")
+	}
+}
+
+func BenchmarkPattern_MatchSymbol(b *testing.B) {
+	p := MustCompilePattern("github.com/org/**.(*Server).*@linux")
+	sym := MustParse("github.com/org/svc/internal/api.(*Server).Handle@linux")
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = p.MatchSymbol(sym)
+	}
+}
+
+func TestPattern_Regexp(t *testing.T) {
+	tests := []struct {
+		pattern string
+		symbol  string
+		want    bool
+	}{
+		{"**.F", "a/b/c.F", true},
+		{"a/**/c.F", "a/c.F", true},
+		{"a/**/c.F", "a/b/b/c.F", true},
+		{"a/**/c.F", "a/b/cc.F", false},
+		{"net/*.F", "net/http.(*T).F", false},
+		{"pkg.[A-Z]*", "pkg.Exported", true},
+		{"pkg.[^A-Z]*", "pkg.Exported", false},
+		{"pkg.**{!own,!owner}", "pkg.F{owner:core}", false},
+		{"pkg.**{!own,!owner}", "pkg.F{own:core}", false},
+		{"pkg.**{!own,!owner}", "pkg.F{ow:core,owners:x}", true},
+		{"pkg.**{team:a*,pos}", "pkg.F{pos:f.go:1:1,team:api}", true},
+		{"pkg.**{team:a*,pos}", "pkg.F{team:api}", false},
+		{"github.com/org/**.(*Server).*", "github.com/org/app.(*Server).Handle·lit2", true},
+		{"**.(*).*", "github.com/org/app.(*Server).Handle·lit2", true},
+		{"**.*", "github.com/org/app.(*Server).Handle·lit2", false},
+	}
+	for _, tt := range tests {
+		re := MustCompilePattern(tt.pattern).Regexp()
+		sym := MustParse(tt.symbol)
+		if got := re.MatchString(sym.String()); got != tt.want {
+			t.Errorf("Regexp() of %q = %s, matched %q: %v, want %v", tt.pattern, re, sym, got, tt.want)
+		}
+		if got := MustCompilePattern(tt.pattern).MatchSymbol(sym); got != tt.want {
+			t.Errorf("MatchSymbol() of %q on %q = %v, want %v", tt.pattern, sym, got, tt.want)
+		}
+	}
+
+	p := MustCompilePattern("pkg.F")
+	if p.Regexp() != p.Regexp() {
+		t.Error("Regexp() is not cached")
+	}
+	if re := p.Regexp(); re.MatchString("xpkg.F") || re.MatchString("pkg.Fx") {
+		t.Errorf("Regexp() %s is not anchored", re)
+	}
+}
+
+func TestPattern_RegexpAgreesWithMatchSymbol(t *testing.T) {
+	patterns := []string{
+		"**.*",
+		"**.**",
+		"**.(*).*",
+		"**.(*Server).*",
+		"**.(Server).*",
+		"**.(*Server).Handle",
+		"**.(*Server).Handle*",
+		"github.com/org/**.(*Server).*",
+		"github.com/org/app.main*",
+		"github.com/org/app.*@linux",
+		"github.com/org/app.**{pos}",
+	}
+	symbols := []string{
+		"github.com/org/app.main",
+		"github.com/org/app.main·lit1",
+		"github.com/org/app.init",
+		"github.com/org/app.(*Server).Handle",
+		"github.com/org/app.(*Server).Handle·lit2",
+		"github.com/org/app.(Server).Handle·lit",
+		"github.com/org/app.(Server).Name",
+		"github.com/org/app.Map[int]@linux{pos:f.go:1:1}",
+		"other.(*Server).Handle·lit1",
+	}
+	for _, pattern := range patterns {
+		p := MustCompilePattern(pattern)
+		for _, symbol := range symbols {
+			sym := MustParse(symbol)
+			if re, match := p.Regexp().MatchString(sym.String()), p.MatchSymbol(sym); re != match {
+				t.Errorf("pattern %q on %q: Regexp() matches %v, MatchSymbol() %v", pattern, symbol, re, match)
+			}
+		}
+	}
+}
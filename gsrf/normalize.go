@@ -0,0 +1,152 @@
+package gsrf
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Normalize returns a canonical copy of the symbol, leaving s unchanged.
+// Two symbols that denote the same logical symbol but were produced by
+// different adapters format identically after normalization:
+//
+//  1. vendor/ prefixes are removed from the package path (spec 5.3.2).
+//  2. Type arguments and constraints are canonicalized with
+//     NormalizeTypeExpr: runs of whitespace collapse to one space, no space
+//     follows an opening or precedes a closing bracket or parenthesis, each
+//     comma is followed by exactly one space, and types ParseTypeExpr
+//     understands are printed without spaces after '*' or nested brackets.
+//  3. An "any" constraint is stored as the empty constraint.
+//  4. A receiverless symbol named "init" is marked IsInit.
+//  5. AnonParent is derived from PackagePath and Name for anonymous
+//     functions and cleared otherwise.
+//  6. Context and metadata values are trimmed of surrounding whitespace, and
+//     empty via entries are dropped.
+//  7. Empty slices and maps are replaced by nil.
+//
+// Receiver pointerness is preserved, since it cannot be recovered once an
+// adapter has discarded it; compare with IgnoreReceiverPointer instead.
+func (s *Symbol) Normalize() *Symbol {
+	n := &Symbol{
+		PackagePath: stripVendor(strings.TrimSpace(s.PackagePath)),
+		Name:        strings.TrimSpace(s.Name),
+		IsInit:      s.IsInit,
+		IsAnonymous: s.IsAnonymous,
+		AnonIndex:   s.AnonIndex,
+		TypeArgs:    normalizeTypeList(s.TypeArgs),
+		Context:     strings.TrimSpace(s.Context),
+		Metadata: Metadata{
+			Via:      normalizeStrings(s.Metadata.Via),
+			Alias:    strings.TrimSpace(s.Metadata.Alias),
+			Position: strings.TrimSpace(s.Metadata.Position),
+		},
+	}
+
+	if s.Receiver != nil {
+		n.Receiver = &Receiver{
+			TypeName:  strings.TrimSpace(s.Receiver.TypeName),
+			IsPointer: s.Receiver.IsPointer,
+			TypeArgs:  normalizeTypeList(s.Receiver.TypeArgs),
+		}
+	}
+
+	if len(s.TypeParams) > 0 {
+		n.TypeParams = make([]TypeParam, len(s.TypeParams))
+		for i, tp := range s.TypeParams {
+			constraint := NormalizeTypeExpr(tp.Constraint)
+			if constraint == "any" {
+				constraint = ""
+			}
+			n.TypeParams[i] = TypeParam{Name: strings.TrimSpace(tp.Name), Constraint: constraint}
+		}
+	}
+
+	if len(s.Metadata.Custom) > 0 {
+		n.Metadata.Custom = make(map[string]string, len(s.Metadata.Custom))
+		for k, v := range s.Metadata.Custom {
+			n.Metadata.Custom[strings.TrimSpace(k)] = strings.TrimSpace(v)
+		}
+	}
+
+	if n.Receiver == nil && !n.IsAnonymous && n.Name == "init" {
+		n.IsInit = true
+	}
+	if n.IsAnonymous {
+		n.AnonParent = n.PackagePath + "." + n.Name
+	}
+
+	return n
+}
+
+// stripVendor removes vendor directory prefixes from a package path.
+func stripVendor(path string) string {
+	if idx := strings.LastIndex(path, "/vendor/"); idx >= 0 {
+		return path[idx+len("/vendor/"):]
+	}
+	return strings.TrimPrefix(path, "vendor/")
+}
+
+// normalizeStrings trims each entry and drops empty ones, returning nil for
+// an empty result.
+func normalizeStrings(list []string) []string {
+	var out []string
+	for _, v := range list {
+		if v = strings.TrimSpace(v); v != "" {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+// normalizeTypeList normalizes each type expression, returning nil for an
+// empty list.
+func normalizeTypeList(types []string) []string {
+	if len(types) == 0 {
+		return nil
+	}
+	out := make([]string, len(types))
+	for i, t := range types {
+		out[i] = NormalizeTypeExpr(t)
+	}
+	return out
+}
+
+// NormalizeTypeExpr canonicalizes a type expression, e.g. "Map[ string,int ]"
+// becomes "Map[string, int]" and "map[string] [] * Foo" becomes
+// "map[string][]*Foo". Expressions ParseTypeExpr accepts are printed in its
+// canonical form; others only have their whitespace canonicalized.
+func NormalizeTypeExpr(expr string) string {
+	spaced := normalizeSpace(expr)
+	if t, err := ParseTypeExpr(spaced); err == nil {
+		return t.String()
+	}
+	return spaced
+}
+
+// EqualTypeExpr reports whether two type expressions denote the same type
+// once normalized, so "Map[string,int]" equals "Map[string, int]".
+func EqualTypeExpr(a, b string) bool {
+	return a == b || NormalizeTypeExpr(a) == NormalizeTypeExpr(b)
+}
+
+// normalizeSpace collapses whitespace in a type expression.
+func normalizeSpace(expr string) string {
+	var b strings.Builder
+	var last rune
+	pendingSpace := false
+	for _, r := range strings.TrimSpace(expr) {
+		if unicode.IsSpace(r) {
+			pendingSpace = true
+			continue
+		}
+		if pendingSpace && r != ']' && r != ')' && r != ',' && last != '[' && last != '(' && last != ',' {
+			b.WriteByte(' ')
+		}
+		pendingSpace = false
+		b.WriteRune(r)
+		if r == ',' {
+			b.WriteByte(' ')
+		}
+		last = r
+	}
+	return b.String()
+}
@@ -0,0 +1,159 @@
+package gsrf
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSymbol_Normalize(t *testing.T) {
+	tests := []struct {
+		name   string
+		symbol *Symbol
+		want   string
+	}{
+		{
+			name:   "vendor prefix",
+			symbol: &Symbol{PackagePath: "vendor/github.com/lib/pkg", Name: "Func"},
+			want:   "github.com/lib/pkg.Func",
+		},
+		{
+			name:   "nested vendor prefix",
+			symbol: &Symbol{PackagePath: "github.com/app/vendor/github.com/lib/pkg", Name: "Func"},
+			want:   "github.com/lib/pkg.Func",
+		},
+		{
+			name:   "type arg spacing",
+			symbol: &Symbol{PackagePath: "pkg", Name: "Map", TypeArgs: []string{"map[ string ]int", "Pair[K,V]"}},
+			want:   "pkg.Map[map[string]int, Pair[K, V]]",
+		},
+		{
+			name: "receiver type arg spacing",
+			symbol: &Symbol{
+				PackagePath: "pkg",
+				Name:        "Get",
+				Receiver:    &Receiver{TypeName: "Cache", IsPointer: true, TypeArgs: []string{"K", "func( int )  string"}},
+			},
+			want: "pkg.(*Cache[K, func(int) string]).Get",
+		},
+		{
+			name:   "init detection",
+			symbol: &Symbol{PackagePath: "pkg", Name: "init"},
+			want:   "pkg.init",
+		},
+		{
+			name:   "anonymous function in init",
+			symbol: MustParse("pkg.init·lit1"),
+			want:   "pkg.init·lit1",
+		},
+		{
+			name:   "trimmed context",
+			symbol: &Symbol{PackagePath: "pkg", Name: "F", Context: " linux "},
+			want:   "pkg.F@linux",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.symbol.Normalize()
+			if got.Format() != tt.want {
+				t.Errorf("Normalize().Format() = %q, want %q", got.Format(), tt.want)
+			}
+			if again := got.Normalize(); !again.Equal(got) {
+				t.Errorf("Normalize() not idempotent: %+v != %+v", again, got)
+			}
+		})
+	}
+}
+
+func TestSymbol_NormalizeInitLiteral(t *testing.T) {
+	sym := MustParse("pkg.init·lit1")
+	if got := sym.Normalize(); got.IsInit || got.Key() != sym.Key() {
+		t.Errorf("Normalize() = %+v, key %q, want key %q", got, got.Key(), sym.Key())
+	}
+}
+
+func TestSymbol_NormalizeFields(t *testing.T) {
+	sym := &Symbol{
+		PackagePath: "vendor/example.com/app",
+		Name:        "handler",
+		IsAnonymous: true,
+		AnonParent:  "stale.parent",
+		TypeArgs:    []string{},
+		TypeParams:  []TypeParam{{Name: "T", Constraint: "any"}},
+		Metadata:    Metadata{Custom: map[string]string{}},
+	}
+	n := sym.Normalize()
+
+	if n.AnonParent != "example.com/app.handler" {
+		t.Errorf("AnonParent = %q", n.AnonParent)
+	}
+	if n.TypeArgs != nil || n.Metadata.Custom != nil {
+		t.Errorf("empty collections not nil: %+v", n)
+	}
+	if n.TypeParams[0].Constraint != "" {
+		t.Errorf("Constraint = %q, want empty", n.TypeParams[0].Constraint)
+	}
+	if sym.PackagePath != "vendor/example.com/app" || sym.AnonParent != "stale.parent" {
+		t.Errorf("Normalize() modified the receiver: %+v", sym)
+	}
+}
+
+func TestNormalizeTypeExpr(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"int", "int"},
+		{"  string ", "string"},
+		{"Map[ K ,V ]", "Map[K, V]"},
+		{"map[string]  []int", "map[string][]int"},
+		{"* Foo", "*Foo"},
+		{"[] * pkg.Foo", "[]*pkg.Foo"},
+		{"Map[string,List[ int ] ]", "Map[string, List[int]]"},
+		{"<- chan int", "<-chan int"},
+		{"chan   int", "chan int"},
+		{"func(a,b int) (string,error)", "func(a, b int) (string, error)"},
+		{"struct{ X int }", "struct{ X int }"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			if got := NormalizeTypeExpr(tt.input); got != tt.want {
+				t.Errorf("NormalizeTypeExpr(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNormalize_DeepTypeArgs(t *testing.T) {
+	// Deeply nested type arguments fall back to whitespace normalization
+	// instead of overflowing the stack.
+	deep := strings.Repeat("*", 3_000_000) + "int"
+	sym := MustParse("pkg.F[" + deep + "]")
+	if got := sym.Normalize().TypeArgs; len(got) != 1 || got[0] != deep {
+		t.Errorf("Normalize() type args are not the input")
+	}
+	if !sym.Equivalent(sym) {
+		t.Error("Equivalent() = false for the same symbol")
+	}
+	sym.Fingerprint()
+}
+
+func TestEqualTypeExpr(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want bool
+	}{
+		{"Map[string, int]", "Map[string,int]", true},
+		{"*Foo", "* Foo", true},
+		{"map[string] []*T", "map[string][]*T", true},
+		{"Map[string, int]", "Map[int, string]", false},
+		{"chan int", "chanint", false},
+	}
+
+	for _, tt := range tests {
+		if got := EqualTypeExpr(tt.a, tt.b); got != tt.want {
+			t.Errorf("EqualTypeExpr(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
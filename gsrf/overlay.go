@@ -0,0 +1,121 @@
+package gsrf
+
+import "sort"
+
+// ContextOverlay splits a corpus into a platform-independent base set and
+// per-context overlays. Symbols without a context modifier form the base;
+// symbols with one (net.(*netFD).connect@linux) go to that context's overlay.
+//
+// Resolution for a context is the base plus that context's overlay. When a
+// symbol appears in both, the overlay entry wins, so the resolved symbol
+// carries the context modifier. Identity ignores context and metadata and
+// follows Normalize, so vendored and whitespace variants collapse.
+type ContextOverlay struct {
+	base     *symbolSet
+	overlays map[string]*symbolSet
+}
+
+// symbolSet is an insertion-ordered set keyed by context-free identity.
+type symbolSet struct {
+	keys []string
+	syms map[string]*Symbol
+}
+
+func newSymbolSet() *symbolSet {
+	return &symbolSet{syms: make(map[string]*Symbol)}
+}
+
+func (s *symbolSet) add(key string, sym *Symbol) {
+	if _, ok := s.syms[key]; !ok {
+		s.keys = append(s.keys, key)
+	}
+	s.syms[key] = sym
+}
+
+// NewContextOverlay builds an overlay from a corpus. Later duplicates replace
+// earlier ones but keep the first position.
+func NewContextOverlay(syms []*Symbol) *ContextOverlay {
+	o := &ContextOverlay{base: newSymbolSet(), overlays: make(map[string]*symbolSet)}
+	for _, sym := range syms {
+		o.Add(sym)
+	}
+	return o
+}
+
+// Add inserts a symbol into the base set or its context's overlay.
+func (o *ContextOverlay) Add(sym *Symbol) {
+	set := o.base
+	if sym.Context != "" {
+		set = o.overlays[sym.Context]
+		if set == nil {
+			set = newSymbolSet()
+			o.overlays[sym.Context] = set
+		}
+	}
+	set.add(contextFreeKey(sym), sym)
+}
+
+// Contexts returns the contexts that have an overlay, sorted.
+func (o *ContextOverlay) Contexts() []string {
+	ctxs := make([]string, 0, len(o.overlays))
+	for ctx := range o.overlays {
+		ctxs = append(ctxs, ctx)
+	}
+	sort.Strings(ctxs)
+	return ctxs
+}
+
+// Base returns the platform-independent symbols in insertion order.
+func (o *ContextOverlay) Base() []*Symbol {
+	return o.resolve("").list()
+}
+
+// Resolve returns the symbols visible in ctx: the base set followed by
+// overlay-only symbols, with overlay entries replacing base entries of the
+// same identity. An empty or unknown context resolves to the base set.
+func (o *ContextOverlay) Resolve(ctx string) []*Symbol {
+	return o.resolve(ctx).list()
+}
+
+func (o *ContextOverlay) resolve(ctx string) *symbolSet {
+	result := newSymbolSet()
+	for _, k := range o.base.keys {
+		result.add(k, o.base.syms[k])
+	}
+	if overlay := o.overlays[ctx]; overlay != nil {
+		for _, k := range overlay.keys {
+			result.add(k, overlay.syms[k])
+		}
+	}
+	return result
+}
+
+func (s *symbolSet) list() []*Symbol {
+	out := make([]*Symbol, 0, len(s.keys))
+	for _, k := range s.keys {
+		out = append(out, s.syms[k])
+	}
+	return out
+}
+
+// Diff returns the symbols that resolve in context a but not in b, and those
+// that resolve in b but not in a. Symbols in the base set never differ.
+func (o *ContextOverlay) Diff(a, b string) (onlyA, onlyB []*Symbol) {
+	ra, rb := o.resolve(a), o.resolve(b)
+	for _, k := range ra.keys {
+		if _, ok := rb.syms[k]; !ok {
+			onlyA = append(onlyA, ra.syms[k])
+		}
+	}
+	for _, k := range rb.keys {
+		if _, ok := ra.syms[k]; !ok {
+			onlyB = append(onlyB, rb.syms[k])
+		}
+	}
+	return onlyA, onlyB
+}
+
+// contextFreeKey is the canonical identity of a symbol ignoring its context.
+func contextFreeKey(s *Symbol) string {
+	return canonicalKey(s)
+}
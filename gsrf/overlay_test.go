@@ -0,0 +1,60 @@
+package gsrf
+
+import (
+	"reflect"
+	"testing"
+)
+
+func formatAll(syms []*Symbol) []string {
+	var out []string
+	for _, s := range syms {
+		out = append(out, s.Format())
+	}
+	return out
+}
+
+func TestContextOverlay(t *testing.T) {
+	var corpus []*Symbol
+	for _, in := range []string{
+		"net.Dial",
+		"net.(*netFD).connect",
+		"net.(*netFD).connect@linux",
+		"net.(*netFD).connect@windows",
+		"net.sysSocket@linux",
+		"net.sysSocket@darwin",
+		"syscall.Epoll@linux",
+		"syscall.Kqueue@darwin",
+		"vendor/golang.org/x/net/route.fetchRIB@darwin",
+	} {
+		corpus = append(corpus, MustParse(in))
+	}
+	o := NewContextOverlay(corpus)
+
+	if got, want := o.Contexts(), []string{"darwin", "linux", "windows"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Contexts() = %v, want %v", got, want)
+	}
+	if got, want := formatAll(o.Base()), []string{"net.Dial", "net.(*netFD).connect"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Base() = %v, want %v", got, want)
+	}
+
+	wantLinux := []string{"net.Dial", "net.(*netFD).connect@linux", "net.sysSocket@linux", "syscall.Epoll@linux"}
+	if got := formatAll(o.Resolve("linux")); !reflect.DeepEqual(got, wantLinux) {
+		t.Errorf("Resolve(linux) = %v, want %v", got, wantLinux)
+	}
+	if got := formatAll(o.Resolve("plan9")); !reflect.DeepEqual(got, formatAll(o.Base())) {
+		t.Errorf("Resolve(plan9) = %v, want base set", got)
+	}
+
+	onlyA, onlyB := o.Diff("linux", "darwin")
+	if got, want := formatAll(onlyA), []string{"syscall.Epoll@linux"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Diff() onlyA = %v, want %v", got, want)
+	}
+	if got, want := formatAll(onlyB), []string{"syscall.Kqueue@darwin", "vendor/golang.org/x/net/route.fetchRIB@darwin"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Diff() onlyB = %v, want %v", got, want)
+	}
+
+	onlyA, onlyB = o.Diff("windows", "")
+	if len(onlyA) != 0 || len(onlyB) != 0 {
+		t.Errorf("Diff(windows, base) = %v, %v; want no differences", formatAll(onlyA), formatAll(onlyB))
+	}
+}
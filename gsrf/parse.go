@@ -6,11 +6,144 @@ import (
 	"strings"
 )
 
+// ParseOptions configures ParseWith. The zero value imposes no limits.
+type ParseOptions struct {
+	MaxLength       int  // Reject inputs longer than this many bytes (0 = no limit)
+	MaxBracketDepth int  // Reject inputs nesting (), [] and {} deeper than this (0 = no limit)
+	Strict          bool // Reject symbols failing Symbol.Validate (*ValidationError) or Symbol.Lint (*StyleError)
+
+	// Metadata quotas (0 = no limit). A symbol over one is rejected with a
+	// *MetadataLimitError, or trimmed if TruncateMetadata is set. Values and
+	// sizes count via, alias and pos entries as well as custom ones.
+	MaxMetadataKeys  int  // Maximum number of custom metadata entries
+	MaxMetadataValue int  // Maximum length of one value in bytes
+	MaxMetadataSize  int  // Maximum length of all keys and values together in bytes
+	TruncateMetadata bool // Drop entries and shorten values over the quotas, recording a digest of what was dropped
+
+	PathStyle PathStyle   // Separators of the position metadata
+	Rules     RulesPolicy // ParseFile: handling of files written under other rules
+
+	// Interner, if set, deduplicates package paths and receiver type names
+	// across parsed symbols. Share one Interner across a whole symbol stream.
+	Interner *Interner
+}
+
+// Limits applied by ParseUntrusted. Real symbols, including deeply generic
+// instantiations and full source paths, stay well within them.
+const (
+	DefaultMaxLength        = 4096
+	DefaultMaxBracketDepth  = 32
+	DefaultMaxMetadataKeys  = 16
+	DefaultMaxMetadataValue = 1024
+	DefaultMaxMetadataSize  = 2048
+)
+
+// TooLongError reports an input rejected by ParseOptions.MaxLength.
+type TooLongError struct {
+	Length int // Length of the rejected input in bytes
+	Max    int // Configured limit in bytes
+}
+
+func (e *TooLongError) Error() string {
+	return fmt.Sprintf("invalid GSRF symbol: length %d exceeds limit of %d bytes", e.Length, e.Max)
+}
+
+// TooDeepError reports an input rejected by ParseOptions.MaxBracketDepth.
+type TooDeepError struct {
+	Offset int // Byte offset of the bracket that exceeded the limit
+	Max    int // Configured limit
+}
+
+func (e *TooDeepError) Error() string {
+	return fmt.Sprintf("invalid GSRF symbol: brackets nested deeper than %d at offset %d", e.Max, e.Offset)
+}
+
+// ParseUntrusted parses a symbol from an untrusted source, such as a
+// user-submitted crash report, rejecting inputs longer than DefaultMaxLength
+// or nested deeper than DefaultMaxBracketDepth before parsing them, and
+// metadata over the DefaultMaxMetadata quotas.
+func ParseUntrusted(input string) (*Symbol, error) {
+	return ParseWith(input, ParseOptions{
+		MaxLength:        DefaultMaxLength,
+		MaxBracketDepth:  DefaultMaxBracketDepth,
+		MaxMetadataKeys:  DefaultMaxMetadataKeys,
+		MaxMetadataValue: DefaultMaxMetadataValue,
+		MaxMetadataSize:  DefaultMaxMetadataSize,
+	})
+}
+
+// ParseWith parses a GSRF symbol string, enforcing the limits in opts.
+func ParseWith(input string, opts ParseOptions) (*Symbol, error) {
+	if opts.MaxLength > 0 && len(input) > opts.MaxLength {
+		return nil, &TooLongError{Length: len(input), Max: opts.MaxLength}
+	}
+	if opts.MaxBracketDepth > 0 {
+		if offset := bracketDepthExceeded(input, opts.MaxBracketDepth); offset >= 0 {
+			return nil, &TooDeepError{Offset: offset, Max: opts.MaxBracketDepth}
+		}
+	}
+	sym, err := Parse(input)
+	if err != nil {
+		return nil, err
+	}
+	return finishParse(sym, input, opts)
+}
+
+// finishParse applies the checks and interning of opts to a parsed symbol.
+func finishParse(sym *Symbol, input string, opts ParseOptions) (*Symbol, error) {
+	if err := limitMetadata(sym, opts); err != nil {
+		return nil, err
+	}
+	sym.Metadata.Position = opts.PathStyle.Apply(sym.Metadata.Position)
+	if opts.Strict {
+		if err := sym.Validate(); err != nil {
+			return nil, err
+		}
+		if warnings := sym.Lint(); len(warnings) > 0 {
+			return nil, &StyleError{Input: input, Warnings: warnings}
+		}
+	}
+	if opts.Interner != nil {
+		opts.Interner.InternSymbol(sym)
+	}
+	return sym, nil
+}
+
+// bracketDepthExceeded returns the offset of the first opening bracket
+// nested deeper than limit, or -1. Brackets in a quoted package path are not
+// counted, and unmatched closing brackets do not lower the depth below zero.
+func bracketDepthExceeded(input string, limit int) int {
+	start := 0
+	if q, err := strconv.QuotedPrefix(input); err == nil {
+		start = len(q)
+	}
+	depth := 0
+	for i := start; i < len(input); i++ {
+		switch input[i] {
+		case '(', '[', '{':
+			depth++
+			if depth > limit {
+				return i
+			}
+		case ')', ']', '}':
+			if depth > 0 {
+				depth--
+			}
+		}
+	}
+	return -1
+}
+
 // Parse parses a GSRF symbol string according to the specification.
 func Parse(input string) (*Symbol, error) {
 	if input == "" {
 		return nil, fmt.Errorf("invalid GSRF symbol: empty string")
 	}
+
+	// Quoted package path: "gopkg.in/yaml.v2".Unmarshal
+	if strings.HasPrefix(input, `"`) {
+		return parseQuoted(input)
+	}
 	
 	// Extract metadata first
 	metadata := Metadata{}
@@ -41,7 +174,7 @@ func Parse(input string) (*Symbol, error) {
 					value := strings.TrimSpace(kv[1])
 					switch key {
 					case "via":
-						metadata.Via = value
+						metadata.Via = append(metadata.Via, value)
 					case "alias":
 						metadata.Alias = value
 					case "pos":
@@ -149,16 +282,14 @@ func Parse(input string) (*Symbol, error) {
 	}
 
 	// Check for anonymous function
-	if strings.Contains(symbolPart, "·lit") {
+	if litIndex, litPart := findAnonMarker(symbolPart); litIndex >= 0 {
 		// Extract parent and index
-		litIndex := strings.Index(symbolPart, "·lit")
 		sym.Name = symbolPart[:litIndex]
 		sym.IsAnonymous = true
 		sym.AnonParent = packagePath + "." + sym.Name
 		
-		// Extract index after ·lit
-		litPart := "·lit"
-		indexStr := symbolPart[litIndex+len(litPart):] // Skip "·lit" properly (5 bytes)
+		// Extract index after the marker (·lit or its ASCII form %lit)
+		indexStr := symbolPart[litIndex+len(litPart):]
 		if indexStr != "" {
 			if index, err := strconv.Atoi(indexStr); err == nil {
 				sym.AnonIndex = index
@@ -171,28 +302,7 @@ func Parse(input string) (*Symbol, error) {
 			return nil, fmt.Errorf("invalid method receiver")
 		}
 		
-		recvStr := symbolPart[1:recvEnd]
-		isPtr := strings.HasPrefix(recvStr, "*")
-		if isPtr {
-			recvStr = recvStr[1:]
-		}
-		
-		// Handle generic receivers
-		typeName := recvStr
-		var typeArgs []string
-		if idx := strings.Index(recvStr, "["); idx > 0 {
-			typeName = recvStr[:idx]
-			if end := strings.LastIndex(recvStr, "]"); end > idx {
-				argsStr := recvStr[idx+1 : end]
-				typeArgs = parseTypeArgs(argsStr)
-			}
-		}
-		
-		sym.Receiver = &Receiver{
-			TypeName:  typeName,
-			IsPointer: isPtr,
-			TypeArgs:  typeArgs,
-		}
+		sym.Receiver = splitReceiver(symbolPart[1:recvEnd])
 		
 		// Extract method name
 		if recvEnd+2 < len(symbolPart) {
@@ -224,6 +334,53 @@ func Parse(input string) (*Symbol, error) {
 	return sym, nil
 }
 
+// findAnonMarker returns the position and spelling of the anonymous function
+// marker in s, accepting both the spec form and the ASCII-safe form.
+func findAnonMarker(s string) (int, string) {
+	for _, marker := range []string{anonMarker, anonMarkerASCII} {
+		if idx := strings.Index(s, marker); idx >= 0 {
+			return idx, marker
+		}
+	}
+	return -1, ""
+}
+
+// parseQuoted parses a symbol whose package path is a Go-quoted string.
+// The quotes make the package/symbol split explicit, so paths containing
+// reserved characters or dotted final elements are never mis-split.
+func parseQuoted(input string) (*Symbol, error) {
+	quoted, err := strconv.QuotedPrefix(input)
+	if err != nil {
+		return nil, fmt.Errorf("invalid GSRF symbol: unterminated quoted package path")
+	}
+	packagePath, err := strconv.Unquote(quoted)
+	if err != nil {
+		return nil, fmt.Errorf("invalid GSRF symbol: %w", err)
+	}
+	if packagePath == "" {
+		return nil, fmt.Errorf("invalid GSRF symbol: empty package or symbol part")
+	}
+
+	rest := input[len(quoted):]
+	if !strings.HasPrefix(rest, ".") || len(rest) == 1 {
+		return nil, fmt.Errorf("invalid GSRF symbol: expected symbol after quoted package path")
+	}
+
+	// Parse the remainder against a placeholder package, then restore the path.
+	sym, err := Parse("_" + rest)
+	if err != nil {
+		return nil, err
+	}
+	if sym.PackagePath != "_" {
+		return nil, fmt.Errorf("invalid GSRF symbol: unexpected package separator after quoted package path")
+	}
+	sym.PackagePath = packagePath
+	if sym.IsAnonymous {
+		sym.AnonParent = packagePath + "." + sym.Name
+	}
+	return sym, nil
+}
+
 // MustParse parses a GSRF symbol string and panics on error.
 func MustParse(input string) *Symbol {
 	sym, err := Parse(input)
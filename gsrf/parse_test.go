@@ -131,7 +131,7 @@ func TestParse(t *testing.T) {
 					IsPointer: true,
 				},
 				Metadata: Metadata{
-					Via: "Writer",
+					Via: []string{"Writer"},
 				},
 			},
 		},
@@ -148,7 +148,7 @@ func TestParse(t *testing.T) {
 				},
 				Context: "linux",
 				Metadata: Metadata{
-					Via:      "Base[T]",
+					Via:      []string{"Base[T]"},
 					Position: "file.go:10:1",
 				},
 			},
@@ -221,7 +221,7 @@ func TestParse(t *testing.T) {
 				if !reflect.DeepEqual(got.TypeArgs, tt.want.TypeArgs) {
 					t.Errorf("TypeArgs = %v, want %v", got.TypeArgs, tt.want.TypeArgs)
 				}
-				if got.Metadata.Via != tt.want.Metadata.Via {
+				if !reflect.DeepEqual(got.Metadata.Via, tt.want.Metadata.Via) {
 					t.Errorf("Metadata.Via = %q, want %q", got.Metadata.Via, tt.want.Metadata.Via)
 				}
 				if got.Metadata.Position != tt.want.Metadata.Position {
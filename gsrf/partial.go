@@ -0,0 +1,171 @@
+package gsrf
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Diagnostic describes a problem ParsePartial worked around.
+type Diagnostic struct {
+	Offset  int    // Byte offset in the input, or -1 if not tied to a position
+	Message string // Human-readable description
+}
+
+func (d Diagnostic) String() string {
+	if d.Offset < 0 {
+		return d.Message
+	}
+	return fmt.Sprintf("offset %d: %s", d.Offset, d.Message)
+}
+
+// ParsePartial parses as much of a malformed symbol as it can, for
+// best-effort display. Well-formed input parses exactly as with Parse and
+// returns no diagnostics. Unbalanced brackets are repaired before parsing;
+// if parsing still fails, the package path, receiver, name, context, and
+// metadata are recovered independently. The symbol is nil only when not even
+// a package path could be found.
+func ParsePartial(input string) (*Symbol, []Diagnostic) {
+	repaired, diags := balanceBrackets(input)
+	sym, err := Parse(repaired)
+	if err == nil {
+		return sym, diags
+	}
+	diags = append(diags, Diagnostic{Offset: -1, Message: strings.TrimPrefix(err.Error(), "invalid GSRF symbol: ")})
+
+	sym, more := salvage(repaired)
+	return sym, append(diags, more...)
+}
+
+// balanceBrackets drops unmatched closing brackets and closes brackets left
+// open at the end of input, reporting each repair. A leading quoted package
+// path is skipped.
+func balanceBrackets(input string) (string, []Diagnostic) {
+	var diags []Diagnostic
+	var b strings.Builder
+	start := 0
+	if q, err := strconv.QuotedPrefix(input); err == nil {
+		start = len(q)
+	}
+	b.WriteString(input[:start])
+
+	type open struct {
+		char   byte
+		offset int
+	}
+	var stack []open
+	closers := map[byte]byte{'(': ')', '[': ']', '{': '}'}
+	for i := start; i < len(input); i++ {
+		c := input[i]
+		switch c {
+		case '(', '[', '{':
+			stack = append(stack, open{c, i})
+		case ')', ']', '}':
+			if len(stack) == 0 || closers[stack[len(stack)-1].char] != c {
+				diags = append(diags, Diagnostic{Offset: i, Message: fmt.Sprintf("unmatched %q", c)})
+				continue
+			}
+			stack = stack[:len(stack)-1]
+		}
+		b.WriteByte(c)
+	}
+	for i := len(stack) - 1; i >= 0; i-- {
+		diags = append(diags, Diagnostic{Offset: stack[i].offset, Message: fmt.Sprintf("unclosed %q", stack[i].char)})
+		b.WriteByte(closers[stack[i].char])
+	}
+	return b.String(), diags
+}
+
+// salvage recovers the components of a symbol Parse rejected. Its input
+// may have been repaired, so its diagnostics carry no offsets.
+func salvage(input string) (*Symbol, []Diagnostic) {
+	var diags []Diagnostic
+	sym := &Symbol{}
+
+	// Metadata and context sit outside all brackets at the end.
+	core := input
+	if idx := lastTopLevel(core, '{'); idx > 0 && strings.HasSuffix(core, "}") {
+		if meta, err := Parse("_.F" + core[idx:]); err == nil {
+			sym.Metadata = meta.Metadata
+		} else {
+			diags = append(diags, Diagnostic{Offset: -1, Message: "unreadable metadata"})
+		}
+		core = core[:idx]
+	}
+	if idx := lastTopLevel(core, '@'); idx > 0 {
+		sym.Context = core[idx+1:]
+		if sym.Context == "" {
+			diags = append(diags, Diagnostic{Offset: -1, Message: "empty context after @"})
+		}
+		core = core[:idx]
+	}
+
+	// The package path ends at the last dot before the first bracket, or
+	// after a quoted path.
+	var rest string
+	if q, err := strconv.QuotedPrefix(core); err == nil {
+		sym.PackagePath, _ = strconv.Unquote(q)
+		rest = strings.TrimPrefix(core[len(q):], ".")
+	} else {
+		end := strings.IndexAny(core, "([")
+		if end < 0 {
+			end = len(core)
+		}
+		dot := strings.LastIndex(core[:end], ".")
+		if dot <= 0 {
+			return nil, append(diags, Diagnostic{Offset: -1, Message: "no package path found"})
+		}
+		sym.PackagePath = core[:dot]
+		rest = core[dot+1:]
+	}
+	if sym.PackagePath == "" {
+		return nil, append(diags, Diagnostic{Offset: -1, Message: "no package path found"})
+	}
+
+	if strings.HasPrefix(rest, "(") {
+		if end := strings.Index(rest, ")"); end > 0 {
+			if m, err := Parse("_." + rest[:end+1] + ".M"); err == nil {
+				sym.Receiver = m.Receiver
+			}
+			rest = strings.TrimPrefix(rest[end+1:], ".")
+		} else {
+			rest = ""
+		}
+	}
+	sym.Name = leadingIdentifier(rest)
+	if sym.Name == "" {
+		diags = append(diags, Diagnostic{Offset: -1, Message: "no name found"})
+	}
+	sym.IsInit = sym.Name == "init" && sym.Receiver == nil
+	return sym, diags
+}
+
+// lastTopLevel returns the offset of the last c outside parentheses and
+// square brackets, or -1.
+func lastTopLevel(s string, c byte) int {
+	last, depth := -1, 0
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '(', '[':
+			depth++
+		case ')', ']':
+			depth--
+		case c:
+			if depth == 0 {
+				last = i
+			}
+		}
+	}
+	return last
+}
+
+// leadingIdentifier returns the longest identifier prefix of s.
+func leadingIdentifier(s string) string {
+	for i, r := range s {
+		if r != '_' && !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			return s[:i]
+		}
+	}
+	return s
+}
@@ -0,0 +1,75 @@
+package gsrf
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParsePartial(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		want      *Symbol
+		wantDiags int
+	}{
+		{
+			name:  "valid",
+			input: "net/http.(*Server).Serve",
+			want:  MustParse("net/http.(*Server).Serve"),
+		},
+		{
+			name:      "unclosed type arguments",
+			input:     "pkg.Map[string, []int",
+			want:      MustParse("pkg.Map[string, []int]"),
+			wantDiags: 1,
+		},
+		{
+			name:      "unclosed receiver",
+			input:     "example.com/svc.(*Handler",
+			want:      &Symbol{PackagePath: "example.com/svc", Receiver: &Receiver{TypeName: "Handler", IsPointer: true}},
+			wantDiags: 3,
+		},
+		{
+			name:      "receiver without method",
+			input:     "pkg.(T).@linux{pos:a.go:3}",
+			want:      &Symbol{PackagePath: "pkg", Receiver: &Receiver{TypeName: "T"}, Context: "linux", Metadata: Metadata{Position: "a.go:3"}},
+			wantDiags: 2,
+		},
+		{
+			name:      "empty context",
+			input:     "pkg.Run@",
+			want:      &Symbol{PackagePath: "pkg", Name: "Run"},
+			wantDiags: 2,
+		},
+		{
+			name:      "quoted path",
+			input:     `"gopkg.in/yaml.v3".Unmarshal@`,
+			want:      &Symbol{PackagePath: "gopkg.in/yaml.v3", Name: "Unmarshal"},
+			wantDiags: 2,
+		},
+		{
+			name:      "no package",
+			input:     "Println",
+			wantDiags: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, diags := ParsePartial(tt.input)
+			if len(diags) != tt.wantDiags {
+				t.Errorf("ParsePartial() diagnostics = %v, want %d", diags, tt.wantDiags)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParsePartial() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParsePartial_Offsets(t *testing.T) {
+	_, diags := ParsePartial("pkg.F[int])")
+	if len(diags) == 0 || diags[0].Offset != 10 || diags[0].String() != `offset 10: unmatched ')'` {
+		t.Errorf("ParsePartial() diagnostics = %v", diags)
+	}
+}
@@ -0,0 +1,54 @@
+package gsrf
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// PathStyle selects the path separators of source positions, so corpora
+// recorded on Windows and Unix build agents compare byte for byte.
+type PathStyle int
+
+const (
+	PathPreserve PathStyle = iota // Keep positions as recorded
+	PathSlash                     // Forward slashes: C:/src/app/main.go:10:2
+	PathNative                    // The separator of the running OS, filepath.Separator
+)
+
+var pathStyleNames = map[PathStyle]string{
+	PathPreserve: "preserve",
+	PathSlash:    "slash",
+	PathNative:   "native",
+}
+
+// String returns the style's name as accepted by ParsePathStyle.
+func (p PathStyle) String() string {
+	if name, ok := pathStyleNames[p]; ok {
+		return name
+	}
+	return fmt.Sprintf("PathStyle(%d)", int(p))
+}
+
+// ParsePathStyle returns the path style with the given name.
+func ParsePathStyle(name string) (PathStyle, error) {
+	for p, n := range pathStyleNames {
+		if strings.EqualFold(name, n) {
+			return p, nil
+		}
+	}
+	return PathPreserve, fmt.Errorf("unknown path style: %q", name)
+}
+
+// Apply rewrites the separators of a path or position in the style. Both
+// "/" and "\" count as separators, whichever system recorded the path; on
+// Unix, native is the same as slash.
+func (p PathStyle) Apply(path string) string {
+	switch {
+	case p == PathSlash || p == PathNative && filepath.Separator == '/':
+		return strings.ReplaceAll(path, `\`, "/")
+	case p == PathNative:
+		return strings.ReplaceAll(path, "/", string(filepath.Separator))
+	}
+	return path
+}
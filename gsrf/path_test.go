@@ -0,0 +1,55 @@
+package gsrf
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestPathStyle_Apply(t *testing.T) {
+	native := `C:/src/app/main.go:10:2`
+	if filepath.Separator == '\\' {
+		native = `C:\src\app\main.go:10:2`
+	}
+	for _, pos := range []string{`C:\src\app\main.go:10:2`, `C:/src/app/main.go:10:2`, `C:\src/app\main.go:10:2`} {
+		if got := PathSlash.Apply(pos); got != "C:/src/app/main.go:10:2" {
+			t.Errorf("PathSlash.Apply(%q) = %q", pos, got)
+		}
+		if got := PathNative.Apply(pos); got != native {
+			t.Errorf("PathNative.Apply(%q) = %q, want %q", pos, got, native)
+		}
+		if got := PathPreserve.Apply(pos); got != pos {
+			t.Errorf("PathPreserve.Apply(%q) = %q", pos, got)
+		}
+	}
+}
+
+func TestParsePathStyle(t *testing.T) {
+	for _, p := range []PathStyle{PathPreserve, PathSlash, PathNative} {
+		got, err := ParsePathStyle(p.String())
+		if err != nil || got != p {
+			t.Errorf("ParsePathStyle(%q) = %v, %v", p.String(), got, err)
+		}
+	}
+	if _, err := ParsePathStyle("windows"); err == nil {
+		t.Error("ParsePathStyle(windows) succeeded")
+	}
+}
+
+func TestPathStyle_ParseAndFormat(t *testing.T) {
+	input := `pkg.(*T).Run{pos:src\pkg\t.go:10:2}`
+	sym, err := ParseWith(input, ParseOptions{PathStyle: PathSlash})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := sym.Metadata.Position, "src/pkg/t.go:10:2"; got != want {
+		t.Errorf("ParseWith() position = %q, want %q", got, want)
+	}
+
+	sym = MustParse(input)
+	if got, want := sym.Format(WithPathStyle(PathSlash)), "pkg.(*T).Run{pos:src/pkg/t.go:10:2}"; got != want {
+		t.Errorf("Format() = %q, want %q", got, want)
+	}
+	if got := sym.Format(); got != input {
+		t.Errorf("Format() = %q, want %q", got, input)
+	}
+}
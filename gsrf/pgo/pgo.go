@@ -0,0 +1,262 @@
+// Package pgo reports, per GSRF symbol, whether a PGO profile makes a
+// function hot and whether the compiler inlined it, so teams can check that
+// profile-guided optimization affects the functions they expect.
+package pgo
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/kis9a/gsrf"
+	"github.com/kis9a/gsrf/internal/pprof"
+)
+
+// DefaultHotCDF matches the compiler's default hot call-site threshold
+// (-d=pgoinlinecdfthreshold=99): the heaviest functions that together
+// account for this percentage of samples are hot.
+const DefaultHotCDF = 99.0
+
+// Inlining is the compiler's inlining decision for a function.
+type Inlining int
+
+const (
+	InlineUnknown Inlining = iota // Not mentioned in the compiler output
+	InlineNever                   // "cannot inline"
+	InlineCan                     // "can inline", but no inlined call was seen
+	InlineDone                    // At least one call site was inlined
+)
+
+func (i Inlining) String() string {
+	switch i {
+	case InlineNever:
+		return "not inlinable"
+	case InlineCan:
+		return "inlinable"
+	case InlineDone:
+		return "inlined"
+	}
+	return "unknown"
+}
+
+// Decision collects what the compiler reported about one function.
+type Decision struct {
+	Symbol       *gsrf.Symbol
+	Inlining     Inlining
+	Cost         int    // Inlining cost, if reported
+	Reason       string // Why the function cannot be inlined
+	InlinedCalls int    // Call sites inlined into their callers
+}
+
+var (
+	pkgHeaderPattern  = regexp.MustCompile(`^# (\S+)`)
+	canInlinePattern  = regexp.MustCompile(`: can inline (\S+)(?: with cost (\d+))?`)
+	cantInlinePattern = regexp.MustCompile(`: cannot inline (\S+?): (.*)$`)
+	inlineCallPattern = regexp.MustCompile(`: inlining call to (\S+)`)
+)
+
+// ReadInlining reads compiler diagnostics from "go build -gcflags=-m=2"
+// (or -m). Function names are package-relative; the package comes from the
+// "# import/path" headers go build prints, or pkg before the first header.
+func ReadInlining(r io.Reader, pkg string) (map[gsrf.SymbolKey]*Decision, error) {
+	decisions := make(map[gsrf.SymbolKey]*Decision)
+	get := func(sym *gsrf.Symbol) *Decision {
+		k := sym.Key()
+		d, ok := decisions[k]
+		if !ok {
+			d = &Decision{Symbol: sym}
+			decisions[k] = d
+		}
+		return d
+	}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line := scanner.Text()
+		if m := pkgHeaderPattern.FindStringSubmatch(line); m != nil {
+			pkg = m[1]
+			continue
+		}
+		m := canInlinePattern.FindStringSubmatch(line)
+		if m == nil {
+			m = cantInlinePattern.FindStringSubmatch(line)
+		}
+		if m == nil {
+			m = inlineCallPattern.FindStringSubmatch(line)
+		}
+		if m == nil {
+			continue
+		}
+		if pkg == "" {
+			return nil, fmt.Errorf("inlining report: line %d: no package header before %q", lineNo, m[1])
+		}
+
+		switch {
+		case strings.Contains(m[0], ": can inline "):
+			d := get(resolve(pkg, m[1], true))
+			if d.Inlining < InlineCan {
+				d.Inlining = InlineCan
+			}
+			if m[2] != "" {
+				d.Cost, _ = strconv.Atoi(m[2])
+			}
+		case strings.Contains(m[0], ": cannot inline "):
+			d := get(resolve(pkg, m[1], true))
+			d.Inlining = InlineNever
+			d.Reason = m[2]
+		default:
+			d := get(resolve(pkg, m[1], false))
+			d.Inlining = InlineDone
+			d.InlinedCalls++
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return decisions, nil
+}
+
+// resolve turns a compiler-reported function name into a symbol. Names of
+// functions defined in pkg are package-relative ("(*T).M", "F.func1").
+// Inlined callees may be qualified instead ("yaml.NewDecoder"); a name is
+// taken as qualified when its first element looks like a package name rather
+// than a receiver, function, or closure. The compiler qualifies by package
+// name, so such symbols carry the name, not the import path.
+func resolve(pkg, name string, local bool) *gsrf.Symbol {
+	if !local {
+		first, rest, ok := strings.Cut(name, ".")
+		qualified := ok && !strings.HasPrefix(name, "(") &&
+			first == strings.ToLower(first) && !strings.HasPrefix(rest, "func")
+		if qualified {
+			if sym, err := gsrf.FromRuntimeName(name); err == nil {
+				if sym.PackagePath == pkg[strings.LastIndex(pkg, "/")+1:] {
+					sym.PackagePath = pkg
+					if sym.IsAnonymous {
+						sym.AnonParent = pkg + "." + sym.Name
+					}
+				}
+				return sym
+			}
+		}
+	}
+	if sym, err := gsrf.FromRuntimeName(pkg + "." + name); err == nil {
+		return sym
+	}
+	return &gsrf.Symbol{PackagePath: pkg, Name: name}
+}
+
+// Heat is a function's share of profile samples.
+type Heat struct {
+	Symbol *gsrf.Symbol
+	Weight int64   // Flat sample weight: samples where the function was innermost
+	Share  float64 // Weight as a percentage of the total
+	Hot    bool
+}
+
+// ReadProfile reads a pprof CPU profile (the default.pgo input of PGO
+// builds) and ranks functions by flat weight, using the first nanosecond
+// sample type or else the first. The heaviest functions that together reach
+// cdf percent of the total weight are hot.
+func ReadProfile(r io.Reader, cdf float64) (map[gsrf.SymbolKey]*Heat, error) {
+	p, err := pprof.Read(r)
+	if err != nil {
+		return nil, err
+	}
+	idx := p.SampleIndex(func(vt pprof.ValueType) bool { return vt.Unit == "nanoseconds" })
+	if idx < 0 {
+		idx = 0
+	}
+
+	heat := make(map[gsrf.SymbolKey]*Heat)
+	var total int64
+	for _, s := range p.Samples {
+		stack := p.Stack(s)
+		if len(stack) == 0 || idx >= len(s.Values) {
+			continue
+		}
+		sym, err := gsrf.FromRuntimeName(stack[0])
+		if err != nil {
+			continue
+		}
+		k := sym.Key()
+		h, ok := heat[k]
+		if !ok {
+			h = &Heat{Symbol: sym}
+			heat[k] = h
+		}
+		h.Weight += s.Values[idx]
+		total += s.Values[idx]
+	}
+	if total == 0 {
+		return heat, nil
+	}
+
+	ranked := make([]*Heat, 0, len(heat))
+	for _, h := range heat {
+		h.Share = 100 * float64(h.Weight) / float64(total)
+		ranked = append(ranked, h)
+	}
+	sortByWeight(ranked, func(h *Heat) (int64, string) { return h.Weight, h.Symbol.String() })
+	var cum float64
+	for _, h := range ranked {
+		if cum >= cdf {
+			break
+		}
+		h.Hot = true
+		cum += h.Share
+	}
+	return heat, nil
+}
+
+// Entry is one line of a report.
+type Entry struct {
+	Symbol *gsrf.Symbol
+	Heat
+	Decision
+}
+
+// Report joins profile heat and inlining decisions by symbol, ordered by
+// weight, heaviest first, then by symbol. Symbols known to only one side are
+// included with the other side's zero value.
+func Report(heat map[gsrf.SymbolKey]*Heat, decisions map[gsrf.SymbolKey]*Decision) []Entry {
+	entries := make(map[gsrf.SymbolKey]*Entry)
+	get := func(k gsrf.SymbolKey, sym *gsrf.Symbol) *Entry {
+		e, ok := entries[k]
+		if !ok {
+			e = &Entry{Symbol: sym}
+			entries[k] = e
+		}
+		return e
+	}
+	for k, h := range heat {
+		get(k, h.Symbol).Heat = *h
+	}
+	for k, d := range decisions {
+		get(k, d.Symbol).Decision = *d
+	}
+
+	out := make([]Entry, 0, len(entries))
+	for _, e := range entries {
+		out = append(out, *e)
+	}
+	sortByWeight(out, func(e Entry) (int64, string) { return e.Weight, e.Symbol.String() })
+	return out
+}
+
+func sortByWeight[T any](s []T, key func(T) (int64, string)) {
+	sort.Slice(s, func(i, j int) bool {
+		wi, si := key(s[i])
+		wj, sj := key(s[j])
+		if wi != wj {
+			return wi > wj
+		}
+		return si < sj
+	})
+}
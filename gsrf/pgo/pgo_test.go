@@ -0,0 +1,146 @@
+package pgo
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/kis9a/gsrf"
+	"github.com/kis9a/gsrf/internal/pprof"
+)
+
+const buildLog = `# example.com/app/api
+./api.go:12:6: can inline (*Handler).decode with cost 42 as: method(h *Handler) decode(b []byte) error { ... }
+./api.go:20:6: cannot inline (*Handler).ServeHTTP: function too complex: cost 312 exceeds budget 80
+./api.go:25:14: inlining call to (*Handler).decode
+./api.go:31:9: inlining call to strings.Cut
+./api.go:40:6: can inline Handle.func1
+# example.com/app
+./main.go:8:6: cannot inline main: unhandled op DEFER
+./main.go:9:12: inlining call to api.NewHandler
+`
+
+func key(s string) gsrf.SymbolKey {
+	return gsrf.MustParse(s).Key()
+}
+
+func TestReadInlining(t *testing.T) {
+	decisions, err := ReadInlining(strings.NewReader(buildLog), "")
+	if err != nil {
+		t.Fatalf("ReadInlining() error = %v", err)
+	}
+
+	tests := []struct {
+		symbol   string
+		inlining Inlining
+		calls    int
+	}{
+		{"example.com/app/api.(*Handler).decode", InlineDone, 1},
+		{"example.com/app/api.(*Handler).ServeHTTP", InlineNever, 0},
+		{"strings.Cut", InlineDone, 1},
+		{"example.com/app/api.Handle·lit1", InlineCan, 0},
+		{"example.com/app.main", InlineNever, 0},
+		{"api.NewHandler", InlineDone, 1},
+	}
+	for _, tt := range tests {
+		d, ok := decisions[key(tt.symbol)]
+		if !ok {
+			t.Errorf("no decision for %s in %v", tt.symbol, decisions)
+			continue
+		}
+		if d.Inlining != tt.inlining || d.InlinedCalls != tt.calls {
+			t.Errorf("%s = %s with %d calls, want %s with %d", tt.symbol, d.Inlining, d.InlinedCalls, tt.inlining, tt.calls)
+		}
+	}
+	if d := decisions[key("example.com/app/api.(*Handler).decode")]; d.Cost != 42 {
+		t.Errorf("decode cost = %d, want 42", d.Cost)
+	}
+	if d := decisions[key("example.com/app/api.(*Handler).ServeHTTP")]; !strings.HasPrefix(d.Reason, "function too complex") {
+		t.Errorf("ServeHTTP reason = %q", d.Reason)
+	}
+
+	if _, err := ReadInlining(strings.NewReader("./a.go:1:6: can inline F\n"), ""); err == nil {
+		t.Error("ReadInlining() accepted a decision without a package")
+	}
+	if d, err := ReadInlining(strings.NewReader("./a.go:1:6: can inline F\n"), "pkg"); err != nil || d[key("pkg.F")] == nil {
+		t.Errorf("ReadInlining() with default package = %v, %v", d, err)
+	}
+}
+
+func profile(t *testing.T, weights map[string]int64) *bytes.Buffer {
+	t.Helper()
+	p := &pprof.Profile{
+		SampleTypes: []pprof.ValueType{{Type: "samples", Unit: "count"}, {Type: "cpu", Unit: "nanoseconds"}},
+		Locations:   make(map[uint64][]uint64),
+		Functions:   map[uint64]string{1: "example.com/app.main"},
+	}
+	id := uint64(2)
+	for name, w := range weights {
+		p.Functions[id] = name
+		p.Locations[id] = []uint64{id}
+		p.Locations[id+100] = []uint64{1}
+		p.Samples = append(p.Samples, pprof.Sample{Locations: []uint64{id, id + 100}, Values: []int64{1, w}})
+		id++
+	}
+	var buf bytes.Buffer
+	if err := pprof.Write(&buf, p); err != nil {
+		t.Fatal(err)
+	}
+	return &buf
+}
+
+func TestReadProfile(t *testing.T) {
+	heat, err := ReadProfile(profile(t, map[string]int64{
+		"example.com/app/api.(*Handler).ServeHTTP": 900,
+		"example.com/app/api.(*Handler).decode":    95,
+		"strings.Cut":                              5,
+	}), 95)
+	if err != nil {
+		t.Fatalf("ReadProfile() error = %v", err)
+	}
+
+	tests := []struct {
+		symbol string
+		share  float64
+		hot    bool
+	}{
+		{"example.com/app/api.(*Handler).ServeHTTP", 90, true},
+		{"example.com/app/api.(*Handler).decode", 9.5, true},
+		{"strings.Cut", 0.5, false},
+	}
+	for _, tt := range tests {
+		h := heat[key(tt.symbol)]
+		if h == nil || h.Share != tt.share || h.Hot != tt.hot {
+			t.Errorf("%s heat = %+v, want share %v hot %v", tt.symbol, h, tt.share, tt.hot)
+		}
+	}
+	if h := heat[key("example.com/app.main")]; h != nil {
+		t.Errorf("caller has flat weight %+v", h)
+	}
+}
+
+func TestReport(t *testing.T) {
+	heat, err := ReadProfile(profile(t, map[string]int64{
+		"example.com/app/api.(*Handler).ServeHTTP": 900,
+		"example.com/app/api.(*Handler).decode":    100,
+	}), DefaultHotCDF)
+	if err != nil {
+		t.Fatal(err)
+	}
+	decisions, err := ReadInlining(strings.NewReader(buildLog), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	entries := Report(heat, decisions)
+	if len(entries) != 6 {
+		t.Fatalf("Report() = %d entries, want 6", len(entries))
+	}
+	first := entries[0]
+	if first.Symbol.String() != "example.com/app/api.(*Handler).ServeHTTP" || !first.Hot || first.Inlining != InlineNever {
+		t.Errorf("Report()[0] = %s hot=%v %s", first.Symbol, first.Hot, first.Inlining)
+	}
+	if last := entries[len(entries)-1]; last.Weight != 0 || last.Hot {
+		t.Errorf("Report() last = %s weight=%d", last.Symbol, last.Weight)
+	}
+}
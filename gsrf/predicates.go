@@ -0,0 +1,63 @@
+package gsrf
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// stdlibRoots are the first path elements of standard library packages.
+var stdlibRoots = map[string]bool{
+	"archive": true, "bufio": true, "bytes": true, "cmp": true, "compress": true,
+	"container": true, "context": true, "crypto": true, "database": true,
+	"debug": true, "embed": true, "encoding": true, "errors": true, "expvar": true,
+	"flag": true, "fmt": true, "go": true, "hash": true, "html": true,
+	"image": true, "index": true, "internal": true, "io": true, "iter": true,
+	"log": true, "maps": true, "math": true, "mime": true, "net": true,
+	"os": true, "path": true, "plugin": true, "reflect": true, "regexp": true,
+	"runtime": true, "slices": true, "sort": true, "strconv": true,
+	"strings": true, "structs": true, "sync": true, "syscall": true,
+	"testing": true, "text": true, "time": true, "unicode": true,
+	"unique": true, "unsafe": true, "uuid": true, "weak": true,
+}
+
+// IsExported reports whether the symbol can be referenced from other
+// packages: its name starts with an upper-case letter and, for methods, so
+// does the receiver type. Function literals and init are never exported.
+func (s *Symbol) IsExported() bool {
+	if s.IsAnonymous || s.IsInit || !startsUpper(s.Name) {
+		return false
+	}
+	return s.Receiver == nil || startsUpper(s.Receiver.TypeName)
+}
+
+// IsStdlib reports whether the symbol belongs to the standard library: the
+// first element of its package path contains no dot and names a standard
+// library root such as "net" or "crypto".
+func (s *Symbol) IsStdlib() bool {
+	first, _, _ := strings.Cut(s.PackagePath, "/")
+	return !strings.Contains(first, ".") && stdlibRoots[first]
+}
+
+// PackageName returns the last element of the package path, e.g. "http" for
+// "net/http".
+func (s *Symbol) PackageName() string {
+	return s.PackagePath[strings.LastIndex(s.PackagePath, "/")+1:]
+}
+
+// QualifiedName returns the symbol qualified by its package name rather than
+// its import path, without type arguments, context, or metadata, e.g.
+// "http.(*Server).Serve".
+func (s *Symbol) QualifiedName() string {
+	return s.FormatWith(FormatOptions{
+		ShortPackage: true,
+		OmitContext:  true,
+		OmitMetadata: true,
+		OmitTypeArgs: true,
+	})
+}
+
+func startsUpper(name string) bool {
+	r, _ := utf8.DecodeRuneInString(name)
+	return unicode.IsUpper(r)
+}
@@ -0,0 +1,41 @@
+package gsrf
+
+import "testing"
+
+func TestSymbol_Predicates(t *testing.T) {
+	tests := []struct {
+		input     string
+		exported  bool
+		stdlib    bool
+		pkgName   string
+		qualified string
+	}{
+		{"net/http.(*Server).Serve", true, true, "http", "http.(*Server).Serve"},
+		{"net/http.(*conn).serve", false, true, "http", "http.(*conn).serve"},
+		{"net/http.(*conn).Close", false, true, "http", "http.(*conn).Close"},
+		{"github.com/spf13/cobra.(*Command).Execute@linux", true, false, "cobra", "cobra.(*Command).Execute"},
+		{"slices.Index[[]string, string]", true, true, "slices", "slices.Index"},
+		{"main.main", false, false, "main", "main.main"},
+		{"myapp/internal/auth.Login", true, false, "auth", "auth.Login"},
+		{"pkg.Handle·lit1", false, false, "pkg", "pkg.Handle·lit1"},
+		{"database/sql.init", false, true, "sql", "sql.init"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			sym := MustParse(tt.input)
+			if got := sym.IsExported(); got != tt.exported {
+				t.Errorf("IsExported() = %v, want %v", got, tt.exported)
+			}
+			if got := sym.IsStdlib(); got != tt.stdlib {
+				t.Errorf("IsStdlib() = %v, want %v", got, tt.stdlib)
+			}
+			if got := sym.PackageName(); got != tt.pkgName {
+				t.Errorf("PackageName() = %q, want %q", got, tt.pkgName)
+			}
+			if got := sym.QualifiedName(); got != tt.qualified {
+				t.Errorf("QualifiedName() = %q, want %q", got, tt.qualified)
+			}
+		})
+	}
+}
@@ -0,0 +1,121 @@
+package gsrf
+
+import (
+	"fmt"
+	"strings"
+)
+
+// FormatProfile selects a named set of formatting choices.
+type FormatProfile int
+
+const (
+	ProfileDefault FormatProfile = iota // Spec notation (·lit for anonymous functions)
+	ProfileASCII                        // ASCII-only notation (%lit for anonymous functions)
+	ProfileHuman                        // Readable output for terminals and reports
+	ProfileCompact                      // Short single-token output for logs
+	ProfileMachine                      // Full fidelity, ASCII-only, deterministic
+	ProfileDebug                        // Full fidelity with deterministic metadata order
+)
+
+var profileNames = map[FormatProfile]string{
+	ProfileDefault: "default",
+	ProfileASCII:   "ascii",
+	ProfileHuman:   "human",
+	ProfileCompact: "compact",
+	ProfileMachine: "machine",
+	ProfileDebug:   "debug",
+}
+
+// String returns the profile's name as accepted by ParseProfile.
+func (p FormatProfile) String() string {
+	if name, ok := profileNames[p]; ok {
+		return name
+	}
+	return fmt.Sprintf("FormatProfile(%d)", int(p))
+}
+
+// ParseProfile returns the profile with the given name.
+func ParseProfile(name string) (FormatProfile, error) {
+	for p, n := range profileNames {
+		if strings.EqualFold(name, n) {
+			return p, nil
+		}
+	}
+	return ProfileDefault, fmt.Errorf("unknown format profile: %q", name)
+}
+
+// Options returns the formatting options bundled by the profile.
+//
+//   - human:   metadata dropped, truncated to 120 runes
+//   - compact: short package, no type args, context or metadata, ASCII, 80 runes
+//   - machine: everything kept, ASCII markers, metadata sorted by key
+//   - debug:   everything kept, metadata sorted by key
+func (p FormatProfile) Options() FormatOptions {
+	switch p {
+	case ProfileASCII:
+		return FormatOptions{ASCII: true}
+	case ProfileHuman:
+		return FormatOptions{OmitMetadata: true, MaxLength: 120}
+	case ProfileCompact:
+		return FormatOptions{
+			OmitMetadata: true,
+			OmitContext:  true,
+			OmitTypeArgs: true,
+			ShortPackage: true,
+			ASCII:        true,
+			MaxLength:    80,
+		}
+	case ProfileMachine:
+		return FormatOptions{ASCII: true, SortMetadata: true}
+	case ProfileDebug:
+		return FormatOptions{SortMetadata: true}
+	default:
+		return FormatOptions{}
+	}
+}
+
+// FormatOption adjusts FormatOptions. Options passed to Symbol.Format
+// cascade: each is applied on top of the previous ones.
+type FormatOption func(*FormatOptions)
+
+// WithProfile replaces the current options with the profile's preset.
+func WithProfile(p FormatProfile) FormatOption {
+	return func(o *FormatOptions) {
+		*o = p.Options()
+	}
+}
+
+// WithOptions replaces the current options.
+func WithOptions(opts FormatOptions) FormatOption {
+	return func(o *FormatOptions) {
+		*o = opts
+	}
+}
+
+// WithMaxLength sets the truncation limit in runes (0 = no limit).
+func WithMaxLength(n int) FormatOption {
+	return func(o *FormatOptions) {
+		o.MaxLength = n
+	}
+}
+
+// WithReceiverPolicy sets how receiver pointerness is rendered.
+func WithReceiverPolicy(p ReceiverPolicy) FormatOption {
+	return func(o *FormatOptions) {
+		o.Receiver = p
+	}
+}
+
+// WithASCII toggles ASCII-only output.
+func WithASCII(ascii bool) FormatOption {
+	return func(o *FormatOptions) {
+		o.ASCII = ascii
+	}
+}
+
+// WithPathStyle sets the separators of the position metadata.
+func WithPathStyle(p PathStyle) FormatOption {
+	return func(o *FormatOptions) {
+		o.PathStyle = p
+	}
+}
@@ -0,0 +1,100 @@
+package gsrf
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSymbol_FormatProfiles(t *testing.T) {
+	sym := MustParse("github.com/user/repo.(*Server[T]).Start@linux{pos:server.go:10:1,team:core,area:net}")
+
+	tests := []struct {
+		name string
+		opts []FormatOption
+		want string
+	}{
+		{
+			name: "no options",
+			want: "github.com/user/repo.(*Server[T]).Start@linux{pos:server.go:10:1,area:net,team:core}",
+		},
+		{
+			name: "human",
+			opts: []FormatOption{WithProfile(ProfileHuman)},
+			want: "github.com/user/repo.(*Server[T]).Start@linux",
+		},
+		{
+			name: "compact",
+			opts: []FormatOption{WithProfile(ProfileCompact)},
+			want: "repo.(*Server).Start",
+		},
+		{
+			name: "machine",
+			opts: []FormatOption{WithProfile(ProfileMachine)},
+			want: "github.com/user/repo.(*Server[T]).Start@linux{area:net,pos:server.go:10:1,team:core}",
+		},
+		{
+			name: "debug",
+			opts: []FormatOption{WithProfile(ProfileDebug)},
+			want: "github.com/user/repo.(*Server[T]).Start@linux{area:net,pos:server.go:10:1,team:core}",
+		},
+		{
+			name: "cascade overrides profile",
+			opts: []FormatOption{WithProfile(ProfileCompact), WithASCII(false), WithReceiverPolicy(ReceiverValue)},
+			want: "repo.(Server).Start",
+		},
+		{
+			name: "truncation",
+			opts: []FormatOption{WithProfile(ProfileHuman), WithMaxLength(20)},
+			want: "github.com/user/rep…",
+		},
+		{
+			name: "ascii truncation",
+			opts: []FormatOption{WithProfile(ProfileMachine), WithMaxLength(20)},
+			want: "github.com/user/r...",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := sym.Format(tt.opts...); got != tt.want {
+				t.Errorf("Format() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSymbol_FormatProfilesAnonymous(t *testing.T) {
+	sym := MustParse("github.com/user/repo.handler·lit2")
+	if got := sym.Format(WithProfile(ProfileCompact)); got != "repo.handler%lit2" {
+		t.Errorf("compact = %q", got)
+	}
+	if got := sym.Format(WithProfile(ProfileDebug)); got != "github.com/user/repo.handler·lit2" {
+		t.Errorf("debug = %q", got)
+	}
+}
+
+func TestReceiverPolicy(t *testing.T) {
+	value := MustParse("pkg.(T).M")
+	if got := value.Format(WithReceiverPolicy(ReceiverPointer)); got != "pkg.(*T).M" {
+		t.Errorf("ReceiverPointer = %q", got)
+	}
+	ptr := MustParse("pkg.(*T).M")
+	if got := ptr.Format(WithReceiverPolicy(ReceiverValue)); got != "pkg.(T).M" {
+		t.Errorf("ReceiverValue = %q", got)
+	}
+}
+
+func TestParseProfile(t *testing.T) {
+	for p, name := range profileNames {
+		got, err := ParseProfile(strings.ToUpper(name))
+		if err != nil || got != p {
+			t.Errorf("ParseProfile(%q) = %v, %v; want %v", name, got, err, p)
+		}
+		if p.String() != name {
+			t.Errorf("String() = %q, want %q", p.String(), name)
+		}
+	}
+	if _, err := ParseProfile("fancy"); err == nil {
+		t.Error("ParseProfile(\"fancy\") expected error")
+	}
+}
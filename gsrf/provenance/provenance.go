@@ -0,0 +1,275 @@
+// Package provenance attributes GSRF symbols to Go modules and their licenses.
+package provenance
+
+import (
+	"bufio"
+	"bytes"
+	"debug/buildinfo"
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kis9a/gsrf"
+)
+
+// Metadata keys written by Resolver.Tag.
+const (
+	KeyModule     = "module"
+	KeyLicense    = "license"
+	KeyCommit     = "commit"      // Revision from a pseudo-version
+	KeyCommitTime = "commit_time" // RFC 3339 commit time from a pseudo-version
+)
+
+// StdModule is the module path used for standard library packages.
+const StdModule = "std"
+
+// Module describes a Go module and its license.
+type Module struct {
+	Path    string // Module path
+	Version string // Module version (empty for the main module)
+	License string // SPDX license identifier or expression
+	Main    bool   // True for the main module, which also owns package "main"
+
+	// Set for pseudo-versions (v0.0.0-20240101000000-abcdefabcdef).
+	Commit     string    // Abbreviated commit hash
+	CommitTime time.Time // Commit timestamp in UTC
+}
+
+// pseudoVersionPattern matches the timestamp and revision suffix shared by
+// all three pseudo-version forms.
+var pseudoVersionPattern = regexp.MustCompile(`^v\d+\.\d+\.\d+-(?:.*\.)?(\d{14})-([0-9a-f]{12})(?:\+incompatible)?$`)
+
+// ParsePseudoVersion extracts the commit hash and time from a Go module
+// pseudo-version. It reports false for release versions.
+func ParsePseudoVersion(version string) (commit string, t time.Time, ok bool) {
+	m := pseudoVersionPattern.FindStringSubmatch(version)
+	if m == nil {
+		return "", time.Time{}, false
+	}
+	t, err := time.Parse("20060102150405", m[1])
+	if err != nil {
+		return "", time.Time{}, false
+	}
+	return m[2], t, true
+}
+
+// HasCommitTime reports whether the module's age is known.
+func (m Module) HasCommitTime() bool {
+	return !m.CommitTime.IsZero()
+}
+
+// Resolver maps package paths to the modules that contain them.
+type Resolver struct {
+	modules []Module // Sorted by descending path length for longest-prefix lookup
+}
+
+// NewResolver creates a resolver over the given modules.
+func NewResolver(modules []Module) *Resolver {
+	sorted := make([]Module, len(modules))
+	copy(sorted, modules)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return len(sorted[i].Path) > len(sorted[j].Path)
+	})
+	return &Resolver{modules: sorted}
+}
+
+// Lookup returns the module containing the package path. Standard library
+// packages resolve to the StdModule entry when one is registered.
+func (r *Resolver) Lookup(pkg string) (Module, bool) {
+	if pkg == "main" {
+		for _, m := range r.modules {
+			if m.Main {
+				return m, true
+			}
+		}
+		return Module{}, false
+	}
+	if (&gsrf.Symbol{PackagePath: pkg}).IsStdlib() {
+		for _, m := range r.modules {
+			if m.Path == StdModule {
+				return m, true
+			}
+		}
+		return Module{}, false
+	}
+	for _, m := range r.modules {
+		if pkg == m.Path || strings.HasPrefix(pkg, m.Path+"/") {
+			return m, true
+		}
+	}
+	return Module{}, false
+}
+
+// Tag records the symbol's module and license in its custom metadata.
+// It reports whether the symbol's package belongs to a known module.
+func (r *Resolver) Tag(sym *gsrf.Symbol) bool {
+	m, ok := r.Lookup(sym.PackagePath)
+	if !ok {
+		return false
+	}
+	if sym.Metadata.Custom == nil {
+		sym.Metadata.Custom = make(map[string]string)
+	}
+	module := m.Path
+	if m.Version != "" {
+		module += "@" + m.Version
+	}
+	sym.Metadata.Custom[KeyModule] = module
+	if m.License != "" {
+		sym.Metadata.Custom[KeyLicense] = m.License
+	}
+	if m.Commit != "" {
+		sym.Metadata.Custom[KeyCommit] = m.Commit
+		sym.Metadata.Custom[KeyCommitTime] = m.CommitTime.Format(time.RFC3339)
+	}
+	return true
+}
+
+// ModulesFromBinary reads the main module and dependencies recorded in a Go
+// binary's build info, plus a StdModule entry versioned by the Go toolchain.
+// Replaced modules are reported under their original path. Commit details
+// are filled in for pseudo-versions.
+func ModulesFromBinary(path string) ([]Module, error) {
+	info, err := buildinfo.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read build info: %w", err)
+	}
+
+	modules := []Module{{Path: info.Main.Path, Version: info.Main.Version, Main: true}}
+	for _, dep := range info.Deps {
+		version := dep.Version
+		if dep.Replace != nil {
+			version = dep.Replace.Version
+		}
+		m := Module{Path: dep.Path, Version: version}
+		m.Commit, m.CommitTime, _ = ParsePseudoVersion(version)
+		modules = append(modules, m)
+	}
+	modules = append(modules, Module{Path: StdModule, Version: info.GoVersion})
+	return modules, nil
+}
+
+// ApplyLicenses fills in module licenses from a module path to SPDX mapping.
+func ApplyLicenses(modules []Module, licenses map[string]string) {
+	for i := range modules {
+		if id, ok := licenses[modules[i].Path]; ok {
+			modules[i].License = id
+		}
+	}
+}
+
+// depsDevVersion is the subset of a deps.dev version record used here.
+type depsDevVersion struct {
+	VersionKey struct {
+		Name string `json:"name"`
+	} `json:"versionKey"`
+	Licenses []string `json:"licenses"`
+}
+
+// ReadLicenses reads a module path to SPDX mapping. It accepts plain text
+// lines of the form "module SPDX-expression" (# comments allowed), a JSON
+// object mapping module paths to identifiers, or deps.dev version records
+// (a single record, an array, or one record per line).
+func ReadLicenses(r io.Reader) (map[string]string, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) > 0 && (trimmed[0] == '{' || trimmed[0] == '[') {
+		return readLicensesJSON(trimmed)
+	}
+
+	licenses := make(map[string]string)
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			return nil, fmt.Errorf("line %d: expected \"module license\"", lineNo)
+		}
+		licenses[fields[0]] = strings.Join(fields[1:], " ")
+	}
+	return licenses, scanner.Err()
+}
+
+func readLicensesJSON(data []byte) (map[string]string, error) {
+	licenses := make(map[string]string)
+	addRecord := func(v depsDevVersion) {
+		if v.VersionKey.Name != "" && len(v.Licenses) > 0 {
+			licenses[v.VersionKey.Name] = strings.Join(v.Licenses, " AND ")
+		}
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	for dec.More() {
+		var raw json.RawMessage
+		if err := dec.Decode(&raw); err != nil {
+			return nil, fmt.Errorf("decode licenses: %w", err)
+		}
+		if raw[0] == '[' {
+			var records []depsDevVersion
+			if err := json.Unmarshal(raw, &records); err != nil {
+				return nil, fmt.Errorf("decode licenses: %w", err)
+			}
+			for _, v := range records {
+				addRecord(v)
+			}
+			continue
+		}
+		if bytes.Contains(raw, []byte(`"versionKey"`)) {
+			var v depsDevVersion
+			if err := json.Unmarshal(raw, &v); err != nil {
+				return nil, fmt.Errorf("decode licenses: %w", err)
+			}
+			addRecord(v)
+			continue
+		}
+		var m map[string]string
+		if err := json.Unmarshal(raw, &m); err != nil {
+			return nil, fmt.Errorf("decode licenses: %w", err)
+		}
+		for k, v := range m {
+			licenses[k] = v
+		}
+	}
+	return licenses, nil
+}
+
+// ParseAge parses an age such as "1y", "6mo", "2w", "30d", or any
+// time.ParseDuration string. Years and months are 365 and 30 days.
+func ParseAge(s string) (time.Duration, error) {
+	units := []struct {
+		suffix string
+		unit   time.Duration
+	}{
+		{"mo", 30 * 24 * time.Hour},
+		{"y", 365 * 24 * time.Hour},
+		{"w", 7 * 24 * time.Hour},
+		{"d", 24 * time.Hour},
+	}
+	for _, u := range units {
+		if n, ok := strings.CutSuffix(s, u.suffix); ok {
+			v, err := strconv.ParseFloat(n, 64)
+			if err != nil || v < 0 {
+				return 0, fmt.Errorf("invalid age: %q", s)
+			}
+			return time.Duration(v * float64(u.unit)), nil
+		}
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid age: %q", s)
+	}
+	return d, nil
+}
@@ -0,0 +1,174 @@
+package provenance
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kis9a/gsrf"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolver_Lookup(t *testing.T) {
+	r := NewResolver([]Module{
+		{Path: "github.com/acme/app", Main: true, License: "Apache-2.0"},
+		{Path: "github.com/lib/pq", Version: "v1.10.9", License: "MIT"},
+		{Path: "github.com/lib/pq/v2", Version: "v2.0.0", License: "GPL-3.0-only"},
+		{Path: StdModule, License: "BSD-3-Clause"},
+	})
+
+	tests := []struct {
+		pkg    string
+		want   string
+		wantOK bool
+	}{
+		{pkg: "github.com/lib/pq", want: "github.com/lib/pq", wantOK: true},
+		{pkg: "github.com/lib/pq/oid", want: "github.com/lib/pq", wantOK: true},
+		{pkg: "github.com/lib/pq/v2/oid", want: "github.com/lib/pq/v2", wantOK: true},
+		{pkg: "github.com/lib/pqx", wantOK: false},
+		{pkg: "net/http", want: StdModule, wantOK: true},
+		{pkg: "main", want: "github.com/acme/app", wantOK: true},
+		{pkg: "example.com/unknown", wantOK: false},
+		{pkg: "example/foo", wantOK: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pkg, func(t *testing.T) {
+			m, ok := r.Lookup(tt.pkg)
+			assert.Equal(t, tt.wantOK, ok)
+			assert.Equal(t, tt.want, m.Path)
+		})
+	}
+}
+
+func TestResolver_Tag(t *testing.T) {
+	r := NewResolver([]Module{{Path: "github.com/lib/pq", Version: "v1.10.9", License: "MIT"}})
+
+	sym := gsrf.MustParse("github.com/lib/pq.(*conn).Query")
+	require.True(t, r.Tag(sym))
+	assert.Equal(t, "github.com/lib/pq@v1.10.9", sym.Metadata.Custom[KeyModule])
+	assert.Equal(t, "MIT", sym.Metadata.Custom[KeyLicense])
+
+	other := gsrf.MustParse("example.com/x.F")
+	assert.False(t, r.Tag(other))
+	assert.Nil(t, other.Metadata.Custom)
+}
+
+func TestReadLicenses(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  map[string]string
+	}{
+		{
+			name:  "text",
+			input: "# licenses\ngithub.com/lib/pq MIT\ngithub.com/x/y MIT OR Apache-2.0\n",
+			want:  map[string]string{"github.com/lib/pq": "MIT", "github.com/x/y": "MIT OR Apache-2.0"},
+		},
+		{
+			name:  "json object",
+			input: `{"github.com/lib/pq": "MIT"}`,
+			want:  map[string]string{"github.com/lib/pq": "MIT"},
+		},
+		{
+			name:  "deps.dev array",
+			input: `[{"versionKey":{"system":"GO","name":"github.com/lib/pq","version":"v1.10.9"},"licenses":["MIT"]}]`,
+			want:  map[string]string{"github.com/lib/pq": "MIT"},
+		},
+		{
+			name: "deps.dev lines",
+			input: `{"versionKey":{"name":"github.com/a/b"},"licenses":["GPL-2.0-only","MIT"]}
+{"versionKey":{"name":"github.com/c/d"},"licenses":["BSD-2-Clause"]}`,
+			want: map[string]string{"github.com/a/b": "GPL-2.0-only AND MIT", "github.com/c/d": "BSD-2-Clause"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ReadLicenses(strings.NewReader(tt.input))
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	t.Run("malformed line", func(t *testing.T) {
+		_, err := ReadLicenses(strings.NewReader("github.com/lib/pq\n"))
+		assert.Error(t, err)
+	})
+}
+
+func TestApplyLicenses(t *testing.T) {
+	mods := []Module{{Path: "github.com/lib/pq"}, {Path: "github.com/x/y"}}
+	ApplyLicenses(mods, map[string]string{"github.com/lib/pq": "MIT"})
+	assert.Equal(t, "MIT", mods[0].License)
+	assert.Empty(t, mods[1].License)
+}
+
+func TestParsePseudoVersion(t *testing.T) {
+	tests := []struct {
+		version    string
+		wantCommit string
+		wantTime   string
+		wantOK     bool
+	}{
+		{version: "v0.0.0-20240101123045-abcdefabcdef", wantCommit: "abcdefabcdef", wantTime: "2024-01-01T12:30:45Z", wantOK: true},
+		{version: "v1.2.4-0.20230615000000-0123456789ab", wantCommit: "0123456789ab", wantTime: "2023-06-15T00:00:00Z", wantOK: true},
+		{version: "v1.2.3-pre.0.20220101000000-0123456789ab", wantCommit: "0123456789ab", wantTime: "2022-01-01T00:00:00Z", wantOK: true},
+		{version: "v2.0.0-20200101000000-0123456789ab+incompatible", wantCommit: "0123456789ab", wantTime: "2020-01-01T00:00:00Z", wantOK: true},
+		{version: "v1.10.9", wantOK: false},
+		{version: "v1.0.0-rc.1", wantOK: false},
+		{version: "(devel)", wantOK: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.version, func(t *testing.T) {
+			commit, ts, ok := ParsePseudoVersion(tt.version)
+			require.Equal(t, tt.wantOK, ok)
+			if !ok {
+				return
+			}
+			assert.Equal(t, tt.wantCommit, commit)
+			assert.Equal(t, tt.wantTime, ts.Format(time.RFC3339))
+		})
+	}
+}
+
+func TestResolver_TagPseudoVersion(t *testing.T) {
+	m := Module{Path: "github.com/x/y", Version: "v0.0.0-20240101000000-abcdefabcdef"}
+	m.Commit, m.CommitTime, _ = ParsePseudoVersion(m.Version)
+
+	sym := gsrf.MustParse("github.com/x/y.F")
+	require.True(t, NewResolver([]Module{m}).Tag(sym))
+	assert.Equal(t, "abcdefabcdef", sym.Metadata.Custom[KeyCommit])
+	assert.Equal(t, "2024-01-01T00:00:00Z", sym.Metadata.Custom[KeyCommitTime])
+}
+
+func TestParseAge(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    time.Duration
+		wantErr bool
+	}{
+		{input: "1y", want: 365 * 24 * time.Hour},
+		{input: "6mo", want: 180 * 24 * time.Hour},
+		{input: "2w", want: 14 * 24 * time.Hour},
+		{input: "30d", want: 30 * 24 * time.Hour},
+		{input: "1.5d", want: 36 * time.Hour},
+		{input: "12h", want: 12 * time.Hour},
+		{input: "xy", wantErr: true},
+		{input: "-1d", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseAge(tt.input)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,400 @@
+// Package query compiles filter expressions over GSRF symbols, so symbols
+// can be selected without writing Go code:
+//
+//	pkg =~ "github.com/org/*" && receiver == "*Server" && has(meta.via)
+//
+// String fields are compared with == and != , or matched against a glob with
+// =~ and !~, where "*" matches any run of characters, including "/", and "?"
+// any one character. Boolean fields stand alone. Conditions combine with
+// &&, || and !, and group with parentheses. has(field) reports whether a
+// string field is set.
+//
+// String fields:
+//
+//	symbol     the canonical GSRF form
+//	pkg        package path
+//	name       function or method name
+//	receiver   receiver type with its pointer mark, such as "*Server"; empty for functions
+//	context    context modifier, such as "linux"
+//	meta.via   promotion path entries; compared entry by entry
+//	meta.alias alias chain
+//	meta.pos   source position
+//	meta.KEY   custom metadata entry KEY
+//	tags       tags, such as "team:payments"; compared tag by tag
+//
+// Boolean fields: method, anonymous, init, generic, exported, stdlib.
+//
+// CompileTags compiles the shorter tag expressions, whose operands are
+// tags themselves:
+//
+//	team:payments && !deprecated
+package query
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/kis9a/gsrf"
+)
+
+// Filter reports whether a symbol satisfies a compiled expression.
+type Filter func(*gsrf.Symbol) bool
+
+// Compile parses expr into a Filter.
+func Compile(expr string) (Filter, error) {
+	p := &parser{src: expr}
+	p.next()
+	f, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if p.tok.kind != tokEOF {
+		return nil, p.errorf("unexpected %s", p.tok)
+	}
+	return f, nil
+}
+
+// CompileTags parses a tag expression into a Filter. Each operand is a tag,
+// bare or quoted, which holds if the symbol carries it; an operand with "*"
+// or "?" holds if any tag matches it as a glob, as in "team:*". Operands
+// combine with &&, || and !, and group with parentheses.
+func CompileTags(expr string) (Filter, error) {
+	p := &parser{src: expr, tags: true}
+	p.next()
+	f, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if p.tok.kind != tokEOF {
+		return nil, p.errorf("unexpected %s", p.tok)
+	}
+	return f, nil
+}
+
+// MustCompile is like Compile but panics if the expression is invalid.
+func MustCompile(expr string) Filter {
+	f, err := Compile(expr)
+	if err != nil {
+		panic(err)
+	}
+	return f
+}
+
+// stringFields return the values of string fields; meta.via has several.
+var stringFields = map[string]func(*gsrf.Symbol) []string{
+	"symbol": func(s *gsrf.Symbol) []string { return []string{s.String()} },
+	"pkg":    func(s *gsrf.Symbol) []string { return []string{s.PackagePath} },
+	"name":   func(s *gsrf.Symbol) []string { return []string{s.Name} },
+	"receiver": func(s *gsrf.Symbol) []string {
+		if s.Receiver == nil {
+			return []string{""}
+		}
+		if s.Receiver.IsPointer {
+			return []string{"*" + s.Receiver.TypeName}
+		}
+		return []string{s.Receiver.TypeName}
+	},
+	"context":    func(s *gsrf.Symbol) []string { return []string{s.Context} },
+	"meta.via":   func(s *gsrf.Symbol) []string { return s.Metadata.Via },
+	"meta.alias": func(s *gsrf.Symbol) []string { return []string{s.Metadata.Alias} },
+	"meta.pos":   func(s *gsrf.Symbol) []string { return []string{s.Metadata.Position} },
+	"tags":       (*gsrf.Symbol).Tags,
+}
+
+var boolFields = map[string]Filter{
+	"method":    func(s *gsrf.Symbol) bool { return s.Receiver != nil },
+	"anonymous": func(s *gsrf.Symbol) bool { return s.IsAnonymous },
+	"init":      func(s *gsrf.Symbol) bool { return s.IsInit },
+	"generic":   func(s *gsrf.Symbol) bool { return len(s.TypeArgs) > 0 || len(s.TypeParams) > 0 },
+	"exported":  (*gsrf.Symbol).IsExported,
+	"stdlib":    (*gsrf.Symbol).IsStdlib,
+}
+
+// stringField returns the accessor of a string field, including custom
+// metadata keys.
+func stringField(name string) (func(*gsrf.Symbol) []string, bool) {
+	if f, ok := stringFields[name]; ok {
+		return f, true
+	}
+	if key, ok := strings.CutPrefix(name, "meta."); ok && key != "" {
+		return func(s *gsrf.Symbol) []string { return []string{s.Metadata.Custom[key]} }, true
+	}
+	return nil, false
+}
+
+type tokKind int
+
+const (
+	tokEOF tokKind = iota
+	tokIdent
+	tokString
+	tokOp // == != =~ !~ && || ! ( )
+)
+
+type tok struct {
+	kind tokKind
+	text string // Operator or identifier; unquoted value for strings
+	pos  int
+}
+
+func (t tok) String() string {
+	switch t.kind {
+	case tokEOF:
+		return "end of expression"
+	case tokString:
+		return strconv.Quote(t.text)
+	}
+	return fmt.Sprintf("%q", t.text)
+}
+
+type parser struct {
+	src  string
+	tags bool // Tag expression: operands are tags
+	pos  int
+	tok  tok
+	err  error
+}
+
+func (p *parser) errorf(format string, args ...any) error {
+	return fmt.Errorf("query: offset %d: %s", p.tok.pos, fmt.Sprintf(format, args...))
+}
+
+// next scans the next token into p.tok, recording scan errors in p.err.
+func (p *parser) next() {
+	for p.pos < len(p.src) {
+		r, size := utf8.DecodeRuneInString(p.src[p.pos:])
+		if !unicode.IsSpace(r) {
+			break
+		}
+		p.pos += size
+	}
+	start := p.pos
+	rest := p.src[start:]
+	switch {
+	case rest == "":
+		p.tok = tok{kind: tokEOF, pos: start}
+	case rest[0] == '"':
+		quoted, err := strconv.QuotedPrefix(rest)
+		if err != nil {
+			p.tok = tok{kind: tokOp, text: `"`, pos: start}
+			if p.err == nil {
+				p.err = fmt.Errorf("query: offset %d: unterminated string", start)
+			}
+			p.pos = len(p.src)
+			return
+		}
+		value, _ := strconv.Unquote(quoted)
+		p.tok = tok{kind: tokString, text: value, pos: start}
+		p.pos += len(quoted)
+	default:
+		for _, op := range []string{"==", "!=", "=~", "!~", "&&", "||", "!", "(", ")"} {
+			if strings.HasPrefix(rest, op) {
+				p.tok = tok{kind: tokOp, text: op, pos: start}
+				p.pos += len(op)
+				return
+			}
+		}
+		n := 0
+		for n < len(rest) {
+			r, size := utf8.DecodeRuneInString(rest[n:])
+			if r != '_' && r != '.' && r != '-' && !unicode.IsLetter(r) && !unicode.IsDigit(r) &&
+				!(p.tags && strings.ContainsRune(":/*?", r)) {
+				break
+			}
+			n += size
+		}
+		if n == 0 {
+			_, n = utf8.DecodeRuneInString(rest)
+		}
+		p.tok = tok{kind: tokIdent, text: rest[:n], pos: start}
+		p.pos += n
+	}
+}
+
+func (p *parser) isOp(op string) bool {
+	return p.tok.kind == tokOp && p.tok.text == op
+}
+
+func (p *parser) parseOr() (Filter, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.isOp("||") {
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		l := left
+		left = func(s *gsrf.Symbol) bool { return l(s) || right(s) }
+	}
+	return left, nil
+}
+
+func (p *parser) parseAnd() (Filter, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.isOp("&&") {
+		p.next()
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		l := left
+		left = func(s *gsrf.Symbol) bool { return l(s) && right(s) }
+	}
+	return left, nil
+}
+
+func (p *parser) parseUnary() (Filter, error) {
+	if p.err != nil {
+		return nil, p.err
+	}
+	if p.isOp("!") {
+		p.next()
+		f, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return func(s *gsrf.Symbol) bool { return !f(s) }, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *parser) parsePrimary() (Filter, error) {
+	if p.isOp("(") {
+		p.next()
+		f, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if !p.isOp(")") {
+			return nil, p.errorf("expected ) but found %s", p.tok)
+		}
+		p.next()
+		return f, nil
+	}
+	if p.tags {
+		return p.parseTag()
+	}
+	if p.tok.kind != tokIdent {
+		return nil, p.errorf("expected a field but found %s", p.tok)
+	}
+
+	name := p.tok.text
+	if name == "has" {
+		p.next()
+		if !p.isOp("(") {
+			return nil, p.errorf("expected ( after has")
+		}
+		p.next()
+		field, ok := stringField(p.tok.text)
+		if p.tok.kind != tokIdent || !ok {
+			return nil, p.errorf("has: unknown string field %s", p.tok)
+		}
+		p.next()
+		if !p.isOp(")") {
+			return nil, p.errorf("expected ) but found %s", p.tok)
+		}
+		p.next()
+		return func(s *gsrf.Symbol) bool {
+			for _, v := range field(s) {
+				if v != "" {
+					return true
+				}
+			}
+			return false
+		}, nil
+	}
+	if f, ok := boolFields[name]; ok {
+		p.next()
+		return f, nil
+	}
+
+	field, ok := stringField(name)
+	if !ok {
+		return nil, p.errorf("unknown field %q", name)
+	}
+	p.next()
+	op := p.tok.text
+	if p.tok.kind != tokOp || (op != "==" && op != "!=" && op != "=~" && op != "!~") {
+		return nil, p.errorf("expected ==, !=, =~ or !~ after %s but found %s", name, p.tok)
+	}
+	p.next()
+	if p.err != nil {
+		return nil, p.err
+	}
+	if p.tok.kind != tokString {
+		return nil, p.errorf("expected a quoted string but found %s", p.tok)
+	}
+	value := p.tok.text
+	p.next()
+
+	match := func(v string) bool { return v == value }
+	if op == "=~" || op == "!~" {
+		re := globRegexp(value)
+		match = re.MatchString
+	}
+	negate := op[0] == '!'
+	return func(s *gsrf.Symbol) bool {
+		for _, v := range field(s) {
+			if match(v) {
+				return !negate
+			}
+		}
+		return negate
+	}, nil
+}
+
+// parseTag parses an operand of a tag expression.
+func (p *parser) parseTag() (Filter, error) {
+	if p.err != nil {
+		return nil, p.err
+	}
+	if p.tok.kind != tokIdent && p.tok.kind != tokString {
+		return nil, p.errorf("expected a tag but found %s", p.tok)
+	}
+	tag := p.tok.text
+	p.next()
+	if !strings.ContainsAny(tag, "*?") {
+		if err := gsrf.ValidateTag(tag); err != nil {
+			return nil, p.errorf("%v", err)
+		}
+		return func(s *gsrf.Symbol) bool { return s.HasTag(tag) }, nil
+	}
+	re := globRegexp(tag)
+	return func(s *gsrf.Symbol) bool {
+		for _, t := range s.Tags() {
+			if re.MatchString(t) {
+				return true
+			}
+		}
+		return false
+	}, nil
+}
+
+// globRegexp compiles a glob in which "*" matches any run of characters and
+// "?" any one character.
+func globRegexp(glob string) *regexp.Regexp {
+	var b strings.Builder
+	b.WriteString(`^(?s:`)
+	for _, r := range glob {
+		switch r {
+		case '*':
+			b.WriteString(`.*`)
+		case '?':
+			b.WriteString(`.`)
+		default:
+			b.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+	b.WriteString(`)$`)
+	return regexp.MustCompile(b.String())
+}
@@ -0,0 +1,168 @@
+package query
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/kis9a/gsrf"
+)
+
+func TestCompile(t *testing.T) {
+	inputs := []string{
+		"github.com/org/api.(*Server).Close{via:Conn}",
+		"github.com/org/api.(*Server).Start",
+		"github.com/org/api.(Server).String@linux",
+		"github.com/other.(*Server).Close{via:Conn}",
+		"net/http.ListenAndServe",
+		"github.com/org/api.handle·lit1{owner:core}",
+		"slices.Sort[int]",
+	}
+
+	tests := []struct {
+		expr string
+		want []string
+	}{
+		{
+			`pkg =~ "github.com/org/*" && receiver == "*Server" && has(meta.via)`,
+			[]string{"github.com/org/api.(*Server).Close{via:Conn}"},
+		},
+		{
+			`receiver == "Server" || context == "linux"`,
+			[]string{"github.com/org/api.(Server).String@linux"},
+		},
+		{
+			`stdlib && !generic`,
+			[]string{"net/http.ListenAndServe"},
+		},
+		{
+			`anonymous && meta.owner == "core"`,
+			[]string{"github.com/org/api.handle·lit1{owner:core}"},
+		},
+		{
+			`method && !(name =~ "S*") && meta.via != "Conn"`,
+			nil,
+		},
+		{
+			`generic && symbol == "slices.Sort[int]"`,
+			[]string{"slices.Sort[int]"},
+		},
+		{
+			`exported && !method && pkg !~ "net/*"`,
+			[]string{"slices.Sort[int]"},
+		},
+		{
+			`name =~ "?tart"`,
+			[]string{"github.com/org/api.(*Server).Start"},
+		},
+	}
+	for _, tt := range tests {
+		f, err := Compile(tt.expr)
+		if err != nil {
+			t.Errorf("Compile(%q) error = %v", tt.expr, err)
+			continue
+		}
+		var got []string
+		for _, s := range inputs {
+			if f(gsrf.MustParse(s)) {
+				got = append(got, s)
+			}
+		}
+		if strings.Join(got, " ") != strings.Join(tt.want, " ") {
+			t.Errorf("Compile(%q) matched %q, want %q", tt.expr, got, tt.want)
+		}
+	}
+}
+
+func TestCompile_Errors(t *testing.T) {
+	tests := []struct {
+		expr string
+		want string
+	}{
+		{``, "expected a field"},
+		{`owner == "x"`, `unknown field "owner"`},
+		{`pkg`, "expected ==, !=, =~ or !~ after pkg"},
+		{`pkg == core`, "expected a quoted string"},
+		{`pkg == "core`, "unterminated string"},
+		{`(method`, "expected )"},
+		{`has(method)`, "unknown string field"},
+		{`method method`, `unexpected "method"`},
+		{`method && `, "expected a field"},
+	}
+	for _, tt := range tests {
+		_, err := Compile(tt.expr)
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("Compile(%q) error = %v, want %q", tt.expr, err, tt.want)
+		}
+	}
+}
+
+func TestCompileTags(t *testing.T) {
+	inputs := []string{
+		"github.com/org/payments.Charge{tags:team:payments}",
+		"github.com/org/payments.Refund{tags:deprecated+team:payments}",
+		"github.com/org/ledger.Post{tags:team:ledger}",
+		"net/http.ListenAndServe",
+	}
+
+	tests := []struct {
+		expr string
+		want []string
+	}{
+		{
+			`team:payments && !deprecated`,
+			[]string{"github.com/org/payments.Charge{tags:team:payments}"},
+		},
+		{
+			`deprecated || "team:ledger"`,
+			[]string{"github.com/org/payments.Refund{tags:deprecated+team:payments}", "github.com/org/ledger.Post{tags:team:ledger}"},
+		},
+		{
+			`!team:*`,
+			[]string{"net/http.ListenAndServe"},
+		},
+		{
+			`team:p* && !(deprecated || team:ledger)`,
+			[]string{"github.com/org/payments.Charge{tags:team:payments}"},
+		},
+	}
+	for _, tt := range tests {
+		f, err := CompileTags(tt.expr)
+		if err != nil {
+			t.Errorf("CompileTags(%q) error = %v", tt.expr, err)
+			continue
+		}
+		var got []string
+		for _, s := range inputs {
+			if f(gsrf.MustParse(s)) {
+				got = append(got, s)
+			}
+		}
+		if strings.Join(got, " ") != strings.Join(tt.want, " ") {
+			t.Errorf("CompileTags(%q) matched %q, want %q", tt.expr, got, tt.want)
+		}
+	}
+
+	f := MustCompile(`tags =~ "team:*" && tags != "deprecated"`)
+	if !f(gsrf.MustParse(inputs[0])) || f(gsrf.MustParse(inputs[1])) {
+		t.Errorf("tags field matched wrongly")
+	}
+}
+
+func TestCompileTags_Errors(t *testing.T) {
+	tests := []struct {
+		expr string
+		want string
+	}{
+		{``, "expected a tag"},
+		{`team:`, "empty name or namespace"},
+		{`a && "b c"`, "invalid character"},
+		{`a b`, `unexpected "b"`},
+		{`(a || b`, "expected )"},
+	}
+	for _, tt := range tests {
+		_, err := CompileTags(tt.expr)
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("CompileTags(%q) error = %v, want %q", tt.expr, err, tt.want)
+		}
+	}
+}
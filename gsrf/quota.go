@@ -0,0 +1,160 @@
+package gsrf
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"unicode/utf8"
+)
+
+// MetadataTruncated is the custom metadata key under which
+// ParseOptions.TruncateMetadata records the digest of the dropped metadata,
+// e.g. {truncated:sha256:0123456789abcdef}. The recorded entry is not
+// counted against the quotas; an entry under this key in the input counts
+// like any other, and truncation always drops it into the digest.
+const MetadataTruncated = "truncated"
+
+// MetadataLimit names a metadata quota of ParseOptions.
+type MetadataLimit string
+
+const (
+	MetadataLimitKeys  MetadataLimit = "keys"  // ParseOptions.MaxMetadataKeys
+	MetadataLimitValue MetadataLimit = "value" // ParseOptions.MaxMetadataValue
+	MetadataLimitSize  MetadataLimit = "size"  // ParseOptions.MaxMetadataSize
+)
+
+// MetadataLimitError reports a symbol rejected by a metadata quota.
+type MetadataLimitError struct {
+	Limit MetadataLimit // Quota exceeded
+	Key   string        // Entry whose value is too long, for MetadataLimitValue
+	Size  int           // Number of entries, or length in bytes
+	Max   int           // Configured limit
+}
+
+func (e *MetadataLimitError) Error() string {
+	switch e.Limit {
+	case MetadataLimitKeys:
+		return fmt.Sprintf("invalid GSRF symbol: %d metadata entries exceed limit of %d", e.Size, e.Max)
+	case MetadataLimitValue:
+		return fmt.Sprintf("invalid GSRF symbol: metadata value of %q is %d bytes, exceeding limit of %d", e.Key, e.Size, e.Max)
+	}
+	return fmt.Sprintf("invalid GSRF symbol: metadata size %d exceeds limit of %d bytes", e.Size, e.Max)
+}
+
+// Size returns the length in bytes of the metadata keys and values, via,
+// alias and pos included, the measure ParseOptions.MaxMetadataSize limits.
+func (m Metadata) Size() int {
+	n := 0
+	for _, e := range m.entries() {
+		n += len(e.key) + len(e.value)
+	}
+	return n
+}
+
+// entries returns the metadata entries in spec order: via entries, alias,
+// pos, then custom entries by key.
+func (m Metadata) entries() []metaEntry {
+	var entries []metaEntry
+	for _, via := range m.Via {
+		entries = append(entries, metaEntry{"via", via})
+	}
+	if m.Alias != "" {
+		entries = append(entries, metaEntry{"alias", m.Alias})
+	}
+	if m.Position != "" {
+		entries = append(entries, metaEntry{"pos", m.Position})
+	}
+	for _, k := range sortedKeys(m.Custom) {
+		entries = append(entries, metaEntry{k, m.Custom[k]})
+	}
+	return entries
+}
+
+// limitMetadata enforces the metadata quotas of opts on sym.
+func limitMetadata(sym *Symbol, opts ParseOptions) error {
+	if opts.MaxMetadataKeys <= 0 && opts.MaxMetadataValue <= 0 && opts.MaxMetadataSize <= 0 {
+		return nil
+	}
+	if opts.TruncateMetadata {
+		truncateMetadata(&sym.Metadata, opts)
+		return nil
+	}
+
+	if n := len(sym.Metadata.Custom); opts.MaxMetadataKeys > 0 && n > opts.MaxMetadataKeys {
+		return &MetadataLimitError{Limit: MetadataLimitKeys, Size: n, Max: opts.MaxMetadataKeys}
+	}
+	size := 0
+	for _, e := range sym.Metadata.entries() {
+		if opts.MaxMetadataValue > 0 && len(e.value) > opts.MaxMetadataValue {
+			return &MetadataLimitError{Limit: MetadataLimitValue, Key: e.key, Size: len(e.value), Max: opts.MaxMetadataValue}
+		}
+		size += len(e.key) + len(e.value)
+	}
+	if opts.MaxMetadataSize > 0 && size > opts.MaxMetadataSize {
+		return &MetadataLimitError{Limit: MetadataLimitSize, Size: size, Max: opts.MaxMetadataSize}
+	}
+	return nil
+}
+
+// truncateMetadata brings the metadata within the quotas of opts. Entries
+// are kept in spec order, custom entries by key: values are cut to the
+// value limit, and entries past the key or size limit are dropped. An input
+// MetadataTruncated entry is always dropped. If anything was cut, the digest
+// of the dropped content is recorded under MetadataTruncated.
+func truncateMetadata(m *Metadata, opts ParseOptions) {
+	h := sha256.New()
+	dropped := false
+	kept := Metadata{}
+	custom := map[string]string{}
+	size := 0
+	for _, e := range m.entries() {
+		k, v := e.key, e.value
+		if k == MetadataTruncated {
+			fmt.Fprintf(h, "%s:%s\n", k, v)
+			dropped = true
+			continue
+		}
+		if opts.MaxMetadataValue > 0 && len(v) > opts.MaxMetadataValue {
+			cut := opts.MaxMetadataValue
+			for cut > 0 && !utf8.RuneStart(v[cut]) {
+				cut--
+			}
+			fmt.Fprintf(h, "%s:%s\n", k, v[cut:])
+			v, dropped = v[:cut], true
+		}
+		isCustom := k != "via" && k != "alias" && k != "pos"
+		if isCustom && opts.MaxMetadataKeys > 0 && len(custom) >= opts.MaxMetadataKeys ||
+			opts.MaxMetadataSize > 0 && size+len(k)+len(v) > opts.MaxMetadataSize {
+			fmt.Fprintf(h, "%s:%s\n", k, v)
+			dropped = true
+			continue
+		}
+		size += len(k) + len(v)
+		switch {
+		case k == "via":
+			kept.Via = append(kept.Via, v)
+		case k == "alias":
+			kept.Alias = v
+		case k == "pos":
+			kept.Position = v
+		default:
+			custom[k] = v
+		}
+	}
+	if !dropped {
+		return
+	}
+	custom[MetadataTruncated] = digestAlgorithm + hex.EncodeToString(h.Sum(nil))[:digestHexLen]
+	kept.Custom = custom
+	*m = kept
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
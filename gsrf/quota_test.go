@@ -0,0 +1,143 @@
+package gsrf
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestParseWith_MetadataLimits(t *testing.T) {
+	input := "pkg.Fn{a:1,b:22,c:333}"
+	tests := []struct {
+		name string
+		opts ParseOptions
+		want MetadataLimitError
+	}{
+		{"keys", ParseOptions{MaxMetadataKeys: 2}, MetadataLimitError{Limit: MetadataLimitKeys, Size: 3, Max: 2}},
+		{"value", ParseOptions{MaxMetadataValue: 2}, MetadataLimitError{Limit: MetadataLimitValue, Key: "c", Size: 3, Max: 2}},
+		{"size", ParseOptions{MaxMetadataSize: 8}, MetadataLimitError{Limit: MetadataLimitSize, Size: 9, Max: 8}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseWith(input, tt.opts)
+			var limitErr *MetadataLimitError
+			if !errors.As(err, &limitErr) {
+				t.Fatalf("ParseWith() error = %v, want *MetadataLimitError", err)
+			}
+			if *limitErr != tt.want {
+				t.Errorf("MetadataLimitError = %+v, want %+v", *limitErr, tt.want)
+			}
+		})
+	}
+
+	if _, err := ParseWith(input, ParseOptions{MaxMetadataKeys: 3, MaxMetadataValue: 3, MaxMetadataSize: 9}); err != nil {
+		t.Errorf("ParseWith() within limits error = %v", err)
+	}
+	// Via, alias and pos count toward the value and size limits, but not
+	// toward the keys limit.
+	big := strings.Repeat("x", 100)
+	for _, input := range []string{"pkg.F{pos:" + big + "}", "pkg.(*T).M{via:" + big + "}", "pkg.F{alias:" + big + "}"} {
+		var limitErr *MetadataLimitError
+		if _, err := ParseWith(input, ParseOptions{MaxMetadataValue: 16}); !errors.As(err, &limitErr) || limitErr.Limit != MetadataLimitValue {
+			t.Errorf("ParseWith(%.20q) with value limit error = %v", input, err)
+		}
+		if _, err := ParseWith(input, ParseOptions{MaxMetadataSize: 64}); !errors.As(err, &limitErr) || limitErr.Limit != MetadataLimitSize {
+			t.Errorf("ParseWith(%.20q) with size limit error = %v", input, err)
+		}
+	}
+	if _, err := ParseWith("pkg.(*T).M{via:Embedded,pos:f.go:1:2}", ParseOptions{MaxMetadataKeys: 1}); err != nil {
+		t.Errorf("ParseWith() without custom metadata error = %v", err)
+	}
+	// An input truncated entry is not exempt.
+	if _, err := ParseWith("pkg.F{truncated:"+big+"}", ParseOptions{MaxMetadataValue: 16}); err == nil {
+		t.Error("ParseWith() accepted an oversized truncated entry")
+	}
+}
+
+func TestParseUntrusted_MetadataLimits(t *testing.T) {
+	var limitErr *MetadataLimitError
+	if _, err := ParseUntrusted("pkg.F{pos:" + strings.Repeat("x", DefaultMaxMetadataValue+1) + "}"); !errors.As(err, &limitErr) {
+		t.Errorf("ParseUntrusted() error = %v, want *MetadataLimitError", err)
+	}
+	many := make([]string, DefaultMaxMetadataKeys+1)
+	for i := range many {
+		many[i] = fmt.Sprintf("k%d:v", i)
+	}
+	if _, err := ParseUntrusted("pkg.F{" + strings.Join(many, ",") + "}"); !errors.As(err, &limitErr) {
+		t.Errorf("ParseUntrusted() error = %v, want *MetadataLimitError", err)
+	}
+}
+
+func TestParseWith_TruncateMetadata(t *testing.T) {
+	opts := ParseOptions{MaxMetadataKeys: 2, MaxMetadataValue: 2, TruncateMetadata: true}
+	sym, err := ParseWith("pkg.Fn{a:1,b:22,c:333}", opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := sym.Metadata.Custom; len(got) != 3 || got["a"] != "1" || got["b"] != "22" {
+		t.Fatalf("Custom = %v, want a:1 b:22 and the digest", got)
+	}
+	digest := sym.Metadata.Custom[MetadataTruncated]
+	if !strings.HasPrefix(digest, "sha256:") {
+		t.Fatalf("digest = %q", digest)
+	}
+
+	// The digest depends on the dropped content.
+	other, err := ParseWith("pkg.Fn{a:1,b:22,c:334}", opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if other.Metadata.Custom[MetadataTruncated] == digest {
+		t.Error("different dropped content has the same digest")
+	}
+
+	// Nothing is recorded when nothing is dropped.
+	sym, err = ParseWith("pkg.Fn{a:1}", opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := sym.Metadata.Custom[MetadataTruncated]; ok {
+		t.Errorf("Custom = %v, want no digest", sym.Metadata.Custom)
+	}
+}
+
+func TestParseWith_TruncateMetadataValue(t *testing.T) {
+	sym, err := ParseWith("pkg.Fn{k:héllo,z:1}", ParseOptions{MaxMetadataValue: 2, MaxMetadataSize: 3, TruncateMetadata: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The value is cut at a rune boundary; z:1 no longer fits the size.
+	if got := sym.Metadata.Custom; got["k"] != "h" || len(got) != 2 {
+		t.Errorf("Custom = %v, want k:h and the digest", got)
+	}
+}
+
+func TestParseWith_TruncateMetadataFields(t *testing.T) {
+	opts := ParseOptions{MaxMetadataValue: 8, MaxMetadataSize: 20, TruncateMetadata: true}
+	sym, err := ParseWith("pkg.(*T).M{via:Embedded,via:Another,pos:dir/file.go:10:2}", opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m := sym.Metadata; len(m.Via) != 1 || m.Via[0] != "Embedded" || m.Position != "" || m.Custom[MetadataTruncated] == "" {
+		t.Errorf("Metadata = %+v, want the first via entry and the digest", m)
+	}
+
+	// An input truncated entry is dropped into the digest even when
+	// everything else fits.
+	sym, err = ParseWith("pkg.F{a:1,truncated:"+strings.Repeat("x", 100000)+"}", ParseOptions{MaxMetadataKeys: 2, TruncateMetadata: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := sym.Metadata.Custom; got["a"] != "1" || !strings.HasPrefix(got[MetadataTruncated], "sha256:") || len(got[MetadataTruncated]) > 32 {
+		t.Errorf("Custom = %.80v, want a:1 and a recomputed digest", got)
+	}
+}
+
+func TestDecoder_MetadataLimits(t *testing.T) {
+	d := NewDecoderWith(strings.NewReader(`{"package":"pkg","name":"F","metadata":{"custom":{"a":"1","b":"2"}}}`+"\n"), ParseOptions{MaxMetadataKeys: 1})
+	var limitErr *MetadataLimitError
+	if _, err := d.Next(); !errors.As(err, &limitErr) {
+		t.Errorf("Next() error = %v, want *MetadataLimitError", err)
+	}
+}
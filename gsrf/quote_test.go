@@ -0,0 +1,75 @@
+package gsrf
+
+import (
+	"testing"
+)
+
+func TestParseQuotedPackage(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		wantPkg  string
+		wantName string
+		wantErr  bool
+	}{
+		{name: "dotted path", input: `"gopkg.in/yaml.v2".Unmarshal`, wantPkg: "gopkg.in/yaml.v2", wantName: "Unmarshal"},
+		{name: "tilde and plus", input: `"example.com/~user/c++".Run`, wantPkg: "example.com/~user/c++", wantName: "Run"},
+		{name: "method", input: `"gopkg.in/yaml.v2".(*Decoder).Decode@linux`, wantPkg: "gopkg.in/yaml.v2", wantName: "Decode"},
+		{name: "reserved characters", input: `"example.com/a@b(c)".F`, wantPkg: "example.com/a@b(c)", wantName: "F"},
+		{name: "unterminated", input: `"gopkg.in/yaml.v2.Unmarshal`, wantErr: true},
+		{name: "missing symbol", input: `"gopkg.in/yaml.v2"`, wantErr: true},
+		{name: "extra separator", input: `"gopkg.in/yaml".v2.Unmarshal`, wantErr: true},
+		{name: "empty path", input: `"".F`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Parse(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Parse() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if got.PackagePath != tt.wantPkg || got.Name != tt.wantName {
+				t.Errorf("Parse() = %q . %q, want %q . %q", got.PackagePath, got.Name, tt.wantPkg, tt.wantName)
+			}
+		})
+	}
+}
+
+func TestFormatQuotedPackage(t *testing.T) {
+	tests := []struct {
+		pkg  string
+		want string
+	}{
+		{pkg: "gopkg.in/yaml.v2", want: "gopkg.in/yaml.v2.F"},
+		{pkg: "example.com/~user/c++", want: "example.com/~user/c++.F"},
+		{pkg: "example.com/a@b", want: `"example.com/a@b".F`},
+		{pkg: "example.com/has space", want: `"example.com/has space".F`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pkg, func(t *testing.T) {
+			sym := &Symbol{PackagePath: tt.pkg, Name: "F"}
+			got := sym.Format()
+			if got != tt.want {
+				t.Errorf("Format() = %q, want %q", got, tt.want)
+			}
+			back, err := Parse(got)
+			if err != nil {
+				t.Fatalf("Parse(%q) error = %v", got, err)
+			}
+			if back.PackagePath != tt.pkg {
+				t.Errorf("round trip PackagePath = %q, want %q", back.PackagePath, tt.pkg)
+			}
+		})
+	}
+
+	t.Run("anonymous parent", func(t *testing.T) {
+		sym := MustParse(`"example.com/a@b".main·lit2`)
+		if sym.AnonParent != "example.com/a@b.main" {
+			t.Errorf("AnonParent = %q", sym.AnonParent)
+		}
+	})
+}
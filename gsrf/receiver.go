@@ -0,0 +1,67 @@
+package gsrf
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ParseReceiver parses a method receiver on its own, e.g. "(*List[T])".
+// The surrounding parentheses are optional.
+func ParseReceiver(input string) (*Receiver, error) {
+	text := strings.TrimSpace(input)
+	if strings.HasPrefix(text, "(") {
+		if !strings.HasSuffix(text, ")") {
+			return nil, fmt.Errorf("invalid GSRF receiver: unbalanced parentheses in %q", input)
+		}
+		text = strings.TrimSpace(text[1 : len(text)-1])
+	}
+	r := splitReceiver(text)
+
+	if problem := identifierSyntaxProblem(r.TypeName); problem != "" {
+		return nil, fmt.Errorf("invalid GSRF receiver: type name %q: %s", r.TypeName, problem)
+	}
+	if idx := strings.Index(text, "["); idx >= 0 {
+		if !strings.HasSuffix(text, "]") || len(r.TypeArgs) == 0 {
+			return nil, fmt.Errorf("invalid GSRF receiver: malformed type arguments in %q", input)
+		}
+		for _, arg := range r.TypeArgs {
+			if problem := typeExprProblem(arg); problem != "" {
+				return nil, fmt.Errorf("invalid GSRF receiver: type argument %q: %s", arg, problem)
+			}
+		}
+	}
+	return r, nil
+}
+
+// splitReceiver splits receiver text without parentheses, such as
+// "*List[T]", into its pointer marker, type name and type arguments.
+func splitReceiver(text string) *Receiver {
+	r := &Receiver{TypeName: text}
+	if strings.HasPrefix(text, "*") {
+		r.IsPointer = true
+		r.TypeName = strings.TrimSpace(text[1:])
+	}
+	if idx := strings.Index(r.TypeName, "["); idx > 0 {
+		args := r.TypeName[idx:]
+		r.TypeName = r.TypeName[:idx]
+		if end := strings.LastIndex(args, "]"); end > 0 {
+			r.TypeArgs = parseTypeArgs(args[1:end])
+		}
+	}
+	return r
+}
+
+// Format formats the receiver as it appears in a symbol, e.g. "(*List[T])".
+func (r *Receiver) Format() string {
+	var b strings.Builder
+	b.WriteByte('(')
+	if r.IsPointer {
+		b.WriteByte('*')
+	}
+	b.WriteString(r.TypeName)
+	if len(r.TypeArgs) > 0 {
+		b.WriteString("[" + strings.Join(r.TypeArgs, ", ") + "]")
+	}
+	b.WriteByte(')')
+	return b.String()
+}
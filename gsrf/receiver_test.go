@@ -0,0 +1,57 @@
+package gsrf
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseReceiver(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    *Receiver
+		format  string
+		wantErr bool
+	}{
+		{input: "(*List[T])", want: &Receiver{TypeName: "List", IsPointer: true, TypeArgs: []string{"T"}}, format: "(*List[T])"},
+		{input: "(Server)", want: &Receiver{TypeName: "Server"}, format: "(Server)"},
+		{input: "*Cache[K,map[K][]V]", want: &Receiver{TypeName: "Cache", IsPointer: true, TypeArgs: []string{"K", "map[K][]V"}}, format: "(*Cache[K, map[K][]V])"},
+		{input: " ( T ) ", want: &Receiver{TypeName: "T"}, format: "(T)"},
+		{input: "", wantErr: true},
+		{input: "(*List[T]", wantErr: true},
+		{input: "(List[])", wantErr: true},
+		{input: "(List[T)", wantErr: true},
+		{input: "(pkg.T)", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseReceiver(tt.input)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("ParseReceiver(%q) = %+v, want error", tt.input, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseReceiver(%q) error = %v", tt.input, err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseReceiver(%q) = %+v, want %+v", tt.input, got, tt.want)
+			}
+			if f := got.Format(); f != tt.format {
+				t.Errorf("Format() = %q, want %q", f, tt.format)
+			}
+		})
+	}
+}
+
+func TestReceiverFormat_MatchesParse(t *testing.T) {
+	sym := MustParse("pkg.(*Tree[K, V]).Insert")
+	if got := sym.Receiver.Format(); got != "(*Tree[K, V])" {
+		t.Errorf("Format() = %q", got)
+	}
+	r, err := ParseReceiver(sym.Receiver.Format())
+	if err != nil || !reflect.DeepEqual(r, sym.Receiver) {
+		t.Errorf("ParseReceiver(Format()) = %+v, %v, want %+v", r, err, sym.Receiver)
+	}
+}
@@ -0,0 +1,295 @@
+package gsrf
+
+import "strings"
+
+// MatchReason describes which reconciliation stage paired two symbols.
+type MatchReason string
+
+const (
+	MatchExact     MatchReason = "exact"      // Identical GSRF strings
+	MatchCanonical MatchReason = "canonical"  // Equal after dropping context, metadata and vendor prefixes
+	MatchCaseFold  MatchReason = "case-fold"  // Canonically equal ignoring case (ReconcileOptions.FoldCase)
+	MatchAnonIndex MatchReason = "anon-index" // Equal ignoring anonymous function indices
+	MatchShape     MatchReason = "shape"      // Equal with type arguments and receiver pointerness collapsed
+	MatchFuzzy     MatchReason = "fuzzy"      // Closest name within the edit distance threshold
+)
+
+// Unmatched reasons reported by Reconcile.
+const (
+	UnmatchedNoPackage = "package not present in other corpus"
+	UnmatchedNoSymbol  = "no matching symbol in package"
+)
+
+// ReconcileMatch pairs a symbol from the left corpus with one from the right.
+type ReconcileMatch struct {
+	Left     *Symbol
+	Right    *Symbol
+	Reason   MatchReason
+	Distance int // Edit distance for fuzzy matches, 0 otherwise
+}
+
+// Unmatched is a symbol for which no counterpart was found.
+type Unmatched struct {
+	Symbol *Symbol
+	Reason string
+}
+
+// ReconcileReport is the result of matching two symbol corpora.
+type ReconcileReport struct {
+	Matched        []ReconcileMatch
+	UnmatchedLeft  []Unmatched
+	UnmatchedRight []Unmatched
+	CaseCollisions []CaseCollision // Only reported with ReconcileOptions.FoldCase
+}
+
+// ReconcileOptions controls optional reconciliation stages.
+type ReconcileOptions struct {
+	// FoldCase adds a stage matching canonical forms case-insensitively, for
+	// corpora from tools that lowercase symbols. Go identifiers differing
+	// only in case are distinct (Foo is exported, foo is not), so folded
+	// matches among them are reported in CaseCollisions.
+	FoldCase bool
+}
+
+// CaseCollision lists symbols of one corpus that differ only in case. A
+// case-folded match against any of them may have picked the wrong one.
+type CaseCollision struct {
+	Symbols []*Symbol
+}
+
+// Reconcile matches symbols across two corpora that may have been produced
+// by different tools. Stages run from strictest to loosest; each symbol is
+// matched at most once, in input order.
+func Reconcile(left, right []*Symbol) *ReconcileReport {
+	return ReconcileWith(left, right, ReconcileOptions{})
+}
+
+// ReconcileWith is Reconcile with optional stages enabled by opts.
+func ReconcileWith(left, right []*Symbol, opts ReconcileOptions) *ReconcileReport {
+	report := &ReconcileReport{}
+	leftUsed := make([]bool, len(left))
+	rightUsed := make([]bool, len(right))
+
+	stages := []struct {
+		reason MatchReason
+		key    func(*Symbol) string
+	}{
+		{MatchExact, func(s *Symbol) string { return s.Format() }},
+		{MatchCanonical, canonicalKey},
+		{MatchCaseFold, foldedKey},
+		{MatchAnonIndex, anonInsensitiveKey},
+		{MatchShape, shapeKey},
+	}
+	if opts.FoldCase {
+		report.CaseCollisions = append(caseCollisions(left), caseCollisions(right)...)
+	}
+
+	for _, stage := range stages {
+		if stage.reason == MatchCaseFold && !opts.FoldCase {
+			continue
+		}
+		index := make(map[string][]int)
+		for j, sym := range right {
+			if !rightUsed[j] {
+				k := stage.key(sym)
+				index[k] = append(index[k], j)
+			}
+		}
+		for i, sym := range left {
+			if leftUsed[i] {
+				continue
+			}
+			k := stage.key(sym)
+			candidates := index[k]
+			if len(candidates) == 0 {
+				continue
+			}
+			j := candidates[0]
+			index[k] = candidates[1:]
+			leftUsed[i], rightUsed[j] = true, true
+			report.Matched = append(report.Matched, ReconcileMatch{Left: sym, Right: right[j], Reason: stage.reason})
+		}
+	}
+
+	// Fuzzy fallback: closest remaining symbol in the same package.
+	for i, sym := range left {
+		if leftUsed[i] {
+			continue
+		}
+		best, bestDist := -1, 0
+		key := shapeKey(sym)
+		for j, other := range right {
+			if rightUsed[j] || stripVendor(other.PackagePath) != stripVendor(sym.PackagePath) {
+				continue
+			}
+			d := levenshtein(key, shapeKey(other))
+			if d <= fuzzyThreshold(sym.Name) && (best == -1 || d < bestDist) {
+				best, bestDist = j, d
+			}
+		}
+		if best >= 0 {
+			leftUsed[i], rightUsed[best] = true, true
+			report.Matched = append(report.Matched, ReconcileMatch{Left: sym, Right: right[best], Reason: MatchFuzzy, Distance: bestDist})
+		}
+	}
+
+	report.UnmatchedLeft = unmatched(left, leftUsed, right)
+	report.UnmatchedRight = unmatched(right, rightUsed, left)
+	return report
+}
+
+// unmatched collects unused symbols with the reason no counterpart was found.
+func unmatched(syms []*Symbol, used []bool, other []*Symbol) []Unmatched {
+	packages := make(map[string]bool, len(other))
+	for _, o := range other {
+		packages[stripVendor(o.PackagePath)] = true
+	}
+
+	var result []Unmatched
+	for i, sym := range syms {
+		if used[i] {
+			continue
+		}
+		reason := UnmatchedNoSymbol
+		if !packages[stripVendor(sym.PackagePath)] {
+			reason = UnmatchedNoPackage
+		}
+		result = append(result, Unmatched{Symbol: sym, Reason: reason})
+	}
+	return result
+}
+
+// canonicalKey formats the normalized symbol without context and metadata.
+func canonicalKey(s *Symbol) string {
+	return s.Normalize().Format(WithOptions(FormatOptions{OmitContext: true, OmitMetadata: true}))
+}
+
+// foldedKey is canonicalKey in lower case.
+func foldedKey(s *Symbol) string {
+	return strings.ToLower(canonicalKey(s))
+}
+
+// caseCollisions groups symbols whose canonical forms differ only in case.
+func caseCollisions(syms []*Symbol) []CaseCollision {
+	var order []string
+	groups := make(map[string][]*Symbol)
+	forms := make(map[string]map[string]bool)
+	for _, sym := range syms {
+		k := foldedKey(sym)
+		if _, ok := groups[k]; !ok {
+			order = append(order, k)
+			forms[k] = make(map[string]bool)
+		}
+		groups[k] = append(groups[k], sym)
+		forms[k][canonicalKey(sym)] = true
+	}
+
+	var result []CaseCollision
+	for _, k := range order {
+		if len(forms[k]) > 1 {
+			result = append(result, CaseCollision{Symbols: groups[k]})
+		}
+	}
+	return result
+}
+
+// RepairCase restores the casing of symbols from a reference corpus, such
+// as the symbols of the source they were produced from. A symbol whose
+// case-folded canonical form matches exactly one reference form takes the
+// reference's package, receiver, name, and type arguments; its context and
+// metadata are kept. Symbols with several candidates are returned unchanged,
+// and listed in ambiguous unless one candidate matches with its case as is.
+// Symbols with no candidate are returned unchanged.
+func RepairCase(syms, reference []*Symbol) (repaired, ambiguous []*Symbol) {
+	candidates := make(map[string][]*Symbol)
+	seen := make(map[string]bool)
+	for _, ref := range reference {
+		k := canonicalKey(ref)
+		if seen[k] {
+			continue
+		}
+		seen[k] = true
+		folded := strings.ToLower(k)
+		candidates[folded] = append(candidates[folded], ref)
+	}
+
+	repaired = make([]*Symbol, len(syms))
+	for i, sym := range syms {
+		repaired[i] = sym
+		refs := candidates[foldedKey(sym)]
+		if len(refs) > 1 {
+			if seen[canonicalKey(sym)] {
+				continue // Already correctly cased
+			}
+			ambiguous = append(ambiguous, sym)
+			continue
+		}
+		if len(refs) == 1 {
+			fixed := refs[0].Clone()
+			fixed.Context = sym.Context
+			fixed.Metadata = sym.Clone().Metadata
+			repaired[i] = fixed
+		}
+	}
+	return repaired, ambiguous
+}
+
+// anonInsensitiveKey is canonicalKey with anonymous indices dropped.
+func anonInsensitiveKey(s *Symbol) string {
+	if !s.IsAnonymous {
+		return canonicalKey(s)
+	}
+	c := *s
+	c.AnonIndex = 0
+	return canonicalKey(&c)
+}
+
+// shapeKey collapses generic instantiations to [...] and ignores receiver
+// pointerness, which stack traces do not preserve.
+func shapeKey(s *Symbol) string {
+	c := *s
+	c.AnonIndex = 0
+	c.TypeParams = nil
+	if len(c.TypeArgs) > 0 || len(s.TypeParams) > 0 {
+		c.TypeArgs = []string{"..."}
+	}
+	if s.Receiver != nil {
+		r := *s.Receiver
+		r.IsPointer = false
+		if len(r.TypeArgs) > 0 {
+			r.TypeArgs = []string{"..."}
+		}
+		c.Receiver = &r
+	}
+	return canonicalKey(&c)
+}
+
+// fuzzyThreshold is the maximum edit distance accepted for a fuzzy match.
+func fuzzyThreshold(name string) int {
+	if n := len(name) / 4; n > 1 {
+		return n
+	}
+	return 1
+}
+
+// levenshtein computes the edit distance between two strings by rune.
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(rb)]
+}
@@ -0,0 +1,140 @@
+package gsrf
+
+import (
+	"testing"
+)
+
+func TestReconcile(t *testing.T) {
+	parseAll := func(inputs ...string) []*Symbol {
+		syms := make([]*Symbol, len(inputs))
+		for i, in := range inputs {
+			syms[i] = MustParse(in)
+		}
+		return syms
+	}
+
+	left := parseAll(
+		"fmt.Println",
+		"pkg.(*Server).Start@linux",
+		"main.main·lit2",
+		"pkg.Map[string, int]",
+		"pkg.(Cache).Get",
+		"pkg.ProcessData",
+		"pkg.OnlyLeft",
+		"other.Func",
+	)
+	right := parseAll(
+		"fmt.Println",
+		"vendor/pkg.(*Server).Start{pos:server.go:1:1}",
+		"main.main·lit",
+		"pkg.Map[float64]",
+		"pkg.(*Cache).Get",
+		"pkg.ProcesData",
+		"pkg.SomethingElse",
+	)
+
+	report := Reconcile(left, right)
+
+	wantReasons := map[string]MatchReason{
+		"fmt.Println":               MatchExact,
+		"pkg.(*Server).Start@linux": MatchCanonical,
+		"main.main·lit2":            MatchAnonIndex,
+		"pkg.Map[string, int]":      MatchShape,
+		"pkg.(Cache).Get":           MatchShape,
+		"pkg.ProcessData":           MatchFuzzy,
+	}
+	if len(report.Matched) != len(wantReasons) {
+		t.Fatalf("Matched = %d, want %d: %+v", len(report.Matched), len(wantReasons), report.Matched)
+	}
+	for _, m := range report.Matched {
+		if want := wantReasons[m.Left.Format()]; m.Reason != want {
+			t.Errorf("%s matched %s by %q, want %q", m.Left, m.Right, m.Reason, want)
+		}
+	}
+
+	if len(report.UnmatchedLeft) != 2 {
+		t.Fatalf("UnmatchedLeft = %+v", report.UnmatchedLeft)
+	}
+	if got := report.UnmatchedLeft[0]; got.Symbol.Name != "OnlyLeft" || got.Reason != UnmatchedNoSymbol {
+		t.Errorf("UnmatchedLeft[0] = %+v", got)
+	}
+	if got := report.UnmatchedLeft[1]; got.Symbol.Name != "Func" || got.Reason != UnmatchedNoPackage {
+		t.Errorf("UnmatchedLeft[1] = %+v", got)
+	}
+	if len(report.UnmatchedRight) != 1 || report.UnmatchedRight[0].Symbol.Name != "SomethingElse" {
+		t.Errorf("UnmatchedRight = %+v", report.UnmatchedRight)
+	}
+}
+
+func TestLevenshtein(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"", "", 0},
+		{"abc", "", 3},
+		{"kitten", "sitting", 3},
+		{"main·lit", "main·lit2", 1},
+	}
+	for _, tt := range tests {
+		if got := levenshtein(tt.a, tt.b); got != tt.want {
+			t.Errorf("levenshtein(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestReconcileWith_FoldCase(t *testing.T) {
+	left := []*Symbol{
+		MustParse("net/http.(*server).serve"),
+		MustParse("pkg.foo"),
+	}
+	right := []*Symbol{
+		MustParse("net/http.(*Server).Serve"),
+		MustParse("pkg.Foo"),
+		MustParse("pkg.foo"),
+	}
+
+	if report := Reconcile(left, right); report.Matched[0].Reason == MatchCaseFold || len(report.CaseCollisions) != 0 {
+		t.Errorf("Reconcile() folds case without FoldCase: %+v", report)
+	}
+
+	report := ReconcileWith(left, right, ReconcileOptions{FoldCase: true})
+	reasons := map[string]MatchReason{}
+	for _, m := range report.Matched {
+		reasons[m.Left.String()+" -> "+m.Right.String()] = m.Reason
+	}
+	if reasons["net/http.(*server).serve -> net/http.(*Server).Serve"] != MatchCaseFold {
+		t.Errorf("Matched = %v, want case-fold match", reasons)
+	}
+	if reasons["pkg.foo -> pkg.foo"] != MatchExact {
+		t.Errorf("Matched = %v, want exact match for pkg.foo", reasons)
+	}
+	if len(report.CaseCollisions) != 1 || len(report.CaseCollisions[0].Symbols) != 2 {
+		t.Errorf("CaseCollisions = %+v, want pkg.Foo and pkg.foo", report.CaseCollisions)
+	}
+}
+
+func TestRepairCase(t *testing.T) {
+	reference := []*Symbol{
+		MustParse("net/http.(*Server).Serve"),
+		MustParse("pkg.Foo"),
+		MustParse("pkg.foo"),
+	}
+	syms := []*Symbol{
+		MustParse("net/http.(*server).serve@linux{pos:server.go:3:1}"),
+		MustParse("pkg.foo"),
+		MustParse("other.run"),
+		MustParse("pkg.FOO"),
+	}
+
+	repaired, ambiguous := RepairCase(syms, reference)
+	want := []string{"net/http.(*Server).Serve@linux{pos:server.go:3:1}", "pkg.foo", "other.run", "pkg.FOO"}
+	for i, w := range want {
+		if got := repaired[i].String(); got != w {
+			t.Errorf("repaired[%d] = %q, want %q", i, got, w)
+		}
+	}
+	if len(ambiguous) != 1 || ambiguous[0] != syms[3] {
+		t.Errorf("ambiguous = %v, want [pkg.FOO]", ambiguous)
+	}
+}
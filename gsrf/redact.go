@@ -0,0 +1,363 @@
+package gsrf
+
+import (
+	"bufio"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Placeholder prefixes identify what a redacted name stood for: a package
+// path element, or an exported or unexported identifier.
+const (
+	redactPackagePrefix    = "p"
+	redactExportedPrefix   = "X"
+	redactUnexportedPrefix = "x"
+	redactHexLen           = 10
+)
+
+// Redactor replaces the package paths and identifiers of private packages
+// with salted-hash placeholders, so traces can be shared with third parties
+// without disclosing proprietary code structure. Structure survives: each
+// path element, receiver type and name is replaced by a placeholder of its
+// own, receivers keep their pointerness, type argument lists keep their
+// length, and exported names stay exported. Placeholders are the first hex
+// digits of an HMAC-SHA256 under the key, so the same name always redacts
+// the same way under one key and cannot be recovered without the map.
+//
+// Private symbols lose alias, position and custom metadata; via entries
+// are redacted like type names. Symbols of other packages pass unchanged
+// except for private types in their type arguments.
+//
+// The Redactor records each placeholder it emits; RedactionMap returns them
+// for Restore. A Redactor is safe for concurrent use.
+type Redactor struct {
+	key     []byte
+	private []string
+
+	mu    sync.Mutex
+	names map[string]string // Placeholder to original
+}
+
+// NewRedactor returns a redactor keyed by key, treating packages at or below
+// the private module paths as private.
+func NewRedactor(key []byte, private ...string) *Redactor {
+	r := &Redactor{
+		key:   append([]byte(nil), key...),
+		names: make(map[string]string),
+	}
+	for _, p := range private {
+		r.private = append(r.private, strings.TrimSuffix(p, "/"))
+	}
+	return r
+}
+
+// IsPrivate reports whether pkg is at or below a private module path.
+func (r *Redactor) IsPrivate(pkg string) bool {
+	for _, p := range r.private {
+		if pkg == p || strings.HasPrefix(pkg, p+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// Redact returns a redacted copy of sym.
+func (r *Redactor) Redact(sym *Symbol) *Symbol {
+	out := sym.Clone()
+	out.TypeArgs = r.redactTypes(out.TypeArgs, out.TypeParams)
+	for i, tp := range out.TypeParams {
+		if tp.Constraint != "" {
+			out.TypeParams[i].Constraint = r.redactTypes([]string{tp.Constraint}, nil)[0]
+		}
+	}
+	if out.Receiver != nil {
+		out.Receiver.TypeArgs = r.redactTypes(out.Receiver.TypeArgs, out.TypeParams)
+	}
+	if !r.IsPrivate(sym.PackagePath) {
+		return out
+	}
+
+	out.PackagePath = r.redactPackage(out.PackagePath)
+	if !out.IsInit {
+		out.Name = mapMethodName(out, r.redactIdent)
+	}
+	if out.Receiver != nil {
+		out.Receiver.TypeName = r.redactIdent(out.Receiver.TypeName)
+	}
+	if out.IsAnonymous {
+		out.AnonParent = out.PackagePath + "." + out.Name
+	}
+	for i, via := range out.Metadata.Via {
+		out.Metadata.Via[i] = r.redactVia(via)
+	}
+	out.Metadata.Alias = ""
+	out.Metadata.Position = ""
+	out.Metadata.Custom = nil
+	return out
+}
+
+// RedactTrace returns a copy of t with every known frame redacted.
+func (r *Redactor) RedactTrace(t *Trace) *Trace {
+	out := &Trace{Frames: make([]Frame, len(t.Frames))}
+	for i, f := range t.Frames {
+		if f.Symbol != nil {
+			f.Symbol = r.Redact(f.Symbol)
+		}
+		out.Frames[i] = f
+	}
+	return out
+}
+
+// RedactionMap returns the placeholders emitted so far.
+func (r *Redactor) RedactionMap() *RedactionMap {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	m := &RedactionMap{names: make(map[string]string, len(r.names))}
+	for k, v := range r.names {
+		m.names[k] = v
+	}
+	return m
+}
+
+func (r *Redactor) redactPackage(pkg string) string {
+	elems := strings.Split(pkg, "/")
+	for i, e := range elems {
+		elems[i] = r.placeholder(redactPackagePrefix, e)
+	}
+	return strings.Join(elems, "/")
+}
+
+func (r *Redactor) redactIdent(name string) string {
+	if startsUpper(name) {
+		return r.placeholder(redactExportedPrefix, name)
+	}
+	return r.placeholder(redactUnexportedPrefix, name)
+}
+
+// redactTypes redacts the private named types in type expressions. An
+// expression that does not parse is replaced as a whole, keeping the list
+// length.
+func (r *Redactor) redactTypes(args []string, params []TypeParam) []string {
+	for i, arg := range args {
+		exprs, err := parseTypeExprs([]string{arg}, params)
+		if err != nil {
+			args[i] = r.placeholder(redactExportedPrefix, arg)
+			continue
+		}
+		changed := false
+		exprs[0].Walk(func(t *TypeExpr) bool {
+			if t.Kind == ExprNamed && t.Package != "" && r.IsPrivate(t.Package) {
+				t.Package = r.redactPackage(t.Package)
+				t.Name = r.redactIdent(t.Name)
+				changed = true
+			}
+			return true
+		})
+		if changed {
+			args[i] = exprs[0].String()
+		}
+	}
+	return args
+}
+
+// redactVia redacts an embedded type name of a private symbol, which is
+// local to the symbol's package unless qualified.
+func (r *Redactor) redactVia(via string) string {
+	star := ""
+	if strings.HasPrefix(via, "*") {
+		star, via = "*", via[1:]
+	}
+	if sep := strings.LastIndex(via, "."); sep >= 0 {
+		pkg, name := via[:sep], via[sep+1:]
+		if !r.IsPrivate(pkg) {
+			return star + via
+		}
+		return star + r.redactPackage(pkg) + "." + r.redactIdent(name)
+	}
+	return star + r.redactIdent(via)
+}
+
+// mapMethodName applies fn to the name of sym. Anonymous functions in
+// methods carry the receiver in the name, as in "(*T).M"; fn is then applied
+// to the type and the method separately, keeping the pointer.
+func mapMethodName(sym *Symbol, fn func(string) string) string {
+	if !sym.IsAnonymous || !strings.HasPrefix(sym.Name, "(") {
+		return fn(sym.Name)
+	}
+	recv, method, ok := strings.Cut(sym.Name[1:], ").")
+	if !ok {
+		return fn(sym.Name)
+	}
+	star := ""
+	if strings.HasPrefix(recv, "*") {
+		star, recv = "*", recv[1:]
+	}
+	return "(" + star + fn(recv) + ")." + fn(method)
+}
+
+// placeholder returns the placeholder of s and records it.
+func (r *Redactor) placeholder(prefix, s string) string {
+	p := redactionPlaceholder(r.key, prefix, s)
+	r.mu.Lock()
+	r.names[p] = s
+	r.mu.Unlock()
+	return p
+}
+
+func redactionPlaceholder(key []byte, prefix, s string) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(prefix + "\x00" + s))
+	return prefix + hex.EncodeToString(mac.Sum(nil))[:redactHexLen]
+}
+
+// RedactionMap maps redaction placeholders back to the original names.
+type RedactionMap struct {
+	names map[string]string
+}
+
+// Len returns the number of placeholders in the map.
+func (m *RedactionMap) Len() int {
+	return len(m.names)
+}
+
+// WriteTo writes the map as text, one "placeholder original" pair per
+// line, sorted by placeholder. The original runs to the end of the line, as
+// type arguments redacted whole may contain spaces.
+func (m *RedactionMap) WriteTo(w io.Writer) (int64, error) {
+	keys := make([]string, 0, len(m.names))
+	for k := range m.names {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	bw := bufio.NewWriter(w)
+	var n int64
+	c, _ := bw.WriteString("# gsrf redaction map\n")
+	n += int64(c)
+	for _, k := range keys {
+		c, _ = fmt.Fprintf(bw, "%s %s\n", k, m.names[k])
+		n += int64(c)
+	}
+	return n, bw.Flush()
+}
+
+// ReadRedactionMap reads a map written by WriteTo and checks each entry
+// against key, so a map made under another key is rejected instead of
+// restoring names wrongly.
+func ReadRedactionMap(r io.Reader, key []byte) (*RedactionMap, error) {
+	m := &RedactionMap{names: make(map[string]string)}
+	scanner := bufio.NewScanner(r)
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		placeholder, orig, ok := strings.Cut(line, " ")
+		if !ok {
+			return nil, fmt.Errorf("redaction map: line %d: expected placeholder and original", lineNo)
+		}
+		if redactionPlaceholder(key, placeholder[:1], orig) != placeholder {
+			return nil, fmt.Errorf("redaction map: line %d: %s does not match the key", lineNo, placeholder)
+		}
+		m.names[placeholder] = orig
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// Restore returns a copy of sym with the placeholders of the map replaced
+// by the original names. Metadata dropped by Redact is not restored.
+func (m *RedactionMap) Restore(sym *Symbol) *Symbol {
+	out := sym.Clone()
+	out.PackagePath = m.restorePackage(out.PackagePath)
+	out.Name = mapMethodName(out, m.restore)
+	if out.Receiver != nil {
+		out.Receiver.TypeName = m.restore(out.Receiver.TypeName)
+		out.Receiver.TypeArgs = m.restoreTypes(out.Receiver.TypeArgs, out.TypeParams)
+	}
+	if out.IsAnonymous {
+		out.AnonParent = out.PackagePath + "." + out.Name
+	}
+	out.TypeArgs = m.restoreTypes(out.TypeArgs, out.TypeParams)
+	for i, tp := range out.TypeParams {
+		if tp.Constraint != "" {
+			out.TypeParams[i].Constraint = m.restoreTypes([]string{tp.Constraint}, nil)[0]
+		}
+	}
+	for i, via := range out.Metadata.Via {
+		star, name := "", via
+		if strings.HasPrefix(name, "*") {
+			star, name = "*", name[1:]
+		}
+		if sep := strings.LastIndex(name, "."); sep >= 0 {
+			name = m.restorePackage(name[:sep]) + "." + m.restore(name[sep+1:])
+		} else {
+			name = m.restore(name)
+		}
+		out.Metadata.Via[i] = star + name
+	}
+	return out
+}
+
+// RestoreTrace returns a copy of t with every known frame restored.
+func (m *RedactionMap) RestoreTrace(t *Trace) *Trace {
+	out := &Trace{Frames: make([]Frame, len(t.Frames))}
+	for i, f := range t.Frames {
+		if f.Symbol != nil {
+			f.Symbol = m.Restore(f.Symbol)
+		}
+		out.Frames[i] = f
+	}
+	return out
+}
+
+func (m *RedactionMap) restore(s string) string {
+	if orig, ok := m.names[s]; ok {
+		return orig
+	}
+	return s
+}
+
+func (m *RedactionMap) restorePackage(pkg string) string {
+	elems := strings.Split(pkg, "/")
+	for i, e := range elems {
+		elems[i] = m.restore(e)
+	}
+	return strings.Join(elems, "/")
+}
+
+func (m *RedactionMap) restoreTypes(args []string, params []TypeParam) []string {
+	for i, arg := range args {
+		if orig, ok := m.names[arg]; ok {
+			args[i] = orig
+			continue
+		}
+		exprs, err := parseTypeExprs([]string{arg}, params)
+		if err != nil {
+			continue
+		}
+		changed := false
+		exprs[0].Walk(func(t *TypeExpr) bool {
+			if t.Kind == ExprNamed && t.Package != "" {
+				pkg, name := m.restorePackage(t.Package), m.restore(t.Name)
+				changed = changed || pkg != t.Package || name != t.Name
+				t.Package, t.Name = pkg, name
+			}
+			return true
+		})
+		if changed {
+			args[i] = exprs[0].String()
+		}
+	}
+	return args
+}
@@ -0,0 +1,95 @@
+package gsrf
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestRedactor(t *testing.T) {
+	key := []byte("secret")
+	r := NewRedactor(key, "github.com/acme")
+
+	inputs := []string{
+		"github.com/acme/billing.(*Invoice).Charge@linux{pos:invoice.go:10,owner:payments}",
+		"github.com/acme/billing.retry·2",
+		"github.com/acme/billing.(*Invoice).Charge·lit2",
+		"github.com/acme/billing.init",
+		"github.com/acme/billing.(Ledger).Sum{via:*Base}",
+		"slices.Sort[[]github.com/acme/billing.Invoice]",
+		"github.com/acme/cache.(*LRU[string, github.com/acme/billing.Invoice]).Get",
+		"net/http.(*Server).Serve",
+	}
+	for _, in := range inputs {
+		sym := MustParse(in)
+		red := r.Redact(sym)
+		out := red.String()
+		for _, secret := range []string{"acme", "billing", "Invoice", "Charge", "retry", "Ledger", "Base", "payments", "invoice.go"} {
+			if strings.Contains(out, secret) {
+				t.Errorf("Redact(%q) = %q, leaks %q", in, out, secret)
+			}
+		}
+		if _, err := Parse(out); err != nil {
+			t.Errorf("Redact(%q) = %q, does not parse: %v", in, out, err)
+		}
+		if (red.Receiver == nil) != (sym.Receiver == nil) || len(red.TypeArgs) != len(sym.TypeArgs) || red.IsExported() != sym.IsExported() {
+			t.Errorf("Redact(%q) = %q, structure changed", in, out)
+		}
+		if sym.String() != MustParse(in).String() {
+			t.Errorf("Redact(%q) modified its argument", in)
+		}
+	}
+
+	// Anonymous functions in methods keep the receiver pointer and an exported
+	// method placeholder.
+	if got := r.Redact(MustParse("github.com/acme/billing.(*Invoice).Charge·lit2")).Name; !strings.HasPrefix(got, "(*"+redactExportedPrefix) || !strings.Contains(got, ")."+redactExportedPrefix) {
+		t.Errorf("Redact() name = %q, want (*X...).X...", got)
+	}
+	if got := r.Redact(MustParse("net/http.(*Server).Serve")).String(); got != "net/http.(*Server).Serve" {
+		t.Errorf("Redact() changed a public symbol: %q", got)
+	}
+	a := r.Redact(MustParse("github.com/acme/billing.Charge")).String()
+	b := NewRedactor(key, "github.com/acme").Redact(MustParse("github.com/acme/billing.Charge")).String()
+	c := NewRedactor([]byte("other"), "github.com/acme").Redact(MustParse("github.com/acme/billing.Charge")).String()
+	if a != b || a == c {
+		t.Errorf("placeholders: same key %q, %q; other key %q", a, b, c)
+	}
+	if got := strings.Count(a, "/"); got != 2 {
+		t.Errorf("Redact() = %q, want 3 path elements", a)
+	}
+
+	var buf bytes.Buffer
+	if _, err := r.RedactionMap().WriteTo(&buf); err != nil {
+		t.Fatalf("WriteTo() error = %v", err)
+	}
+	if _, err := ReadRedactionMap(bytes.NewReader(buf.Bytes()), []byte("other")); err == nil {
+		t.Error("ReadRedactionMap() accepted the wrong key")
+	}
+	m, err := ReadRedactionMap(&buf, key)
+	if err != nil {
+		t.Fatalf("ReadRedactionMap() error = %v", err)
+	}
+	if m.Len() != r.RedactionMap().Len() {
+		t.Errorf("Len() = %d, want %d", m.Len(), r.RedactionMap().Len())
+	}
+
+	restored := map[string]string{
+		"github.com/acme/billing.(*Invoice).Charge@linux{pos:invoice.go:10,owner:payments}": "github.com/acme/billing.(*Invoice).Charge@linux",
+		"github.com/acme/billing.(Ledger).Sum{via:*Base}":                                   "github.com/acme/billing.(Ledger).Sum{via:*Base}",
+	}
+	for _, in := range inputs {
+		want, ok := restored[in]
+		if !ok {
+			want = in
+		}
+		if got := m.Restore(r.Redact(MustParse(in))).String(); got != want {
+			t.Errorf("Restore(Redact(%q)) = %q, want %q", in, got, want)
+		}
+	}
+
+	trace := ParseTrace([]string{"github.com/acme/billing.Charge", "???", "main.main"}, nil)
+	back := m.RestoreTrace(r.RedactTrace(trace))
+	if back.Frames[0].Symbol.String() != "github.com/acme/billing.Charge" || !back.Frames[1].IsUnknown() {
+		t.Errorf("RestoreTrace(RedactTrace()) = %v", back.Frames)
+	}
+}
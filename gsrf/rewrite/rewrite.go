@@ -0,0 +1,215 @@
+// Package rewrite maps GSRF symbols across refactors and module renames, so
+// that symbols and stack traces recorded before a change can be compared
+// with current ones.
+//
+// Rules are applied in order, each to the result of the previous ones:
+//
+//	# rules.yaml
+//	- kind: package            # module or package move, subpackages included
+//	  from: github.com/old/mod
+//	  to: github.com/new/mod
+//	- kind: receiver           # type rename
+//	  package: github.com/new/mod/api
+//	  from: Server
+//	  to: HTTPServer
+//	- kind: name               # function or method renames
+//	  package: github.com/new/mod/*
+//	  receiver: HTTPServer
+//	  from: Handle*
+//	  to: Serve*
+//
+// Package, Receiver and the From of receiver and name rules are wildcard
+// patterns in which "*" matches any run of characters, including "/". In a
+// name rule, each "*" of To is replaced by the text the corresponding "*" of
+// From matched. Quote patterns starting with "*" in YAML.
+package rewrite
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/kis9a/gsrf"
+)
+
+// Rule kinds.
+const (
+	KindPackage  = "package"
+	KindReceiver = "receiver"
+	KindName     = "name"
+)
+
+// Rule is one rewrite rule.
+type Rule struct {
+	Kind     string `yaml:"kind"`
+	Package  string `yaml:"package,omitempty"`  // Receiver and name rules: packages the rule applies to; any if empty
+	Receiver string `yaml:"receiver,omitempty"` // Name rules: receiver type the rule applies to; any symbol if empty
+	From     string `yaml:"from"`
+	To       string `yaml:"to"`
+}
+
+// Rules is a compiled, ordered list of rules.
+type Rules struct {
+	rules []compiled
+}
+
+type compiled struct {
+	Rule
+	pkg, recv, from *regexp.Regexp // Nil when the pattern is empty
+}
+
+// Compile checks and compiles rules.
+func Compile(rules []Rule) (*Rules, error) {
+	rs := &Rules{}
+	for i, r := range rules {
+		if r.From == "" || r.To == "" {
+			return nil, fmt.Errorf("rule %d: from and to are required", i+1)
+		}
+		c := compiled{Rule: r, pkg: wildcard(r.Package), recv: wildcard(r.Receiver)}
+		switch r.Kind {
+		case KindPackage:
+			if r.Package != "" || r.Receiver != "" {
+				return nil, fmt.Errorf("rule %d: package rules take only from and to", i+1)
+			}
+			if strings.Contains(r.From, "*") || strings.Contains(r.To, "*") {
+				return nil, fmt.Errorf("rule %d: package rules match path prefixes and take no wildcards", i+1)
+			}
+		case KindReceiver:
+			if r.Receiver != "" {
+				return nil, fmt.Errorf("rule %d: receiver rules take the receiver in from", i+1)
+			}
+			c.from = wildcard(r.From)
+		case KindName:
+			c.from = wildcard(r.From)
+			if n, m := strings.Count(r.From, "*"), strings.Count(r.To, "*"); m > n {
+				return nil, fmt.Errorf("rule %d: to has %d wildcards but from has %d", i+1, m, n)
+			}
+		default:
+			return nil, fmt.Errorf("rule %d: unknown kind %q", i+1, r.Kind)
+		}
+		rs.rules = append(rs.rules, c)
+	}
+	return rs, nil
+}
+
+// ReadYAML reads a YAML list of rules and compiles it.
+func ReadYAML(r io.Reader) (*Rules, error) {
+	var rules []Rule
+	if err := yaml.NewDecoder(r).Decode(&rules); err != nil && err != io.EOF {
+		return nil, fmt.Errorf("rewrite rules: %w", err)
+	}
+	rs, err := Compile(rules)
+	if err != nil {
+		return nil, fmt.Errorf("rewrite rules: %w", err)
+	}
+	return rs, nil
+}
+
+// Len returns the number of rules.
+func (rs *Rules) Len() int {
+	return len(rs.rules)
+}
+
+// Apply returns sym rewritten by the rules and whether any rule changed it.
+// sym itself is not modified; when no rule applies, it is returned as is.
+func (rs *Rules) Apply(sym *gsrf.Symbol) (*gsrf.Symbol, bool) {
+	out := sym
+	changed := false
+	for _, r := range rs.rules {
+		pkg, recv, name, ok := r.apply(out)
+		if !ok {
+			continue
+		}
+		if !changed {
+			out = sym.Clone()
+			changed = true
+		}
+		out.PackagePath, out.Name = pkg, name
+		if out.Receiver != nil {
+			out.Receiver.TypeName = recv
+		}
+		if out.IsAnonymous {
+			out.AnonParent = out.PackagePath + "." + out.Name
+		}
+	}
+	return out, changed
+}
+
+// apply returns the rewritten package, receiver type and name, or false if
+// the rule does not change sym.
+func (r compiled) apply(sym *gsrf.Symbol) (pkg, recv, name string, ok bool) {
+	pkg, name = sym.PackagePath, sym.Name
+	if sym.Receiver != nil {
+		recv = sym.Receiver.TypeName
+	}
+
+	if r.Kind == KindPackage {
+		rest, found := strings.CutPrefix(pkg, r.From)
+		if !found || (rest != "" && rest[0] != '/') {
+			return "", "", "", false
+		}
+		return r.To + rest, recv, name, true
+	}
+
+	if r.pkg != nil && !r.pkg.MatchString(pkg) {
+		return "", "", "", false
+	}
+	switch r.Kind {
+	case KindReceiver:
+		if sym.Receiver == nil || !r.from.MatchString(recv) {
+			return "", "", "", false
+		}
+		recv = r.To
+	case KindName:
+		if r.recv != nil && (sym.Receiver == nil || !r.recv.MatchString(recv)) {
+			return "", "", "", false
+		}
+		m := r.from.FindStringSubmatch(name)
+		if m == nil {
+			return "", "", "", false
+		}
+		name = substitute(r.To, m[1:])
+	}
+	return pkg, recv, name, pkg != sym.PackagePath || name != sym.Name || (sym.Receiver != nil && recv != sym.Receiver.TypeName)
+}
+
+// ApplyTrace returns a copy of t with every known frame rewritten. Unknown
+// frames are kept in place.
+func (rs *Rules) ApplyTrace(t *gsrf.Trace) *gsrf.Trace {
+	out := &gsrf.Trace{Frames: make([]gsrf.Frame, len(t.Frames))}
+	for i, f := range t.Frames {
+		if f.Symbol != nil {
+			f.Symbol, _ = rs.Apply(f.Symbol)
+		}
+		out.Frames[i] = f
+	}
+	return out
+}
+
+// wildcard compiles a pattern in which "*" matches any run of characters,
+// capturing it. It returns nil for an empty pattern.
+func wildcard(pattern string) *regexp.Regexp {
+	if pattern == "" {
+		return nil
+	}
+	parts := strings.Split(pattern, "*")
+	for i, p := range parts {
+		parts[i] = regexp.QuoteMeta(p)
+	}
+	return regexp.MustCompile(`^(?s:` + strings.Join(parts, "(.*?)") + `)$`)
+}
+
+// substitute replaces the wildcards of to with captures, in order.
+func substitute(to string, captures []string) string {
+	var b strings.Builder
+	for i, part := range strings.Split(to, "*") {
+		if i > 0 {
+			b.WriteString(captures[i-1])
+		}
+		b.WriteString(part)
+	}
+	return b.String()
+}
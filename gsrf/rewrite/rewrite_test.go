@@ -0,0 +1,105 @@
+package rewrite
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/kis9a/gsrf"
+)
+
+const rulesYAML = `
+- kind: package
+  from: github.com/old/mod
+  to: github.com/new/mod
+- kind: receiver
+  package: github.com/new/mod/api
+  from: Server
+  to: HTTPServer
+- kind: name
+  package: github.com/new/mod/*
+  receiver: HTTPServer
+  from: Handle*
+  to: Serve*
+- kind: name
+  from: legacy*Of*
+  to: "*By*"
+`
+
+func TestApply(t *testing.T) {
+	rs, err := ReadYAML(strings.NewReader(rulesYAML))
+	if err != nil {
+		t.Fatalf("ReadYAML() error = %v", err)
+	}
+	if rs.Len() != 4 {
+		t.Fatalf("Len() = %d, want 4", rs.Len())
+	}
+
+	tests := []struct {
+		in, want string
+		changed  bool
+	}{
+		{"github.com/old/mod.Run", "github.com/new/mod.Run", true},
+		{"github.com/old/mod/api.(*Server).HandleGet", "github.com/new/mod/api.(*HTTPServer).ServeGet", true},
+		{"github.com/old/mod/api.(Server).Close@linux{pos:api.go:10}", "github.com/new/mod/api.(HTTPServer).Close@linux{pos:api.go:10}", true},
+		{"github.com/old/mod/internal.(*Server).HandleGet", "github.com/new/mod/internal.(*Server).HandleGet", true},
+		{"github.com/old/mod/api.HandleGet", "github.com/new/mod/api.HandleGet", true},
+		{"github.com/old/mod/api.Run·2", "github.com/new/mod/api.Run·2", true},
+		{"github.com/old/module.Run", "github.com/old/module.Run", false},
+		{"pkg.legacyCostOfItem", "pkg.CostByItem", true},
+		{"pkg.Other", "pkg.Other", false},
+	}
+	for _, tt := range tests {
+		sym := gsrf.MustParse(tt.in)
+		got, changed := rs.Apply(sym)
+		if got.String() != tt.want || changed != tt.changed {
+			t.Errorf("Apply(%q) = %q, %v, want %q, %v", tt.in, got, changed, tt.want, tt.changed)
+		}
+		if sym.String() != gsrf.MustParse(tt.in).String() {
+			t.Errorf("Apply(%q) modified its argument", tt.in)
+		}
+		if again, err := gsrf.Parse(got.String()); err != nil || again.AnonParent != got.AnonParent {
+			t.Errorf("Apply(%q) AnonParent = %q, reparsed %v, %v", tt.in, got.AnonParent, again, err)
+		}
+	}
+}
+
+func TestApplyTrace(t *testing.T) {
+	rs, err := Compile([]Rule{{Kind: KindPackage, From: "example.com/app", To: "example.com/svc"}})
+	if err != nil {
+		t.Fatalf("Compile() error = %v", err)
+	}
+	trace := gsrf.ParseTrace([]string{"example.com/app.main", "not a symbol", "runtime.main"}, nil)
+	got := rs.ApplyTrace(trace)
+	if len(got.Frames) != 3 {
+		t.Fatalf("ApplyTrace() frames = %d, want 3", len(got.Frames))
+	}
+	if s := got.Frames[0].Symbol.String(); s != "example.com/svc.main" {
+		t.Errorf("frame 0 = %q", s)
+	}
+	if !got.Frames[1].IsUnknown() {
+		t.Error("frame 1 is not unknown")
+	}
+	if got.Frames[2].Symbol != trace.Frames[2].Symbol {
+		t.Error("unchanged frame was copied")
+	}
+	if s := trace.Frames[0].Symbol.String(); s != "example.com/app.main" {
+		t.Errorf("input trace modified: %q", s)
+	}
+}
+
+func TestCompile_Errors(t *testing.T) {
+	for _, rules := range [][]Rule{
+		{{Kind: "type", From: "A", To: "B"}},
+		{{Kind: KindName, From: "A"}},
+		{{Kind: KindName, From: "A", To: "B*"}},
+		{{Kind: KindPackage, From: "a/*", To: "b"}},
+		{{Kind: KindReceiver, Receiver: "T", From: "A", To: "B"}},
+	} {
+		if _, err := Compile(rules); err == nil {
+			t.Errorf("Compile(%+v) succeeded", rules)
+		}
+	}
+	if _, err := ReadYAML(strings.NewReader("kind: package\n")); err == nil {
+		t.Error("ReadYAML() accepted a mapping")
+	}
+}
@@ -0,0 +1,84 @@
+package gsrf
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+)
+
+// rulesProbes exercise the parser, every Normalize rule, and the canonical
+// formatter. Their normalized output is hashed into RulesHash.
+var rulesProbes = []string{
+	"fmt.Println",
+	"net/http.(*Server).Serve",
+	"vendor/golang.org/x/net/http2.(*Framer).WriteData",
+	"pkg.Map[K comparable, V any]",
+	"pkg.(*Cache[K,V]).Get[map[string] []int]",
+	"database/sql.init",
+	"pkg.init",
+	"main.main·lit2",
+	"main.(*T).Run·lit",
+	"pkg.·lit",
+	"net.(*netFD).connect@linux",
+	"io.(*MultiWriter).Write{via:Writer,alias:W,pos:multi.go:25:1,team:io}",
+	`"gopkg.in/yaml.v3".Marshal`,
+}
+
+// RulesHash identifies the parsing and normalization rules of this build. It
+// is a content hash of the canonical forms and fingerprints of a fixed probe
+// corpus, so any change that alters how symbols canonicalize changes the hash,
+// whether or not a version constant was bumped. FingerprintIndex and symbol
+// files written by WriteFile record it so loaders can detect drift. Bare
+// fingerprints and the MarshalBinary encoding do not: callers persisting
+// fingerprints should store RulesHash alongside them, and the binary encoding
+// stores the fields of a symbol as given, which no rule change reinterprets.
+var RulesHash = computeRulesHash()
+
+func computeRulesHash() string {
+	h := sha256.New()
+	h.Write([]byte{'g', 's', 'r', 'f', FingerprintVersion, BinaryVersion, 0})
+	for _, in := range rulesProbes {
+		sym, err := Parse(in)
+		if err != nil {
+			h.Write([]byte("!" + err.Error()))
+		} else {
+			h.Write([]byte(sym.Normalize().Format()))
+			h.Write([]byte(strconv.FormatUint(sym.Fingerprint(), 16)))
+		}
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil)[:8])
+}
+
+// RulesMismatchError is returned when an artifact was written under
+// different normalization rules than the running build.
+type RulesMismatchError struct {
+	Artifact string // Kind of artifact, e.g. "fingerprint index"
+	Stored   string // RulesHash recorded in the artifact
+	Current  string // RulesHash of this build
+}
+
+func (e *RulesMismatchError) Error() string {
+	return fmt.Sprintf("%s was written with GSRF rules %s, current rules are %s", e.Artifact, e.Stored, e.Current)
+}
+
+// CheckRulesHash returns a *RulesMismatchError if stored differs from
+// RulesHash.
+func CheckRulesHash(artifact, stored string) error {
+	if stored != RulesHash {
+		return &RulesMismatchError{Artifact: artifact, Stored: stored, Current: RulesHash}
+	}
+	return nil
+}
+
+// RulesPolicy selects how loaders handle artifacts written under different
+// rules.
+type RulesPolicy int
+
+const (
+	// RulesRefuse fails the load with a *RulesMismatchError.
+	RulesRefuse RulesPolicy = iota
+	// RulesMigrate rebuilds derived values from the stored symbols.
+	RulesMigrate
+)
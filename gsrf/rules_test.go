@@ -0,0 +1,105 @@
+package gsrf
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestRulesHash(t *testing.T) {
+	if len(RulesHash) != 16 {
+		t.Errorf("RulesHash = %q, want 16 hex digits", RulesHash)
+	}
+	if got := computeRulesHash(); got != RulesHash {
+		t.Errorf("computeRulesHash() = %q, not deterministic (was %q)", got, RulesHash)
+	}
+	for _, in := range rulesProbes {
+		if _, err := Parse(in); err != nil {
+			t.Errorf("probe %q does not parse: %v", in, err)
+		}
+	}
+}
+
+func TestCheckRulesHash(t *testing.T) {
+	if err := CheckRulesHash("test", RulesHash); err != nil {
+		t.Errorf("CheckRulesHash(current) = %v", err)
+	}
+	err := CheckRulesHash("test", "0000000000000000")
+	var mismatch *RulesMismatchError
+	if !errors.As(err, &mismatch) || mismatch.Current != RulesHash {
+		t.Errorf("CheckRulesHash(stale) = %v, want *RulesMismatchError", err)
+	}
+}
+
+func TestFingerprintIndex_RoundTrip(t *testing.T) {
+	x := NewFingerprintIndex()
+	var fps []uint64
+	for _, in := range []string{"fmt.Println", "net/http.(*Server).Serve", "main.main·lit2"} {
+		fps = append(fps, x.Add(MustParse(in)))
+	}
+	x.Add(MustParse("vendor/fmt.Println")) // Same fingerprint as fmt.Println
+	if x.Len() != 3 {
+		t.Fatalf("Len() = %d, want 3", x.Len())
+	}
+
+	var buf bytes.Buffer
+	if _, err := x.WriteTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(buf.String(), "gsrf-fingerprints 1 rules="+RulesHash+"\n") {
+		t.Errorf("header = %q", strings.SplitN(buf.String(), "\n", 2)[0])
+	}
+
+	back, err := ReadFingerprintIndex(&buf, RulesRefuse)
+	if err != nil {
+		t.Fatalf("ReadFingerprintIndex() error = %v", err)
+	}
+	for _, fp := range fps {
+		want, _ := x.Lookup(fp)
+		got, ok := back.Lookup(fp)
+		if !ok || !got.Equal(want) {
+			t.Errorf("Lookup(%016x) = %v, %v; want %v", fp, got, ok, want)
+		}
+	}
+}
+
+func TestReadFingerprintIndex_Stale(t *testing.T) {
+	stale := "gsrf-fingerprints 1 rules=0000000000000000\n" +
+		"00000000000000ff\tfmt.Println\n"
+
+	_, err := ReadFingerprintIndex(strings.NewReader(stale), RulesRefuse)
+	var mismatch *RulesMismatchError
+	if !errors.As(err, &mismatch) || mismatch.Stored != "0000000000000000" {
+		t.Fatalf("RulesRefuse error = %v, want *RulesMismatchError", err)
+	}
+
+	x, err := ReadFingerprintIndex(strings.NewReader(stale), RulesMigrate)
+	if err != nil {
+		t.Fatalf("RulesMigrate error = %v", err)
+	}
+	if _, ok := x.Lookup(0xff); ok {
+		t.Error("migrated index kept stale fingerprint")
+	}
+	fp := MustParse("fmt.Println").Fingerprint()
+	if sym, ok := x.Lookup(fp); !ok || sym.Format() != "fmt.Println" {
+		t.Errorf("Lookup(current fingerprint) = %v, %v", sym, ok)
+	}
+}
+
+func TestReadFingerprintIndex_Invalid(t *testing.T) {
+	inputs := map[string]string{
+		"empty":      "",
+		"bad header": "hello\n",
+		"no tab":     "gsrf-fingerprints 1 rules=" + RulesHash + "\nfmt.Println\n",
+		"bad hex":    "gsrf-fingerprints 1 rules=" + RulesHash + "\nzz\tfmt.Println\n",
+		"bad symbol": "gsrf-fingerprints 1 rules=" + RulesHash + "\n01\tinvalid\n",
+	}
+	for name, in := range inputs {
+		t.Run(name, func(t *testing.T) {
+			if _, err := ReadFingerprintIndex(strings.NewReader(in), RulesRefuse); err == nil {
+				t.Errorf("ReadFingerprintIndex(%q) expected error", in)
+			}
+		})
+	}
+}
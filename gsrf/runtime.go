@@ -0,0 +1,175 @@
+package gsrf
+
+import (
+	"fmt"
+	"net/url"
+	"reflect"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// FromFunc returns the symbol of a live function or method value, so code can
+// refer to handlers by symbol without hardcoding strings.
+//
+//	sym, err := gsrf.FromFunc((*http.Server).Serve) // net/http.(*Server).Serve
+func FromFunc(fn any) (*Symbol, error) {
+	v := reflect.ValueOf(fn)
+	if v.Kind() != reflect.Func || v.IsNil() {
+		return nil, fmt.Errorf("invalid function value: %T", fn)
+	}
+	f := runtime.FuncForPC(v.Pointer())
+	if f == nil {
+		return nil, fmt.Errorf("no runtime function for %T", fn)
+	}
+	return FromRuntimeName(f.Name())
+}
+
+// FromRuntimeName converts a function name as reported by runtime.Func.Name
+// or runtime.Frame.Function to a symbol:
+//
+//   - Method value wrappers (the -fm suffix) map to the method itself.
+//   - Escaped package paths (gopkg.in/yaml%2ev3) are unescaped.
+//   - Numbered init functions (init.0, init.1) map to init.
+//   - Closures (func1, gowrap1, ...) become anonymous functions; nested
+//     closures map to their outermost function literal.
+//   - Generic instantiations keep the runtime's "..." type arguments, since
+//     the runtime does not record the instantiating types.
+func FromRuntimeName(name string) (*Symbol, error) {
+	name = strings.TrimSuffix(name, "-fm")
+
+	// The package path ends at the first dot after its last slash.
+	end := strings.IndexAny(name, "([")
+	if end < 0 {
+		end = len(name)
+	}
+	slash := strings.LastIndex(name[:end], "/")
+	dot := strings.Index(name[slash+1:], ".")
+	if dot < 0 || slash+1+dot > end {
+		return nil, fmt.Errorf("invalid runtime function name %q: no package separator", name)
+	}
+	dot += slash + 1
+	pkg := name[:dot]
+	if unescaped, err := url.PathUnescape(pkg); err == nil {
+		pkg = unescaped
+	}
+	if pkg == "" {
+		return nil, fmt.Errorf("invalid runtime function name %q: empty package", name)
+	}
+
+	parts := splitRuntimeName(name[dot+1:])
+	sym := &Symbol{PackagePath: pkg}
+
+	// Multiple init functions in a package are numbered "init.0", "init.1".
+	if len(parts) == 2 && parts[0] == "init" {
+		if _, err := strconv.Atoi(parts[1]); err == nil {
+			sym.Name = "init"
+			sym.IsInit = true
+			return sym, nil
+		}
+	}
+
+	// Receiver: "(*T)" / "(T[...])", or a value receiver "T" followed by a
+	// method name that is not a closure.
+	var recvText string
+	if strings.HasPrefix(parts[0], "(") {
+		recvText = parts[0]
+		parts = parts[1:]
+	} else if len(parts) > 1 && !isClosurePart(parts[1]) && parts[0] != "" {
+		recvText = "(" + parts[0] + ")"
+		parts = parts[1:]
+	}
+	if len(parts) == 0 || (parts[0] == "" && len(parts) == 1) {
+		return nil, fmt.Errorf("invalid runtime function name %q: missing function name", name)
+	}
+
+	fnName, typeArgs := splitRuntimeTypeArgs(parts[0])
+	sym.Name = fnName
+	sym.TypeArgs = typeArgs
+	if recvText != "" {
+		inner := strings.TrimSuffix(strings.TrimPrefix(recvText, "("), ")")
+		recv := &Receiver{IsPointer: strings.HasPrefix(inner, "*")}
+		recv.TypeName, recv.TypeArgs = splitRuntimeTypeArgs(strings.TrimPrefix(inner, "*"))
+		sym.Receiver = recv
+	}
+
+	if len(parts) > 1 {
+		// Closure: "F.func1", "T.M.func2.1", or "glob..func1" for package-level
+		// variable initializers before Go 1.21 (later releases use init.funcN).
+		closure := parts[1:]
+		if parts[0] == "glob" && closure[0] == "" && len(closure) > 1 && sym.Receiver == nil {
+			sym.Name = ""
+			closure = closure[1:]
+		}
+		idx, ok := closureIndex(closure[0])
+		if !ok {
+			return nil, fmt.Errorf("invalid runtime function name %q: unexpected %q", name, closure[0])
+		}
+		if sym.Receiver != nil {
+			// Anonymous functions in methods keep the receiver in the name,
+			// matching how Parse reads "pkg.(*T).M·lit".
+			sym.Name = sym.Receiver.Format() + "." + sym.Name
+			sym.Receiver = nil
+		}
+		sym.IsAnonymous = true
+		sym.AnonIndex = idx
+		sym.AnonParent = sym.PackagePath + "." + sym.Name
+		return sym, nil
+	}
+
+	if sym.Receiver == nil && sym.Name == "init" {
+		sym.IsInit = true
+	}
+	return sym, nil
+}
+
+// splitRuntimeName splits on dots outside brackets and parentheses.
+func splitRuntimeName(s string) []string {
+	var parts []string
+	depth, start := 0, 0
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '(', '[':
+			depth++
+		case ')', ']':
+			depth--
+		case '.':
+			if depth == 0 {
+				parts = append(parts, s[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(parts, s[start:])
+}
+
+// splitRuntimeTypeArgs separates "Map[...]" into its name and type arguments.
+func splitRuntimeTypeArgs(s string) (string, []string) {
+	open := strings.Index(s, "[")
+	if open < 0 || !strings.HasSuffix(s, "]") {
+		return s, nil
+	}
+	return s[:open], parseTypeArgs(s[open+1 : len(s)-1])
+}
+
+// closurePrefixes are the compiler-generated names of function literals and
+// go/defer wrappers.
+var closurePrefixes = []string{"func", "gowrap", "deferwrap"}
+
+// isClosurePart reports whether a name component denotes a closure. The
+// empty component comes from package-level initializers ("glob..func1").
+func isClosurePart(part string) bool {
+	_, ok := closureIndex(part)
+	return ok || part == ""
+}
+
+// closureIndex extracts N from "funcN" and its wrapper variants.
+func closureIndex(part string) (int, bool) {
+	for _, prefix := range closurePrefixes {
+		if rest, ok := strings.CutPrefix(part, prefix); ok {
+			n, err := strconv.Atoi(rest)
+			return n, err == nil && n > 0
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,105 @@
+package gsrf
+
+import (
+	"bytes"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+type runtimeTestServer struct{}
+
+func (*runtimeTestServer) Start()      {}
+func (runtimeTestServer) Name() string { return "" }
+
+type runtimeTestList[T any] struct{ items []T }
+
+func (l *runtimeTestList[T]) Push(v T) { l.items = append(l.items, v) }
+
+func runtimeTestMap[K comparable, V any](m map[K]V) int { return len(m) }
+
+var runtimeTestClosure = func() {}
+
+func TestFromFunc(t *testing.T) {
+	var srv runtimeTestServer
+	var list runtimeTestList[int]
+	closure := func() {}
+
+	tests := []struct {
+		name string
+		fn   any
+		want string
+	}{
+		{name: "function", fn: strings.ToUpper, want: "strings.ToUpper"},
+		{name: "pointer method expression", fn: (*http.Server).Serve, want: "net/http.(*Server).Serve"},
+		{name: "value method expression", fn: http.Header.Get, want: "net/http.(Header).Get"},
+		{name: "method value", fn: srv.Name, want: "github.com/kis9a/gsrf.(runtimeTestServer).Name"},
+		{name: "pointer method value", fn: (&srv).Start, want: "github.com/kis9a/gsrf.(*runtimeTestServer).Start"},
+		{name: "generic function", fn: runtimeTestMap[string, int], want: "github.com/kis9a/gsrf.runtimeTestMap[...]"},
+		{name: "generic method", fn: list.Push, want: "github.com/kis9a/gsrf.(*runtimeTestList[...]).Push"},
+		{name: "closure", fn: closure, want: "github.com/kis9a/gsrf.TestFromFunc·lit1"},
+		{name: "package-level closure", fn: runtimeTestClosure, want: "github.com/kis9a/gsrf.init·lit1"},
+		{name: "bytes buffer", fn: (*bytes.Buffer).Write, want: "bytes.(*Buffer).Write"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sym, err := FromFunc(tt.fn)
+			if err != nil {
+				t.Fatalf("FromFunc() error = %v", err)
+			}
+			if got := sym.Format(); got != tt.want {
+				t.Errorf("FromFunc() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	for _, bad := range []any{nil, 42, (func())(nil)} {
+		if _, err := FromFunc(bad); err == nil {
+			t.Errorf("FromFunc(%#v) expected error", bad)
+		}
+	}
+}
+
+func TestFromRuntimeName(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"main.main", "main.main"},
+		{"main.init", "main.init"},
+		{"main.init.0", "main.init"},
+		{"gopkg.in/yaml%2ev3.Marshal", "gopkg.in/yaml.v3.Marshal"},
+		{"main.(*T).Run.func2", "main.(*T).Run·lit2"},
+		{"main.T.Run.func1", "main.(T).Run·lit1"},
+		{"main.main.func1.2", "main.main·lit1"},
+		{"main.main.gowrap1", "main.main·lit1"},
+		{"example.com/x.glob..func3", "example.com/x.·lit3"},
+		{"example.com/x.(*Cache[...]).Get-fm", "example.com/x.(*Cache[...]).Get"},
+		{"nodot", ""},
+		{"pkg.", ""},
+		{"(*T).M", ""},
+		{"Map[pkg.T].Get", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			sym, err := FromRuntimeName(tt.input)
+			if tt.want == "" {
+				if err == nil {
+					t.Errorf("FromRuntimeName() = %q, want error", sym.Format())
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("FromRuntimeName() error = %v", err)
+			}
+			if got := sym.Format(); got != tt.want {
+				t.Errorf("FromRuntimeName() = %q, want %q", got, tt.want)
+			}
+			if !sym.Equal(MustParse(tt.want)) {
+				t.Errorf("FromRuntimeName() = %+v, differs from Parse(%q)", sym, tt.want)
+			}
+		})
+	}
+}
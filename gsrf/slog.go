@@ -0,0 +1,32 @@
+package gsrf
+
+import "log/slog"
+
+// LogValue implements slog.LogValuer. The symbol is logged as a group with
+// the canonical GSRF string and its structured fields; empty fields are
+// omitted:
+//
+//	slog.Info("handler registered", "handler", sym)
+//	// handler.symbol=net/http.(*Server).Serve handler.package=net/http
+//	// handler.name=Serve handler.receiver=*Server
+func (s *Symbol) LogValue() slog.Value {
+	if s == nil {
+		return slog.Value{}
+	}
+	attrs := []slog.Attr{
+		slog.String("symbol", s.Format()),
+		slog.String("package", s.PackagePath),
+		slog.String("name", s.Name),
+	}
+	if s.Receiver != nil {
+		recv := s.Receiver.TypeName
+		if s.Receiver.IsPointer {
+			recv = "*" + recv
+		}
+		attrs = append(attrs, slog.String("receiver", recv))
+	}
+	if s.Metadata.Position != "" {
+		attrs = append(attrs, slog.String("pos", s.Metadata.Position))
+	}
+	return slog.GroupValue(attrs...)
+}
@@ -0,0 +1,67 @@
+package gsrf
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"reflect"
+	"testing"
+)
+
+var _ slog.LogValuer = (*Symbol)(nil)
+
+func TestSymbol_LogValue(t *testing.T) {
+	tests := []struct {
+		input string
+		want  map[string]any
+	}{
+		{
+			input: "fmt.Println",
+			want:  map[string]any{"symbol": "fmt.Println", "package": "fmt", "name": "Println"},
+		},
+		{
+			input: "net/http.(*Server).Serve{pos:server.go:3000:1}",
+			want: map[string]any{
+				"symbol":   "net/http.(*Server).Serve{pos:server.go:3000:1}",
+				"package":  "net/http",
+				"name":     "Serve",
+				"receiver": "*Server",
+				"pos":      "server.go:3000:1",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			var buf bytes.Buffer
+			logger := slog.New(slog.NewJSONHandler(&buf, nil))
+			logger.Info("frame", "sym", MustParse(tt.input))
+
+			var record map[string]any
+			if err := json.Unmarshal(buf.Bytes(), &record); err != nil {
+				t.Fatalf("invalid log output %q: %v", buf.String(), err)
+			}
+			if got := record["sym"]; !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("sym group = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSymbol_LogValueText(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if len(groups) == 0 && (a.Key == slog.TimeKey || a.Key == slog.LevelKey) {
+				return slog.Attr{}
+			}
+			return a
+		},
+	}))
+	logger.Info("frame", "handler", MustParse("pkg.(T).Run"))
+
+	want := "msg=frame handler.symbol=pkg.(T).Run handler.package=pkg handler.name=Run handler.receiver=T\n"
+	if buf.String() != want {
+		t.Errorf("log line = %q, want %q", buf.String(), want)
+	}
+}
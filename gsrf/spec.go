@@ -0,0 +1,125 @@
+package gsrf
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Construct is one construct of the GSRF syntax: its grammar rule in EBNF
+// and canonical examples. Every example parses and formats back unchanged.
+type Construct struct {
+	Name     string
+	Summary  string
+	Rule     string
+	Examples []string
+}
+
+// Constructs returns the constructs of the GSRF syntax in grammar order.
+// The rules are built from the same markers and character sets the parser
+// and formatter use.
+func Constructs() []Construct {
+	return []Construct{
+		{
+			Name:    "symbol",
+			Summary: "A complete symbol",
+			Rule:    `Symbol = Package "." ( Function | Method | Anon ) [ "@" Context ] [ "{" Metadata "}" ] .`,
+			Examples: []string{
+				"fmt.Println",
+				"net/http.(*Server).Serve",
+				"net.(*netFD).connect@linux{pos:fd_unix.go:57:1}",
+			},
+		},
+		{
+			Name:    "package",
+			Summary: "The import path; quoted when it contains reserved characters",
+			Rule: `Package = ImportPath | QuotedPath .
+QuotedPath = Go string literal .  (required if the path contains any of ` + "`" + quotedPathChars + "`" + `, whitespace or non-printable characters)`,
+			Examples: []string{
+				"github.com/spf13/cobra.(*Command).Execute",
+				`"example.com/a@b".Run`,
+			},
+		},
+		{
+			Name:    "function",
+			Summary: "A package-level function, type, or package initializer",
+			Rule: `Function = Name [ TypeList ] | "init" .
+Name = identifier .`,
+			Examples: []string{
+				"strings.Cut",
+				"database/sql.init",
+				"slices.Index[[]string, string]",
+			},
+		},
+		{
+			Name:    "receiver",
+			Summary: "A method with its value or pointer receiver",
+			Rule: `Method = "(" Receiver ")" "." Name [ TypeList ] .
+Receiver = [ "*" ] TypeName [ "[" TypeArg { "," TypeArg } "]" ] .`,
+			Examples: []string{
+				"bytes.(*Buffer).Write",
+				"time.(Time).String",
+				"pkg.(*List[T]).Push",
+			},
+		},
+		{
+			Name:    "generics",
+			Summary: "Type arguments of an instantiation, or type parameters of a definition",
+			Rule: `TypeList = "[" TypeArg { "," TypeArg } "]" | "[" TypeParam { "," TypeParam } "]" .
+TypeArg = Go type expression | "..." .
+TypeParam = identifier [ Constraint ] .  (an omitted constraint means any)`,
+			Examples: []string{
+				"pkg.Map[string, []int]",
+				"pkg.Map[K comparable, V]",
+				"pkg.(*Cache[K, V]).Get",
+				"pkg.Sort[...]",
+			},
+		},
+		{
+			Name:    "anon",
+			Summary: "A function literal, numbered within its parent",
+			Rule: fmt.Sprintf(`Anon = [ Name ] ( %q | %q ) [ Index ] .
+Index = decimal digits .  (%q is the ASCII spelling of %q)`, anonMarker, anonMarkerASCII, anonMarkerASCII, anonMarker),
+			Examples: []string{
+				"main.main" + anonMarker + "1",
+				"net/http.HandleFunc" + anonMarker,
+			},
+		},
+		{
+			Name:    "context",
+			Summary: "The build context a symbol exists in, such as a GOOS or cgo; asm and cexport mark assembly and cgo export entry points",
+			Rule:    `Context = identifier .`,
+			Examples: []string{
+				"os.(*File).Fd@windows",
+				"runtime.cgocall@cgo",
+				"runtime.memmove@asm",
+				"C.Hello@cexport{raw:_cgoexp_1a2b3c_Hello}",
+			},
+		},
+		{
+			Name:    "metadata",
+			Summary: "Key-value annotations; via, alias and pos are predefined",
+			Rule: `Metadata = Entry { "," Entry } .
+Entry = ( "via" | "alias" | "pos" | Key ) ":" Value .
+Value = any text without "," "{" "}" .
+AliasValue = Value { "` + AliasSeparator + `" Value } .  (an alias chain, outermost first)
+"via" may repeat: the embedding path of a promoted method, outermost first.`,
+			Examples: []string{
+				"io.(*MultiWriter).Write{via:Writer,pos:multi.go:25:1}",
+				"pkg.NewReader{alias:compat.Open>v1.Open}",
+				"pkg.(*Server).Close{via:Conn,via:net.Conn}",
+				"pkg.Handle{budget.latency:50ms,owner:web}",
+			},
+		},
+	}
+}
+
+// LookupConstruct returns the construct with the given name.
+func LookupConstruct(name string) (Construct, bool) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	for _, c := range Constructs() {
+		if c.Name == name {
+			return c, true
+		}
+	}
+	return Construct{}, false
+}
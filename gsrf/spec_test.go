@@ -0,0 +1,31 @@
+package gsrf
+
+import "testing"
+
+func TestConstructs_ExamplesRoundTrip(t *testing.T) {
+	for _, c := range Constructs() {
+		if c.Rule == "" || len(c.Examples) == 0 {
+			t.Errorf("construct %q has no rule or examples", c.Name)
+		}
+		for _, ex := range c.Examples {
+			sym, err := Parse(ex)
+			if err != nil {
+				t.Errorf("%s: Parse(%q) error = %v", c.Name, ex, err)
+				continue
+			}
+			if got := sym.Format(); got != ex {
+				t.Errorf("%s: Parse(%q).Format() = %q", c.Name, ex, got)
+			}
+		}
+	}
+}
+
+func TestLookupConstruct(t *testing.T) {
+	c, ok := LookupConstruct(" Receiver ")
+	if !ok || c.Name != "receiver" {
+		t.Errorf("LookupConstruct(receiver) = %+v, %v", c, ok)
+	}
+	if _, ok := LookupConstruct("nope"); ok {
+		t.Error("LookupConstruct(nope) found a construct")
+	}
+}
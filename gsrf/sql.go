@@ -0,0 +1,32 @@
+package gsrf
+
+import (
+	"database/sql/driver"
+	"fmt"
+)
+
+// Value implements driver.Valuer, storing the symbol as its canonical GSRF
+// string. The zero Symbol, which has no package, is stored as NULL, as is a
+// nil *Symbol.
+func (s Symbol) Value() (driver.Value, error) {
+	if s.PackagePath == "" {
+		return nil, nil
+	}
+	return s.String(), nil
+}
+
+// Scan implements sql.Scanner, parsing a GSRF string read from a string or
+// []byte column. NULL scans as the zero Symbol; scan into a **Symbol to tell
+// NULL apart by a nil pointer instead.
+func (s *Symbol) Scan(src any) error {
+	switch v := src.(type) {
+	case nil:
+		*s = Symbol{}
+		return nil
+	case string:
+		return s.UnmarshalText([]byte(v))
+	case []byte:
+		return s.UnmarshalText(v)
+	}
+	return fmt.Errorf("invalid GSRF symbol: cannot scan %T", src)
+}
@@ -0,0 +1,68 @@
+package gsrf
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"testing"
+)
+
+var (
+	_ driver.Valuer = Symbol{}
+	_ driver.Valuer = (*Symbol)(nil)
+	_ sql.Scanner   = (*Symbol)(nil)
+)
+
+func TestSymbol_ValueScan(t *testing.T) {
+	for _, input := range []string{
+		"net/http.(*Server).Serve",
+		"slices.Sort[[]int]@linux{pos:sort.go:10:1}",
+		`"example.com/a@b".Run`,
+	} {
+		v, err := MustParse(input).Value()
+		if err != nil {
+			t.Fatalf("Value(%q) error = %v", input, err)
+		}
+		if v != input {
+			t.Errorf("Value(%q) = %v", input, v)
+		}
+		for _, src := range []any{v, []byte(v.(string))} {
+			var got Symbol
+			if err := got.Scan(src); err != nil {
+				t.Fatalf("Scan(%T) error = %v", src, err)
+			}
+			if got.String() != input {
+				t.Errorf("Scan(%T) = %q, want %q", src, got.String(), input)
+			}
+		}
+	}
+}
+
+func TestSymbol_ValueScan_Null(t *testing.T) {
+	if v, err := (Symbol{}).Value(); v != nil || err != nil {
+		t.Errorf("zero Symbol Value() = %v, %v; want NULL", v, err)
+	}
+	got := *MustParse("fmt.Println")
+	if err := got.Scan(nil); err != nil {
+		t.Fatal(err)
+	}
+	if got.PackagePath != "" || got.Name != "" {
+		t.Errorf("Scan(nil) = %+v, want the zero Symbol", got)
+	}
+}
+
+func TestSymbol_Scan_Errors(t *testing.T) {
+	for _, src := range []any{42, 1.5, true, "", []byte("not a symbol")} {
+		var got Symbol
+		if err := got.Scan(src); err == nil {
+			t.Errorf("Scan(%#v) succeeded", src)
+		}
+	}
+}
+
+func TestSymbol_Value_NilPointer(t *testing.T) {
+	var sym *Symbol
+	v, err := driver.DefaultParameterConverter.ConvertValue(sym)
+	if v != nil || err != nil {
+		t.Errorf("ConvertValue(nil *Symbol) = %v, %v; want NULL", v, err)
+	}
+}
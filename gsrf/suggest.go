@@ -0,0 +1,85 @@
+package gsrf
+
+import (
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// Suggest returns up to n candidates closest to input, for "did you mean"
+// corrections of mistyped symbols. Input is parsed leniently with
+// ParsePartial and compared by component: the edit distances of the package
+// path, receiver type and name are added, with the package compared against
+// the candidate's trailing path elements too, so "http.Serve" suggests
+// "net/http.Serve". Candidates more than one edit per four characters of
+// those components away (at least 2 edits) are not suggested. Results are
+// ordered by distance, then by candidate order.
+func Suggest(input string, candidates []*Symbol, n int) []*Symbol {
+	if n <= 0 {
+		return nil
+	}
+	input = strings.TrimSpace(input)
+	sym, _ := ParsePartial(input)
+	size := utf8.RuneCountInString(input)
+	if sym != nil {
+		size = utf8.RuneCountInString(sym.PackagePath) + utf8.RuneCountInString(sym.Name)
+		if sym.Receiver != nil {
+			size += utf8.RuneCountInString(sym.Receiver.TypeName)
+		}
+	}
+	limit := max(size/4, 2)
+
+	type scored struct {
+		sym  *Symbol
+		dist int
+	}
+	var matches []scored
+	seen := make(map[string]bool)
+	for _, c := range candidates {
+		key := canonicalKey(c)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		var d int
+		if sym == nil {
+			d = levenshtein(input, key)
+		} else {
+			d = suggestDistance(sym, c)
+		}
+		if d <= limit {
+			matches = append(matches, scored{c, d})
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool { return matches[i].dist < matches[j].dist })
+
+	out := make([]*Symbol, 0, min(n, len(matches)))
+	for _, m := range matches[:min(n, len(matches))] {
+		out = append(out, m.sym)
+	}
+	return out
+}
+
+// suggestDistance adds the edit distances of the components of a and b.
+func suggestDistance(a, b *Symbol) int {
+	d := packageDistance(a.PackagePath, b.PackagePath) + levenshtein(a.Name, b.Name)
+	var ra, rb string
+	if a.Receiver != nil {
+		ra = a.Receiver.TypeName
+	}
+	if b.Receiver != nil {
+		rb = b.Receiver.TypeName
+	}
+	return d + levenshtein(ra, rb)
+}
+
+// packageDistance is the edit distance between two package paths, or
+// between a and as many trailing elements of b as a has, if smaller.
+func packageDistance(a, b string) int {
+	d := levenshtein(a, b)
+	elems := strings.Count(a, "/") + 1
+	if parts := strings.Split(b, "/"); len(parts) > elems {
+		d = min(d, levenshtein(a, strings.Join(parts[len(parts)-elems:], "/")))
+	}
+	return d
+}
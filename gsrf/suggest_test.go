@@ -0,0 +1,47 @@
+package gsrf
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSuggest(t *testing.T) {
+	var candidates []*Symbol
+	for _, s := range []string{
+		"net/http.(*Server).Serve",
+		"net/http.(*Server).Shutdown",
+		"net/http.ListenAndServe",
+		"net/http.Serve",
+		"net/rpc.ServeConn",
+		"net/http.Serve@linux",
+		"encoding/json.Marshal",
+	} {
+		candidates = append(candidates, MustParse(s))
+	}
+	suggest := func(input string, n int) []string {
+		var out []string
+		for _, s := range Suggest(input, candidates, n) {
+			out = append(out, s.String())
+		}
+		return out
+	}
+
+	tests := []struct {
+		input string
+		n     int
+		want  []string
+	}{
+		{"net/http.(*Server).Serv", 3, []string{"net/http.(*Server).Serve"}},
+		{"net/htp.Serve", 2, []string{"net/http.Serve"}},
+		{"http.Serve", 1, []string{"net/http.Serve"}},
+		{"encoding/json.Marshall", 5, []string{"encoding/json.Marshal"}},
+		{"net/http.(*Server).Serve(", 1, []string{"net/http.(*Server).Serve"}},
+		{"database/sql.Open", 3, nil},
+		{"net/http.Serve", 0, nil},
+	}
+	for _, tt := range tests {
+		if got := suggest(tt.input, tt.n); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Suggest(%q, %d) = %q, want %q", tt.input, tt.n, got, tt.want)
+		}
+	}
+}
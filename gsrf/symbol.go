@@ -2,8 +2,13 @@
 package gsrf
 
 import (
-	"fmt"
+	"slices"
+	"strconv"
 	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/kis9a/gsrf/token"
 )
 
 // Symbol represents a parsed GSRF symbol with all features.
@@ -17,7 +22,7 @@ type Symbol struct {
 	AnonParent  string            // Parent symbol for anonymous functions
 	AnonIndex   int               // Index for anonymous functions (0 = no index)
 
-	// Extended fields (v1.1)
+	// Extended fields
 	TypeParams []TypeParam        // Type parameters with constraints
 	TypeArgs   []string           // Type arguments (for instantiation)
 	Context    string             // Context modifier (@linux, @cgo, etc)
@@ -39,103 +44,233 @@ type TypeParam struct {
 
 // Metadata represents symbol metadata.
 type Metadata struct {
-	Via      string            // Embedded source (promoted methods)
-	Alias    string            // Alias source
+	Via      []string          // Embedding path of a promoted method, outermost embedded type first
+	Alias    string            // Alias sources, outermost first, joined by AliasSeparator
 	Position string            // Source position (file:line:col)
 	Custom   map[string]string // Additional custom metadata
 }
 
-// Format returns the formatted GSRF string representation.
-func (s *Symbol) Format() string {
-	var result strings.Builder
-	
+// Anonymous function markers. The ASCII form is accepted by Parse and emitted
+// by ProfileASCII for pipelines that mangle non-ASCII text.
+// They are shared with package token, so adapters scanning with it agree.
+const (
+	anonMarker      = token.AnonMarker
+	anonMarkerASCII = token.AnonMarkerASCII
+)
+
+// FormatOptions controls which parts of a symbol are emitted. The zero value
+// produces the full spec notation.
+type FormatOptions struct {
+	OmitMetadata bool           // Drop the {...} metadata block
+	OmitContext  bool           // Drop the @context modifier
+	OmitTypeArgs bool           // Drop type arguments and parameters, including the receiver's
+	ShortPackage bool           // Emit only the last element of the package path
+	ASCII        bool           // Use %lit instead of ·lit, ... when truncating, and quote non-ASCII paths
+	SortMetadata bool           // Order all metadata entries by key instead of spec order
+	MaxLength    int            // Truncate output longer than this many runes (0 = no limit)
+	Receiver     ReceiverPolicy // How receiver pointerness is rendered
+	DigestOver   int            // Replace type argument lists by digests when output exceeds this many bytes (0 = never)
+	PathStyle    PathStyle      // Separators of the position metadata
+}
+
+// ReceiverPolicy controls how method receivers are rendered.
+type ReceiverPolicy int
+
+const (
+	ReceiverAsIs    ReceiverPolicy = iota // Keep the receiver as parsed
+	ReceiverValue                         // Always render value receivers: (T)
+	ReceiverPointer                       // Always render pointer receivers: (*T)
+)
+
+// Format returns the formatted GSRF string representation. Options are
+// applied in order, so later options override earlier ones.
+func (s *Symbol) Format(opts ...FormatOption) string {
+	return s.FormatWith(collectOptions(opts))
+}
+
+// collectOptions applies opts in order to zero options.
+func collectOptions(opts []FormatOption) FormatOptions {
+	var o FormatOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// FormatAs returns the GSRF string representation using the given profile.
+func (s *Symbol) FormatAs(p FormatProfile) string {
+	return s.FormatWith(p.Options())
+}
+
+// FormatRelative formats the symbol like Format, but writes package paths
+// inside the module modulePath relative to the module root, the way go
+// commands accept them: "github.com/org/app/internal/auth.Login" becomes
+// "./internal/auth.Login", and the root package itself is ".". Symbols of
+// other modules are formatted unchanged.
+func (s *Symbol) FormatRelative(modulePath string, opts ...FormatOption) string {
+	modulePath = strings.TrimSuffix(modulePath, "/")
+	rel, ok := strings.CutPrefix(s.PackagePath, modulePath)
+	if modulePath == "" || !ok || (rel != "" && rel[0] != '/') {
+		return s.Format(opts...)
+	}
+	c := *s
+	c.PackagePath = "." + rel
+	return c.Format(opts...)
+}
+
+// FormatWith returns the GSRF string representation restricted by opts.
+func (s *Symbol) FormatWith(opts FormatOptions) string {
+	if opts.DigestOver > 0 {
+		inner := opts
+		inner.DigestOver = 0
+		inner.MaxLength = 0
+		out := s.FormatWith(inner)
+		if len(out) > opts.DigestOver {
+			out = s.withDigests(opts.ASCII).FormatWith(inner)
+		}
+		if opts.MaxLength > 0 {
+			return truncate(out, opts.MaxLength, opts.ASCII)
+		}
+		return out
+	}
+
+	out := s.appendFormat(make([]byte, 0, 64), opts)
+	if opts.MaxLength > 0 {
+		return truncate(string(out), opts.MaxLength, opts.ASCII)
+	}
+	return string(out)
+}
+
+// AppendFormat appends the GSRF string representation to dst and returns
+// the extended buffer, like Format but without allocating a new string, so
+// hot paths can format into a reused buffer:
+//
+//	buf = sym.AppendFormat(buf[:0])
+//
+// Formatting allocates only when the symbol has more than a few metadata
+// entries to sort, or when MaxLength or DigestOver must inspect the output.
+func (s *Symbol) AppendFormat(dst []byte, opts ...FormatOption) []byte {
+	var o FormatOptions
+	if len(opts) > 0 {
+		// Only materialize options when given; &o escapes to the closures.
+		o = collectOptions(opts)
+	}
+	if o.MaxLength > 0 || o.DigestOver > 0 {
+		return append(dst, s.FormatWith(o)...)
+	}
+	return s.appendFormat(dst, o)
+}
+
+// metaEntry is one key:value entry of a metadata block.
+type metaEntry struct {
+	key, value string
+}
+
+// appendFormat appends the symbol restricted by opts, ignoring MaxLength and
+// DigestOver.
+func (s *Symbol) appendFormat(dst []byte, opts FormatOptions) []byte {
 	// Package path
-	result.WriteString(s.PackagePath)
-	result.WriteByte('.')
+	pkg := s.PackagePath
+	if opts.ShortPackage {
+		pkg = pkg[strings.LastIndex(pkg, "/")+1:]
+	}
+	dst = appendPackagePath(dst, pkg, opts.ASCII)
+	dst = append(dst, '.')
 
 	// Receiver (for methods)
 	if s.Receiver != nil {
-		result.WriteByte('(')
-		if s.Receiver.IsPointer {
-			result.WriteByte('*')
+		dst = append(dst, '(')
+		if opts.Receiver == ReceiverPointer || (s.Receiver.IsPointer && opts.Receiver == ReceiverAsIs) {
+			dst = append(dst, '*')
 		}
-		result.WriteString(s.Receiver.TypeName)
-		
+		dst = append(dst, s.Receiver.TypeName...)
+
 		// Generic receiver type args
-		if len(s.Receiver.TypeArgs) > 0 {
-			result.WriteByte('[')
-			result.WriteString(strings.Join(s.Receiver.TypeArgs, ", "))
-			result.WriteByte(']')
+		if len(s.Receiver.TypeArgs) > 0 && !opts.OmitTypeArgs {
+			dst = appendTypeList(dst, s.Receiver.TypeArgs, nil)
 		}
-		
-		result.WriteString(").")
+
+		dst = append(dst, ")."...)
 	}
 
 	// Function/method name
+	dst = append(dst, s.Name...)
 	if s.IsAnonymous {
 		// Anonymous function: use middle dot notation
-		result.WriteString(s.Name)
-		result.WriteString("·lit")
+		if opts.ASCII {
+			dst = append(dst, anonMarkerASCII...)
+		} else {
+			dst = append(dst, anonMarker...)
+		}
 		if s.AnonIndex > 0 {
-			result.WriteString(fmt.Sprintf("%d", s.AnonIndex))
+			dst = strconv.AppendInt(dst, int64(s.AnonIndex), 10)
 		}
-	} else {
-		result.WriteString(s.Name)
 	}
 
 	// Type parameters or arguments
-	if len(s.TypeArgs) > 0 {
-		// Type arguments (instantiation) - takes precedence
-		result.WriteByte('[')
-		result.WriteString(strings.Join(s.TypeArgs, ", "))
-		result.WriteByte(']')
-	} else if len(s.TypeParams) > 0 {
-		// Type parameters (definition)
-		result.WriteByte('[')
-		for i, tp := range s.TypeParams {
-			if i > 0 {
-				result.WriteString(", ")
-			}
-			result.WriteString(tp.Name)
-			if tp.Constraint != "" && tp.Constraint != "any" {
-				result.WriteByte(' ')
-				result.WriteString(tp.Constraint)
-			}
-		}
-		result.WriteByte(']')
+	if !opts.OmitTypeArgs {
+		dst = appendTypeList(dst, s.TypeArgs, s.TypeParams)
 	}
 
 	// Context modifier (@linux, @cgo, etc)
-	if s.Context != "" {
-		result.WriteByte('@')
-		result.WriteString(s.Context)
+	if s.Context != "" && !opts.OmitContext {
+		dst = append(dst, '@')
+		dst = append(dst, s.Context...)
 	}
 
 	// Metadata
-	if hasMetadata(s.Metadata) {
-		var metaParts []string
-		
-		if s.Metadata.Via != "" {
-			metaParts = append(metaParts, "via:"+s.Metadata.Via)
+	if hasMetadata(s.Metadata) && !opts.OmitMetadata {
+		var buf [8]metaEntry
+		entries := buf[:0]
+		for _, via := range s.Metadata.Via {
+			entries = append(entries, metaEntry{"via", via})
 		}
 		if s.Metadata.Alias != "" {
-			metaParts = append(metaParts, "alias:"+s.Metadata.Alias)
+			entries = append(entries, metaEntry{"alias", s.Metadata.Alias})
 		}
 		if s.Metadata.Position != "" {
-			metaParts = append(metaParts, "pos:"+s.Metadata.Position)
+			entries = append(entries, metaEntry{"pos", opts.PathStyle.Apply(s.Metadata.Position)})
 		}
+		custom := len(entries)
 		for k, v := range s.Metadata.Custom {
-			metaParts = append(metaParts, k+":"+v)
+			entries = append(entries, metaEntry{k, v})
 		}
-		
-		if len(metaParts) > 0 {
-			result.WriteByte('{')
-			result.WriteString(strings.Join(metaParts, ","))
-			result.WriteByte('}')
+		byKey := func(a, b metaEntry) int { return strings.Compare(a.key, b.key) }
+		slices.SortFunc(entries[custom:], byKey)
+		if opts.SortMetadata {
+			// Stable by key, so repeated via entries keep their path order.
+			slices.SortStableFunc(entries, byKey)
 		}
+
+		dst = append(dst, '{')
+		for i, e := range entries {
+			if i > 0 {
+				dst = append(dst, ',')
+			}
+			dst = append(dst, e.key...)
+			dst = append(dst, ':')
+			dst = append(dst, e.value...)
+		}
+		dst = append(dst, '}')
 	}
+	return dst
+}
 
-	return result.String()
+// truncate shortens s to at most max runes, marking the cut with an ellipsis.
+func truncate(s string, max int, ascii bool) string {
+	runes := []rune(s)
+	if len(runes) <= max {
+		return s
+	}
+	ellipsis := "…"
+	if ascii {
+		ellipsis = "..."
+	}
+	keep := max - len([]rune(ellipsis))
+	if keep < 0 {
+		keep = 0
+	}
+	return string(runes[:keep]) + ellipsis
 }
 
 // String implements the Stringer interface.
@@ -143,7 +278,117 @@ func (s *Symbol) String() string {
 	return s.Format()
 }
 
+// Clone returns a deep copy of the symbol. The copy shares no slices, maps,
+// or receiver with the original, so either can be mutated independently.
+func (s *Symbol) Clone() *Symbol {
+	if s == nil {
+		return nil
+	}
+	c := *s
+	c.TypeArgs = cloneStrings(s.TypeArgs)
+	if s.TypeParams != nil {
+		c.TypeParams = append([]TypeParam(nil), s.TypeParams...)
+	}
+	if s.Receiver != nil {
+		r := *s.Receiver
+		r.TypeArgs = cloneStrings(s.Receiver.TypeArgs)
+		c.Receiver = &r
+	}
+	c.Metadata.Via = cloneStrings(s.Metadata.Via)
+	if s.Metadata.Custom != nil {
+		c.Metadata.Custom = make(map[string]string, len(s.Metadata.Custom))
+		for k, v := range s.Metadata.Custom {
+			c.Metadata.Custom[k] = v
+		}
+	}
+	return &c
+}
+
+// cloneStrings copies a string slice, preserving nil.
+func cloneStrings(s []string) []string {
+	if s == nil {
+		return nil
+	}
+	return append(make([]string, 0, len(s)), s...)
+}
+
 // hasMetadata checks if the metadata has any values set
 func hasMetadata(m Metadata) bool {
-	return m.Via != "" || m.Alias != "" || m.Position != "" || len(m.Custom) > 0
-}
\ No newline at end of file
+	return len(m.Via) > 0 || m.Alias != "" || m.Position != "" || len(m.Custom) > 0
+}
+
+// appendTypeList appends the bracketed type arguments, or the type
+// parameters when there are no arguments.
+func appendTypeList(dst []byte, args []string, params []TypeParam) []byte {
+	if len(args) > 0 {
+		// Type arguments (instantiation) - takes precedence
+		dst = append(dst, '[')
+		for i, arg := range args {
+			if i > 0 {
+				dst = append(dst, ", "...)
+			}
+			dst = append(dst, arg...)
+		}
+		dst = append(dst, ']')
+	} else if len(params) > 0 {
+		// Type parameters (definition)
+		dst = append(dst, '[')
+		for i, tp := range params {
+			if i > 0 {
+				dst = append(dst, ", "...)
+			}
+			dst = append(dst, tp.Name...)
+			if tp.Constraint != "" && tp.Constraint != "any" {
+				dst = append(dst, ' ')
+				dst = append(dst, tp.Constraint...)
+			}
+		}
+		dst = append(dst, ']')
+	}
+	return dst
+}
+
+// appendPackagePath appends the package path, quoting it when it contains
+// characters that would otherwise be read as GSRF syntax. In ASCII mode,
+// paths with non-ASCII characters are quoted too, and quoted paths escape
+// them as \u sequences.
+func appendPackagePath(dst []byte, path string, ascii bool) []byte {
+	switch {
+	case ascii && (NeedsQuoting(path) || !isASCII(path)):
+		return strconv.AppendQuoteToASCII(dst, path)
+	case NeedsQuoting(path):
+		return strconv.AppendQuote(dst, path)
+	}
+	return append(dst, path...)
+}
+
+// isASCII reports whether s contains only ASCII characters.
+func isASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= utf8.RuneSelf {
+			return false
+		}
+	}
+	return true
+}
+
+// quotedPathChars are the characters that force a package path into quoted
+// form, besides whitespace and non-printable characters.
+const quotedPathChars = `()[]{}@,·"\`
+
+// NeedsQuoting reports whether a package path must be written in quoted
+// form to parse back unambiguously.
+func NeedsQuoting(path string) bool {
+	if strings.HasPrefix(path, `"`) {
+		return true
+	}
+	for _, r := range path {
+		switch {
+		case strings.ContainsRune(quotedPathChars, r):
+			return true
+		case unicode.IsSpace(r) || !unicode.IsPrint(r):
+			return true
+		}
+	}
+	return false
+}
@@ -1,6 +1,7 @@
 package gsrf
 
 import (
+	"reflect"
 	"testing"
 )
 
@@ -103,7 +104,7 @@ func TestSymbol_Format(t *testing.T) {
 					IsPointer: true,
 				},
 				Metadata: Metadata{
-					Via:      "Writer",
+					Via:      []string{"Writer"},
 					Position: "multi.go:25:1",
 				},
 			},
@@ -122,7 +123,7 @@ func TestSymbol_Format(t *testing.T) {
 				TypeArgs: []string{"string", "int"},
 				Context:  "linux",
 				Metadata: Metadata{
-					Via:      "BaseServer[T, U]",
+					Via:      []string{"BaseServer[T, U]"},
 					Position: "server.go:100:5",
 					Custom: map[string]string{
 						"deprecated": "true",
@@ -190,4 +191,185 @@ func TestSymbol_Format(t *testing.T) {
 			}
 		})
 	}
-}
\ No newline at end of file
+}
+
+func TestSymbol_FormatAs(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		profile FormatProfile
+		want    string
+	}{
+		{name: "default", input: "main.main·lit2", profile: ProfileDefault, want: "main.main·lit2"},
+		{name: "ascii", input: "main.main·lit2", profile: ProfileASCII, want: "main.main%lit2"},
+		{name: "ascii without index", input: "main.main·lit", profile: ProfileASCII, want: "main.main%lit"},
+		{name: "ascii non-anonymous", input: "fmt.Println", profile: ProfileASCII, want: "fmt.Println"},
+		{name: "ascii quoted path", input: `"example.com/a·b".Run`, profile: ProfileASCII, want: `"example.com/a\u00b7b".Run`},
+		{name: "ascii non-ASCII path", input: "example.com/café.Run", profile: ProfileASCII, want: `"example.com/caf\u00e9".Run`},
+		{name: "default non-ASCII path", input: "example.com/café.Run", profile: ProfileDefault, want: "example.com/café.Run"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := MustParse(tt.input).FormatAs(tt.profile)
+			if got != tt.want {
+				t.Errorf("FormatAs() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParse_ASCIIAnonymous(t *testing.T) {
+	ascii := MustParse("main.handler%lit3")
+	unicode := MustParse("main.handler·lit3")
+	if ascii.Format() != unicode.Format() {
+		t.Errorf("Parse(%%lit) = %q, want %q", ascii.Format(), unicode.Format())
+	}
+	if !ascii.IsAnonymous || ascii.AnonIndex != 3 || ascii.AnonParent != "main.handler" {
+		t.Errorf("Parse(%%lit) = %+v", ascii)
+	}
+}
+
+func TestSymbol_FormatWith(t *testing.T) {
+	sym := MustParse("github.com/user/repo.(*Cache[K, V]).Get[string]@linux{via:Base}")
+
+	tests := []struct {
+		name string
+		opts FormatOptions
+		want string
+	}{
+		{name: "zero options", opts: FormatOptions{}, want: "github.com/user/repo.(*Cache[K, V]).Get[string]@linux{via:Base}"},
+		{name: "omit metadata", opts: FormatOptions{OmitMetadata: true}, want: "github.com/user/repo.(*Cache[K, V]).Get[string]@linux"},
+		{name: "omit context", opts: FormatOptions{OmitContext: true}, want: "github.com/user/repo.(*Cache[K, V]).Get[string]{via:Base}"},
+		{name: "omit type args", opts: FormatOptions{OmitTypeArgs: true}, want: "github.com/user/repo.(*Cache).Get@linux{via:Base}"},
+		{name: "short package", opts: FormatOptions{ShortPackage: true}, want: "repo.(*Cache[K, V]).Get[string]@linux{via:Base}"},
+		{
+			name: "compact",
+			opts: FormatOptions{OmitMetadata: true, OmitContext: true, OmitTypeArgs: true, ShortPackage: true},
+			want: "repo.(*Cache).Get",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := sym.FormatWith(tt.opts); got != tt.want {
+				t.Errorf("FormatWith() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSymbol_Clone(t *testing.T) {
+	orig := MustParse("pkg.(*Cache[K, V]).Get[string]@linux{via:Base,team:core}")
+	orig.TypeParams = []TypeParam{{Name: "T", Constraint: "any"}}
+
+	c := orig.Clone()
+	if !c.Equal(orig) {
+		t.Fatalf("Clone() = %+v, want %+v", c, orig)
+	}
+
+	c.Receiver.TypeName = "Other"
+	c.Receiver.TypeArgs[0] = "X"
+	c.TypeArgs[0] = "int"
+	c.TypeParams[0].Name = "U"
+	c.Metadata.Custom["team"] = "infra"
+
+	if orig.Receiver.TypeName != "Cache" || orig.Receiver.TypeArgs[0] != "K" {
+		t.Errorf("Clone() aliases Receiver: %+v", orig.Receiver)
+	}
+	if orig.TypeArgs[0] != "string" || orig.TypeParams[0].Name != "T" {
+		t.Errorf("Clone() aliases type lists: %v %v", orig.TypeArgs, orig.TypeParams)
+	}
+	if orig.Metadata.Custom["team"] != "core" {
+		t.Errorf("Clone() aliases Metadata.Custom: %v", orig.Metadata.Custom)
+	}
+
+	var nilSym *Symbol
+	if nilSym.Clone() != nil {
+		t.Error("Clone() of nil symbol is not nil")
+	}
+}
+
+func TestSymbol_FormatRelative(t *testing.T) {
+	tests := []struct {
+		input  string
+		module string
+		want   string
+	}{
+		{"github.com/org/app/internal/auth.Login", "github.com/org/app", "./internal/auth.Login"},
+		{"github.com/org/app/internal/auth.(*Session).Close@linux", "github.com/org/app/", "./internal/auth.(*Session).Close@linux"},
+		{"github.com/org/app.Run", "github.com/org/app", "..Run"},
+		{"github.com/org/apps/x.Run", "github.com/org/app", "github.com/org/apps/x.Run"},
+		{"net/http.(*Server).Serve", "github.com/org/app", "net/http.(*Server).Serve"},
+		{"github.com/org/app/x.Run", "", "github.com/org/app/x.Run"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			if got := MustParse(tt.input).FormatRelative(tt.module); got != tt.want {
+				t.Errorf("FormatRelative(%q) = %q, want %q", tt.module, got, tt.want)
+			}
+		})
+	}
+
+	sym := MustParse("github.com/org/app/auth.Login{pos:auth.go:3:1}")
+	if got := sym.FormatRelative("github.com/org/app", WithOptions(FormatOptions{OmitMetadata: true})); got != "./auth.Login" {
+		t.Errorf("FormatRelative() with options = %q", got)
+	}
+}
+
+func TestSymbol_ViaPath(t *testing.T) {
+	sym := MustParse("pkg.(*T).Close{via:Outer,via:Inner,owner:io}")
+	if !reflect.DeepEqual(sym.Metadata.Via, []string{"Outer", "Inner"}) {
+		t.Fatalf("Via = %q, want [Outer Inner]", sym.Metadata.Via)
+	}
+	if got := sym.String(); got != "pkg.(*T).Close{via:Outer,via:Inner,owner:io}" {
+		t.Errorf("String() = %q", got)
+	}
+	if got := sym.FormatWith(FormatOptions{SortMetadata: true}); got != "pkg.(*T).Close{owner:io,via:Outer,via:Inner}" {
+		t.Errorf("sorted = %q", got)
+	}
+	if MustParse("pkg.(*T).Close{via:Inner,via:Outer}").Equal(sym) {
+		t.Error("Equal() ignores via order")
+	}
+
+	if v, _ := sym.Get(FieldMetadataVia); v != "Outer, Inner" {
+		t.Errorf("Get(via) = %q", v)
+	}
+	if err := sym.Set(FieldMetadataVia, "A, B"); err != nil || !reflect.DeepEqual(sym.Metadata.Via, []string{"A", "B"}) {
+		t.Errorf("Set(via) = %q, %v", sym.Metadata.Via, err)
+	}
+	// A comma inside a via entry would not survive a round trip.
+	if err := sym.Set(FieldMetadataVia, "A, B[K, V]"); err == nil {
+		t.Errorf("Set(via) accepted a reserved character: %q", sym.Metadata.Via)
+	}
+}
+
+func TestSymbol_AppendFormat(t *testing.T) {
+	inputs := []string{
+		"fmt.Println",
+		"main.handler·lit3",
+		`"example.com/a@b".Run`,
+		"pkg.(*Server[T, U]).Handle[string, int]@linux{via:Base,via:Inner,pos:server.go:100:5,team:core,deprecated:true}",
+		"pkg.Process[T comparable, U]",
+	}
+	for _, in := range inputs {
+		sym := MustParse(in)
+		buf := []byte("> ")
+		if got := string(sym.AppendFormat(buf)); got != "> "+sym.Format() {
+			t.Errorf("AppendFormat() = %q, want %q", got, "> "+sym.Format())
+		}
+		opts := []FormatOption{WithProfile(ProfileASCII), WithOptions(FormatOptions{SortMetadata: true, MaxLength: 20})}
+		if got := string(sym.AppendFormat(nil, opts...)); got != sym.Format(opts...) {
+			t.Errorf("AppendFormat(opts) = %q, want %q", got, sym.Format(opts...))
+		}
+	}
+
+	sym := MustParse(inputs[3])
+	buf := make([]byte, 0, 256)
+	allocs := testing.AllocsPerRun(100, func() {
+		buf = sym.AppendFormat(buf[:0])
+	})
+	if allocs != 0 {
+		t.Errorf("AppendFormat() allocated %v times, want 0", allocs)
+	}
+}
@@ -0,0 +1,104 @@
+package gsrf
+
+// SymbolTable holds a set of symbols indexed by package path, name, receiver
+// type and fingerprint, so that each lookup costs a map access instead of a
+// scan. Symbols are distinct by fingerprint; adding a symbol whose
+// fingerprint is already present keeps the first one. Slices returned by the
+// lookup methods are shared with the table and must not be modified.
+type SymbolTable struct {
+	symbols       []*Symbol
+	byPackage     map[string][]*Symbol
+	byName        map[string][]*Symbol
+	byReceiver    map[receiverKey][]*Symbol
+	byFingerprint map[uint64]*Symbol
+}
+
+type receiverKey struct {
+	pkg, typeName string
+}
+
+// NewSymbolTable returns a table holding syms.
+func NewSymbolTable(syms ...*Symbol) *SymbolTable {
+	t := &SymbolTable{
+		byPackage:     make(map[string][]*Symbol),
+		byName:        make(map[string][]*Symbol),
+		byReceiver:    make(map[receiverKey][]*Symbol),
+		byFingerprint: make(map[uint64]*Symbol, len(syms)),
+	}
+	for _, sym := range syms {
+		t.Add(sym)
+	}
+	return t
+}
+
+// Add indexes sym and reports whether it was added, i.e. no symbol with the
+// same fingerprint was present.
+func (t *SymbolTable) Add(sym *Symbol) bool {
+	fp := sym.Fingerprint()
+	if _, ok := t.byFingerprint[fp]; ok {
+		return false
+	}
+	t.byFingerprint[fp] = sym
+	t.symbols = append(t.symbols, sym)
+	t.byPackage[sym.PackagePath] = append(t.byPackage[sym.PackagePath], sym)
+	t.byName[sym.Name] = append(t.byName[sym.Name], sym)
+	if sym.Receiver != nil {
+		key := receiverKey{sym.PackagePath, sym.Receiver.TypeName}
+		t.byReceiver[key] = append(t.byReceiver[key], sym)
+	}
+	return true
+}
+
+// Len returns the number of symbols in the table.
+func (t *SymbolTable) Len() int {
+	return len(t.symbols)
+}
+
+// Symbols returns every symbol in the order added.
+func (t *SymbolTable) Symbols() []*Symbol {
+	return t.symbols
+}
+
+// ByPackage returns the symbols of the package with the given import path.
+func (t *SymbolTable) ByPackage(path string) []*Symbol {
+	return t.byPackage[path]
+}
+
+// ByName returns the symbols with the given function or method name, in any
+// package.
+func (t *SymbolTable) ByName(name string) []*Symbol {
+	return t.byName[name]
+}
+
+// ByReceiver returns the methods of type typeName in package pkg, both with
+// pointer and value receivers. Type arguments are not part of the key, so
+// all instantiations of a generic type are returned together.
+func (t *SymbolTable) ByReceiver(pkg, typeName string) []*Symbol {
+	return t.byReceiver[receiverKey{pkg, typeName}]
+}
+
+// ByFingerprint returns the symbol with fingerprint fp.
+func (t *SymbolTable) ByFingerprint(fp uint64) (*Symbol, bool) {
+	sym, ok := t.byFingerprint[fp]
+	return sym, ok
+}
+
+// Contains reports whether a symbol with the same fingerprint as sym is in
+// the table.
+func (t *SymbolTable) Contains(sym *Symbol) bool {
+	_, ok := t.byFingerprint[sym.Fingerprint()]
+	return ok
+}
+
+// Packages returns the distinct package paths in the order first added.
+func (t *SymbolTable) Packages() []string {
+	var pkgs []string
+	seen := make(map[string]bool, len(t.byPackage))
+	for _, sym := range t.symbols {
+		if !seen[sym.PackagePath] {
+			seen[sym.PackagePath] = true
+			pkgs = append(pkgs, sym.PackagePath)
+		}
+	}
+	return pkgs
+}
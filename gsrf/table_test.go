@@ -0,0 +1,51 @@
+package gsrf
+
+import "testing"
+
+func TestSymbolTable(t *testing.T) {
+	table := NewSymbolTable(
+		MustParse("net/http.(*Server).Serve"),
+		MustParse("net/http.(Server).String"),
+		MustParse("net/http.ListenAndServe"),
+		MustParse("example.com/app.(*Server).Serve"),
+		MustParse("example.com/app.(*List[int]).Push"),
+		MustParse("example.com/app.(*List[string]).Push"),
+	)
+	if table.Len() != 6 {
+		t.Fatalf("Len() = %d, want 6", table.Len())
+	}
+
+	if got := len(table.ByPackage("net/http")); got != 3 {
+		t.Errorf("ByPackage(net/http) returned %d symbols, want 3", got)
+	}
+	if got := table.ByName("Serve"); len(got) != 2 || got[1].PackagePath != "example.com/app" {
+		t.Errorf("ByName(Serve) = %v", got)
+	}
+	if got := len(table.ByReceiver("net/http", "Server")); got != 2 {
+		t.Errorf("ByReceiver(net/http, Server) returned %d symbols, want 2", got)
+	}
+	if got := len(table.ByReceiver("example.com/app", "List")); got != 2 {
+		t.Errorf("ByReceiver(example.com/app, List) returned %d symbols, want 2", got)
+	}
+	if got := table.ByPackage("fmt"); got != nil {
+		t.Errorf("ByPackage(fmt) = %v, want nil", got)
+	}
+
+	sym := MustParse("net/http.ListenAndServe{pos:server.go:1:1}")
+	if !table.Contains(sym) {
+		t.Error("Contains() ignores metadata differences, want true")
+	}
+	if table.Add(sym) {
+		t.Error("Add() of a symbol already present returned true")
+	}
+	found, ok := table.ByFingerprint(sym.Fingerprint())
+	if !ok || found.Metadata.Position != "" {
+		t.Errorf("ByFingerprint() = %v, %v; want the first symbol added", found, ok)
+	}
+
+	want := []string{"net/http", "example.com/app"}
+	got := table.Packages()
+	if len(got) != len(want) || got[0] != want[0] || got[1] != want[1] {
+		t.Errorf("Packages() = %v, want %v", got, want)
+	}
+}
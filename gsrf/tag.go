@@ -0,0 +1,83 @@
+package gsrf
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// MetadataTags is the custom metadata key holding a symbol's tags, sorted
+// and joined by "+": pkg.Fn{tags:deprecated+team:payments}.
+const MetadataTags = "tags"
+
+// ValidateTag reports whether tag is a legal tag: a name, or a namespace
+// and a name separated by ":", as in "team:payments". Names and namespaces
+// consist of letters, digits, "_", "-", "." and "/".
+func ValidateTag(tag string) error {
+	ns, name, namespaced := strings.Cut(tag, ":")
+	if tag == "" || namespaced && (ns == "" || name == "") {
+		return fmt.Errorf("invalid tag %q: empty name or namespace", tag)
+	}
+	for _, r := range ns + name {
+		if r != '_' && r != '-' && r != '.' && r != '/' && !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			return fmt.Errorf("invalid tag %q: invalid character %q", tag, r)
+		}
+	}
+	return nil
+}
+
+// Tags returns the symbol's tags in sorted order.
+func (s *Symbol) Tags() []string {
+	if s == nil || s.Metadata.Custom[MetadataTags] == "" {
+		return nil
+	}
+	return strings.Split(s.Metadata.Custom[MetadataTags], "+")
+}
+
+// HasTag reports whether the symbol carries tag.
+func (s *Symbol) HasTag(tag string) bool {
+	for _, t := range s.Tags() {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// WithTags returns a copy with tags added. Tags should pass ValidateTag;
+// like WithMetadata, WithTags panics on tags containing ',', '{' or '}'.
+func (s *Symbol) WithTags(tags ...string) *Symbol {
+	set := make(map[string]bool)
+	for _, t := range s.Tags() {
+		set[t] = true
+	}
+	for _, t := range tags {
+		set[t] = true
+	}
+	return s.withTagSet(set)
+}
+
+// WithoutTags returns a copy with tags removed, or with all tags removed if
+// none are given.
+func (s *Symbol) WithoutTags(tags ...string) *Symbol {
+	set := make(map[string]bool)
+	if len(tags) > 0 {
+		for _, t := range s.Tags() {
+			set[t] = true
+		}
+		for _, t := range tags {
+			delete(set, t)
+		}
+	}
+	return s.withTagSet(set)
+}
+
+func (s *Symbol) withTagSet(set map[string]bool) *Symbol {
+	tags := make([]string, 0, len(set))
+	for t := range set {
+		tags = append(tags, t)
+	}
+	sort.Strings(tags)
+	return s.WithMetadata(MetadataTags, strings.Join(tags, "+"))
+}
@@ -0,0 +1,49 @@
+package gsrf
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSymbol_WithTags(t *testing.T) {
+	orig := MustParse("github.com/org/payments.Charge{owner:core}")
+
+	tagged := orig.WithTags("team:payments", "deprecated", "team:payments")
+	if got, want := tagged.String(), "github.com/org/payments.Charge{owner:core,tags:deprecated+team:payments}"; got != want {
+		t.Errorf("WithTags() = %q, want %q", got, want)
+	}
+	if orig.Tags() != nil {
+		t.Errorf("WithTags() mutated the receiver: %v", orig.Tags())
+	}
+
+	reparsed := MustParse(tagged.String())
+	if got, want := reparsed.Tags(), []string{"deprecated", "team:payments"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Tags() = %q, want %q", got, want)
+	}
+	if !reparsed.HasTag("team:payments") || reparsed.HasTag("team") {
+		t.Errorf("HasTag() wrong for %v", reparsed.Tags())
+	}
+
+	if got, want := reparsed.WithoutTags("deprecated").String(), "github.com/org/payments.Charge{owner:core,tags:team:payments}"; got != want {
+		t.Errorf("WithoutTags() = %q, want %q", got, want)
+	}
+	if got, want := reparsed.WithoutTags().String(), "github.com/org/payments.Charge{owner:core}"; got != want {
+		t.Errorf("WithoutTags() = %q, want %q", got, want)
+	}
+	if err := reparsed.Validate(); err != nil {
+		t.Errorf("Validate() error = %v", err)
+	}
+}
+
+func TestValidateTag(t *testing.T) {
+	for _, tag := range []string{"deprecated", "team:payments", "owner:org/sre", "tier:v1.2-beta"} {
+		if err := ValidateTag(tag); err != nil {
+			t.Errorf("ValidateTag(%q) error = %v", tag, err)
+		}
+	}
+	for _, tag := range []string{"", ":x", "team:", "a:b:c", "a+b", "a,b", "with space"} {
+		if err := ValidateTag(tag); err == nil {
+			t.Errorf("ValidateTag(%q) succeeded", tag)
+		}
+	}
+}
@@ -0,0 +1,204 @@
+// Package token splits GSRF text into tokens, for authors of adapters who
+// parse formats close to GSRF and want their scanning to agree with the core
+// parser on markers and quoting.
+//
+//	net/http.(*Server).Serve·lit2@linux
+//
+// scans as Ident "net", Slash, Ident "http", Dot, LParen, Star,
+// Ident "Server", RParen, Dot, Ident "Serve", MiddleDot, Int "2", At,
+// Ident "linux", EOF.
+package token
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Kind is the kind of a token.
+type Kind int
+
+const (
+	EOF       Kind = iota // End of input
+	Ident                 // Identifier or path element: letters, digits, _ - ~ + and %XX escapes
+	Int                   // Decimal digits only, such as an anonymous function index
+	String                // Go-quoted package path, such as "gopkg.in/yaml.v2"
+	Dot                   // .
+	Slash                 // /
+	Star                  // *
+	LParen                // (
+	RParen                // )
+	LBracket              // [
+	RBracket              // ]
+	LBrace                // {
+	RBrace                // }
+	At                    // @
+	Colon                 // :
+	Comma                 // ,
+	MiddleDot             // Anonymous function marker, ·lit or its ASCII form %lit
+	Other                 // Any other character, such as > in alias chains
+)
+
+var kindNames = [...]string{
+	EOF:       "EOF",
+	Ident:     "Ident",
+	Int:       "Int",
+	String:    "String",
+	Dot:       "Dot",
+	Slash:     "Slash",
+	Star:      "Star",
+	LParen:    "LParen",
+	RParen:    "RParen",
+	LBracket:  "LBracket",
+	RBracket:  "RBracket",
+	LBrace:    "LBrace",
+	RBrace:    "RBrace",
+	At:        "At",
+	Colon:     "Colon",
+	Comma:     "Comma",
+	MiddleDot: "MiddleDot",
+	Other:     "Other",
+}
+
+func (k Kind) String() string {
+	if k >= 0 && int(k) < len(kindNames) {
+		return kindNames[k]
+	}
+	return fmt.Sprintf("Kind(%d)", int(k))
+}
+
+// Anonymous function markers, as written by the core formatter.
+const (
+	AnonMarker      = "·lit"
+	AnonMarkerASCII = "%lit"
+)
+
+var punctuation = map[byte]Kind{
+	'.': Dot, '/': Slash, '*': Star, '(': LParen, ')': RParen,
+	'[': LBracket, ']': RBracket, '{': LBrace, '}': RBrace,
+	'@': At, ':': Colon, ',': Comma,
+}
+
+// Token is one token of the input.
+type Token struct {
+	Kind Kind
+	Text string // Source text; for String, still quoted
+	Pos  int    // Byte offset in the input
+}
+
+func (t Token) String() string {
+	if t.Kind == EOF {
+		return "EOF"
+	}
+	return fmt.Sprintf("%s %q", t.Kind, t.Text)
+}
+
+// Scanner produces the tokens of a string one at a time. Whitespace, which
+// appears between type arguments, separates tokens and is skipped.
+type Scanner struct {
+	src string
+	pos int
+	err error
+}
+
+// NewScanner returns a scanner reading src.
+func NewScanner(src string) *Scanner {
+	return &Scanner{src: src}
+}
+
+// Next returns the next token, or an EOF token at the end of the input. An
+// unterminated quoted string is returned as Other and recorded in Err.
+func (s *Scanner) Next() Token {
+	for s.pos < len(s.src) {
+		r, size := utf8.DecodeRuneInString(s.src[s.pos:])
+		if !unicode.IsSpace(r) {
+			break
+		}
+		s.pos += size
+	}
+	start := s.pos
+	if start == len(s.src) {
+		return Token{Kind: EOF, Pos: start}
+	}
+	rest := s.src[start:]
+
+	for _, marker := range []string{AnonMarker, AnonMarkerASCII} {
+		if strings.HasPrefix(rest, marker) {
+			return s.emit(MiddleDot, len(marker))
+		}
+	}
+	if kind, ok := punctuation[rest[0]]; ok {
+		return s.emit(kind, 1)
+	}
+	if rest[0] == '"' {
+		quoted, err := strconv.QuotedPrefix(rest)
+		if err != nil {
+			if s.err == nil {
+				s.err = fmt.Errorf("offset %d: unterminated quoted string", start)
+			}
+			return s.emit(Other, 1)
+		}
+		return s.emit(String, len(quoted))
+	}
+
+	n, digits := 0, true
+	for n < len(rest) {
+		r, size := utf8.DecodeRuneInString(rest[n:])
+		switch {
+		case isEscape(rest[n:]) && !strings.HasPrefix(rest[n:], AnonMarkerASCII):
+			size, digits = 3, false
+		case r == '_' || r == '-' || r == '~' || r == '+' || unicode.IsLetter(r):
+			digits = false
+		case unicode.IsDigit(r):
+		default:
+			size = 0
+		}
+		if size == 0 {
+			break
+		}
+		n += size
+	}
+	if n == 0 {
+		_, size := utf8.DecodeRuneInString(rest)
+		return s.emit(Other, size)
+	}
+	if digits {
+		return s.emit(Int, n)
+	}
+	return s.emit(Ident, n)
+}
+
+func (s *Scanner) emit(kind Kind, n int) Token {
+	t := Token{Kind: kind, Text: s.src[s.pos : s.pos+n], Pos: s.pos}
+	s.pos += n
+	return t
+}
+
+// isEscape reports whether s starts with a %XX escape.
+func isEscape(s string) bool {
+	return len(s) >= 3 && s[0] == '%' && isHex(s[1]) && isHex(s[2])
+}
+
+func isHex(c byte) bool {
+	return '0' <= c && c <= '9' || 'a' <= c && c <= 'f' || 'A' <= c && c <= 'F'
+}
+
+// Err returns the first error met while scanning.
+func (s *Scanner) Err() error {
+	return s.err
+}
+
+// Tokenize returns all tokens of src, ending with EOF.
+func Tokenize(src string) ([]Token, error) {
+	s := NewScanner(src)
+	var tokens []Token
+	for {
+		t := s.Next()
+		tokens = append(tokens, t)
+		if t.Kind == EOF {
+			return tokens, s.Err()
+		}
+	}
+}
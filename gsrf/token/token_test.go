@@ -0,0 +1,76 @@
+package token
+
+import (
+	"strings"
+	"testing"
+)
+
+func kinds(tokens []Token) string {
+	var parts []string
+	for _, t := range tokens {
+		if t.Kind == Ident || t.Kind == Int || t.Kind == String || t.Kind == Other {
+			parts = append(parts, t.Kind.String()+"("+t.Text+")")
+		} else {
+			parts = append(parts, t.Kind.String())
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+func TestTokenize(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{
+			"net/http.(*Server).Serve·lit2@linux",
+			"Ident(net) Slash Ident(http) Dot LParen Star Ident(Server) RParen Dot Ident(Serve) MiddleDot Int(2) At Ident(linux) EOF",
+		},
+		{
+			"pkg.Map[K, V]{pos:m.go:12:1}",
+			"Ident(pkg) Dot Ident(Map) LBracket Ident(K) Comma Ident(V) RBracket LBrace Ident(pos) Colon Ident(m) Dot Ident(go) Colon Int(12) Colon Int(1) RBrace EOF",
+		},
+		{
+			`"gopkg.in/yaml.v2".Unmarshal`,
+			`String("gopkg.in/yaml.v2") Dot Ident(Unmarshal) EOF`,
+		},
+		{
+			"gopkg.in/yaml%2ev3.run%lit",
+			"Ident(gopkg) Dot Ident(in) Slash Ident(yaml%2ev3) Dot Ident(run) MiddleDot EOF",
+		},
+		{
+			"example.com/go-kit~v2.F{alias:A>B}",
+			"Ident(example) Dot Ident(com) Slash Ident(go-kit~v2) Dot Ident(F) LBrace Ident(alias) Colon Ident(A) Other(>) Ident(B) RBrace EOF",
+		},
+		{"", "EOF"},
+	}
+	for _, tt := range tests {
+		tokens, err := Tokenize(tt.input)
+		if err != nil {
+			t.Errorf("Tokenize(%q) error = %v", tt.input, err)
+		}
+		if got := kinds(tokens); got != tt.want {
+			t.Errorf("Tokenize(%q) =\n  %s\nwant\n  %s", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestScanner_Positions(t *testing.T) {
+	const input = "pkg.(T).M·lit"
+	s := NewScanner(input)
+	for tok := s.Next(); tok.Kind != EOF; tok = s.Next() {
+		if input[tok.Pos:tok.Pos+len(tok.Text)] != tok.Text {
+			t.Errorf("token %v at %d does not match the input", tok, tok.Pos)
+		}
+	}
+}
+
+func TestTokenize_Unterminated(t *testing.T) {
+	tokens, err := Tokenize(`"gopkg.in/yaml.v2.F`)
+	if err == nil {
+		t.Fatal("Tokenize() of an unterminated string succeeded")
+	}
+	if tokens[0].Kind != Other || tokens[len(tokens)-1].Kind != EOF {
+		t.Errorf("Tokenize() = %v", tokens)
+	}
+}
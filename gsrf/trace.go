@@ -0,0 +1,284 @@
+package gsrf
+
+import (
+	"encoding/binary"
+	"hash/fnv"
+	"strings"
+	"unicode/utf8"
+)
+
+// UnknownFrame is a trace frame that could not be parsed as a symbol, such
+// as foreign code or a corrupted line. The raw text is kept verbatim.
+type UnknownFrame struct {
+	Raw string
+}
+
+// Frame is one entry of a Trace: a parsed symbol or an unknown frame.
+// Exactly one of Symbol and Unknown is set.
+type Frame struct {
+	Symbol  *Symbol
+	Unknown *UnknownFrame
+}
+
+// IsUnknown reports whether the frame could not be parsed.
+func (f Frame) IsUnknown() bool {
+	return f.Symbol == nil
+}
+
+// String returns the GSRF form of a known frame or the raw text of an
+// unknown one.
+func (f Frame) String() string {
+	if f.Symbol != nil {
+		return f.Symbol.Format()
+	}
+	if f.Unknown != nil {
+		return f.Unknown.Raw
+	}
+	return ""
+}
+
+// Trace is an ordered list of frames, innermost first. Unknown frames keep
+// their position so trace length and ordering survive parsing.
+type Trace struct {
+	Frames []Frame
+}
+
+// ParseTrace parses one frame per line with parse (Parse if nil). Lines
+// that fail to parse become unknown frames; blank lines are skipped.
+func ParseTrace(lines []string, parse func(string) (*Symbol, error)) *Trace {
+	return ParseTraceWith(lines, TraceOptions{Parse: parse})
+}
+
+// MaxWrappedLines is the number of continuation lines TraceOptions.JoinWrapped
+// joins to a frame at most.
+const MaxWrappedLines = 3
+
+// TraceOptions controls ParseTraceWith.
+type TraceOptions struct {
+	// Parse parses one frame; Parse if nil.
+	Parse func(string) (*Symbol, error)
+
+	// JoinWrapped re-assembles frames that log shippers wrapped across
+	// lines, splitting them mid-token. A line that is not a valid symbol
+	// is joined with up to MaxWrappedLines following lines, without
+	// separator, when the result is a valid symbol and each continuation
+	// line is not one on its own ("github.com/app.(*Se" + "rver).Start").
+	// A continuation line that is valid on its own is only joined to a
+	// truncated package path ("github.com/or" + "g/app.Run"). Once the
+	// joined text is valid, further lines are joined only while the last
+	// one is as long as the wrap width, which is the length of the first
+	// line unless WrapWidth is set.
+	JoinWrapped bool
+
+	// WrapWidth is the line length at which the shipper wraps. With
+	// JoinWrapped, lines at least this long are joined even if they are
+	// valid symbols on their own ("app.Run@lin" + "ux"). Without it, valid
+	// lines are never joined, since a valid line followed by an unrelated
+	// one often joins into a valid symbol too.
+	WrapWidth int
+}
+
+// ParseTraceWith parses one frame per line like ParseTrace, with opts.
+func ParseTraceWith(lines []string, opts TraceOptions) *Trace {
+	parse := opts.Parse
+	if parse == nil {
+		parse = Parse
+	}
+	var trimmed []string
+	for _, line := range lines {
+		if line = strings.TrimSpace(line); line != "" {
+			trimmed = append(trimmed, line)
+		}
+	}
+
+	t := &Trace{}
+	for i := 0; i < len(trimmed); i++ {
+		line := trimmed[i]
+		if opts.JoinWrapped {
+			if sym, n := joinWrapped(trimmed[i:], parse, opts.WrapWidth); n > 0 {
+				t.Frames = append(t.Frames, Frame{Symbol: sym})
+				i += n
+				continue
+			}
+		}
+		if sym, err := parse(line); err == nil {
+			t.Frames = append(t.Frames, Frame{Symbol: sym})
+		} else {
+			t.Frames = append(t.Frames, Frame{Unknown: &UnknownFrame{Raw: line}})
+		}
+	}
+	return t
+}
+
+// joinWrapped joins lines[0] with the continuation lines that follow it and
+// returns the longest valid symbol and the number of continuation lines it
+// consumed, or 0 if lines[0] does not look wrapped.
+func joinWrapped(lines []string, parse func(string) (*Symbol, error), width int) (*Symbol, int) {
+	valid := func(s string) (*Symbol, bool) {
+		sym, err := parse(s)
+		return sym, err == nil && sym.Validate() == nil
+	}
+	_, headValid := valid(lines[0])
+	if headValid && (width == 0 || utf8.RuneCountInString(lines[0]) < width) {
+		return nil, 0
+	}
+	if width == 0 {
+		// Lines are wrapped at the same width, so the head shows it.
+		width = utf8.RuneCountInString(lines[0])
+	}
+	joined := lines[0]
+	var best *Symbol
+	bestN := 0
+	for n := 1; n <= MaxWrappedLines && n < len(lines); n++ {
+		if best != nil && utf8.RuneCountInString(lines[n-1]) < width {
+			// The frame is complete unless the last line was wrapped too.
+			break
+		}
+		_, tailValid := valid(lines[n])
+		truncatedPath := n == 1 && !headValid && strings.Contains(lines[0], "/")
+		if tailValid && !truncatedPath {
+			break
+		}
+		joined += lines[n]
+		if sym, ok := valid(joined); ok {
+			// Keep extending: the frame may continue on the next line.
+			best, bestN = sym, n
+		} else if tailValid {
+			break
+		}
+	}
+	return best, bestN
+}
+
+// Len returns the number of frames, known and unknown.
+func (t *Trace) Len() int {
+	return len(t.Frames)
+}
+
+// UnknownCount returns the number of unknown frames.
+func (t *Trace) UnknownCount() int {
+	n := 0
+	for _, f := range t.Frames {
+		if f.IsUnknown() {
+			n++
+		}
+	}
+	return n
+}
+
+// Symbols returns the known frames' symbols in order.
+func (t *Trace) Symbols() []*Symbol {
+	syms := make([]*Symbol, 0, len(t.Frames))
+	for _, f := range t.Frames {
+		if f.Symbol != nil {
+			syms = append(syms, f.Symbol)
+		}
+	}
+	return syms
+}
+
+// Lines re-emits the trace, one frame per line, in original order.
+func (t *Trace) Lines() []string {
+	lines := make([]string, len(t.Frames))
+	for i, f := range t.Frames {
+		lines[i] = f.String()
+	}
+	return lines
+}
+
+// UnknownFramePolicy controls how Trace.Fingerprint treats unknown frames.
+type UnknownFramePolicy int
+
+const (
+	// UnknownPositional hashes every unknown frame as the same placeholder,
+	// so traces group by shape regardless of corrupted text.
+	UnknownPositional UnknownFramePolicy = iota
+	// UnknownRaw hashes the unknown frame's raw text.
+	UnknownRaw
+	// UnknownCollapse hashes each run of consecutive unknown frames as a
+	// single placeholder, tolerating varying depth of foreign code.
+	UnknownCollapse
+	// UnknownSkip leaves unknown frames out of the hash entirely.
+	UnknownSkip
+)
+
+// Fingerprint returns a stable hash of the trace. Known frames contribute
+// their Symbol.Fingerprint; unknown frames are treated per policy.
+func (t *Trace) Fingerprint(policy UnknownFramePolicy) uint64 {
+	h := fnv.New64a()
+	writeFingerprintHeader(h, 't')
+	h.Write([]byte{byte(policy)})
+
+	var buf [9]byte
+	prevUnknown := false
+	for _, f := range t.Frames {
+		if !f.IsUnknown() {
+			buf[0] = 's'
+			binary.BigEndian.PutUint64(buf[1:], f.Symbol.Fingerprint())
+			h.Write(buf[:])
+			prevUnknown = false
+			continue
+		}
+
+		switch policy {
+		case UnknownSkip:
+			continue
+		case UnknownCollapse:
+			if prevUnknown {
+				continue
+			}
+			h.Write([]byte{'u'})
+		case UnknownRaw:
+			raw := fnv.New64a()
+			raw.Write([]byte(f.String()))
+			buf[0] = 'r'
+			binary.BigEndian.PutUint64(buf[1:], raw.Sum64())
+			h.Write(buf[:])
+		default:
+			h.Write([]byte{'u'})
+		}
+		prevUnknown = true
+	}
+	return h.Sum64()
+}
+
+// PruneToModules returns a copy of the trace cut at module boundaries: the
+// innermost run of frames from first-party modules, preceded by up to
+// context frames it called into (typically where a dependency panicked).
+// Callers outside the run, such as server loops and goroutine entry points,
+// are dropped, so crashes in the same first-party code group together
+// regardless of how it was reached. A frame belongs to a module if its
+// package path is the module path or below it. A trace without first-party
+// frames is returned unchanged.
+func (t *Trace) PruneToModules(modules []string, context int) *Trace {
+	inModules := func(f Frame) bool {
+		if f.Symbol == nil {
+			return false
+		}
+		for _, m := range modules {
+			if p := f.Symbol.PackagePath; p == m || strings.HasPrefix(p, m+"/") {
+				return true
+			}
+		}
+		return false
+	}
+
+	start := -1
+	for i, f := range t.Frames {
+		if inModules(f) {
+			start = i
+			break
+		}
+	}
+	if start < 0 {
+		return &Trace{Frames: append([]Frame(nil), t.Frames...)}
+	}
+	end := start
+	for end < len(t.Frames) && inModules(t.Frames[end]) {
+		end++
+	}
+	if start -= context; start < 0 {
+		start = 0
+	}
+	return &Trace{Frames: append([]Frame(nil), t.Frames[start:end]...)}
+}
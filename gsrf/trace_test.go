@@ -0,0 +1,180 @@
+package gsrf
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseTrace(t *testing.T) {
+	lines := []string{
+		"main.main",
+		"???",
+		"",
+		"net/http.(*Server).Serve",
+		"corrupted\x00frame",
+	}
+	trace := ParseTrace(lines, nil)
+
+	if trace.Len() != 4 {
+		t.Fatalf("Len() = %d, want 4", trace.Len())
+	}
+	if trace.UnknownCount() != 2 {
+		t.Errorf("UnknownCount() = %d, want 2", trace.UnknownCount())
+	}
+	if len(trace.Symbols()) != 2 {
+		t.Errorf("Symbols() = %v", trace.Symbols())
+	}
+
+	want := []string{"main.main", "???", "net/http.(*Server).Serve", "corrupted\x00frame"}
+	if got := trace.Lines(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Lines() = %q, want %q", got, want)
+	}
+	if !trace.Frames[1].IsUnknown() || trace.Frames[1].Unknown.Raw != "???" {
+		t.Errorf("Frames[1] = %+v", trace.Frames[1])
+	}
+}
+
+func TestParseTraceWith_JoinWrapped(t *testing.T) {
+	lines := []string{
+		"github.com/org/app.(*Se",
+		"  rver).Start",
+		"github.com/or",
+		"g/app.Run",
+		"libc_start",
+		"main.main",
+		"github.com/org/ap",
+		"p.(*Handler[T]).S",
+		"erveHTTP@linux",
+		"app.Run@lin",
+		"ux",
+		"???",
+	}
+	want := []string{
+		"github.com/org/app.(*Server).Start",
+		"github.com/org/app.Run",
+		"libc_start",
+		"main.main",
+		"github.com/org/app.(*Handler[T]).ServeHTTP@linux",
+		"app.Run@lin",
+		"ux",
+		"???",
+	}
+	trace := ParseTraceWith(lines, TraceOptions{JoinWrapped: true})
+	if got := trace.Lines(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Lines() = %q, want %q", got, want)
+	}
+
+	// With the wrap width, a full-width valid line is joined too.
+	want[5] = "app.Run@linux"
+	want = append(want[:6], want[7:]...)
+	trace = ParseTraceWith(lines, TraceOptions{JoinWrapped: true, WrapWidth: 11})
+	if got := trace.Lines(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Lines() with WrapWidth = %q, want %q", got, want)
+	}
+
+	// Without JoinWrapped, every line is a frame.
+	if n := ParseTraceWith(lines, TraceOptions{}).Len(); n != len(lines) {
+		t.Errorf("Len() = %d, want %d", n, len(lines))
+	}
+}
+
+func TestTrace_Fingerprint(t *testing.T) {
+	trace := func(lines ...string) *Trace { return ParseTrace(lines, nil) }
+
+	a := trace("main.main", "???", "pkg.F")
+	b := trace("main.main", "!!!", "pkg.F")
+	c := trace("main.main", "???", "!!!", "pkg.F")
+	d := trace("main.main", "pkg.F")
+
+	tests := []struct {
+		name   string
+		policy UnknownFramePolicy
+		x, y   *Trace
+		same   bool
+	}{
+		{"positional ignores text", UnknownPositional, a, b, true},
+		{"positional counts frames", UnknownPositional, a, c, false},
+		{"raw compares text", UnknownRaw, a, b, false},
+		{"raw same text", UnknownRaw, a, trace("main.main", "???", "pkg.F"), true},
+		{"collapse merges runs", UnknownCollapse, a, c, true},
+		{"collapse keeps presence", UnknownCollapse, a, d, false},
+		{"skip drops unknown", UnknownSkip, a, d, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.x.Fingerprint(tt.policy) == tt.y.Fingerprint(tt.policy)
+			if got != tt.same {
+				t.Errorf("fingerprints equal = %v, want %v", got, tt.same)
+			}
+		})
+	}
+
+	if a.Fingerprint(UnknownPositional) == a.Fingerprint(UnknownSkip) {
+		t.Error("policy not mixed into fingerprint")
+	}
+}
+
+func TestTrace_PruneToModules(t *testing.T) {
+	modules := []string{"example.com/app"}
+	trace := ParseTrace([]string{
+		"runtime.gopanic",
+		"encoding/json.(*decodeState).object",
+		"encoding/json.Unmarshal",
+		"example.com/app/api.(*Handler).Decode",
+		"example.com/app.(*Server).handle",
+		"net/http.HandlerFunc.ServeHTTP",
+		"example.com/app.main",
+	}, nil)
+
+	tests := []struct {
+		name    string
+		context int
+		want    []string
+	}{
+		{name: "no context", context: 0, want: []string{"example.com/app/api.(*Handler).Decode", "example.com/app.(*Server).handle"}},
+		{name: "one frame", context: 1, want: []string{"encoding/json.Unmarshal", "example.com/app/api.(*Handler).Decode", "example.com/app.(*Server).handle"}},
+		{name: "context past start", context: 10, want: []string{
+			"runtime.gopanic", "encoding/json.(*decodeState).object", "encoding/json.Unmarshal",
+			"example.com/app/api.(*Handler).Decode", "example.com/app.(*Server).handle",
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := trace.PruneToModules(modules, tt.context).Lines(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("PruneToModules() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("groups by first-party code", func(t *testing.T) {
+		other := ParseTrace([]string{
+			"runtime.gopanic",
+			"encoding/json.Unmarshal",
+			"example.com/app/api.(*Handler).Decode",
+			"example.com/app.(*Server).handle",
+			"example.com/app/internal/worker.run",
+		}, nil)
+		a := trace.PruneToModules(modules, 1).Fingerprint(UnknownPositional)
+		b := other.PruneToModules(modules, 1).Fingerprint(UnknownPositional)
+		if a == b {
+			t.Error("traces with different first-party runs share a fingerprint")
+		}
+		other.Frames = other.Frames[:4]
+		if b = other.PruneToModules(modules, 1).Fingerprint(UnknownPositional); a != b {
+			t.Error("traces with the same first-party run differ in fingerprint")
+		}
+	})
+
+	t.Run("no first-party frames", func(t *testing.T) {
+		if got := trace.PruneToModules([]string{"example.com/other"}, 1); got.Len() != trace.Len() {
+			t.Errorf("PruneToModules() Len() = %d, want %d", got.Len(), trace.Len())
+		}
+	})
+
+	t.Run("module prefix is path-aware", func(t *testing.T) {
+		if got := trace.PruneToModules([]string{"example.com/ap"}, 0); got.Len() != trace.Len() {
+			t.Errorf("PruneToModules() matched a partial path element")
+		}
+	})
+}
@@ -0,0 +1,452 @@
+package gsrf
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// TypeExprKind identifies the form of a TypeExpr.
+type TypeExprKind int
+
+const (
+	ExprNamed     TypeExprKind = iota // Package.Name[Args], or a predeclared type
+	ExprTypeParam                     // A type parameter of the enclosing symbol
+	ExprPointer                       // *Elem
+	ExprSlice                         // []Elem
+	ExprArray                         // [Len]Elem
+	ExprMap                           // map[Key]Elem
+	ExprChan                          // chan Elem, chan<- Elem, <-chan Elem
+	ExprFunc                          // func(Params) Results
+	ExprLiteral                       // struct{...} or interface{...}, kept as Raw text
+	ExprElided                        // "...", the runtime's placeholder for unknown type arguments
+)
+
+// ChanDir is the direction of a channel type.
+type ChanDir int
+
+const (
+	ChanBoth ChanDir = iota // chan T
+	ChanSend                // chan<- T
+	ChanRecv                // <-chan T
+)
+
+// TypeExpr is the structure of a type argument, so tools can inspect and
+// rewrite "map[string][]*Foo" instead of treating it as opaque text.
+// String prints it back in canonical Go syntax.
+type TypeExpr struct {
+	Kind     TypeExprKind
+	Package  string      // ExprNamed: package path or name; empty for predeclared and local types
+	Name     string      // ExprNamed, ExprTypeParam
+	Args     []*TypeExpr // ExprNamed: type arguments
+	Len      string      // ExprArray: length expression
+	Key      *TypeExpr   // ExprMap
+	Elem     *TypeExpr   // ExprPointer, ExprSlice, ExprArray, ExprMap, ExprChan
+	Dir      ChanDir     // ExprChan
+	Params   []*TypeExpr // ExprFunc
+	Results  []*TypeExpr // ExprFunc
+	Variadic bool        // ExprFunc: the last parameter is ...T
+	Raw      string      // ExprLiteral
+}
+
+// maxTypeExprDepth bounds the nesting of type expressions, so that hostile
+// input fails with an error instead of overflowing the stack.
+const maxTypeExprDepth = 256
+
+// ParseTypeExpr parses a type expression. Unqualified names listed in
+// params parse as ExprTypeParam. Expressions nested deeper than 256 levels
+// are rejected.
+func ParseTypeExpr(expr string, params ...string) (*TypeExpr, error) {
+	p := &typeExprParser{s: expr, params: params}
+	t, err := p.parseType()
+	if err != nil {
+		return nil, err
+	}
+	p.skipSpace()
+	if p.pos < len(p.s) {
+		return nil, p.errorf("unexpected %q", p.s[p.pos:])
+	}
+	return t, nil
+}
+
+// TypeArgExprs parses the symbol's type arguments. Names of the symbol's
+// type parameters parse as ExprTypeParam.
+func (s *Symbol) TypeArgExprs() ([]*TypeExpr, error) {
+	return parseTypeExprs(s.TypeArgs, s.TypeParams)
+}
+
+// SetTypeArgExprs replaces the symbol's type arguments with the printed
+// expressions.
+func (s *Symbol) SetTypeArgExprs(exprs []*TypeExpr) {
+	s.TypeArgs = nil
+	for _, e := range exprs {
+		s.TypeArgs = append(s.TypeArgs, e.String())
+	}
+}
+
+func parseTypeExprs(args []string, typeParams []TypeParam) ([]*TypeExpr, error) {
+	if len(args) == 0 {
+		return nil, nil
+	}
+	names := make([]string, len(typeParams))
+	for i, tp := range typeParams {
+		names[i] = tp.Name
+	}
+	exprs := make([]*TypeExpr, len(args))
+	for i, arg := range args {
+		e, err := ParseTypeExpr(arg, names...)
+		if err != nil {
+			return nil, err
+		}
+		exprs[i] = e
+	}
+	return exprs, nil
+}
+
+// Walk calls fn for t and, while fn returns true, for each of its
+// component types in depth-first order.
+func (t *TypeExpr) Walk(fn func(*TypeExpr) bool) {
+	if t == nil || !fn(t) {
+		return
+	}
+	for _, list := range [][]*TypeExpr{t.Args, {t.Key, t.Elem}, t.Params, t.Results} {
+		for _, c := range list {
+			c.Walk(fn)
+		}
+	}
+}
+
+// String prints the expression in canonical Go syntax.
+func (t *TypeExpr) String() string {
+	var b strings.Builder
+	t.write(&b)
+	return b.String()
+}
+
+func (t *TypeExpr) write(b *strings.Builder) {
+	writeList := func(list []*TypeExpr, variadic bool) {
+		for i, e := range list {
+			if i > 0 {
+				b.WriteString(", ")
+			}
+			if variadic && i == len(list)-1 {
+				b.WriteString("...")
+			}
+			e.write(b)
+		}
+	}
+
+	switch t.Kind {
+	case ExprNamed:
+		if t.Package != "" {
+			b.WriteString(t.Package + ".")
+		}
+		b.WriteString(t.Name)
+		if len(t.Args) > 0 {
+			b.WriteByte('[')
+			writeList(t.Args, false)
+			b.WriteByte(']')
+		}
+	case ExprTypeParam:
+		b.WriteString(t.Name)
+	case ExprPointer:
+		b.WriteByte('*')
+		t.Elem.write(b)
+	case ExprSlice:
+		b.WriteString("[]")
+		t.Elem.write(b)
+	case ExprArray:
+		b.WriteString("[" + t.Len + "]")
+		t.Elem.write(b)
+	case ExprMap:
+		b.WriteString("map[")
+		t.Key.write(b)
+		b.WriteByte(']')
+		t.Elem.write(b)
+	case ExprChan:
+		switch t.Dir {
+		case ChanSend:
+			b.WriteString("chan<- ")
+		case ChanRecv:
+			b.WriteString("<-chan ")
+		default:
+			b.WriteString("chan ")
+		}
+		if t.Dir != ChanRecv && t.Elem.Kind == ExprChan && t.Elem.Dir == ChanRecv {
+			// "chan <-chan T" would read as chan<- (chan T).
+			b.WriteByte('(')
+			t.Elem.write(b)
+			b.WriteByte(')')
+		} else {
+			t.Elem.write(b)
+		}
+	case ExprFunc:
+		b.WriteString("func(")
+		writeList(t.Params, t.Variadic)
+		b.WriteByte(')')
+		switch {
+		case len(t.Results) == 1:
+			b.WriteByte(' ')
+			t.Results[0].write(b)
+		case len(t.Results) > 1:
+			b.WriteString(" (")
+			writeList(t.Results, false)
+			b.WriteByte(')')
+		}
+	case ExprLiteral:
+		b.WriteString(t.Raw)
+	case ExprElided:
+		b.WriteString("...")
+	}
+}
+
+type typeExprParser struct {
+	s      string
+	pos    int
+	depth  int
+	params []string
+}
+
+func (p *typeExprParser) errorf(format string, args ...any) error {
+	return fmt.Errorf("invalid type expression %q at offset %d: %s", p.s, p.pos, fmt.Sprintf(format, args...))
+}
+
+func (p *typeExprParser) skipSpace() {
+	for p.pos < len(p.s) && (p.s[p.pos] == ' ' || p.s[p.pos] == '\t') {
+		p.pos++
+	}
+}
+
+// accept consumes tok, after optional space, if it comes next.
+func (p *typeExprParser) accept(tok string) bool {
+	p.skipSpace()
+	if strings.HasPrefix(p.s[p.pos:], tok) {
+		p.pos += len(tok)
+		return true
+	}
+	return false
+}
+
+func (p *typeExprParser) expect(tok string) error {
+	if !p.accept(tok) {
+		return p.errorf("expected %q", tok)
+	}
+	return nil
+}
+
+// keyword consumes word if it comes next and is not the prefix of a longer
+// name.
+func (p *typeExprParser) keyword(word string) bool {
+	p.skipSpace()
+	rest := p.s[p.pos:]
+	if !strings.HasPrefix(rest, word) {
+		return false
+	}
+	if r, _ := utf8.DecodeRuneInString(rest[len(word):]); isNameRune(r) {
+		return false
+	}
+	p.pos += len(word)
+	return true
+}
+
+func (p *typeExprParser) parseType() (*TypeExpr, error) {
+	if p.depth++; p.depth > maxTypeExprDepth {
+		return nil, p.errorf("nested deeper than %d", maxTypeExprDepth)
+	}
+	defer func() { p.depth-- }()
+	p.skipSpace()
+	start := p.pos
+	switch {
+	case p.accept("..."):
+		return &TypeExpr{Kind: ExprElided}, nil
+	case p.accept("*"):
+		return p.wrap(&TypeExpr{Kind: ExprPointer})
+	case p.accept("<-"):
+		if !p.keyword("chan") {
+			return nil, p.errorf("expected chan after <-")
+		}
+		return p.wrap(&TypeExpr{Kind: ExprChan, Dir: ChanRecv})
+	case p.accept("("):
+		t, err := p.parseType()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect(")"); err != nil {
+			return nil, err
+		}
+		return t, nil
+	case p.accept("["):
+		end := strings.IndexByte(p.s[p.pos:], ']')
+		if end < 0 {
+			return nil, p.errorf("unclosed [")
+		}
+		n := strings.TrimSpace(p.s[p.pos : p.pos+end])
+		p.pos += end + 1
+		if n != "" {
+			return p.wrap(&TypeExpr{Kind: ExprArray, Len: n})
+		}
+		return p.wrap(&TypeExpr{Kind: ExprSlice})
+	case p.keyword("map"):
+		if err := p.expect("["); err != nil {
+			return nil, err
+		}
+		key, err := p.parseType()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect("]"); err != nil {
+			return nil, err
+		}
+		return p.wrap(&TypeExpr{Kind: ExprMap, Key: key})
+	case p.keyword("chan"):
+		t := &TypeExpr{Kind: ExprChan}
+		if p.accept("<-") {
+			t.Dir = ChanSend
+		}
+		return p.wrap(t)
+	case p.keyword("func"):
+		return p.parseFunc()
+	case p.keyword("struct"), p.keyword("interface"):
+		return p.parseLiteral(start)
+	}
+	return p.parseNamed()
+}
+
+// wrap parses the element type of a composite type.
+func (p *typeExprParser) wrap(t *TypeExpr) (*TypeExpr, error) {
+	elem, err := p.parseType()
+	if err != nil {
+		return nil, err
+	}
+	t.Elem = elem
+	return t, nil
+}
+
+func (p *typeExprParser) parseFunc() (*TypeExpr, error) {
+	t := &TypeExpr{Kind: ExprFunc}
+	if err := p.expect("("); err != nil {
+		return nil, err
+	}
+	params, variadic, err := p.parseList(")")
+	if err != nil {
+		return nil, err
+	}
+	t.Params, t.Variadic = params, variadic
+
+	p.skipSpace()
+	if p.pos == len(p.s) || strings.ContainsRune(",])", rune(p.s[p.pos])) {
+		return t, nil
+	}
+	if p.accept("(") {
+		if t.Results, _, err = p.parseList(")"); err != nil {
+			return nil, err
+		}
+		return t, nil
+	}
+	result, err := p.parseType()
+	if err != nil {
+		return nil, err
+	}
+	t.Results = []*TypeExpr{result}
+	return t, nil
+}
+
+// parseList parses comma-separated types up to and including the closing
+// token, reporting whether the last parameter was written ...T. In type
+// argument lists "..." is an elided type instead.
+func (p *typeExprParser) parseList(closing string) ([]*TypeExpr, bool, error) {
+	var list []*TypeExpr
+	variadic := false
+	for !p.accept(closing) {
+		if len(list) > 0 {
+			if err := p.expect(","); err != nil {
+				return nil, false, err
+			}
+			if p.accept(closing) {
+				break // trailing comma
+			}
+		}
+		if variadic {
+			return nil, false, p.errorf("... must be the last parameter")
+		}
+		variadic = closing == ")" && p.accept("...")
+		t, err := p.parseType()
+		if err != nil {
+			return nil, false, err
+		}
+		list = append(list, t)
+	}
+	return list, variadic, nil
+}
+
+// parseLiteral keeps a struct or interface type literal, starting at
+// start, as text.
+func (p *typeExprParser) parseLiteral(start int) (*TypeExpr, error) {
+	if err := p.expect("{"); err != nil {
+		return nil, err
+	}
+	for depth := 1; depth > 0; p.pos++ {
+		if p.pos >= len(p.s) {
+			return nil, p.errorf("unclosed {")
+		}
+		switch p.s[p.pos] {
+		case '{':
+			depth++
+		case '}':
+			depth--
+		}
+	}
+	return &TypeExpr{Kind: ExprLiteral, Raw: p.s[start:p.pos]}, nil
+}
+
+// parseNamed parses a possibly qualified, possibly instantiated type name.
+// The qualifier may be a full import path: "example.com/m/pkg.T".
+func (p *typeExprParser) parseNamed() (*TypeExpr, error) {
+	p.skipSpace()
+	start := p.pos
+	for p.pos < len(p.s) {
+		r, size := utf8.DecodeRuneInString(p.s[p.pos:])
+		if !isNameRune(r) && !strings.ContainsRune("./-~", r) {
+			break
+		}
+		p.pos += size
+	}
+	text := p.s[start:p.pos]
+	if text == "" {
+		if p.pos == len(p.s) {
+			return nil, p.errorf("missing type")
+		}
+		return nil, p.errorf("unexpected %q", p.s[p.pos:])
+	}
+
+	t := &TypeExpr{Kind: ExprNamed, Name: text}
+	if dot := strings.LastIndex(text, "."); dot >= 0 {
+		t.Package, t.Name = text[:dot], text[dot+1:]
+		if t.Package == "" || t.Name == "" {
+			return nil, p.errorf("malformed qualified name %q", text)
+		}
+	} else {
+		for _, param := range p.params {
+			if text == param {
+				t.Kind = ExprTypeParam
+			}
+		}
+	}
+
+	if t.Kind == ExprNamed && p.accept("[") {
+		args, _, err := p.parseList("]")
+		if err != nil {
+			return nil, err
+		}
+		if len(args) == 0 {
+			return nil, p.errorf("empty type argument list")
+		}
+		t.Args = args
+	}
+	return t, nil
+}
+
+func isNameRune(r rune) bool {
+	return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
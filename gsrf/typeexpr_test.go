@@ -0,0 +1,128 @@
+package gsrf
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseTypeExpr_RoundTrip(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string // empty means same as input
+	}{
+		{input: "int"},
+		{input: "map[string][]*Foo"},
+		{input: "[4]byte"},
+		{input: "[ ]int", want: "[]int"},
+		{input: "context.Context"},
+		{input: "example.com/m/pkg.List[int]"},
+		{input: "Pair[K, map[K]V]"},
+		{input: "chan int"},
+		{input: "chan<- error"},
+		{input: "<-chan struct{}"},
+		{input: "chan (<-chan int)"},
+		{input: "chan<- chan int"},
+		{input: "func()"},
+		{input: "func(int, ...string) error"},
+		{input: "func(context.Context) (int,error)", want: "func(context.Context) (int, error)"},
+		{input: "func() func() int"},
+		{input: "interface{ String() string }"},
+		{input: "*struct{ x, y int }"},
+		{input: "..."},
+		{input: "Map[...]"},
+		{input: "(*T)", want: "*T"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			expr, err := ParseTypeExpr(tt.input)
+			if err != nil {
+				t.Fatalf("ParseTypeExpr() error = %v", err)
+			}
+			want := tt.want
+			if want == "" {
+				want = tt.input
+			}
+			if got := expr.String(); got != want {
+				t.Errorf("String() = %q, want %q", got, want)
+			}
+		})
+	}
+}
+
+func TestParseTypeExpr_Structure(t *testing.T) {
+	expr, err := ParseTypeExpr("map[string][]*pkg.Foo[T]", "T")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &TypeExpr{
+		Kind: ExprMap,
+		Key:  &TypeExpr{Kind: ExprNamed, Name: "string"},
+		Elem: &TypeExpr{Kind: ExprSlice, Elem: &TypeExpr{Kind: ExprPointer, Elem: &TypeExpr{
+			Kind: ExprNamed, Package: "pkg", Name: "Foo",
+			Args: []*TypeExpr{{Kind: ExprTypeParam, Name: "T"}},
+		}}},
+	}
+	if !reflect.DeepEqual(expr, want) {
+		t.Errorf("ParseTypeExpr() = %s, want %s", expr, want)
+	}
+
+	var names []string
+	expr.Walk(func(e *TypeExpr) bool {
+		if e.Kind == ExprNamed || e.Kind == ExprTypeParam {
+			names = append(names, e.Name)
+		}
+		return true
+	})
+	if !reflect.DeepEqual(names, []string{"string", "Foo", "T"}) {
+		t.Errorf("Walk() visited %v", names)
+	}
+}
+
+func TestParseTypeExpr_Errors(t *testing.T) {
+	for _, input := range []string{
+		"",
+		"map[string",
+		"[]",
+		"func(int",
+		"func(...int, string)",
+		"<-int",
+		"List[]",
+		"pkg.",
+		"int string",
+		"struct{",
+		strings.Repeat("*", maxTypeExprDepth) + "int",
+		strings.Repeat("[]", maxTypeExprDepth) + "int",
+		strings.Repeat("List[", maxTypeExprDepth) + "int" + strings.Repeat("]", maxTypeExprDepth),
+	} {
+		if expr, err := ParseTypeExpr(input); err == nil {
+			t.Errorf("ParseTypeExpr(%q) = %s, want error", input, expr)
+		}
+	}
+	if _, err := ParseTypeExpr(strings.Repeat("*", maxTypeExprDepth-1) + "int"); err != nil {
+		t.Errorf("ParseTypeExpr() at the depth limit error = %v", err)
+	}
+}
+
+func TestSymbol_TypeArgExprs(t *testing.T) {
+	sym := MustParse("pkg.Map[string, []*example.com/m.Item]")
+	exprs, err := sym.TypeArgExprs()
+	if err != nil {
+		t.Fatalf("TypeArgExprs() error = %v", err)
+	}
+	if len(exprs) != 2 || exprs[1].Elem.Elem.Package != "example.com/m" {
+		t.Fatalf("TypeArgExprs() = %v", exprs)
+	}
+
+	// Rewrite the element type structurally and store it back.
+	exprs[1].Elem.Elem.Package = "example.com/m/v2"
+	sym.SetTypeArgExprs(exprs)
+	if got, want := sym.Format(), "pkg.Map[string, []*example.com/m/v2.Item]"; got != want {
+		t.Errorf("Format() = %q, want %q", got, want)
+	}
+
+	generic := &Symbol{PackagePath: "pkg", Name: "F", TypeParams: []TypeParam{{Name: "T"}}, TypeArgs: []string{"[]T"}}
+	if exprs, err := generic.TypeArgExprs(); err != nil || exprs[0].Elem.Kind != ExprTypeParam {
+		t.Errorf("TypeArgExprs() = %v, %v", exprs, err)
+	}
+}
@@ -0,0 +1,201 @@
+package gsrf
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// ValidationError lists the specification violations found by
+// Symbol.Validate, in field order.
+type ValidationError struct {
+	Violations []Warning
+}
+
+func (e *ValidationError) Error() string {
+	msg := "invalid GSRF symbol: " + e.Violations[0].String()
+	if n := len(e.Violations) - 1; n > 0 {
+		msg += fmt.Sprintf(" (and %d more)", n)
+	}
+	return msg
+}
+
+// importPathExcluded are the characters the Go specification allows
+// compilers to exclude from import paths, in addition to non-graphic
+// characters, spaces, and U+FFFD.
+const importPathExcluded = "!\"#$%&'()*,:;<=>?[\\]^`{|}�"
+
+// Validate checks the symbol against the specification's lexical rules and
+// returns a *ValidationError listing every violation, or nil:
+//
+//   - PackagePath is a legal import path: non-empty slash-separated elements
+//     of graphic Unicode characters, excluding the characters in
+//     importPathExcluded.
+//   - Name, receiver type, and type parameter names are Go identifiers
+//     (Unicode letters, digits, underscores; no leading digit). Anonymous
+//     functions may have an empty name.
+//   - Type arguments are non-empty with balanced brackets and parentheses.
+//   - Context is a build tag: letters, digits, underscores, and dots.
+//   - Metadata values do not contain ",", "{" or "}"; via entries and alias
+//     hops are not empty; custom metadata keys are identifiers that may also
+//     contain "-" and ".".
+//
+// Unlike Lint, Validate does not reject keywords or methods on predeclared
+// types, which are style rather than syntax problems.
+func (s *Symbol) Validate() error {
+	var violations []Warning
+	add := func(field, value, msg string) {
+		violations = append(violations, Warning{Field: field, Value: value, Message: msg})
+	}
+	ident := func(field, value string) {
+		if msg := identifierSyntaxProblem(value); msg != "" {
+			add(field, value, msg)
+		}
+	}
+	types := func(field string, list []string) {
+		for _, t := range list {
+			if msg := typeExprProblem(t); msg != "" {
+				add(field, t, msg)
+			}
+		}
+	}
+
+	if msg := importPathProblem(s.PackagePath); msg != "" {
+		add(FieldPackagePath, s.PackagePath, msg)
+	}
+
+	name, recv := s.Name, s.Receiver
+	if s.IsAnonymous && recv == nil {
+		name, recv = splitAnonMethod(name)
+	}
+	if !(s.IsAnonymous && name == "") {
+		ident(FieldName, name)
+	}
+	if s.IsInit && (s.Name != "init" || s.Receiver != nil) {
+		add(FieldIsInit, s.Name, "init function must be named init and have no receiver")
+	}
+	if s.AnonIndex < 0 {
+		add(FieldAnonIndex, fmt.Sprint(s.AnonIndex), "negative index")
+	}
+	if recv != nil {
+		ident(FieldReceiverTypeName, recv.TypeName)
+		types(FieldReceiverTypeArgs, recv.TypeArgs)
+	}
+	types(FieldTypeArgs, s.TypeArgs)
+	for _, tp := range s.TypeParams {
+		ident("typeParams", tp.Name)
+		if tp.Constraint != "" {
+			if msg := typeExprProblem(tp.Constraint); msg != "" {
+				add("typeParams", tp.Constraint, msg)
+			}
+		}
+	}
+
+	if s.Context != "" {
+		for _, r := range s.Context {
+			if r != '_' && r != '.' && !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+				add(FieldContext, s.Context, fmt.Sprintf("invalid build tag character %q", r))
+				break
+			}
+		}
+	}
+
+	for _, via := range s.Metadata.Via {
+		if strings.TrimSpace(via) == "" {
+			add(FieldMetadataVia, via, "empty via entry")
+		} else if strings.ContainsAny(via, ",{}") {
+			add(FieldMetadataVia, via, "metadata value contains reserved characters")
+		}
+	}
+	for _, m := range []struct{ field, value string }{
+		{FieldMetadataAlias, s.Metadata.Alias},
+		{FieldMetadataPosition, s.Metadata.Position},
+	} {
+		if strings.ContainsAny(m.value, ",{}") {
+			add(m.field, m.value, "metadata value contains reserved characters")
+		}
+	}
+	for _, hop := range s.Metadata.AliasChain() {
+		if strings.TrimSpace(hop) == "" {
+			add(FieldMetadataAlias, s.Metadata.Alias, "empty alias hop")
+			break
+		}
+	}
+	keys := make([]string, 0, len(s.Metadata.Custom))
+	for k := range s.Metadata.Custom {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		field := customFieldPrefix + key
+		if msg := metadataKeyProblem(key); msg != "" {
+			add(field, key, msg)
+		}
+		if value := s.Metadata.Custom[key]; strings.ContainsAny(value, ",{}") {
+			add(field, value, "metadata value contains reserved characters")
+		}
+	}
+
+	if len(violations) == 0 {
+		return nil
+	}
+	return &ValidationError{Violations: violations}
+}
+
+// importPathProblem describes why path is not a legal import path.
+func importPathProblem(path string) string {
+	if path == "" {
+		return "empty import path"
+	}
+	for _, elem := range strings.Split(path, "/") {
+		if elem == "" {
+			return "empty path element"
+		}
+	}
+	for _, r := range path {
+		if !unicode.IsGraphic(r) || unicode.IsSpace(r) || strings.ContainsRune(importPathExcluded, r) {
+			return fmt.Sprintf("invalid import path character %q", r)
+		}
+	}
+	return ""
+}
+
+// typeExprProblem describes why a type argument or constraint is malformed.
+func typeExprProblem(expr string) string {
+	if strings.TrimSpace(expr) == "" {
+		return "empty type expression"
+	}
+	depth := 0
+	for _, r := range expr {
+		switch r {
+		case '[', '(':
+			depth++
+		case ']', ')':
+			depth--
+			if depth < 0 {
+				return "unbalanced brackets"
+			}
+		}
+	}
+	if depth != 0 {
+		return "unbalanced brackets"
+	}
+	return ""
+}
+
+// metadataKeyProblem describes why key is not a legal custom metadata key.
+func metadataKeyProblem(key string) string {
+	if key == "" {
+		return "empty metadata key"
+	}
+	for i, r := range key {
+		switch {
+		case r == '_' || unicode.IsLetter(r):
+		case i > 0 && (r == '-' || r == '.' || unicode.IsDigit(r)):
+		default:
+			return fmt.Sprintf("invalid metadata key character %q", r)
+		}
+	}
+	return ""
+}
@@ -0,0 +1,82 @@
+package gsrf
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestSymbol_Validate(t *testing.T) {
+	tests := []struct {
+		name       string
+		sym        *Symbol
+		wantFields []string
+	}{
+		{name: "function", sym: MustParse("fmt.Println")},
+		{name: "method", sym: MustParse("net/http.(*Server).Serve")},
+		{name: "unicode", sym: MustParse("example.com/größe.Größe٣")},
+		{name: "generic", sym: MustParse("pkg.Map[string, []int]")},
+		{name: "anonymous in method", sym: MustParse("main.(*Server).Start·lit2")},
+		{name: "package closure", sym: MustParse("pkg.·lit")},
+		{name: "context and metadata", sym: MustParse("pkg.F@go1.21{via:T,pos:f.go:1:2,x-trace.id:7}")},
+		{name: "keyword allowed", sym: MustParse("pkg.func")},
+		{name: "empty path element", sym: &Symbol{PackagePath: "a//b", Name: "F"}, wantFields: []string{FieldPackagePath}},
+		{name: "path space", sym: &Symbol{PackagePath: "my pkg", Name: "F"}, wantFields: []string{FieldPackagePath}},
+		{name: "path reserved", sym: &Symbol{PackagePath: "pkg:v2", Name: "F"}, wantFields: []string{FieldPackagePath}},
+		{name: "leading digit", sym: MustParse("pkg.9lives"), wantFields: []string{FieldName}},
+		{name: "empty name", sym: &Symbol{PackagePath: "pkg"}, wantFields: []string{FieldName}},
+		{name: "receiver", sym: &Symbol{PackagePath: "pkg", Name: "M", Receiver: &Receiver{TypeName: "T-1"}}, wantFields: []string{FieldReceiverTypeName}},
+		{name: "type args", sym: &Symbol{PackagePath: "pkg", Name: "F", TypeArgs: []string{"map[K", ""}}, wantFields: []string{FieldTypeArgs, FieldTypeArgs}},
+		{name: "context", sym: &Symbol{PackagePath: "pkg", Name: "F", Context: "linux amd64"}, wantFields: []string{FieldContext}},
+		{name: "metadata", sym: &Symbol{PackagePath: "pkg", Name: "F", Metadata: Metadata{
+			Via:    []string{"A,B"},
+			Custom: map[string]string{"1key": "v", "ok": "{x}"},
+		}}, wantFields: []string{FieldMetadataVia, "metadata.custom.1key", "metadata.custom.ok"}},
+		{name: "init", sym: &Symbol{PackagePath: "pkg", Name: "Setup", IsInit: true}, wantFields: []string{FieldIsInit}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.sym.Validate()
+			if len(tt.wantFields) == 0 {
+				if err != nil {
+					t.Fatalf("Validate() error = %v", err)
+				}
+				return
+			}
+			var verr *ValidationError
+			if !errors.As(err, &verr) {
+				t.Fatalf("Validate() error = %v, want *ValidationError", err)
+			}
+			if len(verr.Violations) != len(tt.wantFields) {
+				t.Fatalf("Violations = %v, want fields %v", verr.Violations, tt.wantFields)
+			}
+			for i, v := range verr.Violations {
+				if v.Field != tt.wantFields[i] {
+					t.Errorf("Violations[%d].Field = %q, want %q", i, v.Field, tt.wantFields[i])
+				}
+			}
+		})
+	}
+}
+
+func TestValidationError_Error(t *testing.T) {
+	err := (&Symbol{PackagePath: "a b", Name: "1x"}).Validate()
+	if err == nil {
+		t.Fatal("Validate() = nil")
+	}
+	if msg := err.Error(); !strings.HasPrefix(msg, "invalid GSRF symbol: packagePath") || !strings.HasSuffix(msg, "(and 1 more)") {
+		t.Errorf("Error() = %q", msg)
+	}
+}
+
+func TestParseWith_StrictValidate(t *testing.T) {
+	_, err := ParseWith("pkg.Do-It", ParseOptions{Strict: true})
+	var verr *ValidationError
+	if !errors.As(err, &verr) {
+		t.Fatalf("ParseWith() error = %v, want *ValidationError", err)
+	}
+	if verr.Violations[0].Field != FieldName {
+		t.Errorf("Violations = %v", verr.Violations)
+	}
+}
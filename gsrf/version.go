@@ -0,0 +1,96 @@
+package gsrf
+
+import (
+	"cmp"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Version is a version of the GSRF specification, "major.minor".
+type Version string
+
+// Specification versions and the constructs each introduced.
+const (
+	V1_0 Version = "1.0" // Packages, functions, receivers, init and anonymous functions
+	V1_1 Version = "1.1" // Type parameters and arguments, contexts, metadata
+	V1_2 Version = "1.2" // Quoted package paths, %lit, alias chains, repeated via
+
+	// SpecVersion is the latest version implemented by this package.
+	SpecVersion = V1_2
+)
+
+// Compare returns -1, 0, or +1 as v is older than, equal to, or newer than
+// w. Versions that are not "major.minor" sort before all valid versions.
+func (v Version) Compare(w Version) int {
+	vm, vn, verr := v.split()
+	wm, wn, werr := w.split()
+	switch {
+	case verr != nil && werr != nil:
+		return 0
+	case verr != nil:
+		return -1
+	case werr != nil:
+		return 1
+	case vm != wm:
+		return cmp.Compare(vm, wm)
+	}
+	return cmp.Compare(vn, wn)
+}
+
+func (v Version) split() (major, minor int, err error) {
+	ma, mi, ok := strings.Cut(string(v), ".")
+	if ok {
+		if major, err = strconv.Atoi(ma); err == nil {
+			minor, err = strconv.Atoi(mi)
+		}
+	}
+	if !ok || err != nil || major < 0 || minor < 0 {
+		return 0, 0, fmt.Errorf("invalid GSRF version %q", string(v))
+	}
+	return major, minor, nil
+}
+
+// MinSpecVersion returns the oldest specification version that can
+// represent the symbol, so producers can tell whether a consumer that
+// supports only an older version will read it.
+func (s *Symbol) MinSpecVersion() Version {
+	switch {
+	case NeedsQuoting(s.PackagePath),
+		len(s.Metadata.Via) > 1,
+		strings.Contains(s.Metadata.Alias, AliasSeparator):
+		return V1_2
+	case len(s.TypeParams) > 0,
+		len(s.TypeArgs) > 0,
+		s.Receiver != nil && len(s.Receiver.TypeArgs) > 0,
+		s.Context != "",
+		hasMetadata(s.Metadata):
+		return V1_1
+	}
+	return V1_0
+}
+
+// ParseVersion parses input as a symbol of the given specification version.
+// Input using constructs introduced after that version is rejected, so a
+// consumer can parse strictly as, say, V1_0. Versions newer than
+// SpecVersion are rejected as unsupported.
+func ParseVersion(input string, version Version) (*Symbol, error) {
+	if _, _, err := version.split(); err != nil {
+		return nil, err
+	}
+	if version.Compare(SpecVersion) > 0 {
+		return nil, fmt.Errorf("unsupported GSRF version %s: newest supported is %s", version, SpecVersion)
+	}
+	if version.Compare(V1_2) < 0 && strings.Contains(input, anonMarkerASCII) {
+		return nil, fmt.Errorf("invalid GSRF %s symbol: %q marker requires %s", version, anonMarkerASCII, V1_2)
+	}
+
+	sym, err := Parse(input)
+	if err != nil {
+		return nil, err
+	}
+	if min := sym.MinSpecVersion(); min.Compare(version) > 0 {
+		return nil, fmt.Errorf("invalid GSRF %s symbol: %q requires %s", version, input, min)
+	}
+	return sym, nil
+}
@@ -0,0 +1,75 @@
+package gsrf
+
+import "testing"
+
+func TestSymbol_MinSpecVersion(t *testing.T) {
+	tests := []struct {
+		input string
+		want  Version
+	}{
+		{"fmt.Println", V1_0},
+		{"net/http.(*Server).Serve", V1_0},
+		{"main.main·lit2", V1_0},
+		{"slices.Sort[int]", V1_1},
+		{"container.(*Stack[T]).Push", V1_1},
+		{"syscall.Open@darwin", V1_1},
+		{"pkg.F{pos:f.go:1:1}", V1_1},
+		{"pkg.(*T).Close{via:Conn}", V1_1},
+		{"pkg.(*T).Close{via:Conn,via:net.Conn}", V1_2},
+		{"pkg.Open{alias:compat.Open>v1.Open}", V1_2},
+		{`"example.com/a@b".Run`, V1_2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			if got := MustParse(tt.input).MinSpecVersion(); got != tt.want {
+				t.Errorf("MinSpecVersion() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseVersion(t *testing.T) {
+	tests := []struct {
+		input   string
+		version Version
+		wantErr bool
+	}{
+		{"fmt.Println", V1_0, false},
+		{"slices.Sort[int]", V1_0, true},
+		{"slices.Sort[int]", V1_1, false},
+		{"main.main%lit2", V1_1, true},
+		{"main.main%lit2", V1_2, false},
+		{"pkg.(*T).Close{via:A,via:B}", V1_1, true},
+		{"pkg.(*T).Close{via:A,via:B}", SpecVersion, false},
+		{"fmt.Println", "9.0", true},
+		{"fmt.Println", "latest", true},
+		{"not a symbol", V1_2, true},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.version)+" "+tt.input, func(t *testing.T) {
+			_, err := ParseVersion(tt.input, tt.version)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseVersion() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestVersion_Compare(t *testing.T) {
+	tests := []struct {
+		a, b Version
+		want int
+	}{
+		{V1_0, V1_1, -1},
+		{V1_2, V1_1, 1},
+		{"1.10", V1_2, 1},
+		{"2.0", "1.9", 1},
+		{V1_1, V1_1, 0},
+		{"bad", V1_0, -1},
+	}
+	for _, tt := range tests {
+		if got := tt.a.Compare(tt.b); got != tt.want {
+			t.Errorf("%s.Compare(%s) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
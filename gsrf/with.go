@@ -0,0 +1,90 @@
+package gsrf
+
+import (
+	"fmt"
+	"strings"
+)
+
+// The With* methods return a modified copy of the symbol and never mutate
+// the receiver, so pipeline stages can derive variants of shared symbols.
+// Like Clone, they return nil for a nil symbol.
+
+// WithContext returns a copy with the context set; "" removes it.
+func (s *Symbol) WithContext(context string) *Symbol {
+	c := s.Clone()
+	if c != nil {
+		c.Context = context
+	}
+	return c
+}
+
+// WithPosition returns a copy with the source position (file:line:col) set.
+// Like WithMetadata, it panics if the position contains ',', '{' or '}'.
+func (s *Symbol) WithPosition(pos string) *Symbol {
+	return s.WithMetadata("pos", pos)
+}
+
+// ValidateMetadata reports whether key and value form a metadata entry that
+// survives a round trip through Parse: the key must be non-empty and free of
+// ':', ',', '{' and '}', and the value free of ',', '{' and '}'. A "via"
+// value is a comma-separated list, checked entry by entry.
+func ValidateMetadata(key, value string) error {
+	if key == "" || strings.ContainsAny(key, ":,{}") {
+		return fmt.Errorf("invalid metadata key %q", key)
+	}
+	values := []string{value}
+	if key == "via" {
+		values = splitFieldList(value)
+	}
+	for _, v := range values {
+		if strings.ContainsAny(v, ",{}") {
+			return fmt.Errorf("invalid metadata value %q for %s: reserved characters", v, key)
+		}
+	}
+	return nil
+}
+
+// WithMetadata returns a copy with one metadata value set. The keys "via",
+// "alias" and "pos" set the corresponding Metadata fields; any other key is
+// stored in Metadata.Custom. An empty value removes the key. WithMetadata
+// panics if ValidateMetadata rejects the entry; check values from untrusted
+// sources first.
+func (s *Symbol) WithMetadata(key, value string) *Symbol {
+	if err := ValidateMetadata(key, value); err != nil {
+		panic(err)
+	}
+	c := s.Clone()
+	if c == nil {
+		return nil
+	}
+	switch key {
+	case "via":
+		c.Metadata.Via = splitFieldList(value)
+	case "alias":
+		c.Metadata.Alias = value
+	case "pos":
+		c.Metadata.Position = value
+	default:
+		if value == "" {
+			delete(c.Metadata.Custom, key)
+			if len(c.Metadata.Custom) == 0 {
+				c.Metadata.Custom = nil
+			}
+			break
+		}
+		if c.Metadata.Custom == nil {
+			c.Metadata.Custom = make(map[string]string)
+		}
+		c.Metadata.Custom[key] = value
+	}
+	return c
+}
+
+// WithoutMetadata returns a copy with all metadata removed.
+func (s *Symbol) WithoutMetadata() *Symbol {
+	c := s.Clone()
+	if c != nil {
+		c.Metadata = Metadata{}
+	}
+	return c
+}
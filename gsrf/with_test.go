@@ -0,0 +1,55 @@
+package gsrf
+
+import "testing"
+
+func TestSymbol_With(t *testing.T) {
+	orig := MustParse("pkg.(*T).Run{via:Base,owner:core}")
+	before := orig.String()
+
+	tests := []struct {
+		name string
+		got  *Symbol
+		want string
+	}{
+		{"context", orig.WithContext("linux"), "pkg.(*T).Run@linux{via:Base,owner:core}"},
+		{"position", orig.WithPosition("t.go:10:2"), "pkg.(*T).Run{via:Base,pos:t.go:10:2,owner:core}"},
+		{"custom", orig.WithMetadata("team", "infra"), "pkg.(*T).Run{via:Base,owner:core,team:infra}"},
+		{"remove custom", orig.WithMetadata("owner", ""), "pkg.(*T).Run{via:Base}"},
+		{"remove field", orig.WithMetadata("via", ""), "pkg.(*T).Run{owner:core}"},
+		{"without metadata", orig.WithoutMetadata(), "pkg.(*T).Run"},
+		{"chained", orig.WithoutMetadata().WithContext("cgo").WithPosition("a.go:1:1"), "pkg.(*T).Run@cgo{pos:a.go:1:1}"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.got.String(); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	if orig.String() != before {
+		t.Errorf("With* modified the original: %q, want %q", orig, before)
+	}
+
+	for _, entry := range [][2]string{{"owner", "a,b}"}, {"pos", "f.go:1}"}, {"via", "A, B{x}"}, {"k:v", "x"}, {"", "x"}} {
+		if err := ValidateMetadata(entry[0], entry[1]); err == nil {
+			t.Errorf("ValidateMetadata(%q, %q) = nil, want error", entry[0], entry[1])
+		}
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("WithMetadata(%q, %q) did not panic", entry[0], entry[1])
+				}
+			}()
+			orig.WithMetadata(entry[0], entry[1])
+		}()
+	}
+	if err := ValidateMetadata("via", "A, *B"); err != nil {
+		t.Errorf("ValidateMetadata(via) error = %v", err)
+	}
+
+	var nilSym *Symbol
+	if nilSym.WithContext("linux") != nil || nilSym.WithMetadata("k", "v") != nil || nilSym.WithoutMetadata() != nil {
+		t.Error("With* of nil symbol is not nil")
+	}
+}
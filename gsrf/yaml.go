@@ -0,0 +1,47 @@
+package gsrf
+
+import (
+	"fmt"
+
+	"gopkg.in/yaml.v3"
+)
+
+// MarshalYAML implements yaml.Marshaler. Symbols are written as GSRF strings
+// so configuration files stay readable.
+func (s *Symbol) MarshalYAML() (interface{}, error) {
+	return s.Format(), nil
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler. It accepts a GSRF string or a
+// mapping using the same keys as the JSON object schema.
+func (s *Symbol) UnmarshalYAML(value *yaml.Node) error {
+	switch value.Kind {
+	case yaml.ScalarNode:
+		return s.UnmarshalText([]byte(value.Value))
+	case yaml.MappingNode:
+		var w symbolJSON
+		if err := value.Decode(&w); err != nil {
+			return err
+		}
+		sym, err := w.symbol()
+		if err != nil {
+			return err
+		}
+		*s = sym
+		return nil
+	}
+	return fmt.Errorf("invalid GSRF symbol: line %d: expected string or mapping", value.Line)
+}
+
+// MarshalYAML implements yaml.Marshaler using the GSRF string form.
+func (k SymbolKey) MarshalYAML() (interface{}, error) {
+	return k.String(), nil
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler by parsing a GSRF string.
+func (k *SymbolKey) UnmarshalYAML(value *yaml.Node) error {
+	if value.Kind != yaml.ScalarNode {
+		return fmt.Errorf("invalid GSRF symbol: line %d: expected string", value.Line)
+	}
+	return k.UnmarshalText([]byte(value.Value))
+}
@@ -0,0 +1,102 @@
+package gsrf
+
+import (
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	_ yaml.Marshaler   = (*Symbol)(nil)
+	_ yaml.Unmarshaler = (*Symbol)(nil)
+	_ yaml.Marshaler   = SymbolKey{}
+	_ yaml.Unmarshaler = (*SymbolKey)(nil)
+)
+
+func TestSymbol_YAMLConfig(t *testing.T) {
+	type rule struct {
+		Match   *Symbol `yaml:"match"`
+		Rewrite *Symbol `yaml:"rewrite,omitempty"`
+	}
+	type config struct {
+		Allow    []*Symbol         `yaml:"allow"`
+		Rules    []rule            `yaml:"rules"`
+		Sampling map[SymbolKey]int `yaml:"sampling"`
+	}
+
+	input := `allow:
+  - fmt.Println
+  - net/http.(*Server).Serve
+  - "pkg.Map[K comparable, V any]@linux"
+rules:
+  - match: main.main·lit1
+    rewrite:
+      package: main
+      name: main
+      anonymous: true
+      anon_parent: main.main
+      anon_index: 2
+sampling:
+  pkg.(*T).M: 10
+`
+
+	var cfg config
+	if err := yaml.Unmarshal([]byte(input), &cfg); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	if len(cfg.Allow) != 3 || cfg.Allow[1].Receiver == nil || cfg.Allow[1].Receiver.TypeName != "Server" {
+		t.Fatalf("Allow = %+v", cfg.Allow)
+	}
+	if got := cfg.Allow[2].Format(); got != "pkg.Map[K comparable, V any]@linux" {
+		t.Errorf("Allow[2] = %q", got)
+	}
+	if got := cfg.Rules[0].Rewrite.Format(); got != "main.main·lit2" {
+		t.Errorf("Rewrite = %q", got)
+	}
+	if cfg.Sampling[MustParse("pkg.(*T).M").Key()] != 10 {
+		t.Errorf("Sampling = %v", cfg.Sampling)
+	}
+
+	out, err := yaml.Marshal(cfg)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	var back config
+	if err := yaml.Unmarshal(out, &back); err != nil {
+		t.Fatalf("Unmarshal(Marshal()) error = %v\n%s", err, out)
+	}
+	for i := range cfg.Allow {
+		if !back.Allow[i].Equal(cfg.Allow[i]) {
+			t.Errorf("Allow[%d] round trip = %q", i, back.Allow[i].Format())
+		}
+	}
+	if !back.Rules[0].Rewrite.Equal(cfg.Rules[0].Rewrite) {
+		t.Errorf("Rewrite round trip = %q", back.Rules[0].Rewrite.Format())
+	}
+}
+
+func TestSymbol_MarshalYAML(t *testing.T) {
+	out, err := yaml.Marshal(MustParse("net/http.(*Server).Serve"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(out) != "net/http.(*Server).Serve\n" {
+		t.Errorf("Marshal() = %q", out)
+	}
+}
+
+func TestSymbol_UnmarshalYAMLErrors(t *testing.T) {
+	inputs := map[string]string{
+		"invalid string": "invalid",
+		"sequence":       "[fmt.Println]",
+		"missing name":   "package: fmt",
+	}
+	for name, in := range inputs {
+		t.Run(name, func(t *testing.T) {
+			var sym Symbol
+			if err := yaml.Unmarshal([]byte(in), &sym); err == nil {
+				t.Errorf("Unmarshal(%q) expected error", in)
+			}
+		})
+	}
+}